@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/dshills/gocontext-mcp/internal/archcheck"
+	"github.com/dshills/gocontext-mcp/internal/config"
+	"github.com/dshills/gocontext-mcp/internal/indexer"
+	"github.com/dshills/gocontext-mcp/internal/storage"
+)
+
+// Exit codes of check-architecture
+const (
+	exitPassed     = 0 // No violations
+	exitViolations = 1 // At least one violation
+	exitError      = 2 // The check could not run
+)
+
+// runCheckArchitecture implements the check-architecture command for CI: it
+// checks a project's imports against the architecture rules of its
+// .gocontext.yaml and returns the process exit code
+func runCheckArchitecture(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("check-architecture", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dbPath := flags.String("db", "", "Use the index in this database directory instead of indexing the project")
+	ref := flags.String("ref", "", "Check this git revision instead of the working tree")
+	includeTests := flags.Bool("include-tests", false, "Also check imports of _test.go files")
+	asJSON := flags.Bool("json", false, "Print violations as JSON")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "Usage: gocontext check-architecture [flags] [path]\n\n")
+		_, _ = fmt.Fprintf(stderr, "Checks imports against the architecture rules of .gocontext.yaml.\n")
+		_, _ = fmt.Fprintf(stderr, "Exits 1 when a rule is violated and 2 when the check cannot run.\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	violations, err := checkArchitecture(context.Background(), root, *dbPath, *ref, *includeTests)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "check-architecture: %v\n", err)
+		return exitError
+	}
+
+	if *asJSON {
+		if violations == nil {
+			violations = []archcheck.Violation{}
+		}
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(violations); err != nil {
+			_, _ = fmt.Fprintf(stderr, "check-architecture: %v\n", err)
+			return exitError
+		}
+	} else {
+		for _, v := range violations {
+			_, _ = fmt.Fprintf(stdout, "%s:%d: imports %q: %s\n", v.File, v.Line, v.Import, v.Message)
+		}
+	}
+
+	if len(violations) > 0 {
+		_, _ = fmt.Fprintf(stderr, "%d architecture violation(s)\n", len(violations))
+		return exitViolations
+	}
+	return exitPassed
+}
+
+// checkArchitecture indexes the project at root into a temporary in-memory
+// database, or reads it from the index in dbPath, and checks its imports
+func checkArchitecture(ctx context.Context, root, dbPath, ref string, includeTests bool) ([]archcheck.Violation, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Architecture.Rules) == 0 {
+		return nil, fmt.Errorf("no architecture rules configured in %s", root)
+	}
+	checker, err := archcheck.New(cfg.Architecture)
+	if err != nil {
+		return nil, err
+	}
+
+	var store storage.Storage
+	if dbPath == "" {
+		store, err = storage.NewSQLiteStorage(":memory:")
+	} else {
+		store, err = storage.NewSQLiteStorage(filepath.Join(dbPath, "gocontext.db"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	if dbPath == "" {
+		// Imports are all the check needs, so skip embeddings
+		_, err := indexer.New(store).IndexProject(ctx, root, &indexer.Config{
+			IncludeTests:       includeTests,
+			GenerateEmbeddings: false,
+			Project:            cfg,
+			Ref:                ref,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("indexing failed: %w", err)
+		}
+	}
+
+	projectPath := root
+	if ref != "" {
+		projectPath = storage.SnapshotRootPath(root, ref)
+	}
+	project, err := store.GetProject(ctx, projectPath)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("%s is not indexed in %s", projectPath, dbPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := archcheck.LoadFiles(ctx, store, project.ID, includeTests)
+	if err != nil {
+		return nil, err
+	}
+	return checker.Check(files), nil
+}
@@ -29,6 +29,11 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Subcommands run once and exit instead of serving MCP
+	if len(os.Args) > 1 && os.Args[1] == "check-architecture" {
+		os.Exit(runCheckArchitecture(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Log startup info to stderr (stdout reserved for MCP protocol)
 	log.SetOutput(os.Stderr)
 	log.Printf("GoContext MCP Server v%s starting...", version)
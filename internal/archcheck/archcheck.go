@@ -0,0 +1,225 @@
+package archcheck
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dshills/gocontext-mcp/internal/config"
+)
+
+// File is an indexed file with the imports it declares
+type File struct {
+	Path       string // Relative to the project root, slash-separated
+	ImportPath string // Import path of the file's package, empty outside modules
+	Imports    []Import
+}
+
+// Import is an import spec of a file
+type Import struct {
+	Path string
+	Line int
+}
+
+// Violation is an import a rule forbids
+type Violation struct {
+	Rule    string `json:"rule"`             // Rule name, or its description when unnamed
+	Reason  string `json:"reason,omitempty"` // The rule's reason, if any
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Package string `json:"package"` // Importing package directory
+	Import  string `json:"import"`  // Imported package path
+	Message string `json:"message"` // What the import breaks, e.g. "domain must not import infrastructure"
+}
+
+// pkg identifies a package by directory and import path; imports of packages
+// outside the project have no directory
+type pkg struct {
+	dir        string
+	importPath string
+}
+
+// set is a compiled package reference: a layer's globs, or a single glob
+type set struct {
+	name  string
+	globs []string
+}
+
+// contains reports whether the set includes a package
+func (s set) contains(p pkg) bool {
+	for _, glob := range s.globs {
+		if config.MatchPackage(glob, p.dir) || config.MatchPackage(glob, p.importPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// rule is a compiled config.ArchRule
+type rule struct {
+	name   string
+	reason string
+	from   *set
+	deny   []set
+	allow  []set
+	to     *set
+	only   []set
+}
+
+// Checker evaluates a project's architecture rules
+type Checker struct {
+	rules []rule
+}
+
+// New compiles the rules of a project configuration. References to layers
+// resolve to the layers' package globs; anything else is a glob.
+func New(cfg config.ArchitectureConfig) (*Checker, error) {
+	layers := make(map[string][]string, len(cfg.Layers))
+	for _, layer := range cfg.Layers {
+		layers[layer.Name] = layer.Packages
+	}
+	resolve := func(refs []string) []set {
+		sets := make([]set, len(refs))
+		for i, ref := range refs {
+			if globs, ok := layers[ref]; ok {
+				sets[i] = set{name: ref, globs: globs}
+			} else {
+				sets[i] = set{name: ref, globs: []string{ref}}
+			}
+		}
+		return sets
+	}
+
+	c := &Checker{rules: make([]rule, 0, len(cfg.Rules))}
+	for i, r := range cfg.Rules {
+		compiled := rule{name: r.Name, reason: r.Reason}
+		switch {
+		case r.From != "" && (len(r.Deny) > 0 || len(r.Allow) > 0):
+			compiled.from = &resolve([]string{r.From})[0]
+			compiled.deny = resolve(r.Deny)
+			compiled.allow = resolve(r.Allow)
+		case r.To != "" && len(r.OnlyFrom) > 0:
+			compiled.to = &resolve([]string{r.To})[0]
+			compiled.only = resolve(r.OnlyFrom)
+		default:
+			return nil, fmt.Errorf("rule %d: requires from with deny or allow, or to with only_from", i)
+		}
+		if compiled.name == "" {
+			compiled.name = compiled.describe()
+		}
+		c.rules = append(c.rules, compiled)
+	}
+	return c, nil
+}
+
+// Rules returns the number of rules checked
+func (c *Checker) Rules() int {
+	return len(c.rules)
+}
+
+// Check returns the imports of files that break a rule, ordered by file and line
+func (c *Checker) Check(files []File) []Violation {
+	// Imports of project packages resolve to their directory so globs on
+	// directories match both sides
+	dirs := make(map[string]string)
+	for _, f := range files {
+		if f.ImportPath != "" {
+			dirs[f.ImportPath] = dir(f.Path)
+		}
+	}
+
+	var violations []Violation
+	for _, f := range files {
+		from := pkg{dir: dir(f.Path), importPath: f.ImportPath}
+		for _, imp := range f.Imports {
+			d, internal := dirs[imp.Path]
+			to := pkg{dir: d, importPath: imp.Path}
+			for i := range c.rules {
+				r := &c.rules[i]
+				message, broken := r.check(from, to, internal)
+				if !broken {
+					continue
+				}
+				violations = append(violations, Violation{
+					Rule:    r.name,
+					Reason:  r.reason,
+					File:    f.Path,
+					Line:    imp.Line,
+					Package: from.dir,
+					Import:  imp.Path,
+					Message: message,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
+		}
+		return violations[i].Line < violations[j].Line
+	})
+	return violations
+}
+
+// check reports whether an import from one package of another breaks the rule.
+// Imports within the constrained set of packages are always allowed, and allow
+// lists only restrict imports of project packages.
+func (r *rule) check(from, to pkg, internal bool) (string, bool) {
+	if r.to != nil {
+		if !r.to.contains(to) || r.to.contains(from) {
+			return "", false
+		}
+		if slices.ContainsFunc(r.only, func(s set) bool { return s.contains(from) }) {
+			return "", false
+		}
+		return fmt.Sprintf("%s may only be imported by %s", r.to.name, names(r.only)), true
+	}
+
+	if !r.from.contains(from) || r.from.contains(to) {
+		return "", false
+	}
+	for _, s := range r.deny {
+		if s.contains(to) {
+			return fmt.Sprintf("%s must not import %s", r.from.name, s.name), true
+		}
+	}
+	if len(r.allow) > 0 && internal &&
+		!slices.ContainsFunc(r.allow, func(s set) bool { return s.contains(to) }) {
+		return fmt.Sprintf("%s may only import %s", r.from.name, names(r.allow)), true
+	}
+	return "", false
+}
+
+// describe names an unnamed rule after what it enforces
+func (r *rule) describe() string {
+	if r.to != nil {
+		return fmt.Sprintf("%s only imported by %s", r.to.name, names(r.only))
+	}
+	var parts []string
+	if len(r.deny) > 0 {
+		parts = append(parts, "must not import "+names(r.deny))
+	}
+	if len(r.allow) > 0 {
+		parts = append(parts, "may only import "+names(r.allow))
+	}
+	return r.from.name + " " + strings.Join(parts, " and ")
+}
+
+// names lists the references of sets
+func names(sets []set) string {
+	list := make([]string, len(sets))
+	for i, s := range sets {
+		list[i] = s.name
+	}
+	return strings.Join(list, ", ")
+}
+
+// dir returns the slash-separated directory of a relative file path, "." at the root
+func dir(path string) string {
+	if i := strings.LastIndexByte(path, '/'); i >= 0 {
+		return path[:i]
+	}
+	return "."
+}
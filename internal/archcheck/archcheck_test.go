@@ -0,0 +1,155 @@
+package archcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dshills/gocontext-mcp/internal/config"
+	"github.com/dshills/gocontext-mcp/internal/storage"
+)
+
+// layered is a project with domain, application and infrastructure layers
+var layered = []File{
+	{Path: "internal/domain/order.go", ImportPath: "example.com/shop/internal/domain", Imports: []Import{
+		{Path: "errors", Line: 3},
+		{Path: "example.com/shop/internal/domain/money", Line: 4},
+		{Path: "example.com/shop/internal/infra/db", Line: 5},
+		{Path: "database/sql", Line: 6},
+	}},
+	{Path: "internal/domain/money/money.go", ImportPath: "example.com/shop/internal/domain/money"},
+	{Path: "internal/app/orders.go", ImportPath: "example.com/shop/internal/app", Imports: []Import{
+		{Path: "context", Line: 3},
+		{Path: "example.com/shop/internal/domain", Line: 4},
+		{Path: "example.com/shop/internal/infra/db", Line: 5},
+	}},
+	{Path: "internal/infra/db/db.go", ImportPath: "example.com/shop/internal/infra/db", Imports: []Import{
+		{Path: "example.com/shop/internal/domain", Line: 4},
+		{Path: "example.com/shop/internal/storage", Line: 5},
+	}},
+	{Path: "internal/storage/sqlite.go", ImportPath: "example.com/shop/internal/storage"},
+	{Path: "cmd/shop/main.go", ImportPath: "example.com/shop/cmd/shop", Imports: []Import{
+		{Path: "example.com/shop/internal/storage", Line: 7},
+	}},
+}
+
+var layers = []config.Layer{
+	{Name: "domain", Packages: []string{"internal/domain/**"}},
+	{Name: "application", Packages: []string{"internal/app/**"}},
+	{Name: "infrastructure", Packages: []string{"internal/infra/**"}},
+}
+
+func TestCheck_Deny(t *testing.T) {
+	checker, err := New(config.ArchitectureConfig{
+		Layers: layers,
+		Rules: []config.ArchRule{
+			{From: "domain", Deny: []string{"infrastructure", "database/sql"}, Reason: "keep the domain pure"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, checker.Rules())
+
+	// The domain's own subpackage and the standard library are fine
+	violations := checker.Check(layered)
+	assert.Equal(t, []Violation{
+		{
+			Rule:    "domain must not import infrastructure, database/sql",
+			Reason:  "keep the domain pure",
+			File:    "internal/domain/order.go",
+			Line:    5,
+			Package: "internal/domain",
+			Import:  "example.com/shop/internal/infra/db",
+			Message: "domain must not import infrastructure",
+		},
+		{
+			Rule:    "domain must not import infrastructure, database/sql",
+			Reason:  "keep the domain pure",
+			File:    "internal/domain/order.go",
+			Line:    6,
+			Package: "internal/domain",
+			Import:  "database/sql",
+			Message: "domain must not import database/sql",
+		},
+	}, violations)
+}
+
+func TestCheck_Allow(t *testing.T) {
+	checker, err := New(config.ArchitectureConfig{
+		Layers: layers,
+		Rules:  []config.ArchRule{{Name: "application", From: "application", Allow: []string{"domain"}}},
+	})
+	require.NoError(t, err)
+
+	// Allow lists leave the standard library alone
+	violations := checker.Check(layered)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "application", violations[0].Rule)
+	assert.Equal(t, "internal/app/orders.go", violations[0].File)
+	assert.Equal(t, 5, violations[0].Line)
+	assert.Equal(t, "application may only import domain", violations[0].Message)
+}
+
+func TestCheck_OnlyFrom(t *testing.T) {
+	checker, err := New(config.ArchitectureConfig{
+		Rules: []config.ArchRule{{To: "internal/storage/**", OnlyFrom: []string{"internal/infra/**"}}},
+	})
+	require.NoError(t, err)
+
+	violations := checker.Check(layered)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "internal/storage/** only imported by internal/infra/**", violations[0].Rule)
+	assert.Equal(t, "cmd/shop/main.go", violations[0].File)
+	assert.Equal(t, 7, violations[0].Line)
+	assert.Equal(t, "cmd/shop", violations[0].Package)
+	assert.Equal(t, "internal/storage/** may only be imported by internal/infra/**", violations[0].Message)
+}
+
+func TestCheck_ImportPathGlobs(t *testing.T) {
+	checker, err := New(config.ArchitectureConfig{
+		Rules: []config.ArchRule{{From: "example.com/shop/internal/domain/**", Deny: []string{"example.com/shop/internal/infra/**"}}},
+	})
+	require.NoError(t, err)
+
+	violations := checker.Check(layered)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "example.com/shop/internal/infra/db", violations[0].Import)
+}
+
+func TestCheck_NoRules(t *testing.T) {
+	checker, err := New(config.ArchitectureConfig{})
+	require.NoError(t, err)
+	assert.Zero(t, checker.Rules())
+	assert.Empty(t, checker.Check(layered))
+}
+
+func TestLoadFiles(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+	ctx := context.Background()
+
+	project := &storage.Project{RootPath: "/shop", ModuleName: "example.com/shop"}
+	require.NoError(t, store.CreateProject(ctx, project))
+	for i, path := range []string{"internal/domain/order.go", "internal/domain/order_test.go"} {
+		file := &storage.File{ProjectID: project.ID, FilePath: path, PackageName: "domain",
+			ImportPath: "example.com/shop/internal/domain", ContentHash: [32]byte{byte(i + 1)}, ModTime: time.Now()}
+		require.NoError(t, store.UpsertFile(ctx, file))
+		require.NoError(t, store.UpsertImport(ctx, &storage.Import{FileID: file.ID, ImportPath: "database/sql", Line: 3 + i}))
+	}
+
+	files, err := LoadFiles(ctx, store, project.ID, false)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, File{
+		Path:       "internal/domain/order.go",
+		ImportPath: "example.com/shop/internal/domain",
+		Imports:    []Import{{Path: "database/sql", Line: 3}},
+	}, files[0])
+
+	files, err = LoadFiles(ctx, store, project.ID, true)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+}
@@ -0,0 +1,48 @@
+// Package archcheck enforces architecture layer rules on the imports stored for
+// an indexed project.
+//
+// Layers name groups of packages, and rules constrain the imports between them.
+// Both come from the architecture section of .gocontext.yaml:
+//
+//	architecture:
+//	  layers:
+//	    - name: domain
+//	      packages: [internal/domain/**]
+//	    - name: application
+//	      packages: [internal/app/**]
+//	    - name: infrastructure
+//	      packages: [internal/infra/**]
+//	  rules:
+//	    - from: domain
+//	      deny: [application, infrastructure, database/sql]
+//	      reason: the domain model stays persistence-agnostic
+//	    - from: application
+//	      allow: [domain]
+//	    - name: storage access
+//	      to: internal/storage/**
+//	      only_from: [internal/indexer/**, internal/searcher/**, internal/mcp/**]
+//
+// A rule references packages by layer name or by a glob matched against the
+// package directory or import path; "internal/domain/**" also matches the
+// internal/domain package itself. deny forbids any import, including the
+// standard library and other modules, while allow and only_from restrict imports
+// between project packages. Imports within the constrained set of packages are
+// always allowed.
+//
+// # Basic Usage
+//
+//	checker, err := archcheck.New(cfg.Architecture)
+//	if err != nil {
+//	    return err
+//	}
+//	files, err := archcheck.LoadFiles(ctx, store, project.ID, false)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, v := range checker.Check(files) {
+//	    fmt.Printf("%s:%d: imports %s: %s\n", v.File, v.Line, v.Import, v.Message)
+//	}
+//
+// The check_architecture MCP tool and the gocontext check-architecture command
+// share this package, so a CI job gates on the same rules an assistant sees.
+package archcheck
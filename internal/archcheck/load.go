@@ -0,0 +1,40 @@
+package archcheck
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/dshills/gocontext-mcp/internal/storage"
+)
+
+// LoadFiles reads the files of an indexed project with their stored imports.
+// Test files are skipped unless includeTests is set.
+func LoadFiles(ctx context.Context, store storage.Storage, projectID int64, includeTests bool) ([]File, error) {
+	files, err := store.ListFiles(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	imports, err := store.ListImportsByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	byFile := make(map[int64][]Import)
+	for _, imp := range imports {
+		byFile[imp.FileID] = append(byFile[imp.FileID], Import{Path: imp.ImportPath, Line: imp.Line})
+	}
+
+	result := make([]File, 0, len(files))
+	for _, f := range files {
+		if !includeTests && strings.HasSuffix(f.FilePath, "_test.go") {
+			continue
+		}
+		result = append(result, File{
+			Path:       filepath.ToSlash(f.FilePath),
+			ImportPath: f.ImportPath,
+			Imports:    byFile[f.ID],
+		})
+	}
+	return result, nil
+}
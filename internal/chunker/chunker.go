@@ -17,12 +17,34 @@ const (
 	TokensPerChar = 4
 )
 
+// Strategy selects how a file is split into chunks
+type Strategy string
+
+const (
+	// StrategySymbol creates one chunk per top-level symbol (default)
+	StrategySymbol Strategy = "symbol"
+
+	// StrategyFile creates a single chunk covering the whole file
+	StrategyFile Strategy = "file"
+)
+
 // Chunker creates semantic code chunks from parsed Go files
-type Chunker struct{}
+type Chunker struct {
+	strategy Strategy
+}
 
 // New creates a new Chunker instance
 func New() *Chunker {
-	return &Chunker{}
+	return &Chunker{strategy: StrategySymbol}
+}
+
+// NewWithStrategy creates a Chunker using the given strategy.
+// Unknown or empty strategies fall back to StrategySymbol.
+func NewWithStrategy(strategy Strategy) *Chunker {
+	if strategy != StrategyFile {
+		strategy = StrategySymbol
+	}
+	return &Chunker{strategy: strategy}
 }
 
 // ChunkFile creates semantic chunks from a Go source file with its parse results
@@ -32,7 +54,11 @@ func (c *Chunker) ChunkFile(filePath string, parseResult *types.ParseResult, fil
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	return c.ChunkSource(content, parseResult, fileID)
+}
 
+// ChunkSource splits already-read file content into chunks using the symbols in parseResult
+func (c *Chunker) ChunkSource(content []byte, parseResult *types.ParseResult, fileID int64) ([]*types.Chunk, error) {
 	// Use the parseResult which already has symbol positions from the parser
 	// Don't re-parse - the parser may have handled syntax errors gracefully
 	// and we can still create chunks from the symbols that were extracted
@@ -43,8 +69,20 @@ func (c *Chunker) ChunkFile(filePath string, parseResult *types.ParseResult, fil
 	// Build context information
 	contextBefore := c.buildPackageContext(parseResult, lines)
 
+	if c.strategy == StrategyFile {
+		return c.createFileChunk(lines, contextBefore, fileID), nil
+	}
+
 	chunks := make([]*types.Chunk, 0)
 
+	// Interface methods are declared inside their interface, so they share its chunk
+	interfaces := make(map[string]bool)
+	for i := range parseResult.Symbols {
+		if parseResult.Symbols[i].Kind == types.KindInterface {
+			interfaces[parseResult.Symbols[i].Name] = true
+		}
+	}
+
 	// Create chunks for each symbol
 	for i := range parseResult.Symbols {
 		sym := &parseResult.Symbols[i]
@@ -52,6 +90,9 @@ func (c *Chunker) ChunkFile(filePath string, parseResult *types.ParseResult, fil
 		if sym.Kind == types.KindField {
 			continue
 		}
+		if sym.Kind == types.KindMethod && interfaces[sym.Receiver] {
+			continue
+		}
 
 		chunk := c.createChunkForSymbol(sym, lines, contextBefore, fileID)
 		if chunk != nil {
@@ -131,6 +172,31 @@ func (c *Chunker) createPackageChunk(packageName string, lines []string, fileID
 	return chunk
 }
 
+// createFileChunk creates a single chunk spanning the whole file
+func (c *Chunker) createFileChunk(lines []string, contextBefore string, fileID int64) []*types.Chunk {
+	// Drop the trailing empty line produced by the final newline
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return []*types.Chunk{}
+	}
+
+	chunk := &types.Chunk{
+		FileID:        fileID,
+		Content:       strings.Join(lines, "\n"),
+		ContextBefore: contextBefore,
+		StartLine:     1,
+		EndLine:       len(lines),
+		ChunkType:     types.ChunkPackage,
+	}
+
+	chunk.ComputeTokenCount()
+	chunk.ComputeContentHash()
+
+	return []*types.Chunk{chunk}
+}
+
 // buildPackageContext builds the context information (package + imports)
 func (c *Chunker) buildPackageContext(parseResult *types.ParseResult, lines []string) string {
 	var context strings.Builder
@@ -237,7 +303,7 @@ func (c *Chunker) ExtractRelatedContext(sym *types.Symbol, allSymbols []types.Sy
 		for i := range allSymbols {
 			s := &allSymbols[i]
 			if s.Kind == types.KindStruct && s.Name == sym.Receiver {
-				related = append(related, fmt.Sprintf("// Receiver: %s", s.Signature))
+				related = append(related, fmt.Sprintf("// Receiver: %s", strings.ReplaceAll(s.Signature, "\n", "\n// ")))
 				break
 			}
 		}
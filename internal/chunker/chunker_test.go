@@ -3,6 +3,7 @@ package chunker
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/dshills/gocontext-mcp/internal/parser"
@@ -111,6 +112,37 @@ func (u *User) SetName(name string) {
 	assert.Equal(t, 2, methodChunks) // GetID and SetName
 }
 
+func TestChunkFile_FileStrategy(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+
+	content := `package testpkg
+
+func A() {}
+
+func B() {}
+`
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+	parseResult, err := parser.New().ParseFile(testFile)
+	require.NoError(t, err)
+
+	chunks, err := NewWithStrategy(StrategyFile).ChunkFile(testFile, parseResult, 1)
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+
+	assert.Equal(t, 1, chunks[0].StartLine)
+	assert.Equal(t, 5, chunks[0].EndLine)
+	assert.Contains(t, chunks[0].Content, "func A()")
+	assert.Contains(t, chunks[0].Content, "func B()")
+	assert.Contains(t, chunks[0].ContextBefore, "package testpkg")
+
+	// Unknown strategies fall back to per-symbol chunks
+	chunks, err = NewWithStrategy("bogus").ChunkFile(testFile, parseResult, 1)
+	require.NoError(t, err)
+	assert.Len(t, chunks, 2)
+}
+
 func TestChunkFile_EmptyFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	testFile := filepath.Join(tmpDir, "empty.go")
@@ -264,6 +296,7 @@ type Reader interface {
 
 	require.NotNil(t, interfaceChunk)
 	assert.Contains(t, interfaceChunk.Content, "Reader interface")
+	assert.Len(t, chunks, 1, "interface methods share the interface chunk")
 }
 
 func TestChunkFile_NonExistentFile(t *testing.T) {
@@ -467,7 +500,8 @@ func TestContextAfterField(t *testing.T) {
 
 // UserRepository handles user data persistence
 type UserRepository struct {
-	db Database
+	db    Database
+	cache map[int]*User
 }
 
 // FindByID retrieves a user by ID
@@ -529,6 +563,9 @@ func (r *UserRepository) Save(user *User) error {
 	// Test ExtractRelatedContext for method (finds receiver)
 	relatedContext = c.ExtractRelatedContext(methodSym, parseResult.Symbols)
 	assert.Contains(t, relatedContext, "UserRepository", "Related context should include receiver struct")
+	for _, line := range strings.Split(relatedContext, "\n") {
+		assert.True(t, strings.HasPrefix(line, "//"), "multi-line receiver signature should stay commented: %q", line)
+	}
 
 	// Document current behavior:
 	// - ContextAfter field exists in types.Chunk
@@ -0,0 +1,470 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames lists accepted configuration file names, in lookup order
+var FileNames = []string{".gocontext.yaml", ".gocontext.yml"}
+
+// Chunk strategies
+const (
+	ChunkStrategySymbol = "symbol" // One chunk per top-level symbol (default)
+	ChunkStrategyFile   = "file"   // One chunk per file
+)
+
+// Dependency indexing modes
+const (
+	DependenciesNone   = "none"   // Index only the project (default)
+	DependenciesDirect = "direct" // Also index direct requirements from the module cache
+	DependenciesAll    = "all"    // Also index indirect requirements
+)
+
+// ErrInvalidConfig is returned when a configuration file contains invalid values
+var ErrInvalidConfig = errors.New("invalid project configuration")
+
+// ProjectConfig holds per-project settings
+type ProjectConfig struct {
+	Index        IndexConfig        `yaml:"index"`
+	Embeddings   EmbeddingsConfig   `yaml:"embeddings"`
+	Search       SearchConfig       `yaml:"search"`
+	DDD          DDDConfig          `yaml:"ddd"`
+	Architecture ArchitectureConfig `yaml:"architecture"`
+
+	// Path is the file the configuration was loaded from, empty if none was found
+	Path string `yaml:"-"`
+}
+
+// IndexConfig controls which files are indexed and how they are chunked
+type IndexConfig struct {
+	Include       []string `yaml:"include"`
+	Exclude       []string `yaml:"exclude"`
+	IncludeTests  *bool    `yaml:"include_tests"`
+	IncludeVendor *bool    `yaml:"include_vendor"`
+	MaxFileSize   ByteSize `yaml:"max_file_size"`
+	ChunkStrategy string   `yaml:"chunk_strategy"`
+
+	// RespectGitIgnore applies .gitignore, .git/info/exclude and global excludes
+	// (default: true). .gocontextignore files are always honored.
+	RespectGitIgnore *bool `yaml:"respect_gitignore"`
+
+	// GitHistory records per-chunk last commit, author, date and change count
+	// from the git repository (default: false); HistoryDepth bounds the number of
+	// first-parent commits followed (default: 1000)
+	GitHistory   *bool `yaml:"git_history"`
+	HistoryDepth int   `yaml:"history_depth"`
+
+	// Dependencies indexes the exported API of required modules found in the
+	// local module cache or vendor directory: none, direct or all
+	Dependencies string `yaml:"dependencies"`
+
+	// Stdlib indexes the standard library of the project's Go version from the
+	// local GOROOT, once per release, shared by all projects (default: false)
+	Stdlib *bool `yaml:"stdlib"`
+}
+
+// EmbeddingsConfig controls embedding generation
+type EmbeddingsConfig struct {
+	Enabled *bool    `yaml:"enabled"`
+	Exclude []string `yaml:"exclude"` // Paths indexed without embeddings
+}
+
+// SearchConfig holds default search_code options
+type SearchConfig struct {
+	Limit        int     `yaml:"limit"`
+	Mode         string  `yaml:"mode"`
+	Rerank       string  `yaml:"rerank"`
+	SnippetMode  string  `yaml:"snippet_mode"`
+	Highlight    *bool   `yaml:"highlight"`
+	MinRelevance float64 `yaml:"min_relevance"`
+}
+
+// DDDConfig controls domain-driven design pattern detection
+type DDDConfig struct {
+	// Builtin keeps the naming conventions ("*Repository", "*Aggregate", ...)
+	// alongside Rules (default: true)
+	Builtin *bool     `yaml:"builtin"`
+	Rules   []DDDRule `yaml:"rules"`
+}
+
+// DDDRule marks the types meeting every criterion it sets with a pattern
+type DDDRule struct {
+	Pattern string `yaml:"pattern"` // aggregate, entity, value_object, repository, service, command, query or handler
+	Kind    string `yaml:"kind"`    // struct, interface or type; any kind when empty
+
+	Name       string   `yaml:"name"`       // Regular expression matched against the type name
+	Package    string   `yaml:"package"`    // Glob matched against the package directory or import path
+	Embeds     []string `yaml:"embeds"`     // Embedded types, "Base" or qualified "domain.Base"
+	Implements []string `yaml:"implements"` // Interfaces whose methods the type declares, "Entity" or "domain.Entity"
+	Fields     []string `yaml:"fields"`     // Field names the struct declares
+	Methods    []string `yaml:"methods"`    // Method names the type declares
+
+	Confidence float64 `yaml:"confidence"` // 0-1 (default: 0.8)
+	Reason     string  `yaml:"reason"`     // Replaces the generated list of matched criteria
+}
+
+// ArchitectureConfig declares package layers and the import rules between them
+type ArchitectureConfig struct {
+	Layers []Layer    `yaml:"layers"`
+	Rules  []ArchRule `yaml:"rules"`
+}
+
+// Layer names a group of packages that rules can refer to
+type Layer struct {
+	Name     string   `yaml:"name"`
+	Packages []string `yaml:"packages"` // Globs matched against package directories or import paths
+}
+
+// ArchRule constrains imports between packages. Packages are referenced by layer
+// name or by a glob matched against the package directory or import path.
+type ArchRule struct {
+	Name string `yaml:"name"` // Reported with violations; described from the rule when empty
+
+	// From packages must not import Deny and, when Allow is set, may import no
+	// other project packages than Allow
+	From  string   `yaml:"from"`
+	Deny  []string `yaml:"deny"`
+	Allow []string `yaml:"allow"`
+
+	// To packages may only be imported by OnlyFrom
+	To       string   `yaml:"to"`
+	OnlyFrom []string `yaml:"only_from"`
+
+	Reason string `yaml:"reason"` // Why the rule exists, reported with violations
+}
+
+// ByteSize is a size in bytes that accepts KB/MB/GB suffixes in YAML
+type ByteSize int64
+
+// UnmarshalYAML parses sizes such as 1048576, "512KB" or "1MB"
+func (b *ByteSize) UnmarshalYAML(node *yaml.Node) error {
+	size, err := ParseByteSize(node.Value)
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+// ParseByteSize parses a byte count with an optional B, KB, MB or GB suffix (base 1024)
+func ParseByteSize(s string) (ByteSize, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		factor int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.factor
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return ByteSize(n * multiplier), nil
+}
+
+// Default returns an empty configuration; every setting falls back to built-in defaults
+func Default() *ProjectConfig {
+	return &ProjectConfig{}
+}
+
+// Load reads the configuration file from rootPath.
+// A missing file is not an error and yields Default().
+func Load(rootPath string) (*ProjectConfig, error) {
+	for _, name := range FileNames {
+		path := filepath.Join(rootPath, name)
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+
+		cfg, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		cfg.Path = path
+		return cfg, nil
+	}
+	return Default(), nil
+}
+
+// Parse decodes and validates configuration file contents
+func Parse(data []byte) (*ProjectConfig, error) {
+	cfg := Default()
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks enumerated values and glob syntax
+func (c *ProjectConfig) Validate() error {
+	switch c.Index.ChunkStrategy {
+	case "", ChunkStrategySymbol, ChunkStrategyFile:
+	default:
+		return fmt.Errorf("%w: index.chunk_strategy must be %q or %q, got %q",
+			ErrInvalidConfig, ChunkStrategySymbol, ChunkStrategyFile, c.Index.ChunkStrategy)
+	}
+
+	switch c.Index.Dependencies {
+	case "", DependenciesNone, DependenciesDirect, DependenciesAll:
+	default:
+		return fmt.Errorf("%w: index.dependencies must be none, direct or all, got %q", ErrInvalidConfig, c.Index.Dependencies)
+	}
+
+	switch c.Search.Mode {
+	case "", "hybrid", "vector", "keyword":
+	default:
+		return fmt.Errorf("%w: search.mode must be hybrid, vector or keyword, got %q", ErrInvalidConfig, c.Search.Mode)
+	}
+
+	switch c.Search.Rerank {
+	case "", "none", "lexical", "recency", "cross_encoder":
+	default:
+		return fmt.Errorf("%w: search.rerank must be none, lexical, recency or cross_encoder, got %q", ErrInvalidConfig, c.Search.Rerank)
+	}
+
+	switch c.Search.SnippetMode {
+	case "", "full", "compact":
+	default:
+		return fmt.Errorf("%w: search.snippet_mode must be full or compact, got %q", ErrInvalidConfig, c.Search.SnippetMode)
+	}
+
+	if c.Search.Limit < 0 || c.Search.Limit > 100 {
+		return fmt.Errorf("%w: search.limit must be between 1 and 100, got %d", ErrInvalidConfig, c.Search.Limit)
+	}
+	if c.Index.HistoryDepth < 0 {
+		return fmt.Errorf("%w: index.history_depth must not be negative, got %d", ErrInvalidConfig, c.Index.HistoryDepth)
+	}
+	if c.Search.MinRelevance < 0 || c.Search.MinRelevance > 1 {
+		return fmt.Errorf("%w: search.min_relevance must be between 0.0 and 1.0, got %f", ErrInvalidConfig, c.Search.MinRelevance)
+	}
+
+	for i, rule := range c.DDD.Rules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("%w: ddd.rules[%d]: %v", ErrInvalidConfig, i, err)
+		}
+	}
+
+	if err := c.Architecture.validate(); err != nil {
+		return fmt.Errorf("%w: architecture.%v", ErrInvalidConfig, err)
+	}
+
+	for _, group := range [][]string{c.Index.Include, c.Index.Exclude, c.Embeddings.Exclude} {
+		for _, pattern := range group {
+			if _, err := compileGlob(pattern); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks a DDD rule's pattern, kind, name expression and package glob
+func (r DDDRule) validate() error {
+	switch r.Pattern {
+	case "aggregate", "entity", "value_object", "repository", "service", "command", "query", "handler":
+	default:
+		return fmt.Errorf("pattern must be aggregate, entity, value_object, repository, service, command, query or handler, got %q", r.Pattern)
+	}
+
+	switch r.Kind {
+	case "", "struct", "interface", "type":
+	default:
+		return fmt.Errorf("kind must be struct, interface or type, got %q", r.Kind)
+	}
+
+	if r.Name == "" && r.Package == "" && len(r.Embeds) == 0 && len(r.Implements) == 0 &&
+		len(r.Fields) == 0 && len(r.Methods) == 0 {
+		return errors.New("rule has no criteria")
+	}
+	if _, err := regexp.Compile(r.Name); err != nil {
+		return fmt.Errorf("name: %v", err)
+	}
+	if r.Package != "" {
+		if _, err := compileGlob(r.Package); err != nil {
+			return fmt.Errorf("package: %v", err)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be between 0.0 and 1.0, got %f", r.Confidence)
+	}
+	return nil
+}
+
+// validate checks layer names and globs and that every rule is complete
+func (c ArchitectureConfig) validate() error {
+	layers := make(map[string]bool, len(c.Layers))
+	for i, layer := range c.Layers {
+		switch {
+		case layer.Name == "":
+			return fmt.Errorf("layers[%d]: name is required", i)
+		case layers[layer.Name]:
+			return fmt.Errorf("layers[%d]: duplicate layer %q", i, layer.Name)
+		case len(layer.Packages) == 0:
+			return fmt.Errorf("layers[%d]: packages is required", i)
+		}
+		for _, pattern := range layer.Packages {
+			if _, err := compileGlob(pattern); err != nil {
+				return fmt.Errorf("layers[%d]: %v", i, err)
+			}
+		}
+		layers[layer.Name] = true
+	}
+
+	for i, rule := range c.Rules {
+		if err := rule.validate(layers); err != nil {
+			return fmt.Errorf("rules[%d]: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// validate checks that a rule has one form, from with deny or allow, or to with
+// only_from, and that its references are layers or valid globs
+func (r ArchRule) validate(layers map[string]bool) error {
+	switch {
+	case r.From != "" && r.To != "":
+		return errors.New("rule sets both from and to")
+	case r.From != "":
+		if len(r.Deny) == 0 && len(r.Allow) == 0 {
+			return errors.New("from requires deny or allow")
+		}
+		if len(r.OnlyFrom) > 0 {
+			return errors.New("only_from requires to instead of from")
+		}
+	case r.To != "":
+		if len(r.OnlyFrom) == 0 {
+			return errors.New("to requires only_from")
+		}
+		if len(r.Deny) > 0 || len(r.Allow) > 0 {
+			return errors.New("deny and allow require from instead of to")
+		}
+	default:
+		return errors.New("rule requires from or to")
+	}
+
+	refs := append([]string{r.From, r.To}, r.Deny...)
+	refs = append(refs, r.Allow...)
+	refs = append(refs, r.OnlyFrom...)
+	for _, ref := range refs {
+		if ref == "" || layers[ref] {
+			continue
+		}
+		// With layers declared, a bare word is more likely a misspelled layer than a glob
+		if len(layers) > 0 && !strings.ContainsAny(ref, "/*?[.") {
+			return fmt.Errorf("unknown layer %q", ref)
+		}
+		if _, err := compileGlob(ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseBuiltin returns the builtin setting, defaulting to true
+func (c DDDConfig) UseBuiltin() bool {
+	return c.Builtin == nil || *c.Builtin
+}
+
+// ShouldIndex reports whether a file (slash-separated path relative to the project root)
+// passes the include and exclude globs
+func (c *ProjectConfig) ShouldIndex(relPath string) bool {
+	if len(c.Index.Include) > 0 && !MatchAny(c.Index.Include, relPath) {
+		return false
+	}
+	return !MatchAny(c.Index.Exclude, relPath)
+}
+
+// ExcludesDir reports whether an exclude glob covers every file under dir,
+// so discovery can skip walking it
+func (c *ProjectConfig) ExcludesDir(relDir string) bool {
+	for _, pattern := range c.Index.Exclude {
+		if strings.HasSuffix(pattern, "/**") && Match(strings.TrimSuffix(pattern, "/**"), relDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// EmbeddingsEnabled reports whether chunks of a file should be embedded
+func (c *ProjectConfig) EmbeddingsEnabled(relPath string) bool {
+	if c.Embeddings.Enabled != nil && !*c.Embeddings.Enabled {
+		return false
+	}
+	return !MatchAny(c.Embeddings.Exclude, relPath)
+}
+
+// IncludeTests returns the include_tests setting, or def when unset
+func (c *ProjectConfig) IncludeTests(def bool) bool {
+	if c.Index.IncludeTests == nil {
+		return def
+	}
+	return *c.Index.IncludeTests
+}
+
+// UseGitIgnore returns the respect_gitignore setting, defaulting to true
+func (c *ProjectConfig) UseGitIgnore() bool {
+	return c.Index.RespectGitIgnore == nil || *c.Index.RespectGitIgnore
+}
+
+// IncludeVendor returns the include_vendor setting, or def when unset
+func (c *ProjectConfig) IncludeVendor(def bool) bool {
+	if c.Index.IncludeVendor == nil {
+		return def
+	}
+	return *c.Index.IncludeVendor
+}
+
+// GitHistory returns the git_history setting, or def when unset
+func (c *ProjectConfig) GitHistory(def bool) bool {
+	if c.Index.GitHistory == nil {
+		return def
+	}
+	return *c.Index.GitHistory
+}
+
+// DependencyMode returns the dependencies setting, or def when unset
+func (c *ProjectConfig) DependencyMode(def string) string {
+	if c.Index.Dependencies == "" {
+		return def
+	}
+	return c.Index.Dependencies
+}
+
+// IndexStdlib returns the stdlib setting, or def when unset
+func (c *ProjectConfig) IndexStdlib(def bool) bool {
+	if c.Index.Stdlib == nil {
+		return def
+	}
+	return *c.Index.Stdlib
+}
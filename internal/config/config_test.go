@@ -0,0 +1,238 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_MissingFile(t *testing.T) {
+	cfg, err := Load(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Path)
+	assert.True(t, cfg.ShouldIndex("main.go"))
+	assert.True(t, cfg.EmbeddingsEnabled("main.go"))
+	assert.True(t, cfg.IncludeTests(true))
+	assert.False(t, cfg.IncludeVendor(false))
+	assert.False(t, cfg.GitHistory(false))
+	assert.Equal(t, DependenciesNone, cfg.DependencyMode(DependenciesNone))
+	assert.False(t, cfg.IndexStdlib(false))
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	data := `index:
+  include: ["**/*.go"]
+  exclude: ["internal/gen/**", "*.pb.go"]
+  include_tests: false
+  max_file_size: 512KB
+  chunk_strategy: file
+  git_history: true
+  history_depth: 200
+  dependencies: direct
+  stdlib: true
+embeddings:
+  exclude: ["testdata/**"]
+search:
+  limit: 25
+  mode: keyword
+  rerank: lexical
+  snippet_mode: compact
+  min_relevance: 0.3
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gocontext.yml"), []byte(data), 0644))
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, ".gocontext.yml"), cfg.Path)
+	assert.False(t, cfg.IncludeTests(true))
+	assert.Equal(t, ByteSize(512*1024), cfg.Index.MaxFileSize)
+	assert.Equal(t, ChunkStrategyFile, cfg.Index.ChunkStrategy)
+	assert.True(t, cfg.GitHistory(false))
+	assert.Equal(t, 200, cfg.Index.HistoryDepth)
+	assert.Equal(t, DependenciesDirect, cfg.DependencyMode(DependenciesNone))
+	assert.True(t, cfg.IndexStdlib(false))
+	assert.Equal(t, 25, cfg.Search.Limit)
+	assert.Equal(t, "keyword", cfg.Search.Mode)
+	assert.Equal(t, "lexical", cfg.Search.Rerank)
+	assert.Equal(t, "compact", cfg.Search.SnippetMode)
+	assert.InDelta(t, 0.3, cfg.Search.MinRelevance, 1e-9)
+
+	assert.True(t, cfg.ShouldIndex("cmd/main.go"))
+	assert.False(t, cfg.ShouldIndex("internal/gen/api.go"))
+	assert.False(t, cfg.ShouldIndex("api/v1/service.pb.go"))
+	assert.False(t, cfg.ShouldIndex("README.md"))
+	assert.True(t, cfg.ExcludesDir("internal/gen"))
+	assert.False(t, cfg.ExcludesDir("internal"))
+
+	assert.True(t, cfg.EmbeddingsEnabled("main.go"))
+	assert.False(t, cfg.EmbeddingsEnabled("testdata/fixture.go"))
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"unknown key", "index:\n  exclud: [\"x\"]\n"},
+		{"bad chunk strategy", "index:\n  chunk_strategy: line\n"},
+		{"bad mode", "search:\n  mode: fuzzy\n"},
+		{"bad rerank", "search:\n  rerank: magic\n"},
+		{"bad limit", "search:\n  limit: 500\n"},
+		{"negative history depth", "index:\n  history_depth: -1\n"},
+		{"bad dependencies", "index:\n  dependencies: everything\n"},
+		{"bad size", "index:\n  max_file_size: huge\n"},
+		{"bad glob", "index:\n  exclude: [\"internal/[gen\"]\n"},
+		{"bad ddd pattern", "ddd:\n  rules:\n    - pattern: factory\n      name: Factory$\n"},
+		{"bad ddd kind", "ddd:\n  rules:\n    - pattern: entity\n      kind: func\n      name: X\n"},
+		{"bad ddd name", "ddd:\n  rules:\n    - pattern: entity\n      name: \"(\"\n"},
+		{"bad ddd package", "ddd:\n  rules:\n    - pattern: entity\n      package: \"domain/[x\"\n"},
+		{"bad ddd confidence", "ddd:\n  rules:\n    - pattern: entity\n      name: X\n      confidence: 2\n"},
+		{"ddd rule without criteria", "ddd:\n  rules:\n    - pattern: entity\n"},
+		{"unnamed layer", "architecture:\n  layers:\n    - packages: [domain/**]\n"},
+		{"duplicate layer", "architecture:\n  layers:\n    - {name: domain, packages: [a/**]}\n    - {name: domain, packages: [b/**]}\n"},
+		{"layer without packages", "architecture:\n  layers:\n    - name: domain\n"},
+		{"arch rule without target", "architecture:\n  rules:\n    - from: internal/domain/**\n"},
+		{"arch rule from and to", "architecture:\n  rules:\n    - {from: a/**, to: b/**, deny: [c/**]}\n"},
+		{"arch rule to with deny", "architecture:\n  rules:\n    - {to: b/**, deny: [c/**]}\n"},
+		{"arch unknown layer", "architecture:\n  layers:\n    - {name: domain, packages: [internal/domain/**]}\n  rules:\n    - {from: domain, deny: [infrastucture]}\n"},
+		{"arch bad glob", "architecture:\n  rules:\n    - {from: \"a/[x\", deny: [b/**]}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	cfg, err := Parse(nil)
+	require.NoError(t, err)
+	assert.True(t, cfg.ShouldIndex("main.go"))
+}
+
+func TestParse_DDDRules(t *testing.T) {
+	cfg, err := Parse([]byte(`ddd:
+  builtin: false
+  rules:
+    - pattern: aggregate
+      kind: struct
+      package: internal/domain/**
+      embeds: [domain.AggregateRoot]
+      implements: [Versioned]
+      fields: [ID]
+      methods: [Apply]
+      confidence: 0.9
+      reason: aggregate base type
+`))
+	require.NoError(t, err)
+	assert.False(t, cfg.DDD.UseBuiltin())
+	require.Len(t, cfg.DDD.Rules, 1)
+	assert.Equal(t, DDDRule{
+		Pattern:    "aggregate",
+		Kind:       "struct",
+		Package:    "internal/domain/**",
+		Embeds:     []string{"domain.AggregateRoot"},
+		Implements: []string{"Versioned"},
+		Fields:     []string{"ID"},
+		Methods:    []string{"Apply"},
+		Confidence: 0.9,
+		Reason:     "aggregate base type",
+	}, cfg.DDD.Rules[0])
+
+	assert.True(t, Default().DDD.UseBuiltin())
+}
+
+func TestParse_Architecture(t *testing.T) {
+	cfg, err := Parse([]byte(`architecture:
+  layers:
+    - name: domain
+      packages: [internal/domain/**]
+    - name: infrastructure
+      packages: [internal/infra/**, internal/storage/**]
+  rules:
+    - from: domain
+      deny: [infrastructure, database/sql]
+      reason: the domain stays persistence-agnostic
+    - name: storage access
+      to: internal/storage/**
+      only_from: [internal/indexer/**, internal/mcp/**]
+`))
+	require.NoError(t, err)
+	require.Len(t, cfg.Architecture.Layers, 2)
+	assert.Equal(t, []string{"internal/infra/**", "internal/storage/**"}, cfg.Architecture.Layers[1].Packages)
+	assert.Equal(t, []ArchRule{
+		{From: "domain", Deny: []string{"infrastructure", "database/sql"}, Reason: "the domain stays persistence-agnostic"},
+		{Name: "storage access", To: "internal/storage/**", OnlyFrom: []string{"internal/indexer/**", "internal/mcp/**"}},
+	}, cfg.Architecture.Rules)
+}
+
+func TestEmbeddingsDisabled(t *testing.T) {
+	cfg, err := Parse([]byte("embeddings:\n  enabled: false\n"))
+	require.NoError(t, err)
+	assert.False(t, cfg.EmbeddingsEnabled("main.go"))
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "a/b/main.go", true},
+		{"*_test.go", "pkg/x_test.go", true},
+		{"internal/**", "internal/a/b.go", true},
+		{"internal/**", "cmd/main.go", false},
+		{"internal/*.go", "internal/a/b.go", false},
+		{"internal/*.go", "internal/b.go", true},
+		{"**/gen/*.go", "gen/a.go", true},
+		{"**/gen/*.go", "x/y/gen/a.go", true},
+		{"pkg/?.go", "pkg/a.go", true},
+		{"pkg/[ab].go", "pkg/c.go", false},
+		{"./cmd/**", "cmd/tool/main.go", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"_"+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, Match(tt.pattern, tt.path))
+		})
+	}
+}
+
+func TestMatchPackage(t *testing.T) {
+	assert.True(t, MatchPackage("internal/domain/**", "internal/domain"))
+	assert.True(t, MatchPackage("internal/domain/**", "internal/domain/order"))
+	assert.True(t, MatchPackage("example.com/shop/internal/domain/**", "example.com/shop/internal/domain"))
+	assert.True(t, MatchPackage("domain", "internal/domain"))
+	assert.False(t, MatchPackage("internal/domain/**", "internal/domainx"))
+	assert.False(t, MatchPackage("internal/domain/**", ""))
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ByteSize
+	}{
+		{"1024", 1024},
+		{"10B", 10},
+		{"2KB", 2048},
+		{"1mb", 1 << 20},
+		{"1 GB", 1 << 30},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, err := ParseByteSize("-1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+// Package config loads per-project settings from a .gocontext.yaml file at the
+// project root, so every team member indexes and searches a codebase the same way.
+//
+// # File Format
+//
+//	# .gocontext.yaml
+//	index:
+//	  include: ["**/*.go"]                    # default: all Go files
+//	  exclude: ["internal/gen/**", "**/*.pb.go"]
+//	  include_tests: true
+//	  include_vendor: false
+//	  max_file_size: 512KB                    # bytes, or KB/MB suffix
+//	  chunk_strategy: symbol                  # symbol (default) or file
+//	  respect_gitignore: true                 # honor .gitignore files (default)
+//	embeddings:
+//	  enabled: true
+//	  exclude: ["testdata/**"]                # indexed for keyword search only
+//	search:
+//	  limit: 20
+//	  mode: hybrid
+//	  rerank: lexical
+//	  snippet_mode: compact
+//	  min_relevance: 0.2
+//
+// All settings are optional. Tool arguments override values from the file,
+// and values missing from both fall back to the built-in defaults.
+//
+// # Globs
+//
+// Patterns are matched against slash-separated paths relative to the project
+// root. "*" matches within a path segment, "**" matches any number of
+// segments, and a pattern without "/" matches the base name at any depth.
+//
+// # Basic Usage
+//
+//	cfg, err := config.Load("/path/to/project")
+//	if err != nil {
+//	    return err // malformed file
+//	}
+//	if cfg.ShouldIndex("internal/gen/api.pb.go") { ... }
+package config
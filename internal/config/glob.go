@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// globCache memoizes compiled patterns; configs are matched against every discovered file
+var globCache sync.Map // map[string]*regexp.Regexp
+
+// Match reports whether a slash-separated relative path matches a glob pattern.
+// Invalid patterns never match (Load rejects them up front).
+func Match(pattern, relPath string) bool {
+	re, err := compileGlob(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(path.Clean(strings.TrimPrefix(relPath, "./")))
+}
+
+// MatchAny reports whether relPath matches at least one pattern
+func MatchAny(patterns []string, relPath string) bool {
+	for _, p := range patterns {
+		if Match(p, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPackage reports whether a package directory or import path matches a
+// glob; "domain/**" also matches the domain package itself
+func MatchPackage(pattern, pkg string) bool {
+	if pkg == "" {
+		return false
+	}
+	return Match(pattern, pkg) ||
+		strings.HasSuffix(pattern, "/**") && Match(strings.TrimSuffix(pattern, "/**"), pkg)
+}
+
+// compileGlob converts a glob with "**" support into an anchored regular expression.
+// Patterns without a "/" match the base name at any depth, like .gitignore.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := globCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	p := strings.TrimPrefix(strings.TrimSpace(pattern), "./")
+	if p == "" {
+		return nil, fmt.Errorf("empty glob pattern")
+	}
+	if !strings.Contains(p, "/") {
+		p = "**/" + p
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch c {
+		case '*':
+			if i+1 < len(p) && p[i+1] == '*' {
+				i++
+				if i+1 < len(p) && p[i+1] == '/' {
+					// "**/" matches zero or more leading directories
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(p[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class in glob %q", pattern)
+			}
+			class := p[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+	}
+	globCache.Store(pattern, re)
+	return re, nil
+}
@@ -0,0 +1,387 @@
+package cqrs
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// Relation kinds
+const (
+	RelationHandledBy      = "handled_by"      // Command or query to its handler
+	RelationUsesRepository = "uses_repository" // Handler to a repository it holds or takes
+	RelationUsesAggregate  = "uses_aggregate"  // Handler to an aggregate it loads, builds or returns
+	RelationPersists       = "persists"        // Repository to the aggregates and entities it stores
+)
+
+// Symbol is a declaration to link
+type Symbol struct {
+	ID       int64
+	Name     string
+	Kind     types.SymbolKind
+	Receiver string // Type of a method or field
+	Package  string // Import path, or the directory when the file has none
+
+	Command    bool
+	Query      bool
+	Handler    bool
+	Repository bool
+	Aggregate  bool
+	Entity     bool
+
+	Refs []types.Ref
+}
+
+// Relation links two symbols
+type Relation struct {
+	From   int64
+	To     int64
+	Kind   string
+	Reason string
+}
+
+// key identifies a declaration by package and name
+type key struct {
+	pkg  string
+	name string
+}
+
+// linker resolves references between the declarations of a project
+type linker struct {
+	types     map[key]*Symbol
+	byName    map[string][]*Symbol // Types by name, across packages
+	functions map[key]*Symbol
+	methods   map[key][]*Symbol // By receiver type
+	fields    map[key][]*Symbol // By struct type
+	persisted map[int64]bool    // Types a repository persists
+
+	relations map[relationKey]*relation
+}
+
+type relationKey struct {
+	from, to int64
+	kind     string
+}
+
+// relation accumulates the reasons for a relation in the order found
+type relation struct {
+	Relation
+	reasons []string
+}
+
+// Link infers the relations between commands, queries, handlers, repositories
+// and aggregates, ordered by source, kind and target
+func Link(symbols []*Symbol) []Relation {
+	l := &linker{
+		types:     make(map[key]*Symbol),
+		byName:    make(map[string][]*Symbol),
+		functions: make(map[key]*Symbol),
+		methods:   make(map[key][]*Symbol),
+		fields:    make(map[key][]*Symbol),
+		persisted: make(map[int64]bool),
+		relations: make(map[relationKey]*relation),
+	}
+	for _, sym := range symbols {
+		switch sym.Kind {
+		case types.KindStruct, types.KindInterface, types.KindType:
+			l.types[key{sym.Package, sym.Name}] = sym
+			l.byName[sym.Name] = append(l.byName[sym.Name], sym)
+		case types.KindFunction:
+			l.functions[key{sym.Package, sym.Name}] = sym
+		case types.KindMethod:
+			k := key{sym.Package, sym.Receiver}
+			l.methods[k] = append(l.methods[k], sym)
+		case types.KindField:
+			k := key{sym.Package, sym.Receiver}
+			l.fields[k] = append(l.fields[k], sym)
+		}
+	}
+
+	handlers := l.linkHandlers(symbols)
+	for _, sym := range symbols {
+		if sym.Repository && isType(sym) {
+			l.linkPersisted(sym)
+		}
+	}
+	for _, sym := range symbols {
+		if handlers[sym.ID] || sym.Handler && isType(sym) {
+			l.linkHandler(sym)
+		}
+	}
+
+	relations := make([]Relation, 0, len(l.relations))
+	for _, r := range l.relations {
+		r.Reason = strings.Join(r.reasons, "; ")
+		relations = append(relations, r.Relation)
+	}
+	sort.Slice(relations, func(i, j int) bool {
+		a, b := relations[i], relations[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.To < b.To
+	})
+	return relations
+}
+
+// isType reports whether sym declares a type
+func isType(sym *Symbol) bool {
+	return sym.Kind == types.KindStruct || sym.Kind == types.KindInterface || sym.Kind == types.KindType
+}
+
+// add records a relation, merging its reason with earlier evidence
+func (l *linker) add(from, to *Symbol, kind, reason string) {
+	if from.ID == to.ID {
+		return
+	}
+	k := relationKey{from.ID, to.ID, kind}
+	r, ok := l.relations[k]
+	if !ok {
+		r = &relation{Relation: Relation{From: from.ID, To: to.ID, Kind: kind}}
+		l.relations[k] = r
+	}
+	for _, existing := range r.reasons {
+		if existing == reason {
+			return
+		}
+	}
+	r.reasons = append(r.reasons, reason)
+}
+
+// resolve returns the type a reference from sym names, nil when it is not
+// declared in the project
+func (l *linker) resolve(sym *Symbol, ref types.Ref) *Symbol {
+	if ref.Kind == types.RefSelect {
+		return nil // Fields and methods, not types
+	}
+	pkg := ref.Package
+	if pkg == "" {
+		pkg = sym.Package
+	}
+	return l.types[key{pkg, ref.Name}]
+}
+
+// linkHandlers links each command and query to its handlers and returns the
+// IDs of the handlers found
+func (l *linker) linkHandlers(symbols []*Symbol) map[int64]bool {
+	handlers := make(map[int64]bool)
+	link := func(message, handler *Symbol, reason string) {
+		l.add(message, handler, RelationHandledBy, reason)
+		handlers[handler.ID] = true
+	}
+
+	// A method or function taking the message
+	for _, sym := range symbols {
+		if sym.Kind != types.KindMethod && sym.Kind != types.KindFunction {
+			continue
+		}
+		for _, ref := range sym.Refs {
+			if ref.Kind != types.RefParam {
+				continue
+			}
+			message := l.resolve(sym, ref)
+			if message == nil || !message.Command && !message.Query {
+				continue
+			}
+			if sym.Kind == types.KindFunction {
+				if hasPrefix(sym.Name, "Handle", "handle", "Execute", "execute") {
+					link(message, sym, sym.Name+" takes "+message.Name)
+				}
+				continue
+			}
+			receiver := l.types[key{sym.Package, sym.Receiver}]
+			if receiver == nil || receiver.Kind == types.KindInterface {
+				continue
+			}
+			if receiver.Handler || hasPrefix(sym.Name, "Handle", "Execute", "Process") {
+				link(message, receiver, receiver.Name+"."+sym.Name+" takes "+message.Name)
+			}
+		}
+	}
+
+	// A type named after the message
+	for _, message := range symbols {
+		if !isType(message) || !message.Command && !message.Query {
+			continue
+		}
+		base := trimSuffix(message.Name, "Command", "Cmd", "Query")
+		for _, name := range []string{base + "Handler", message.Name + "Handler"} {
+			if handler := l.named(name, message.Package); handler != nil && handler.Kind != types.KindInterface {
+				link(message, handler, "named after "+message.Name)
+				break
+			}
+		}
+	}
+	return handlers
+}
+
+// named returns the type with the given name, preferring package pkg; a name
+// declared in several other packages is ambiguous
+func (l *linker) named(name, pkg string) *Symbol {
+	if sym := l.types[key{pkg, name}]; sym != nil {
+		return sym
+	}
+	if candidates := l.byName[name]; len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// persisted reports whether a repository stores t: an aggregate or entity, or
+// the type the repository is named after
+func persisted(repository, t *Symbol) bool {
+	if t.Aggregate || t.Entity {
+		return true
+	}
+	return t.Kind == types.KindStruct && trimSuffix(repository.Name, "Repository", "Repo") == t.Name
+}
+
+// linkPersisted links a repository to the types its methods take and return
+func (l *linker) linkPersisted(repository *Symbol) {
+	for _, method := range l.methods[key{repository.Package, repository.Name}] {
+		for _, ref := range method.Refs {
+			if ref.Kind != types.RefParam && ref.Kind != types.RefResult {
+				continue
+			}
+			if t := l.resolve(method, ref); t != nil && t.ID != repository.ID && persisted(repository, t) {
+				l.add(repository, t, RelationPersists, method.Name+verb(ref.Kind)+t.Name)
+				l.persisted[t.ID] = true
+			}
+		}
+	}
+}
+
+// aggregate reports whether t is an aggregate, or persisted by a repository
+// without being flagged as one
+func (l *linker) aggregate(t *Symbol) bool {
+	return t.Aggregate || l.persisted[t.ID]
+}
+
+// aggregateResults returns the aggregates a repository method or function
+// returns
+func (l *linker) aggregateResults(fn *Symbol) []*Symbol {
+	var aggregates []*Symbol
+	for _, ref := range fn.Refs {
+		if ref.Kind != types.RefResult {
+			continue
+		}
+		if t := l.resolve(fn, ref); t != nil && l.aggregate(t) {
+			aggregates = append(aggregates, t)
+		}
+	}
+	return aggregates
+}
+
+// linkHandler links a handler type or function to the repositories it holds or
+// takes and the aggregates it works with
+func (l *linker) linkHandler(handler *Symbol) {
+	// Repositories held in fields, by field name
+	repositories := make(map[string]*Symbol)
+	for _, field := range l.fields[key{handler.Package, handler.Name}] {
+		for _, ref := range field.Refs {
+			if repository := l.resolve(field, ref); repository != nil && repository.Repository {
+				repositories[field.Name] = repository
+				l.add(handler, repository, RelationUsesRepository, "field "+field.Name)
+			}
+		}
+	}
+
+	bodies := []*Symbol{handler}
+	if isType(handler) {
+		bodies = l.methods[key{handler.Package, handler.Name}]
+	}
+	for _, body := range bodies {
+		calls := make(map[*Symbol][]string) // Methods called on each repository
+		for _, ref := range body.Refs {
+			switch ref.Kind {
+			case types.RefMethod:
+				repository := repositories[ref.Via]
+				if repository == nil {
+					continue
+				}
+				calls[repository] = append(calls[repository], ref.Name)
+				for _, method := range l.methods[key{repository.Package, repository.Name}] {
+					if method.Name != ref.Name {
+						continue
+					}
+					for _, aggregate := range l.aggregateResults(method) {
+						l.add(handler, aggregate, RelationUsesAggregate, "through "+ref.Via+"."+ref.Name)
+					}
+				}
+			case types.RefCall:
+				pkg := ref.Package
+				if pkg == "" {
+					pkg = body.Package
+				}
+				if fn := l.functions[key{pkg, ref.Name}]; fn != nil {
+					for _, aggregate := range l.aggregateResults(fn) {
+						l.add(handler, aggregate, RelationUsesAggregate, "through "+ref.Name)
+					}
+				}
+			default:
+				t := l.resolve(body, ref)
+				if t == nil {
+					continue
+				}
+				if t.Repository && ref.Kind == types.RefParam {
+					l.add(handler, t, RelationUsesRepository, body.Name+verb(ref.Kind)+t.Name)
+				}
+				if l.aggregate(t) {
+					l.add(handler, t, RelationUsesAggregate, body.Name+verb(ref.Kind)+t.Name)
+				}
+			}
+		}
+		for _, field := range sortedFields(repositories) {
+			if names := calls[repositories[field]]; len(names) > 0 {
+				l.add(handler, repositories[field], RelationUsesRepository, "calls "+strings.Join(names, ", "))
+			}
+		}
+	}
+}
+
+// sortedFields returns the field names of a map in order
+func sortedFields(fields map[string]*Symbol) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// verb describes how a declaration refers to a type in a reason
+func verb(kind types.RefKind) string {
+	switch kind {
+	case types.RefParam:
+		return " takes "
+	case types.RefResult:
+		return " returns "
+	default:
+		return " uses "
+	}
+}
+
+// hasPrefix reports whether name starts with one of the prefixes
+func hasPrefix(name string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimSuffix removes the first of the suffixes name ends with, unless that would
+// leave nothing
+func trimSuffix(name string, suffixes ...string) string {
+	for _, suffix := range suffixes {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != name && trimmed != "" {
+			return trimmed
+		}
+	}
+	return name
+}
@@ -0,0 +1,124 @@
+package cqrs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+const (
+	appPkg    = "example.com/shop/internal/app"
+	domainPkg = "example.com/shop/internal/domain"
+)
+
+func TestLink(t *testing.T) {
+	symbols := []*Symbol{
+		// Domain
+		{ID: 1, Name: "Order", Kind: types.KindStruct, Package: domainPkg},
+		{ID: 2, Name: "OrderRepository", Kind: types.KindInterface, Package: domainPkg, Repository: true},
+		{ID: 3, Name: "Load", Kind: types.KindMethod, Receiver: "OrderRepository", Package: domainPkg, Refs: []types.Ref{
+			{Kind: types.RefParam, Name: "OrderID"},
+			{Kind: types.RefResult, Name: "Order"},
+		}},
+		{ID: 4, Name: "Save", Kind: types.KindMethod, Receiver: "OrderRepository", Package: domainPkg, Refs: []types.Ref{
+			{Kind: types.RefParam, Name: "Order"},
+		}},
+		{ID: 5, Name: "Invoice", Kind: types.KindStruct, Package: domainPkg, Aggregate: true},
+
+		// A command handled by a type with a Handle method
+		{ID: 10, Name: "PlaceOrderCommand", Kind: types.KindStruct, Package: appPkg, Command: true},
+		{ID: 11, Name: "OrderPlacer", Kind: types.KindStruct, Package: appPkg},
+		{ID: 12, Name: "orders", Kind: types.KindField, Receiver: "OrderPlacer", Package: appPkg, Refs: []types.Ref{
+			{Kind: types.RefType, Package: domainPkg, Name: "OrderRepository"},
+		}},
+		{ID: 13, Name: "Handle", Kind: types.KindMethod, Receiver: "OrderPlacer", Package: appPkg, Refs: []types.Ref{
+			{Kind: types.RefParam, Package: "context", Name: "Context"},
+			{Kind: types.RefParam, Name: "PlaceOrderCommand"},
+			{Kind: types.RefMethod, Name: "Load", Via: "orders"},
+			{Kind: types.RefCall, Name: "newInvoice"},
+			{Kind: types.RefMethod, Name: "Save", Via: "orders"},
+		}},
+		{ID: 14, Name: "newInvoice", Kind: types.KindFunction, Package: appPkg, Refs: []types.Ref{
+			{Kind: types.RefResult, Package: domainPkg, Name: "Invoice"},
+		}},
+
+		// A query handled by a function and named after by a type
+		{ID: 20, Name: "GetOrderQuery", Kind: types.KindStruct, Package: appPkg, Query: true},
+		{ID: 21, Name: "GetOrderHandler", Kind: types.KindStruct, Package: appPkg, Handler: true},
+		{ID: 22, Name: "HandleGetOrder", Kind: types.KindFunction, Package: appPkg, Refs: []types.Ref{
+			{Kind: types.RefParam, Package: domainPkg, Name: "OrderRepository"},
+			{Kind: types.RefParam, Name: "GetOrderQuery"},
+			{Kind: types.RefResult, Package: domainPkg, Name: "Order"},
+		}},
+
+		// Neither a handler name nor a handler receiver
+		{ID: 30, Name: "Audit", Kind: types.KindFunction, Package: appPkg, Refs: []types.Ref{
+			{Kind: types.RefParam, Name: "PlaceOrderCommand"},
+		}},
+	}
+
+	assert.Equal(t, []Relation{
+		{From: 2, To: 1, Kind: RelationPersists, Reason: "Load returns Order; Save takes Order"},
+		{From: 10, To: 11, Kind: RelationHandledBy, Reason: "OrderPlacer.Handle takes PlaceOrderCommand"},
+		{From: 11, To: 1, Kind: RelationUsesAggregate, Reason: "through orders.Load"},
+		{From: 11, To: 5, Kind: RelationUsesAggregate, Reason: "through newInvoice"},
+		{From: 11, To: 2, Kind: RelationUsesRepository, Reason: "field orders; calls Load, Save"},
+		{From: 20, To: 21, Kind: RelationHandledBy, Reason: "named after GetOrderQuery"},
+		{From: 20, To: 22, Kind: RelationHandledBy, Reason: "HandleGetOrder takes GetOrderQuery"},
+		{From: 22, To: 1, Kind: RelationUsesAggregate, Reason: "HandleGetOrder returns Order"},
+		{From: 22, To: 2, Kind: RelationUsesRepository, Reason: "HandleGetOrder takes OrderRepository"},
+	}, Link(symbols))
+}
+
+func TestLink_NamedHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		symbols []*Symbol
+		want    []Relation
+	}{
+		{
+			name: "same package preferred",
+			symbols: []*Symbol{
+				{ID: 1, Name: "CancelOrderCmd", Kind: types.KindStruct, Package: "app", Command: true},
+				{ID: 2, Name: "CancelOrderHandler", Kind: types.KindStruct, Package: "app"},
+				{ID: 3, Name: "CancelOrderHandler", Kind: types.KindStruct, Package: "legacy"},
+			},
+			want: []Relation{{From: 1, To: 2, Kind: RelationHandledBy, Reason: "named after CancelOrderCmd"}},
+		},
+		{
+			name: "other package when unambiguous",
+			symbols: []*Symbol{
+				{ID: 1, Name: "Refund", Kind: types.KindStruct, Package: "commands", Command: true},
+				{ID: 2, Name: "RefundHandler", Kind: types.KindStruct, Package: "handlers"},
+			},
+			want: []Relation{{From: 1, To: 2, Kind: RelationHandledBy, Reason: "named after Refund"}},
+		},
+		{
+			name: "ambiguous",
+			symbols: []*Symbol{
+				{ID: 1, Name: "Refund", Kind: types.KindStruct, Package: "commands", Command: true},
+				{ID: 2, Name: "RefundHandler", Kind: types.KindStruct, Package: "a"},
+				{ID: 3, Name: "RefundHandler", Kind: types.KindStruct, Package: "b"},
+			},
+		},
+		{
+			name: "interfaces are not handlers",
+			symbols: []*Symbol{
+				{ID: 1, Name: "RefundCommand", Kind: types.KindStruct, Package: "app", Command: true},
+				{ID: 2, Name: "RefundHandler", Kind: types.KindInterface, Package: "app"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relations := Link(tt.symbols)
+			if tt.want == nil {
+				assert.Empty(t, relations)
+				return
+			}
+			assert.Equal(t, tt.want, relations)
+		})
+	}
+}
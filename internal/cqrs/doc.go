@@ -0,0 +1,35 @@
+// Package cqrs links commands and queries to the code that handles them.
+//
+// Link infers a use case's chain from declarations alone: a command or query
+// is handled by a type or function, the handler uses repositories, and the
+// repositories persist aggregates. The evidence is the pattern flags set by
+// DDD detection, names, and the references the parser records on functions,
+// methods and fields:
+//
+//	PlaceOrderCommand  handled_by       PlaceOrderHandler  (PlaceOrderHandler.Handle takes PlaceOrderCommand)
+//	PlaceOrderHandler  uses_repository  OrderRepository    (field orders; calls Load, Save)
+//	PlaceOrderHandler  uses_aggregate   Order              (through orders.Load)
+//	OrderRepository    persists         Order              (Save takes Order)
+//
+// A command is handled by:
+//   - a method taking it whose receiver is a handler, or whose name starts with
+//     Handle, Execute or Process
+//   - a function taking it whose name starts with Handle or Execute
+//   - a type named after it: PlaceOrderHandler for PlaceOrderCommand or
+//     PlaceOrder, preferably in the same package
+//
+// A repository persists the aggregates and entities its methods take or
+// return, and the struct it is named after: Order for OrderRepository. Types a
+// repository persists count as aggregates for handlers, flagged or not.
+//
+// Method calls are not type-checked: a call through a handler's field is
+// attributed to the field's declared type, and other method calls are not
+// followed.
+//
+// # Basic Usage
+//
+//	relations := cqrs.Link(symbols)
+//	for _, r := range relations {
+//	    fmt.Println(r.From, r.Kind, r.To, r.Reason)
+//	}
+package cqrs
@@ -0,0 +1,249 @@
+package ddd
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dshills/gocontext-mcp/internal/config"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// Patterns
+const (
+	PatternAggregate   = "aggregate"
+	PatternEntity      = "entity"
+	PatternValueObject = "value_object"
+	PatternRepository  = "repository"
+	PatternService     = "service"
+	PatternCommand     = "command"
+	PatternQuery       = "query"
+	PatternHandler     = "handler"
+)
+
+// DefaultConfidence applies to rules that don't set one
+const DefaultConfidence = 0.8
+
+// builtinRules encode the naming conventions
+var builtinRules = []config.DDDRule{
+	{Pattern: PatternAggregate, Name: `(Aggregate|AggregateRoot)$`, Confidence: 0.8, Reason: "name ends in Aggregate or AggregateRoot"},
+	{Pattern: PatternEntity, Name: `Entity$`, Confidence: 0.8, Reason: "name ends in Entity"},
+	{Pattern: PatternValueObject, Name: `(VO|ValueObject)$`, Confidence: 0.8, Reason: "name ends in VO or ValueObject"},
+	{Pattern: PatternRepository, Name: `(Repository|Repo)$`, Confidence: 0.7, Reason: "name ends in Repository or Repo"},
+	{Pattern: PatternService, Name: `Service$`, Confidence: 0.7, Reason: "name ends in Service"},
+	{Pattern: PatternCommand, Name: `(Command|Cmd)$`, Confidence: 0.7, Reason: "name ends in Command or Cmd"},
+	{Pattern: PatternQuery, Name: `Query$`, Confidence: 0.7, Reason: "name ends in Query"},
+	{Pattern: PatternHandler, Name: `Handler$`, Confidence: 0.7, Reason: "name ends in Handler"},
+}
+
+// builtin classifies with the naming conventions only
+var builtin = mustNew(config.DDDConfig{})
+
+// Type describes a declared type to classify
+type Type struct {
+	Name       string
+	Kind       types.SymbolKind // KindStruct, KindInterface or KindType
+	ImportPath string
+	Dir        string   // Package directory relative to the project root, slash-separated
+	Embeds     []string // As written: "*Base", "domain.Base"
+	Fields     []string // Named fields of a struct
+	Methods    []string // Declared methods, including interface methods
+}
+
+// rule is a compiled config.DDDRule
+type rule struct {
+	config.DDDRule
+	name *regexp.Regexp
+}
+
+// Classifier applies a project's rules
+type Classifier struct {
+	rules []rule
+}
+
+// New compiles the rules of a project configuration, after the built-in ones
+// unless cfg.Builtin is false
+func New(cfg config.DDDConfig) (*Classifier, error) {
+	var rules []config.DDDRule
+	if cfg.UseBuiltin() {
+		rules = append(rules, builtinRules...)
+	}
+	rules = append(rules, cfg.Rules...)
+
+	c := &Classifier{rules: make([]rule, 0, len(rules))}
+	for i, r := range rules {
+		compiled := rule{DDDRule: r}
+		if r.Name != "" {
+			re, err := regexp.Compile(r.Name)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: invalid name expression: %w", i, err)
+			}
+			compiled.name = re
+		}
+		if compiled.Confidence == 0 {
+			compiled.Confidence = DefaultConfidence
+		}
+		c.rules = append(c.rules, compiled)
+	}
+	return c, nil
+}
+
+// mustNew is New for rules known to be valid
+func mustNew(cfg config.DDDConfig) *Classifier {
+	c, err := New(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Builtin returns a classifier using only the naming conventions, for code
+// classified without a project configuration
+func Builtin() *Classifier {
+	return builtin
+}
+
+// Classify returns the patterns t matches, most confident first. interfaces
+// resolves implements criteria and may be nil when none are known.
+func (c *Classifier) Classify(t *Type, interfaces *Interfaces) []types.DDDMatch {
+	type evidence struct {
+		missing float64 // Probability that every matching rule is wrong
+		reasons []string
+	}
+	found := make(map[string]*evidence)
+	add := func(pattern string, confidence float64, reason string) {
+		e, ok := found[pattern]
+		if !ok {
+			e = &evidence{missing: 1}
+			found[pattern] = e
+		}
+		e.missing *= 1 - confidence
+		e.reasons = append(e.reasons, reason)
+	}
+
+	for i := range c.rules {
+		r := &c.rules[i]
+		if reason, ok := r.match(t, interfaces); ok {
+			add(r.Pattern, r.Confidence, reason)
+		}
+	}
+	if agg, ok := found[PatternAggregate]; ok {
+		add(PatternEntity, 1-agg.missing, "aggregate roots are entities")
+	}
+
+	var matches []types.DDDMatch
+	for pattern, e := range found {
+		matches = append(matches, types.DDDMatch{
+			Pattern:    pattern,
+			Confidence: math.Round((1-e.missing)*100) / 100,
+			Reason:     strings.Join(e.reasons, "; "),
+		})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		return matches[i].Pattern < matches[j].Pattern
+	})
+	return matches
+}
+
+// match reports whether t meets every criterion of the rule, with the reason
+func (r *rule) match(t *Type, interfaces *Interfaces) (string, bool) {
+	var met []string
+
+	if r.Kind != "" {
+		if string(t.Kind) != r.Kind {
+			return "", false
+		}
+		met = append(met, "is a "+r.Kind)
+	}
+	if r.name != nil {
+		if !r.name.MatchString(t.Name) {
+			return "", false
+		}
+		met = append(met, "name matches "+r.Name)
+	}
+	if r.Package != "" {
+		if !config.MatchPackage(r.Package, t.Dir) && !config.MatchPackage(r.Package, t.ImportPath) {
+			return "", false
+		}
+		met = append(met, "in package "+r.Package)
+	}
+	for _, embed := range r.Embeds {
+		if !slices.ContainsFunc(t.Embeds, func(e string) bool { return sameType(embed, e) }) {
+			return "", false
+		}
+		met = append(met, "embeds "+embed)
+	}
+	for _, name := range r.Implements {
+		if !interfaces.implemented(name, t) {
+			return "", false
+		}
+		met = append(met, "implements "+name)
+	}
+	if len(r.Fields) > 0 {
+		for _, field := range r.Fields {
+			if !slices.Contains(t.Fields, field) {
+				return "", false
+			}
+		}
+		met = append(met, "has fields "+strings.Join(r.Fields, ", "))
+	}
+	if len(r.Methods) > 0 {
+		for _, method := range r.Methods {
+			if !slices.Contains(t.Methods, method) {
+				return "", false
+			}
+		}
+		met = append(met, "has methods "+strings.Join(r.Methods, ", "))
+	}
+
+	if r.Reason != "" {
+		return r.Reason, true
+	}
+	return strings.Join(met, ", "), true
+}
+
+// sameType reports whether an embed as written ("*domain.Base[T]") is the type a
+// rule names; an unqualified name in the rule matches any package
+func sameType(ruleName, embed string) bool {
+	embed = strings.TrimPrefix(embed, "*")
+	if i := strings.IndexByte(embed, '['); i >= 0 {
+		embed = embed[:i]
+	}
+	if !strings.Contains(ruleName, ".") {
+		embed = embed[strings.LastIndexByte(embed, '.')+1:]
+	}
+	return embed == ruleName
+}
+
+// Apply records matches on a symbol, setting the pattern flags
+func Apply(sym *types.Symbol, matches []types.DDDMatch) {
+	sym.IsAggregateRoot, sym.IsEntity, sym.IsValueObject, sym.IsRepository = false, false, false, false
+	sym.IsService, sym.IsCommand, sym.IsQuery, sym.IsHandler = false, false, false, false
+	for _, m := range matches {
+		switch m.Pattern {
+		case PatternAggregate:
+			sym.IsAggregateRoot = true
+		case PatternEntity:
+			sym.IsEntity = true
+		case PatternValueObject:
+			sym.IsValueObject = true
+		case PatternRepository:
+			sym.IsRepository = true
+		case PatternService:
+			sym.IsService = true
+		case PatternCommand:
+			sym.IsCommand = true
+		case PatternQuery:
+			sym.IsQuery = true
+		case PatternHandler:
+			sym.IsHandler = true
+		}
+	}
+	sym.DDD = matches
+}
@@ -0,0 +1,134 @@
+package ddd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dshills/gocontext-mcp/internal/config"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+func TestBuiltin(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     types.SymbolKind
+		patterns []string
+	}{
+		{"OrderAggregate", types.KindStruct, []string{PatternAggregate, PatternEntity}},
+		{"CustomerEntity", types.KindStruct, []string{PatternEntity}},
+		{"MoneyVO", types.KindType, []string{PatternValueObject}},
+		{"OrderRepository", types.KindInterface, []string{PatternRepository}},
+		{"UserRepo", types.KindStruct, []string{PatternRepository}},
+		{"PlaceOrderCmd", types.KindStruct, []string{PatternCommand}},
+		{"OrderPlacedHandler", types.KindStruct, []string{PatternHandler}},
+		{"UserSettings", types.KindStruct, nil},
+		{"ProductList", types.KindType, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := Builtin().Classify(&Type{Name: tt.name, Kind: tt.kind}, nil)
+			var patterns []string
+			for _, m := range matches {
+				patterns = append(patterns, m.Pattern)
+			}
+			assert.Equal(t, tt.patterns, patterns)
+		})
+	}
+
+	matches := Builtin().Classify(&Type{Name: "OrderRepository", Kind: types.KindInterface}, nil)
+	assert.Equal(t, []types.DDDMatch{{Pattern: PatternRepository, Confidence: 0.7, Reason: "name ends in Repository or Repo"}}, matches)
+}
+
+func TestClassify_Rules(t *testing.T) {
+	builtin := false
+	classifier, err := New(config.DDDConfig{
+		Builtin: &builtin,
+		Rules: []config.DDDRule{
+			{
+				Pattern:    PatternAggregate,
+				Kind:       "struct",
+				Package:    "internal/domain/**",
+				Embeds:     []string{"domain.AggregateRoot"},
+				Confidence: 0.9,
+			},
+			{Pattern: PatternEntity, Kind: "struct", Fields: []string{"ID"}, Methods: []string{"Validate"}, Confidence: 0.6},
+			{Pattern: PatternEntity, Implements: []string{"domain.Identifiable"}, Confidence: 0.6},
+			{Pattern: PatternRepository, Name: `Store$`, Reason: "stores are repositories here"},
+		},
+	})
+	require.NoError(t, err)
+
+	interfaces := NewInterfaces([]*Interface{
+		{Name: "Identifier", PackageName: "domain", ImportPath: "example.com/shop/internal/domain", Methods: []string{"Identity"}},
+		{Name: "Identifiable", PackageName: "domain", ImportPath: "example.com/shop/internal/domain",
+			Methods: []string{"Version"}, Embeds: []string{"Identifier"}},
+		{Name: "Empty", PackageName: "domain", ImportPath: "example.com/shop/internal/domain"},
+	})
+
+	order := &Type{
+		Name:       "Order",
+		Kind:       types.KindStruct,
+		ImportPath: "example.com/shop/internal/domain/order",
+		Dir:        "internal/domain/order",
+		Embeds:     []string{"*domain.AggregateRoot"},
+		Fields:     []string{"ID", "Lines"},
+		Methods:    []string{"Validate", "Identity", "Version"},
+	}
+	assert.Equal(t, []types.DDDMatch{
+		{
+			Pattern:    PatternEntity,
+			Confidence: 0.98,
+			Reason: "is a struct, has fields ID, has methods Validate; implements domain.Identifiable; " +
+				"aggregate roots are entities",
+		},
+		{
+			Pattern:    PatternAggregate,
+			Confidence: 0.9,
+			Reason:     "is a struct, in package internal/domain/**, embeds domain.AggregateRoot",
+		},
+	}, classifier.Classify(order, interfaces))
+
+	// Outside the domain package, without the embedded interface's method
+	line := &Type{Name: "Line", Kind: types.KindStruct, Dir: "internal/app", Embeds: []string{"domain.AggregateRoot"},
+		Methods: []string{"Version"}}
+	assert.Nil(t, classifier.Classify(line, interfaces))
+
+	assert.Equal(t, []types.DDDMatch{{Pattern: PatternRepository, Confidence: DefaultConfidence, Reason: "stores are repositories here"}},
+		classifier.Classify(&Type{Name: "OrderStore", Kind: types.KindInterface}, nil))
+
+	// The naming conventions are off
+	assert.Nil(t, classifier.Classify(&Type{Name: "OrderService", Kind: types.KindStruct}, interfaces))
+}
+
+func TestInterfaces_Implemented(t *testing.T) {
+	interfaces := NewInterfaces([]*Interface{
+		{Name: "Reader", PackageName: "store", ImportPath: "example.com/a/store", Methods: []string{"Read"}},
+		{Name: "Reader", PackageName: "cache", ImportPath: "example.com/a/cache", Methods: []string{"Get"}},
+		{Name: "ReadCloser", PackageName: "store", ImportPath: "example.com/a/store",
+			Methods: []string{"Close"}, Embeds: []string{"Reader", "ReadCloser"}},
+		{Name: "Empty", PackageName: "store", ImportPath: "example.com/a/store"},
+	})
+
+	reader := &Type{Methods: []string{"Read"}}
+	assert.True(t, interfaces.implemented("Reader", reader))
+	assert.True(t, interfaces.implemented("store.Reader", reader))
+	assert.True(t, interfaces.implemented("example.com/a/store.Reader", reader))
+	assert.False(t, interfaces.implemented("cache.Reader", reader))
+	assert.False(t, interfaces.implemented("ReadCloser", reader))
+	assert.True(t, interfaces.implemented("ReadCloser", &Type{Methods: []string{"Close", "Read"}}))
+	assert.False(t, interfaces.implemented("Empty", reader))
+	assert.False(t, interfaces.implemented("Missing", reader))
+}
+
+func TestApply(t *testing.T) {
+	sym := types.Symbol{Name: "Order", Kind: types.KindStruct, IsService: true}
+	matches := []types.DDDMatch{{Pattern: PatternAggregate, Confidence: 0.9}, {Pattern: PatternEntity, Confidence: 0.9}}
+	Apply(&sym, matches)
+
+	assert.True(t, sym.IsAggregateRoot)
+	assert.True(t, sym.IsEntity)
+	assert.False(t, sym.IsService)
+	assert.Equal(t, matches, sym.DDD)
+}
@@ -0,0 +1,46 @@
+// Package ddd detects domain-driven design patterns on declared types using
+// configurable rules.
+//
+// A rule names a pattern (aggregate, entity, value_object, repository, service,
+// command, query or handler) and the criteria a type must all meet: a name
+// regular expression, a package glob, embedded types, implemented interfaces,
+// and the fields and methods it declares. Rules come from the ddd section of
+// .gocontext.yaml; the built-in rules encode the naming conventions
+// ("*Repository", "*Aggregate", "*Command", ...) and can be turned off:
+//
+//	ddd:
+//	  builtin: false
+//	  rules:
+//	    - pattern: aggregate
+//	      package: internal/domain/**
+//	      embeds: [domain.AggregateRoot]
+//	      confidence: 0.9
+//	    - pattern: entity
+//	      kind: struct
+//	      fields: [ID]
+//	      methods: [Validate]
+//
+// Each match carries a confidence and a reason listing the criteria met. When
+// several rules find the same pattern their evidence adds up: two rules at 0.6
+// give 0.84. Aggregate roots are also reported as entities.
+//
+// # Basic Usage
+//
+//	classifier, err := ddd.New(project.DDD)
+//	if err != nil {
+//	    return err
+//	}
+//	interfaces := ddd.NewInterfaces(declaredInterfaces)
+//	matches := classifier.Classify(&ddd.Type{
+//	    Name:       "OrderAggregate",
+//	    Kind:       types.KindStruct,
+//	    ImportPath: "example.com/shop/internal/domain",
+//	    Dir:        "internal/domain",
+//	    Methods:    []string{"Apply", "Version"},
+//	}, interfaces)
+//	ddd.Apply(&symbol, matches)
+//
+// Implemented interfaces are judged by method names: a type implements an
+// interface when it declares every method of the interface and of the
+// interfaces it embeds. Methods promoted from embedded types are not counted.
+package ddd
@@ -0,0 +1,111 @@
+package ddd
+
+import (
+	"slices"
+	"strings"
+)
+
+// Interface is a declared interface that implements criteria can name
+type Interface struct {
+	Name        string
+	PackageName string
+	ImportPath  string
+	Methods     []string // Declared method names
+	Embeds      []string // Embedded interfaces as written: "Reader", "io.Closer"
+}
+
+// Interfaces indexes declared interfaces by name and resolves their method sets
+type Interfaces struct {
+	byName  map[string][]*Interface
+	methods map[*Interface][]string
+}
+
+// NewInterfaces indexes interfaces for implements criteria
+func NewInterfaces(interfaces []*Interface) *Interfaces {
+	idx := &Interfaces{
+		byName:  make(map[string][]*Interface),
+		methods: make(map[*Interface][]string),
+	}
+	for _, iface := range interfaces {
+		idx.byName[iface.Name] = append(idx.byName[iface.Name], iface)
+	}
+	return idx
+}
+
+// implemented reports whether t declares every method of an interface named by
+// ref: "Entity", "domain.Entity" (package name) or "example.com/shop/domain.Entity".
+// Interfaces without methods are never implemented, as everything would qualify.
+func (idx *Interfaces) implemented(ref string, t *Type) bool {
+	if idx == nil {
+		return false
+	}
+	for _, iface := range idx.lookup(ref, "") {
+		methods := idx.methodSet(iface, nil)
+		if len(methods) == 0 {
+			continue
+		}
+		if !slices.ContainsFunc(methods, func(m string) bool { return !slices.Contains(t.Methods, m) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup finds the interfaces a reference names; an unqualified name prefers
+// the interface declared in importPath when there is one
+func (idx *Interfaces) lookup(ref, importPath string) []*Interface {
+	qualifier, name := "", ref
+	if i := strings.LastIndexByte(ref, '.'); i >= 0 {
+		qualifier, name = ref[:i], ref[i+1:]
+	}
+
+	var found []*Interface
+	for _, iface := range idx.byName[name] {
+		switch {
+		case qualifier == "":
+			if importPath != "" && iface.ImportPath == importPath {
+				return []*Interface{iface}
+			}
+			found = append(found, iface)
+		case qualifier == iface.PackageName || qualifier == iface.ImportPath:
+			found = append(found, iface)
+		}
+	}
+	return found
+}
+
+// methodSet returns an interface's methods including those of the interfaces it
+// embeds; visiting guards against embedding cycles
+func (idx *Interfaces) methodSet(iface *Interface, visiting map[*Interface]bool) []string {
+	if methods, ok := idx.methods[iface]; ok {
+		return methods
+	}
+	if visiting == nil {
+		visiting = make(map[*Interface]bool)
+	}
+	if visiting[iface] {
+		return nil
+	}
+	visiting[iface] = true
+
+	methods := slices.Clone(iface.Methods)
+	for _, embed := range iface.Embeds {
+		// Embedded interfaces are unqualified in their own package, qualified by package name otherwise
+		importPath := ""
+		if !strings.Contains(embed, ".") {
+			importPath = iface.ImportPath
+		}
+		for _, embedded := range idx.lookup(strings.TrimPrefix(embed, "*"), importPath) {
+			if !strings.Contains(embed, ".") && embedded.ImportPath != iface.ImportPath {
+				continue
+			}
+			for _, m := range idx.methodSet(embedded, visiting) {
+				if !slices.Contains(methods, m) {
+					methods = append(methods, m)
+				}
+			}
+		}
+	}
+	idx.methods[iface] = methods
+	return methods
+}
@@ -0,0 +1,458 @@
+package deadcode
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// Confidence levels, from most to least certain
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// Symbol is a declaration to check
+type Symbol struct {
+	ID          int64
+	Name        string
+	Kind        types.SymbolKind
+	Receiver    string // Type of a method or field
+	Package     string // Import path, or the directory when the file has none
+	PackageName string // Declared package name
+	File        string
+	Line        int
+	EndLine     int
+	Test        bool // Declared in a _test.go file
+	Tagged      bool // Field with a struct tag
+	Refs        []types.Ref
+}
+
+// Finding is an unused declaration
+type Finding struct {
+	Symbol     *Symbol
+	Confidence string
+	Reason     string
+	TestOnly   bool // Used, but only by tests
+	Members    int  // Fields and methods of an unused type folded into it
+}
+
+// Options tune Find
+type Options struct {
+	IncludeTests bool // Report the declarations of test files
+}
+
+// wellKnownMethods are methods that implement common standard library
+// interfaces, which the index does not see
+var wellKnownMethods = map[string]string{
+	"String": "fmt.Stringer", "GoString": "fmt.GoStringer", "Format": "fmt.Formatter",
+	"Error": "error", "Unwrap": "errors.Unwrap", "Is": "errors.Is", "As": "errors.As",
+	"Read": "io.Reader", "Write": "io.Writer", "Close": "io.Closer", "Seek": "io.Seeker",
+	"ReadAt": "io.ReaderAt", "WriteAt": "io.WriterAt", "ReadFrom": "io.ReaderFrom", "WriteTo": "io.WriterTo",
+	"ReadByte": "io.ByteReader", "UnreadByte": "io.ByteScanner", "WriteByte": "io.ByteWriter",
+	"ReadRune": "io.RuneReader", "UnreadRune": "io.RuneScanner", "WriteString": "io.StringWriter",
+	"ServeHTTP": "http.Handler", "RoundTrip": "http.RoundTripper",
+	"MarshalJSON": "json.Marshaler", "UnmarshalJSON": "json.Unmarshaler",
+	"MarshalText": "encoding.TextMarshaler", "UnmarshalText": "encoding.TextUnmarshaler",
+	"MarshalBinary": "encoding.BinaryMarshaler", "UnmarshalBinary": "encoding.BinaryUnmarshaler",
+	"MarshalXML": "xml.Marshaler", "UnmarshalXML": "xml.Unmarshaler",
+	"MarshalYAML": "yaml.Marshaler", "UnmarshalYAML": "yaml.Unmarshaler",
+	"Scan": "sql.Scanner", "Value": "driver.Valuer",
+	"Len": "sort.Interface", "Less": "sort.Interface", "Swap": "sort.Interface",
+	"Push": "heap.Interface", "Pop": "heap.Interface",
+	"Lock": "sync.Locker", "Unlock": "sync.Locker",
+	"Deadline": "context.Context", "Done": "context.Context", "Err": "context.Context",
+	"Timeout": "net.Error", "Temporary": "net.Error",
+	"Open": "fs.FS", "Stat": "fs.StatFS", "ReadDir": "fs.ReadDirFS", "ReadFile": "fs.ReadFileFS",
+	"Sub": "fs.SubFS", "Glob": "fs.GlobFS", "Name": "fs.FileInfo", "Size": "fs.FileInfo",
+	"Mode": "fs.FileInfo", "ModTime": "fs.FileInfo", "IsDir": "fs.FileInfo", "Sys": "fs.FileInfo",
+	"Info": "fs.DirEntry", "Type": "fs.DirEntry", "Set": "flag.Value",
+}
+
+// key identifies a declaration by package and name
+type key struct {
+	pkg  string
+	name string
+}
+
+// member identifies a field or method by package, receiver type and name
+type member struct {
+	pkg      string
+	receiver string
+	name     string
+}
+
+// usage records who uses a declaration
+type usage struct {
+	code  bool // Outside test files
+	tests bool
+}
+
+// mark records a use by sym
+func (u *usage) mark(sym *Symbol) {
+	if sym.Test {
+		u.tests = true
+	} else {
+		u.code = true
+	}
+}
+
+// finder resolves the uses of a project's declarations
+type finder struct {
+	decls      map[key][]*Symbol        // Package-level declarations
+	kinds      map[key]types.SymbolKind // Of types, to tell interface methods apart
+	interfaces map[string][]string      // Interfaces declaring a method, by method name
+	fields     map[key][]*Symbol        // Fields, by struct
+	declared   map[member]bool          // Fields and methods
+	used       map[int64]*usage
+	members    map[string]*usage // Uses of fields and methods on an unknown type, by name
+	typed      map[member]*usage // Uses of fields and methods on a known type
+}
+
+// Find returns the unused declarations of a project, ordered by package, file
+// and line
+func Find(symbols []*Symbol, opts Options) []Finding {
+	f := &finder{
+		decls:      make(map[key][]*Symbol),
+		kinds:      make(map[key]types.SymbolKind),
+		interfaces: make(map[string][]string),
+		fields:     make(map[key][]*Symbol),
+		declared:   make(map[member]bool),
+		used:       make(map[int64]*usage),
+		members:    make(map[string]*usage),
+		typed:      make(map[member]*usage),
+	}
+	symbols = packageLevel(symbols)
+	for _, sym := range symbols {
+		if sym.Receiver == "" {
+			k := key{sym.Package, sym.Name}
+			f.decls[k] = append(f.decls[k], sym)
+			if isType(sym) {
+				f.kinds[k] = sym.Kind
+			}
+			continue
+		}
+		f.declared[member{sym.Package, sym.Receiver, sym.Name}] = true
+		if sym.Kind == types.KindField {
+			k := key{sym.Package, sym.Receiver}
+			f.fields[k] = append(f.fields[k], sym)
+		}
+	}
+	for _, sym := range symbols {
+		if sym.Kind == types.KindMethod && f.kinds[key{sym.Package, sym.Receiver}] == types.KindInterface {
+			f.interfaces[sym.Name] = append(f.interfaces[sym.Name], sym.Receiver)
+		}
+		f.resolve(sym)
+	}
+
+	var findings []Finding
+	for _, sym := range symbols {
+		if sym.Name == "_" || sym.Test && !opts.IncludeTests || isEntryPoint(sym) {
+			continue
+		}
+		if finding, ok := f.check(sym); ok {
+			findings = append(findings, finding)
+		}
+	}
+	findings = foldMembers(findings)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i].Symbol, findings[j].Symbol
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return findings
+}
+
+// packageLevel drops the declarations inside function bodies, which the parser
+// indexes too; their uses are recorded on the enclosing function
+func packageLevel(symbols []*Symbol) []*Symbol {
+	type span struct{ start, end int }
+	bodies := make(map[string][]span) // By file
+	for _, sym := range symbols {
+		if sym.Kind == types.KindFunction || sym.Kind == types.KindMethod {
+			bodies[sym.File] = append(bodies[sym.File], span{sym.Line, sym.EndLine})
+		}
+	}
+
+	result := make([]*Symbol, 0, len(symbols))
+	for _, sym := range symbols {
+		local := false
+		for _, body := range bodies[sym.File] {
+			if sym.Line > body.start && sym.Line <= body.end {
+				local = true
+				break
+			}
+		}
+		if !local {
+			result = append(result, sym)
+		}
+	}
+	return result
+}
+
+// resolve marks the declarations and members sym refers to
+func (f *finder) resolve(sym *Symbol) {
+	for _, ref := range sym.Refs {
+		if ref.Kind == types.RefMethod || ref.Kind == types.RefSelect {
+			if m, ok := f.viaMember(sym, ref); ok {
+				f.typedUsage(m).mark(sym)
+			} else {
+				f.memberUsage(ref.Name).mark(sym)
+			}
+			continue
+		}
+		pkg := ref.Package
+		if pkg == "" {
+			pkg = sym.Package
+		}
+		for _, target := range f.decls[key{pkg, ref.Name}] {
+			if target.ID != sym.ID {
+				f.usageOf(target.ID).mark(sym)
+			}
+		}
+	}
+}
+
+// viaMember resolves a method called on a field of sym's receiver, as in
+// h.orders.Save, to the method of the field's type. It fails when the field's
+// type is not a single concrete type of the project declaring the method:
+// interfaces, embedded methods and types such as map[ID]Order are matched by name.
+func (f *finder) viaMember(sym *Symbol, ref types.Ref) (member, bool) {
+	if ref.Via == "" || sym.Receiver == "" {
+		return member{}, false
+	}
+	var owners []key
+	for _, field := range f.fields[key{sym.Package, sym.Receiver}] {
+		if field.Name != ref.Via {
+			continue
+		}
+		for _, typeRef := range field.Refs {
+			if typeRef.Kind != types.RefType {
+				continue
+			}
+			pkg := typeRef.Package
+			if pkg == "" {
+				pkg = field.Package
+			}
+			owners = append(owners, key{pkg, typeRef.Name})
+		}
+	}
+	if len(owners) != 1 {
+		return member{}, false
+	}
+	owner := owners[0]
+	if kind, ok := f.kinds[owner]; !ok || kind == types.KindInterface {
+		return member{}, false
+	}
+	m := member{owner.pkg, owner.name, ref.Name}
+	return m, f.declared[m]
+}
+
+// typedUsage returns the usage of a member of a known type, creating it if needed
+func (f *finder) typedUsage(m member) *usage {
+	u, ok := f.typed[m]
+	if !ok {
+		u = &usage{}
+		f.typed[m] = u
+	}
+	return u
+}
+
+// memberUsage returns the usage of a field or method name, creating it if needed
+func (f *finder) memberUsage(name string) *usage {
+	u, ok := f.members[name]
+	if !ok {
+		u = &usage{}
+		f.members[name] = u
+	}
+	return u
+}
+
+// usageOf returns the usage of a declaration, creating it if needed
+func (f *finder) usageOf(id int64) *usage {
+	u, ok := f.used[id]
+	if !ok {
+		u = &usage{}
+		f.used[id] = u
+	}
+	return u
+}
+
+// memberUsed merges the uses of a field or method by name and on its type
+func (f *finder) memberUsed(sym *Symbol) *usage {
+	byName, onType := f.members[sym.Name], f.typed[member{sym.Package, sym.Receiver, sym.Name}]
+	if byName == nil || onType == nil {
+		if byName != nil {
+			return byName
+		}
+		return onType
+	}
+	return &usage{code: byName.code || onType.code, tests: byName.tests || onType.tests}
+}
+
+// check reports whether sym is unused, and how confidently
+func (f *finder) check(sym *Symbol) (Finding, bool) {
+	var u *usage
+	switch sym.Kind {
+	case types.KindMethod, types.KindField:
+		u = f.memberUsed(sym)
+	default:
+		u = f.used[sym.ID]
+	}
+	if u != nil && (u.code || u.tests && sym.Test) {
+		return Finding{}, false
+	}
+
+	finding := Finding{Symbol: sym}
+	switch {
+	case u != nil && u.tests:
+		finding.Confidence, finding.Reason, finding.TestOnly = ConfidenceMedium, "used only by tests", true
+	case sym.Kind == types.KindField:
+		finding.Confidence, finding.Reason = fieldReason(sym)
+	case sym.Kind == types.KindMethod:
+		finding.Confidence, finding.Reason = f.methodReason(sym)
+	case !ast.IsExported(sym.Name):
+		finding.Confidence, finding.Reason = ConfidenceHigh, "unused in its package"
+	case importable(sym):
+		finding.Confidence, finding.Reason = ConfidenceMedium, "exported but unused in the indexed module; importers outside it are not seen"
+	default:
+		finding.Confidence, finding.Reason = ConfidenceHigh, "exported from a package only the module can import, and unused"
+	}
+	return finding, true
+}
+
+// fieldReason rates an unused field: encoders and templates reach exported and
+// tagged fields by reflection
+func fieldReason(sym *Symbol) (confidence, reason string) {
+	switch {
+	case sym.Tagged:
+		return ConfidenceLow, "field never selected, but its struct tag suggests encoders read it by reflection"
+	case ast.IsExported(sym.Name):
+		return ConfidenceLow, "exported field never selected; encoders and templates may read it by reflection"
+	}
+	return ConfidenceMedium, "field never selected or set by key; positional composite literals are not seen"
+}
+
+// methodReason rates an unused method: it may implement an interface, be
+// called by reflection, or be called from outside the module. Code outside the
+// module can only name the exported types of importable packages, so other
+// exported methods are reached through interfaces if at all.
+func (f *finder) methodReason(sym *Symbol) (confidence, reason string) {
+	if f.kinds[key{sym.Package, sym.Receiver}] == types.KindInterface {
+		return ConfidenceLow, "interface method never called; implementations may still need it"
+	}
+	if interfaces := f.interfaces[sym.Name]; len(interfaces) > 0 {
+		return ConfidenceLow, "never called by name, but may implement " + strings.Join(dedupe(interfaces), ", ")
+	}
+	if iface, ok := wellKnownMethods[sym.Name]; ok {
+		return ConfidenceLow, "never called by name, but may implement " + iface
+	}
+	if !ast.IsExported(sym.Name) {
+		return ConfidenceHigh, "method never called or selected"
+	}
+	if ast.IsExported(sym.Receiver) && importable(sym) {
+		return ConfidenceMedium, "exported method never called or selected; calls by reflection or from outside the module are not seen"
+	}
+	return ConfidenceMedium, "exported method never called or selected; it may implement an interface of another module, which the index does not see"
+}
+
+// foldMembers drops the fields and methods of unused types, counting them on
+// the type's finding
+func foldMembers(findings []Finding) []Finding {
+	unusedTypes := make(map[key]*Finding)
+	for i := range findings {
+		if sym := findings[i].Symbol; isType(sym) && !findings[i].TestOnly {
+			unusedTypes[key{sym.Package, sym.Name}] = &findings[i]
+		}
+	}
+
+	folded := make(map[int]bool)
+	for i, finding := range findings {
+		if sym := finding.Symbol; sym.Receiver != "" {
+			if owner := unusedTypes[key{sym.Package, sym.Receiver}]; owner != nil {
+				owner.Members++
+				folded[i] = true
+			}
+		}
+	}
+
+	kept := make([]Finding, 0, len(findings)-len(folded))
+	for i, finding := range findings {
+		if !folded[i] {
+			kept = append(kept, finding)
+		}
+	}
+	return kept
+}
+
+// isType reports whether sym declares a type
+func isType(sym *Symbol) bool {
+	return sym.Kind == types.KindStruct || sym.Kind == types.KindInterface || sym.Kind == types.KindType
+}
+
+// isEntryPoint reports whether sym is called by the runtime or go test
+func isEntryPoint(sym *Symbol) bool {
+	if sym.Kind != types.KindFunction {
+		return false
+	}
+	switch {
+	case sym.Name == "init":
+		return true
+	case sym.Name == "main":
+		return sym.PackageName == "main"
+	case sym.Test:
+		return sym.Name == "TestMain" || isTestName(sym.Name, "Test") || isTestName(sym.Name, "Benchmark") ||
+			isTestName(sym.Name, "Fuzz") || isTestName(sym.Name, "Example")
+	}
+	return false
+}
+
+// isTestName reports whether name is prefix followed by nothing or a
+// non-lowercase letter, as go test requires
+func isTestName(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	if len(name) == len(prefix) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(name[len(prefix):])
+	return !unicode.IsLower(r)
+}
+
+// importable reports whether code outside the module can import sym's package
+func importable(sym *Symbol) bool {
+	if sym.PackageName == "main" {
+		return false
+	}
+	for _, elem := range strings.Split(sym.Package, "/") {
+		if elem == "internal" {
+			return false
+		}
+	}
+	return true
+}
+
+// dedupe returns names in order without repeats
+func dedupe(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,220 @@
+package deadcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+const (
+	cmdPkg   = "example.com/shop/cmd/shop"
+	storePkg = "example.com/shop/internal/store"
+	apiPkg   = "example.com/shop/api"
+)
+
+func TestFind(t *testing.T) {
+	symbols := []*Symbol{
+		// A command using part of the store
+		{ID: 1, Name: "main", Kind: types.KindFunction, Package: cmdPkg, PackageName: "main", File: "cmd/shop/main.go", Line: 3, Refs: []types.Ref{
+			{Kind: types.RefCall, Package: storePkg, Name: "Open"},
+			{Kind: types.RefMethod, Name: "Get"},
+		}},
+		{ID: 2, Name: "init", Kind: types.KindFunction, Package: cmdPkg, PackageName: "main", File: "cmd/shop/main.go", Line: 9},
+		{ID: 3, Name: "Usage", Kind: types.KindFunction, Package: cmdPkg, PackageName: "main", File: "cmd/shop/main.go", Line: 11},
+
+		// The store
+		{ID: 10, Name: "Open", Kind: types.KindFunction, Package: storePkg, PackageName: "store", File: "internal/store/store.go", Line: 5, Refs: []types.Ref{
+			{Kind: types.RefResult, Name: "Store"},
+			{Kind: types.RefUse, Name: "defaultSize"},
+		}},
+		{ID: 11, Name: "Store", Kind: types.KindStruct, Package: storePkg, PackageName: "store", File: "internal/store/store.go", Line: 10},
+		{ID: 12, Name: "items", Kind: types.KindField, Receiver: "Store", Package: storePkg, File: "internal/store/store.go", Line: 11},
+		{ID: 13, Name: "count", Kind: types.KindField, Receiver: "Store", Package: storePkg, File: "internal/store/store.go", Line: 12},
+		{ID: 14, Name: "Name", Kind: types.KindField, Receiver: "Store", Package: storePkg, File: "internal/store/store.go", Line: 13, Tagged: true},
+		{ID: 15, Name: "Get", Kind: types.KindMethod, Receiver: "Store", Package: storePkg, File: "internal/store/store.go", Line: 15, Refs: []types.Ref{
+			{Kind: types.RefSelect, Name: "items"},
+			{Kind: types.RefCall, Name: "lookup"},
+		}},
+		{ID: 16, Name: "String", Kind: types.KindMethod, Receiver: "Store", Package: storePkg, File: "internal/store/store.go", Line: 20},
+		{ID: 17, Name: "reset", Kind: types.KindMethod, Receiver: "Store", Package: storePkg, File: "internal/store/store.go", Line: 22},
+		{ID: 18, Name: "Flush", Kind: types.KindMethod, Receiver: "Store", Package: storePkg, File: "internal/store/store.go", Line: 24},
+		{ID: 19, Name: "lookup", Kind: types.KindFunction, Package: storePkg, File: "internal/store/store.go", Line: 26, EndLine: 29, Refs: []types.Ref{
+			{Kind: types.RefCall, Name: "lookup"}, // Recursion is not a use
+		}},
+		{ID: 29, Name: "miss", Kind: types.KindVar, Package: storePkg, File: "internal/store/store.go", Line: 27}, // Local
+		{ID: 20, Name: "defaultSize", Kind: types.KindConst, Package: storePkg, File: "internal/store/store.go", Line: 3},
+		{ID: 21, Name: "maxSize", Kind: types.KindConst, Package: storePkg, File: "internal/store/store.go", Line: 4},
+		{ID: 22, Name: "Cache", Kind: types.KindInterface, Package: storePkg, File: "internal/store/cache.go", Line: 3},
+		{ID: 23, Name: "Flush", Kind: types.KindMethod, Receiver: "Cache", Package: storePkg, File: "internal/store/cache.go", Line: 4},
+		{ID: 24, Name: "legacy", Kind: types.KindStruct, Package: storePkg, File: "internal/store/cache.go", Line: 6},
+		{ID: 25, Name: "size", Kind: types.KindField, Receiver: "legacy", Package: storePkg, File: "internal/store/cache.go", Line: 7},
+		{ID: 26, Name: "grow", Kind: types.KindMethod, Receiver: "legacy", Package: storePkg, File: "internal/store/cache.go", Line: 9},
+		{ID: 27, Name: "_", Kind: types.KindVar, Package: storePkg, File: "internal/store/cache.go", Line: 12, Refs: []types.Ref{
+			{Kind: types.RefType, Name: "Cache"},
+		}},
+		{ID: 28, Name: "fixture", Kind: types.KindFunction, Package: storePkg, File: "internal/store/store.go", Line: 30},
+
+		// Tests
+		{ID: 30, Name: "TestStore", Kind: types.KindFunction, Package: storePkg, File: "internal/store/store_test.go", Line: 5, Test: true, Refs: []types.Ref{
+			{Kind: types.RefCall, Name: "fixture"},
+			{Kind: types.RefCall, Name: "helper"},
+		}},
+		{ID: 31, Name: "helper", Kind: types.KindFunction, Package: storePkg, File: "internal/store/store_test.go", Line: 10, Test: true},
+		{ID: 32, Name: "stale", Kind: types.KindFunction, Package: storePkg, File: "internal/store/store_test.go", Line: 12, Test: true},
+		{ID: 33, Name: "Testify", Kind: types.KindFunction, Package: storePkg, File: "internal/store/store_test.go", Line: 14, Test: true},
+
+		// An importable package
+		{ID: 40, Name: "Client", Kind: types.KindStruct, Package: apiPkg, PackageName: "api", File: "api/client.go", Line: 3},
+	}
+
+	type result struct {
+		Name       string
+		Confidence string
+		TestOnly   bool
+		Members    int
+	}
+	summarize := func(findings []Finding) []result {
+		results := make([]result, len(findings))
+		for i, f := range findings {
+			results[i] = result{f.Symbol.Name, f.Confidence, f.TestOnly, f.Members}
+			assert.NotEmpty(t, f.Reason, f.Symbol.Name)
+		}
+		return results
+	}
+
+	assert.Equal(t, []result{
+		{Name: "Client", Confidence: ConfidenceMedium},
+		{Name: "Usage", Confidence: ConfidenceHigh},
+		{Name: "Flush", Confidence: ConfidenceLow}, // Interface method
+		{Name: "legacy", Confidence: ConfidenceHigh, Members: 2},
+		{Name: "maxSize", Confidence: ConfidenceHigh},
+		{Name: "count", Confidence: ConfidenceMedium},
+		{Name: "Name", Confidence: ConfidenceLow},
+		{Name: "String", Confidence: ConfidenceLow},
+		{Name: "reset", Confidence: ConfidenceHigh},
+		{Name: "Flush", Confidence: ConfidenceLow}, // May implement Cache
+		{Name: "fixture", Confidence: ConfidenceMedium, TestOnly: true},
+	}, summarize(Find(symbols, Options{})))
+
+	// Test files on request; entry points and helpers used by tests stay out
+	var inTests []result
+	for _, r := range summarize(Find(symbols, Options{IncludeTests: true})) {
+		if r.Name == "stale" || r.Name == "Testify" || r.Name == "helper" || r.Name == "TestStore" {
+			inTests = append(inTests, r)
+		}
+	}
+	assert.Equal(t, []result{
+		{Name: "stale", Confidence: ConfidenceHigh},
+		{Name: "Testify", Confidence: ConfidenceHigh}, // Not a test: a lowercase letter follows Test
+	}, inTests)
+}
+
+func TestFind_Reasons(t *testing.T) {
+	symbols := []*Symbol{
+		{ID: 1, Name: "Cache", Kind: types.KindInterface, Package: storePkg},
+		{ID: 2, Name: "Evict", Kind: types.KindMethod, Receiver: "Cache", Package: storePkg},
+		{ID: 3, Name: "LRU", Kind: types.KindStruct, Package: storePkg},
+		{ID: 4, Name: "Evict", Kind: types.KindMethod, Receiver: "LRU", Package: storePkg},
+		{ID: 5, Name: "New", Kind: types.KindFunction, Package: storePkg, Refs: []types.Ref{
+			{Kind: types.RefResult, Name: "Cache"},
+			{Kind: types.RefType, Name: "LRU"},
+		}},
+	}
+
+	reasons := make(map[string]string)
+	for _, f := range Find(symbols, Options{}) {
+		reasons[f.Symbol.Receiver+"."+f.Symbol.Name] = f.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"Cache.Evict": "interface method never called; implementations may still need it",
+		"LRU.Evict":   "never called by name, but may implement Cache",
+		".New":        "exported from a package only the module can import, and unused",
+	}, reasons)
+}
+
+func TestFind_FSImplementation(t *testing.T) {
+	const repoPkg = "example.com/shop/internal/gitrepo"
+	symbols := []*Symbol{
+		// CommitFS returns a tree as an fs.FS; the io/fs functions call its methods
+		{ID: 1, Name: "CommitFS", Kind: types.KindFunction, Package: repoPkg, Refs: []types.Ref{
+			{Kind: types.RefResult, Package: "io/fs", Name: "FS"},
+			{Kind: types.RefType, Name: "treeFS"},
+		}},
+		{ID: 2, Name: "treeFS", Kind: types.KindStruct, Package: repoPkg},
+		{ID: 3, Name: "Open", Kind: types.KindMethod, Receiver: "treeFS", Package: repoPkg, Refs: []types.Ref{
+			{Kind: types.RefType, Name: "fileInfo"},
+		}},
+		{ID: 4, Name: "ReadFile", Kind: types.KindMethod, Receiver: "treeFS", Package: repoPkg},
+		{ID: 5, Name: "ReadDir", Kind: types.KindMethod, Receiver: "treeFS", Package: repoPkg},
+		{ID: 6, Name: "Stat", Kind: types.KindMethod, Receiver: "treeFS", Package: repoPkg},
+		{ID: 7, Name: "Walk", Kind: types.KindMethod, Receiver: "treeFS", Package: repoPkg},
+		{ID: 8, Name: "fileInfo", Kind: types.KindStruct, Package: repoPkg},
+		{ID: 9, Name: "Sys", Kind: types.KindMethod, Receiver: "fileInfo", Package: repoPkg},
+		{ID: 10, Name: "Info", Kind: types.KindMethod, Receiver: "fileInfo", Package: repoPkg},
+		{ID: 11, Name: "Type", Kind: types.KindMethod, Receiver: "fileInfo", Package: repoPkg},
+		{ID: 12, Name: "ModTime", Kind: types.KindMethod, Receiver: "fileInfo", Package: repoPkg},
+		{ID: 13, Name: "Set", Kind: types.KindMethod, Receiver: "fileInfo", Package: repoPkg},
+	}
+
+	reasons := make(map[string]string)
+	for _, f := range Find(symbols, Options{}) {
+		reasons[f.Symbol.Receiver+"."+f.Symbol.Name] = f.Confidence + ": " + f.Reason
+	}
+	assert.Equal(t, map[string]string{
+		".CommitFS":        "high: exported from a package only the module can import, and unused",
+		"treeFS.Open":      "low: never called by name, but may implement fs.FS",
+		"treeFS.ReadFile":  "low: never called by name, but may implement fs.ReadFileFS",
+		"treeFS.ReadDir":   "low: never called by name, but may implement fs.ReadDirFS",
+		"treeFS.Stat":      "low: never called by name, but may implement fs.StatFS",
+		"treeFS.Walk":      "medium: exported method never called or selected; it may implement an interface of another module, which the index does not see",
+		"fileInfo.Sys":     "low: never called by name, but may implement fs.FileInfo",
+		"fileInfo.Info":    "low: never called by name, but may implement fs.DirEntry",
+		"fileInfo.Type":    "low: never called by name, but may implement fs.DirEntry",
+		"fileInfo.ModTime": "low: never called by name, but may implement fs.FileInfo",
+		"fileInfo.Set":     "low: never called by name, but may implement flag.Value",
+	}, reasons)
+}
+
+func TestFind_MethodsThroughFields(t *testing.T) {
+	symbols := []*Symbol{
+		{ID: 1, Name: "Handler", Kind: types.KindStruct, Package: storePkg},
+		{ID: 2, Name: "orders", Kind: types.KindField, Receiver: "Handler", Package: storePkg, Refs: []types.Ref{
+			{Kind: types.RefType, Name: "OrderRepo"},
+		}},
+		{ID: 3, Name: "cache", Kind: types.KindField, Receiver: "Handler", Package: storePkg, Refs: []types.Ref{
+			{Kind: types.RefType, Name: "Cache"},
+		}},
+		{ID: 4, Name: "Serve", Kind: types.KindMethod, Receiver: "Handler", Package: storePkg, Refs: []types.Ref{
+			{Kind: types.RefSelect, Name: "orders"},
+			{Kind: types.RefSelect, Name: "cache"},
+			{Kind: types.RefMethod, Name: "Save", Via: "orders"},
+			{Kind: types.RefMethod, Name: "Fetch", Via: "cache"},
+		}},
+		{ID: 5, Name: "New", Kind: types.KindFunction, Package: cmdPkg, PackageName: "main", Refs: []types.Ref{
+			{Kind: types.RefType, Package: storePkg, Name: "Handler"},
+			{Kind: types.RefType, Package: storePkg, Name: "FileRepo"},
+			{Kind: types.RefType, Package: storePkg, Name: "memCache"},
+			{Kind: types.RefMethod, Name: "Serve"},
+		}},
+
+		// Save is called on the orders field, so only OrderRepo's is used
+		{ID: 10, Name: "OrderRepo", Kind: types.KindStruct, Package: storePkg},
+		{ID: 11, Name: "Save", Kind: types.KindMethod, Receiver: "OrderRepo", Package: storePkg},
+		{ID: 12, Name: "FileRepo", Kind: types.KindStruct, Package: storePkg},
+		{ID: 13, Name: "Save", Kind: types.KindMethod, Receiver: "FileRepo", Package: storePkg},
+
+		// Fetch is called on an interface field, so any Fetch may be used
+		{ID: 20, Name: "Cache", Kind: types.KindInterface, Package: storePkg},
+		{ID: 21, Name: "Fetch", Kind: types.KindMethod, Receiver: "Cache", Package: storePkg},
+		{ID: 22, Name: "memCache", Kind: types.KindStruct, Package: storePkg},
+		{ID: 23, Name: "Fetch", Kind: types.KindMethod, Receiver: "memCache", Package: storePkg},
+	}
+
+	var unused []string
+	for _, f := range Find(symbols, Options{}) {
+		unused = append(unused, f.Symbol.Receiver+"."+f.Symbol.Name)
+	}
+	assert.Equal(t, []string{".New", "FileRepo.Save"}, unused)
+}
@@ -0,0 +1,38 @@
+// Package deadcode reports the declarations of a project nothing uses.
+//
+// Find resolves the references the parser records on every symbol, without
+// type information, and reports each unused declaration with a confidence:
+//
+//	high    unexported, or exported from package main or an internal package,
+//	        and not used in the package or module
+//	medium  exported from an importable package, where importers outside the
+//	        indexed module are not seen; other exported methods, which may
+//	        implement an interface of another module; unexported fields, which
+//	        positional composite literals may set; declarations used only by tests
+//	low     methods that may implement an interface of the project or a common
+//	        standard library one (String, ServeHTTP, Open, ReadFile, ...), interface
+//	        methods, and exported or tagged fields that encoders and templates
+//	        may reach by reflection
+//
+// Unqualified names resolve to the package-level declarations of the user's own
+// package and qualified names to those of the imported package. A method called
+// on a field of the receiver, as in h.orders.Save, resolves to the method of the
+// field's type when that is a concrete type of the project. Other fields and
+// methods are resolved by name alone: a method is used when any code selects or
+// calls a method of that name, on any type. Resolution errs toward used, so a
+// report can miss dead code but rarely flags live code; an empty report does not
+// prove that every method is live. A declaration used only by other unused
+// declarations is not reported.
+//
+// Entry points are never reported: main in package main, init, and the Test,
+// Benchmark, Fuzz and Example functions and TestMain of test files. Declarations
+// in test files are only reported with IncludeTests. The fields and methods of
+// an unused type are folded into the type's finding.
+//
+// # Basic Usage
+//
+//	findings := deadcode.Find(symbols, deadcode.Options{})
+//	for _, f := range findings {
+//	    fmt.Println(f.Symbol.Package, f.Symbol.Name, f.Confidence, f.Reason)
+//	}
+package deadcode
@@ -3,6 +3,7 @@ package embedder
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -11,6 +12,9 @@ const (
 	EnvEmbeddingProvider = "GOCONTEXT_EMBEDDING_PROVIDER"
 	EnvJinaAPIKey        = "JINA_API_KEY"
 	EnvOpenAIAPIKey      = "OPENAI_API_KEY"
+
+	// EnvEmbeddingCacheSize overrides the maximum number of entries in the persistent cache
+	EnvEmbeddingCacheSize = "GOCONTEXT_EMBEDDING_CACHE_SIZE"
 )
 
 // Config holds embedder configuration
@@ -95,3 +99,13 @@ func DetectProvider() string {
 
 	return ProviderLocal
 }
+
+// PersistentCacheSizeFromEnv returns the persistent cache size from the environment,
+// falling back to DefaultPersistentCacheSize when unset or invalid
+func PersistentCacheSizeFromEnv() int {
+	size, err := strconv.Atoi(os.Getenv(EnvEmbeddingCacheSize))
+	if err != nil || size <= 0 {
+		return DefaultPersistentCacheSize
+	}
+	return size
+}
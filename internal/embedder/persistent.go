@@ -0,0 +1,194 @@
+package embedder
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+const (
+	// DefaultPersistentCacheSize is the default maximum number of entries in the on-disk cache
+	DefaultPersistentCacheSize = 100000
+
+	// pruneInterval is the number of cache writes between eviction passes
+	pruneInterval = 500
+)
+
+// PersistentCache stores embeddings across process restarts.
+// Entries are keyed by provider, model and content hash (see ComputeHash).
+type PersistentCache interface {
+	// GetCachedEmbeddings returns the cached vectors of the hashes it holds, by hash
+	GetCachedEmbeddings(ctx context.Context, provider, model string, textHashes []string) (map[string][]float32, error)
+
+	// PutCachedEmbeddings stores or replaces cached vectors, by hash
+	PutCachedEmbeddings(ctx context.Context, provider, model string, vectors map[string][]float32) error
+
+	// PruneCachedEmbeddings evicts least recently used entries beyond maxEntries
+	PruneCachedEmbeddings(ctx context.Context, maxEntries int) (int, error)
+}
+
+// PersistentCachedEmbedder wraps any Embedder with a disk-backed cache.
+// Cache failures are logged and never fail an embedding request.
+type PersistentCachedEmbedder struct {
+	inner      Embedder
+	cache      PersistentCache
+	maxEntries int
+	writes     atomic.Int64
+}
+
+// NewPersistentCachedEmbedder wraps inner with a persistent cache holding at most maxEntries vectors
+func NewPersistentCachedEmbedder(inner Embedder, cache PersistentCache, maxEntries int) *PersistentCachedEmbedder {
+	if maxEntries <= 0 {
+		maxEntries = DefaultPersistentCacheSize
+	}
+	return &PersistentCachedEmbedder{
+		inner:      inner,
+		cache:      cache,
+		maxEntries: maxEntries,
+	}
+}
+
+// GenerateEmbedding returns a cached embedding or generates and caches a new one
+func (p *PersistentCachedEmbedder) GenerateEmbedding(ctx context.Context, req EmbeddingRequest) (*Embedding, error) {
+	if err := ValidateRequest(req); err != nil {
+		return nil, err
+	}
+
+	model := p.modelFor(req.Model)
+	hash := ComputeHash(req.Text)
+	if emb := p.lookup(ctx, model, []string{hash})[hash]; emb != nil {
+		return emb, nil
+	}
+
+	emb, err := p.inner.GenerateEmbedding(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.store(ctx, model, map[string][]float32{hash: emb.Vector})
+	return emb, nil
+}
+
+// GenerateBatch serves cached texts from disk and only sends misses to the wrapped provider
+func (p *PersistentCachedEmbedder) GenerateBatch(ctx context.Context, req BatchEmbeddingRequest) (*BatchEmbeddingResponse, error) {
+	if err := ValidateBatchRequest(req); err != nil {
+		return nil, err
+	}
+
+	model := p.modelFor(req.Model)
+	embeddings := make([]*Embedding, len(req.Texts))
+	hashes := make([]string, len(req.Texts))
+	for i, text := range req.Texts {
+		hashes[i] = ComputeHash(text)
+	}
+	cached := p.lookup(ctx, model, hashes)
+
+	var missTexts []string
+	var missIdx []int
+	for i, text := range req.Texts {
+		if emb := cached[hashes[i]]; emb != nil {
+			embeddings[i] = emb
+			continue
+		}
+		missTexts = append(missTexts, text)
+		missIdx = append(missIdx, i)
+	}
+
+	provider := p.inner.Provider()
+	if len(missTexts) > 0 {
+		resp, err := p.inner.GenerateBatch(ctx, BatchEmbeddingRequest{Texts: missTexts, Model: req.Model})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Embeddings) != len(missTexts) {
+			return nil, fmt.Errorf("%w: expected %d embeddings, got %d", ErrProviderFailed, len(missTexts), len(resp.Embeddings))
+		}
+
+		fresh := make(map[string][]float32, len(missTexts))
+		for j, emb := range resp.Embeddings {
+			embeddings[missIdx[j]] = emb
+			fresh[hashes[missIdx[j]]] = emb.Vector
+		}
+		p.store(ctx, model, fresh)
+
+		if resp.Provider != "" {
+			provider = resp.Provider
+		}
+		if resp.Model != "" {
+			model = resp.Model
+		}
+	}
+
+	return &BatchEmbeddingResponse{
+		Embeddings: embeddings,
+		Provider:   provider,
+		Model:      model,
+	}, nil
+}
+
+// Dimension returns the wrapped provider's embedding dimension
+func (p *PersistentCachedEmbedder) Dimension() int {
+	return p.inner.Dimension()
+}
+
+// Provider returns the wrapped provider name
+func (p *PersistentCachedEmbedder) Provider() string {
+	return p.inner.Provider()
+}
+
+// Model returns the wrapped provider's model name
+func (p *PersistentCachedEmbedder) Model() string {
+	return p.inner.Model()
+}
+
+// Close closes the wrapped provider. The cache is owned by the caller.
+func (p *PersistentCachedEmbedder) Close() error {
+	return p.inner.Close()
+}
+
+// modelFor resolves the model used for cache keys
+func (p *PersistentCachedEmbedder) modelFor(override string) string {
+	if override != "" {
+		return override
+	}
+	return p.inner.Model()
+}
+
+// lookup returns the cached embeddings of hashes, by hash, in one cache read;
+// misses are absent and a failed read misses everything
+func (p *PersistentCachedEmbedder) lookup(ctx context.Context, model string, hashes []string) map[string]*Embedding {
+	vectors, err := p.cache.GetCachedEmbeddings(ctx, p.inner.Provider(), model, hashes)
+	if err != nil {
+		log.Printf("Warning: embedding cache read failed: %v", err)
+		return nil
+	}
+
+	embeddings := make(map[string]*Embedding, len(vectors))
+	for hash, vector := range vectors {
+		embeddings[hash] = &Embedding{
+			Vector:    vector,
+			Dimension: len(vector),
+			Provider:  p.inner.Provider(),
+			Model:     model,
+			Hash:      hash,
+		}
+	}
+	return embeddings
+}
+
+// store writes vectors to the cache in one batch and periodically evicts old entries
+func (p *PersistentCachedEmbedder) store(ctx context.Context, model string, vectors map[string][]float32) {
+	if err := p.cache.PutCachedEmbeddings(ctx, p.inner.Provider(), model, vectors); err != nil {
+		log.Printf("Warning: embedding cache write failed: %v", err)
+		return
+	}
+
+	if p.writes.Add(int64(len(vectors))) < pruneInterval {
+		return
+	}
+	p.writes.Store(0)
+	if _, err := p.cache.PruneCachedEmbeddings(ctx, p.maxEntries); err != nil {
+		log.Printf("Warning: embedding cache eviction failed: %v", err)
+	}
+}
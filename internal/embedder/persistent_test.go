@@ -0,0 +1,202 @@
+package embedder
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// memoryPersistentCache is an in-memory PersistentCache for testing
+type memoryPersistentCache struct {
+	mu      sync.Mutex
+	entries map[string][]float32
+	getErr  error
+	reads   int
+	writes  int
+	prunes  int
+}
+
+func newMemoryPersistentCache() *memoryPersistentCache {
+	return &memoryPersistentCache{entries: make(map[string][]float32)}
+}
+
+func (m *memoryPersistentCache) key(provider, model, hash string) string {
+	return provider + "|" + model + "|" + hash
+}
+
+func (m *memoryPersistentCache) GetCachedEmbeddings(ctx context.Context, provider, model string, textHashes []string) (map[string][]float32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reads++
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	hits := make(map[string][]float32)
+	for _, hash := range textHashes {
+		if v, ok := m.entries[m.key(provider, model, hash)]; ok {
+			hits[hash] = v
+		}
+	}
+	return hits, nil
+}
+
+func (m *memoryPersistentCache) PutCachedEmbeddings(ctx context.Context, provider, model string, vectors map[string][]float32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writes++
+	for hash, vector := range vectors {
+		m.entries[m.key(provider, model, hash)] = vector
+	}
+	return nil
+}
+
+func (m *memoryPersistentCache) PruneCachedEmbeddings(ctx context.Context, maxEntries int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prunes++
+	return 0, nil
+}
+
+// countingEmbedder wraps LocalProvider and counts texts sent to it
+type countingEmbedder struct {
+	*LocalProvider
+	texts int
+}
+
+func newCountingEmbedder(t *testing.T) *countingEmbedder {
+	local, err := NewLocalProvider(nil)
+	if err != nil {
+		t.Fatalf("NewLocalProvider() error = %v", err)
+	}
+	return &countingEmbedder{LocalProvider: local}
+}
+
+func (c *countingEmbedder) GenerateEmbedding(ctx context.Context, req EmbeddingRequest) (*Embedding, error) {
+	c.texts++
+	return c.LocalProvider.GenerateEmbedding(ctx, req)
+}
+
+func (c *countingEmbedder) GenerateBatch(ctx context.Context, req BatchEmbeddingRequest) (*BatchEmbeddingResponse, error) {
+	c.texts += len(req.Texts)
+	return c.LocalProvider.GenerateBatch(ctx, req)
+}
+
+func TestPersistentCachedEmbedder_GenerateEmbedding(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingEmbedder(t)
+	cache := newMemoryPersistentCache()
+
+	emb := NewPersistentCachedEmbedder(inner, cache, 10)
+
+	first, err := emb.GenerateEmbedding(ctx, EmbeddingRequest{Text: "func main() {}"})
+	if err != nil {
+		t.Fatalf("GenerateEmbedding() error = %v", err)
+	}
+
+	// A new wrapper over the same cache simulates a process restart
+	restarted := NewPersistentCachedEmbedder(inner, cache, 10)
+	second, err := restarted.GenerateEmbedding(ctx, EmbeddingRequest{Text: "func main() {}"})
+	if err != nil {
+		t.Fatalf("GenerateEmbedding() error = %v", err)
+	}
+
+	if inner.texts != 1 {
+		t.Errorf("provider called for %d texts, want 1", inner.texts)
+	}
+	if len(second.Vector) != len(first.Vector) || second.Vector[0] != first.Vector[0] {
+		t.Errorf("cached vector differs from generated vector")
+	}
+	if second.Provider != ProviderLocal || second.Model != inner.Model() {
+		t.Errorf("cached embedding metadata = %s/%s", second.Provider, second.Model)
+	}
+}
+
+func TestPersistentCachedEmbedder_GenerateBatchOnlySendsMisses(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingEmbedder(t)
+	cache := newMemoryPersistentCache()
+	emb := NewPersistentCachedEmbedder(inner, cache, 10)
+
+	if _, err := emb.GenerateEmbedding(ctx, EmbeddingRequest{Text: "b"}); err != nil {
+		t.Fatalf("GenerateEmbedding() error = %v", err)
+	}
+
+	resp, err := emb.GenerateBatch(ctx, BatchEmbeddingRequest{Texts: []string{"a", "b", "c"}})
+	if err != nil {
+		t.Fatalf("GenerateBatch() error = %v", err)
+	}
+
+	if len(resp.Embeddings) != 3 {
+		t.Fatalf("got %d embeddings, want 3", len(resp.Embeddings))
+	}
+	for i, e := range resp.Embeddings {
+		if e == nil {
+			t.Fatalf("embedding %d is nil", i)
+		}
+	}
+	if resp.Embeddings[1].Hash != ComputeHash("b") {
+		t.Errorf("embedding order not preserved")
+	}
+	// 1 for the single request + 2 misses in the batch
+	if inner.texts != 3 {
+		t.Errorf("provider called for %d texts, want 3", inner.texts)
+	}
+	// One read and one write per request, not per text
+	if cache.reads != 2 || cache.writes != 2 {
+		t.Errorf("cache read %d and written %d times, want 2 and 2", cache.reads, cache.writes)
+	}
+}
+
+func TestPersistentCachedEmbedder_CacheErrorsAreNotFatal(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingEmbedder(t)
+	cache := newMemoryPersistentCache()
+	cache.getErr = errors.New("disk unavailable")
+
+	emb := NewPersistentCachedEmbedder(inner, cache, 10)
+	if _, err := emb.GenerateEmbedding(ctx, EmbeddingRequest{Text: "x"}); err != nil {
+		t.Fatalf("GenerateEmbedding() error = %v, want fallback to provider", err)
+	}
+	if inner.texts != 1 {
+		t.Errorf("provider called for %d texts, want 1", inner.texts)
+	}
+}
+
+func TestPersistentCachedEmbedder_PrunesPeriodically(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingEmbedder(t)
+	cache := newMemoryPersistentCache()
+	emb := NewPersistentCachedEmbedder(inner, cache, 10)
+
+	texts := make([]string, MaxBatchSize)
+	for round := 0; round < pruneInterval/MaxBatchSize; round++ {
+		for i := range texts {
+			texts[i] = string(rune('A'+round)) + string(rune('a'+i%26)) + string(rune('0'+i/26))
+		}
+		if _, err := emb.GenerateBatch(ctx, BatchEmbeddingRequest{Texts: texts}); err != nil {
+			t.Fatalf("GenerateBatch() error = %v", err)
+		}
+	}
+
+	if cache.prunes != 1 {
+		t.Errorf("prune called %d times, want 1", cache.prunes)
+	}
+}
+
+func TestPersistentCacheSizeFromEnv(t *testing.T) {
+	t.Setenv(EnvEmbeddingCacheSize, "")
+	if got := PersistentCacheSizeFromEnv(); got != DefaultPersistentCacheSize {
+		t.Errorf("default size = %d, want %d", got, DefaultPersistentCacheSize)
+	}
+
+	t.Setenv(EnvEmbeddingCacheSize, "250")
+	if got := PersistentCacheSizeFromEnv(); got != 250 {
+		t.Errorf("size = %d, want 250", got)
+	}
+
+	t.Setenv(EnvEmbeddingCacheSize, "not-a-number")
+	if got := PersistentCacheSizeFromEnv(); got != DefaultPersistentCacheSize {
+		t.Errorf("invalid size = %d, want default", got)
+	}
+}
@@ -0,0 +1,227 @@
+package gitrepo
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signature is the author or committer line of a commit
+type Signature struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+// Commit is a parsed commit object
+type Commit struct {
+	Hash      Hash
+	Tree      Hash
+	Parents   []Hash
+	Author    Signature
+	Committer Signature
+	Message   string
+}
+
+// Summary returns the first line of the commit message
+func (c *Commit) Summary() string {
+	summary, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return summary
+}
+
+// TreeEntry is one entry of a tree object
+type TreeEntry struct {
+	Name string
+	Mode uint32 // Octal file mode, e.g. 0o100644 or 0o40000
+	Hash Hash
+}
+
+// Tree entry modes
+const (
+	ModeTree       uint32 = 0o40000
+	ModeFile       uint32 = 0o100644
+	ModeExecutable uint32 = 0o100755
+	ModeSymlink    uint32 = 0o120000
+	ModeSubmodule  uint32 = 0o160000
+)
+
+// IsDir reports whether the entry is a subtree
+func (e TreeEntry) IsDir() bool {
+	return e.Mode == ModeTree
+}
+
+// IsFile reports whether the entry is a regular (possibly executable) file
+func (e TreeEntry) IsFile() bool {
+	return e.Mode == ModeFile || e.Mode == ModeExecutable
+}
+
+// Commit reads and parses a commit object
+func (r *Repo) Commit(h Hash) (*Commit, error) {
+	typ, data, err := r.readObject(h)
+	if err != nil {
+		return nil, err
+	}
+	if typ != ObjectCommit {
+		return nil, fmt.Errorf("%s is a %s, not a commit", h, typ)
+	}
+	c, err := parseCommit(data)
+	if err != nil {
+		return nil, fmt.Errorf("commit %s: %w", h, err)
+	}
+	c.Hash = h
+	return c, nil
+}
+
+// Tree reads a tree object. Trees are cached, so callers must not modify the result.
+func (r *Repo) Tree(h Hash) ([]TreeEntry, error) {
+	r.mu.Lock()
+	entries, ok := r.trees[h]
+	r.mu.Unlock()
+	if ok {
+		return entries, nil
+	}
+
+	typ, data, err := r.readObject(h)
+	if err != nil {
+		return nil, err
+	}
+	if typ != ObjectTree {
+		return nil, fmt.Errorf("%s is a %s, not a tree", h, typ)
+	}
+	if entries, err = parseTree(data); err != nil {
+		return nil, fmt.Errorf("tree %s: %w", h, err)
+	}
+
+	r.mu.Lock()
+	r.trees[h] = entries
+	r.mu.Unlock()
+	return entries, nil
+}
+
+// Blob reads the content of a blob object. The caller owns the returned slice.
+func (r *Repo) Blob(h Hash) ([]byte, error) {
+	typ, data, err := r.readObject(h)
+	if err != nil {
+		return nil, err
+	}
+	if typ != ObjectBlob {
+		return nil, fmt.Errorf("%s is a %s, not a blob", h, typ)
+	}
+	// Packed objects are shared with the delta base cache
+	return bytes.Clone(data), nil
+}
+
+func parseCommit(data []byte) (*Commit, error) {
+	c := &Commit{}
+	header, message, _ := bytes.Cut(data, []byte("\n\n"))
+	c.Message = string(message)
+
+	for _, line := range strings.Split(string(header), "\n") {
+		// Continuation lines of multi-line headers (gpgsig, mergetag) start with a space
+		if line == "" || line[0] == ' ' {
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		var err error
+		switch key {
+		case "tree":
+			c.Tree, err = ParseHash(value)
+		case "parent":
+			var p Hash
+			if p, err = ParseHash(value); err == nil {
+				c.Parents = append(c.Parents, p)
+			}
+		case "author":
+			c.Author, err = parseSignature(value)
+		case "committer":
+			c.Committer, err = parseSignature(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s header: %w", key, err)
+		}
+	}
+
+	if c.Tree.IsZero() {
+		return nil, fmt.Errorf("missing tree header")
+	}
+	return c, nil
+}
+
+// parseSignature parses "Name <email> 1700000000 +0100"
+func parseSignature(s string) (Signature, error) {
+	open := strings.LastIndexByte(s, '<')
+	closing := strings.LastIndexByte(s, '>')
+	if open < 0 || closing < open {
+		return Signature{}, fmt.Errorf("malformed signature %q", s)
+	}
+
+	sig := Signature{
+		Name:  strings.TrimSpace(s[:open]),
+		Email: s[open+1 : closing],
+	}
+
+	fields := strings.Fields(s[closing+1:])
+	if len(fields) == 0 {
+		return sig, nil
+	}
+	secs, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return Signature{}, fmt.Errorf("malformed timestamp in %q", s)
+	}
+	loc := time.UTC
+	if len(fields) > 1 && len(fields[1]) == 5 {
+		tz := fields[1]
+		hours, err1 := strconv.Atoi(tz[1:3])
+		minutes, err2 := strconv.Atoi(tz[3:5])
+		if err1 == nil && err2 == nil {
+			offset := hours*3600 + minutes*60
+			if tz[0] == '-' {
+				offset = -offset
+			}
+			loc = time.FixedZone(tz, offset)
+		}
+	}
+	sig.When = time.Unix(secs, 0).In(loc)
+	return sig, nil
+}
+
+func parseTree(data []byte) ([]TreeEntry, error) {
+	var entries []TreeEntry
+	for len(data) > 0 {
+		sp := bytes.IndexByte(data, ' ')
+		if sp < 0 {
+			return nil, fmt.Errorf("malformed entry")
+		}
+		mode, err := strconv.ParseUint(string(data[:sp]), 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("malformed mode %q", data[:sp])
+		}
+		data = data[sp+1:]
+
+		nul := bytes.IndexByte(data, 0)
+		if nul < 0 || nul+1+len(Hash{}) > len(data) {
+			return nil, fmt.Errorf("malformed entry")
+		}
+		entry := TreeEntry{Name: string(data[:nul]), Mode: uint32(mode)}
+		copy(entry.Hash[:], data[nul+1:])
+		data = data[nul+1+len(Hash{}):]
+
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseTagTarget returns the object an annotated tag points at
+func parseTagTarget(data []byte) (Hash, error) {
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			break
+		}
+		if target, ok := strings.CutPrefix(line, "object "); ok {
+			return ParseHash(target)
+		}
+	}
+	return Hash{}, fmt.Errorf("missing object header")
+}
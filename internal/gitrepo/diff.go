@@ -0,0 +1,246 @@
+package gitrepo
+
+import (
+	"path"
+	"sort"
+)
+
+// Change is a regular file added, removed or modified between two trees.
+// From is zero for added files and To is zero for removed ones.
+type Change struct {
+	Path string // Slash-separated path from the tree root
+	From Hash
+	To   Hash
+}
+
+// DiffTrees lists the files that differ between two trees; from may be the zero
+// hash to diff against the empty tree. Identical subtrees are skipped without
+// being read, so the cost follows the size of the change.
+func (r *Repo) DiffTrees(from, to Hash) ([]Change, error) {
+	var changes []Change
+	if err := r.diffTrees("", from, to, &changes); err != nil {
+		return nil, err
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+func (r *Repo) diffTrees(prefix string, from, to Hash, changes *[]Change) error {
+	if from == to {
+		return nil
+	}
+	a, err := r.treeOrEmpty(from)
+	if err != nil {
+		return err
+	}
+	b, err := r.treeOrEmpty(to)
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string][2]TreeEntry, len(a)+len(b))
+	for _, e := range a {
+		pair := byName[e.Name]
+		pair[0] = e
+		byName[e.Name] = pair
+	}
+	for _, e := range b {
+		pair := byName[e.Name]
+		pair[1] = e
+		byName[e.Name] = pair
+	}
+
+	for name, pair := range byName {
+		old, cur := pair[0], pair[1]
+		p := path.Join(prefix, name)
+
+		// Subtrees on either side are recursed into against the other side's subtree, if any
+		var oldTree, curTree Hash
+		if old.IsDir() {
+			oldTree = old.Hash
+		}
+		if cur.IsDir() {
+			curTree = cur.Hash
+		}
+		if !oldTree.IsZero() || !curTree.IsZero() {
+			if err := r.diffTrees(p, oldTree, curTree, changes); err != nil {
+				return err
+			}
+		}
+
+		var oldBlob, curBlob Hash
+		if old.IsFile() {
+			oldBlob = old.Hash
+		}
+		if cur.IsFile() {
+			curBlob = cur.Hash
+		}
+		if oldBlob != curBlob {
+			*changes = append(*changes, Change{Path: p, From: oldBlob, To: curBlob})
+		}
+	}
+	return nil
+}
+
+func (r *Repo) treeOrEmpty(h Hash) ([]TreeEntry, error) {
+	if h.IsZero() {
+		return nil, nil
+	}
+	return r.Tree(h)
+}
+
+// Hunk is a run of changed lines between two versions of a file. Line numbers are
+// 0-based; a pure insertion has OldLines == 0 and a pure deletion NewLines == 0.
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+}
+
+// maxEditDistance bounds the work done by DiffLines. Beyond it the differing
+// region is reported as one hunk, as if the file had been rewritten.
+const maxEditDistance = 2000
+
+// DiffLines returns the hunks turning a into b, using Myers' algorithm after
+// trimming the common prefix and suffix
+func DiffLines(a, b []string) []Hunk {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	ops := myers(internLines(a, b))
+	if ops == nil {
+		return []Hunk{{OldStart: prefix, OldLines: len(a), NewStart: prefix, NewLines: len(b)}}
+	}
+
+	var hunks []Hunk
+	x, y := prefix, prefix
+	for i := 0; i < len(ops); {
+		if ops[i] == opEqual {
+			x, y = x+1, y+1
+			i++
+			continue
+		}
+		h := Hunk{OldStart: x, NewStart: y}
+		for ; i < len(ops) && ops[i] != opEqual; i++ {
+			if ops[i] == opDelete {
+				h.OldLines++
+				x++
+			} else {
+				h.NewLines++
+				y++
+			}
+		}
+		hunks = append(hunks, h)
+	}
+	return hunks
+}
+
+// internLines maps lines to integers so the diff compares ints instead of strings
+func internLines(a, b []string) ([]int, []int) {
+	ids := make(map[string]int, len(a)+len(b))
+	intern := func(lines []string) []int {
+		out := make([]int, len(lines))
+		for i, l := range lines {
+			id, ok := ids[l]
+			if !ok {
+				id = len(ids)
+				ids[l] = id
+			}
+			out[i] = id
+		}
+		return out
+	}
+	return intern(a), intern(b)
+}
+
+const (
+	opEqual byte = iota
+	opDelete
+	opInsert
+)
+
+// myers returns the shortest edit script from a to b, or nil if it is longer than maxEditDistance
+func myers(a, b []int) []byte {
+	n, m := len(a), len(b)
+	limit := n + m
+	if limit > maxEditDistance {
+		limit = maxEditDistance
+	}
+
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	trace := make([][]int, 0, 16) // trace[d][k+d] is the furthest x on diagonal k after d edits
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+				return backtrack(trace, n, m)
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+	return nil
+}
+
+// backtrack walks the Myers trace from (n, m) back to the origin
+func backtrack(trace [][]int, n, m int) []byte {
+	ops := make([]byte, 0, n+m)
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		at := func(k int) int { return prev[k+d-1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, opEqual)
+			x, y = x-1, y-1
+		}
+		if x == prevX {
+			ops = append(ops, opInsert)
+			y--
+		} else {
+			ops = append(ops, opDelete)
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, opEqual)
+		x, y = x-1, y-1
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
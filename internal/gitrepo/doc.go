@@ -0,0 +1,28 @@
+// Package gitrepo reads commits, trees and blobs straight from a local git
+// object database.
+//
+// It is a small, read-only, pure-Go reader: no git binary and no network
+// access is needed. Loose objects, packfiles (version 2 indexes, offset and
+// reference deltas), loose and packed refs and linked worktrees are supported.
+// SHA-256 repositories are not.
+//
+// A commit's tree is exposed as an fs.FS, so code that walks the working tree
+// with fs.WalkDir and fs.ReadFile can index a revision unchanged.
+//
+// # Basic Usage
+//
+//	repo, err := gitrepo.Open("/path/to/repo")
+//	if err != nil {
+//	    return err
+//	}
+//	hash, err := repo.ResolveRevision("main~2")
+//	if err != nil {
+//	    return err
+//	}
+//	commit, err := repo.Commit(hash)
+//	if err != nil {
+//	    return err
+//	}
+//	fsys := repo.CommitFS(commit)
+//	data, err := fs.ReadFile(fsys, "cmd/server/main.go")
+package gitrepo
@@ -0,0 +1,217 @@
+package gitrepo
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TreeFS is a read-only fs.FS over the tree of a commit. Regular files are
+// exposed with their blob content; symlinks and submodules are omitted.
+// Every entry reports the commit time as its modification time.
+type TreeFS struct {
+	repo    *Repo
+	root    Hash
+	modTime time.Time
+}
+
+var (
+	_ fs.ReadDirFS  = (*TreeFS)(nil)
+	_ fs.ReadFileFS = (*TreeFS)(nil)
+	_ fs.StatFS     = (*TreeFS)(nil)
+)
+
+// CommitFS returns the file system of a commit's tree
+func (r *Repo) CommitFS(c *Commit) *TreeFS {
+	return &TreeFS{repo: r, root: c.Tree, modTime: c.Committer.When}
+}
+
+// lookup walks name from the root tree and returns its entry
+func (t *TreeFS) lookup(op, name string) (TreeEntry, error) {
+	if !fs.ValidPath(name) {
+		return TreeEntry{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	entry := TreeEntry{Name: ".", Mode: ModeTree, Hash: t.root}
+	if name == "." {
+		return entry, nil
+	}
+
+	for _, part := range strings.Split(name, "/") {
+		if !entry.IsDir() {
+			return TreeEntry{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		entries, err := t.repo.Tree(entry.Hash)
+		if err != nil {
+			return TreeEntry{}, &fs.PathError{Op: op, Path: name, Err: err}
+		}
+		found := false
+		for _, e := range entries {
+			if e.Name == part && (e.IsDir() || e.IsFile()) {
+				entry, found = e, true
+				break
+			}
+		}
+		if !found {
+			return TreeEntry{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+	}
+	return entry, nil
+}
+
+// Open implements fs.FS
+func (t *TreeFS) Open(name string) (fs.File, error) {
+	entry, err := t.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	info := &fileInfo{fsys: t, entry: entry, name: path.Base(name)}
+
+	if entry.IsDir() {
+		entries, err := t.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &dirFile{info: info, entries: entries}, nil
+	}
+
+	data, err := t.repo.Blob(entry.Hash)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	info.size = int64(len(data))
+	info.sizeKnown = true
+	return &blobFile{info: info, Reader: bytes.NewReader(data)}, nil
+}
+
+// ReadDir implements fs.ReadDirFS; entries are sorted by name
+func (t *TreeFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entry, err := t.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	entries, err := t.repo.Tree(entry.Hash)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	out := make([]fs.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && !e.IsFile() {
+			continue
+		}
+		out = append(out, fs.FileInfoToDirEntry(&fileInfo{fsys: t, entry: e, name: e.Name}))
+	}
+	// Git sorts directories as if their names ended in "/"; fs.WalkDir expects plain name order
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out, nil
+}
+
+// ReadFile implements fs.ReadFileFS
+func (t *TreeFS) ReadFile(name string) ([]byte, error) {
+	entry, err := t.lookup("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	if entry.IsDir() {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	data, err := t.repo.Blob(entry.Hash)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	return data, nil
+}
+
+// Stat implements fs.StatFS
+func (t *TreeFS) Stat(name string) (fs.FileInfo, error) {
+	entry, err := t.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo{fsys: t, entry: entry, name: path.Base(name)}, nil
+}
+
+// fileInfo describes a tree entry; blob sizes are looked up on first use
+type fileInfo struct {
+	fsys      *TreeFS
+	entry     TreeEntry
+	name      string
+	size      int64
+	sizeKnown bool
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) ModTime() time.Time { return fi.fsys.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.entry.IsDir() }
+func (fi *fileInfo) Sys() any           { return fi.entry }
+
+func (fi *fileInfo) Mode() fs.FileMode {
+	switch {
+	case fi.entry.IsDir():
+		return fs.ModeDir | 0o755
+	case fi.entry.Mode == ModeExecutable:
+		return 0o755
+	default:
+		return 0o644
+	}
+}
+
+// Size returns the blob size, or 0 for directories and unreadable blobs
+func (fi *fileInfo) Size() int64 {
+	if fi.entry.IsDir() {
+		return 0
+	}
+	if !fi.sizeKnown {
+		size, err := fi.fsys.repo.objectSize(fi.entry.Hash)
+		if err != nil {
+			return 0
+		}
+		fi.size, fi.sizeKnown = size, true
+	}
+	return fi.size
+}
+
+type blobFile struct {
+	info *fileInfo
+	*bytes.Reader
+}
+
+func (f *blobFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *blobFile) Close() error               { return nil }
+
+type dirFile struct {
+	info    *fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *dirFile) Close() error               { return nil }
+
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir implements fs.ReadDirFile
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+	return remaining[:n], nil
+}
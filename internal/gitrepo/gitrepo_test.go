@@ -0,0 +1,297 @@
+package gitrepo
+
+import (
+	"io/fs"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gitCmd runs git in dir with a fixed identity and dates, skipping the test if git is missing
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Ada",
+		"GIT_AUTHOR_EMAIL=ada@example.com",
+		"GIT_AUTHOR_DATE=2024-03-01T10:00:00+01:00",
+		"GIT_COMMITTER_NAME=Ada",
+		"GIT_COMMITTER_EMAIL=ada@example.com",
+		"GIT_COMMITTER_DATE=2024-03-01T10:00:00+01:00",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %s: %s", strings.Join(args, " "), out)
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+// helpers pads util.go so that git stores its second version as a delta when packing
+var helpers = strings.Repeat("\n// Helper documents a function that is identical in both revisions.\nfunc Helper() int { return 42 }\n", 20)
+
+// setupRepo creates a repository with two commits on main and a v1 tag on the first
+func setupRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "-q", "-b", "main")
+
+	writeFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+	writeFile(t, dir, "internal/util/util.go", "package util\n\nfunc Old() {}\n"+helpers)
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "Initial commit")
+	gitCmd(t, dir, "tag", "-a", "v1", "-m", "Release v1")
+
+	writeFile(t, dir, "internal/util/util.go", "package util\n\nfunc New() {}\n"+helpers)
+	writeFile(t, dir, "internal/util/extra.go", "package util\n\nfunc Extra() {}\n")
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "Rename helper\n\nLonger description.")
+
+	// Dirty working tree that must not leak into revisions
+	writeFile(t, dir, "main.go", "package main\n\nfunc main() { panic(1) }\n")
+	return dir
+}
+
+func TestResolveRevision(t *testing.T) {
+	dir := setupRepo(t)
+	head := gitCmd(t, dir, "rev-parse", "HEAD")
+	first := gitCmd(t, dir, "rev-parse", "HEAD~1")
+
+	repo, err := Open(filepath.Join(dir, "internal"))
+	require.NoError(t, err)
+	defer func() { _ = repo.Close() }()
+
+	tests := []struct {
+		rev  string
+		want string
+	}{
+		{"HEAD", head},
+		{"main", head},
+		{"refs/heads/main", head},
+		{"HEAD~1", first},
+		{"main^", first},
+		{"v1", first},
+		{head, head},
+		{head[:8], head},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rev, func(t *testing.T) {
+			h, err := repo.ResolveRevision(tt.rev)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, h.String())
+		})
+	}
+
+	_, err = repo.ResolveRevision("no-such-branch")
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+	_, err = repo.ResolveRevision("HEAD~5")
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+}
+
+func TestResolveRevision_InvalidRefName(t *testing.T) {
+	dir := setupRepo(t)
+	head := gitCmd(t, dir, "rev-parse", "HEAD")
+	// A hash outside the git directory must not be reachable through the ref name
+	writeFile(t, dir, "outside", head+"\n")
+
+	repo, err := Open(dir)
+	require.NoError(t, err)
+	defer func() { _ = repo.Close() }()
+
+	for _, rev := range []string{"refs/../../outside", "../outside", "refs/heads/.hidden", "refs/heads/main.lock", "/etc/passwd"} {
+		_, err := repo.ResolveRevision(rev)
+		assert.ErrorIs(t, err, ErrRevisionNotFound, rev)
+	}
+
+	for name, want := range map[string]bool{
+		"HEAD":                 true,
+		"refs/heads/feature/x": true,
+		"refs/tags/v1.2.0":     true,
+		"refs/heads/a..b":      false,
+		"refs/heads/":          false,
+		"refs//heads":          false,
+		"refs/heads/x@{1}":     false,
+		"refs/heads/a b":       false,
+		"refs/heads/a\\b":      false,
+	} {
+		assert.Equal(t, want, validRefName(name), name)
+	}
+}
+
+func TestOpen_NotRepository(t *testing.T) {
+	_, err := Open(t.TempDir())
+	assert.ErrorIs(t, err, ErrNotRepository)
+}
+
+func testCommitFS(t *testing.T, dir string) {
+	repo, err := Open(dir)
+	require.NoError(t, err)
+	defer func() { _ = repo.Close() }()
+
+	h, err := repo.ResolveRevision("main")
+	require.NoError(t, err)
+	c, err := repo.Commit(h)
+	require.NoError(t, err)
+	assert.Equal(t, "Rename helper", c.Summary())
+	assert.Equal(t, "Ada", c.Author.Name)
+	assert.Equal(t, "ada@example.com", c.Author.Email)
+	assert.Equal(t, int64(1709283600), c.Committer.When.Unix())
+	require.Len(t, c.Parents, 1)
+
+	fsys := repo.CommitFS(c)
+	require.NoError(t, fstest.TestFS(fsys, "go.mod", "main.go", "internal/util/util.go", "internal/util/extra.go"))
+
+	data, err := fs.ReadFile(fsys, "main.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\nfunc main() {}\n", string(data), "revision content, not the dirty working tree")
+
+	info, err := fs.Stat(fsys, "internal/util/util.go")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("package util\n\nfunc New() {}\n"+helpers)), info.Size())
+	assert.True(t, info.ModTime().Equal(c.Committer.When))
+
+	// The parent revision has the old content and no extra.go
+	parent, err := repo.Commit(c.Parents[0])
+	require.NoError(t, err)
+	old := repo.CommitFS(parent)
+	data, err = fs.ReadFile(old, "internal/util/util.go")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "func Old()")
+	_, err = fs.Stat(old, "internal/util/extra.go")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestCommitFS_LooseObjects(t *testing.T) {
+	testCommitFS(t, setupRepo(t))
+}
+
+func TestCommitFS_PackedObjects(t *testing.T) {
+	dir := setupRepo(t)
+	// Aggressive repacking stores util.go's versions as deltas of each other
+	gitCmd(t, dir, "gc", "-q", "--aggressive", "--prune=now")
+	matches, err := filepath.Glob(filepath.Join(dir, ".git", "objects", "pack", "*.pack"))
+	require.NoError(t, err)
+	require.NotEmpty(t, matches)
+	_, err = os.Stat(filepath.Join(dir, ".git", "packed-refs"))
+	require.NoError(t, err, "gc should pack refs")
+
+	testCommitFS(t, dir)
+}
+
+func TestCommitFS_SkipsUnreadablePack(t *testing.T) {
+	dir := setupRepo(t)
+	gitCmd(t, dir, "gc", "-q", "--prune=now")
+	// Version 1 indexes have no header; they are not supported
+	writeFile(t, dir, ".git/objects/pack/pack-0000000000000000000000000000000000000000.idx", strings.Repeat("\x00", 256*4))
+
+	testCommitFS(t, dir)
+
+	repo, err := Open(dir)
+	require.NoError(t, err)
+	defer func() { _ = repo.Close() }()
+	_, _, err = repo.readObject(Hash{1})
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+	assert.ErrorContains(t, err, "pack-0000000000000000000000000000000000000000.idx: unsupported pack index format")
+}
+
+func TestApplyDelta(t *testing.T) {
+	base := []byte("hello, world")
+	delta := []byte{
+		12, 11, // source and target sizes
+		0x80 | 0x10, 7, // copy 7 bytes from offset 0: "hello, "
+		4, 'g', 'o', 'p', 'h', // insert "goph"
+	}
+	out, err := applyDelta(base, delta)
+	require.NoError(t, err)
+	assert.Equal(t, "hello, goph", string(out))
+
+	_, err = applyDelta([]byte("short"), delta)
+	assert.Error(t, err, "base size mismatch")
+}
+
+func TestDiffLines(t *testing.T) {
+	a := strings.Split("a b c d e f", " ")
+	b := strings.Split("a x c d f g", " ")
+	assert.Equal(t, []Hunk{
+		{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1}, // b -> x
+		{OldStart: 4, OldLines: 1, NewStart: 4, NewLines: 0}, // e deleted
+		{OldStart: 6, OldLines: 0, NewStart: 5, NewLines: 1}, // g appended
+	}, DiffLines(a, b))
+
+	assert.Nil(t, DiffLines(a, a))
+	assert.Equal(t, []Hunk{{OldStart: 0, OldLines: 0, NewStart: 0, NewLines: 6}}, DiffLines(nil, a))
+
+	// Applying the hunks to a must always reproduce b
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		a := randomLines(rng)
+		b := randomLines(rng)
+		assert.Equal(t, b, applyHunks(a, b, DiffLines(a, b)))
+	}
+}
+
+func randomLines(rng *rand.Rand) []string {
+	lines := make([]string, rng.Intn(30))
+	for i := range lines {
+		lines[i] = string(rune('a' + rng.Intn(4)))
+	}
+	return lines
+}
+
+// applyHunks rebuilds b by copying unchanged runs of a and the new side of each hunk
+func applyHunks(a, b []string, hunks []Hunk) []string {
+	out := []string{}
+	pos := 0
+	for _, h := range hunks {
+		out = append(out, a[pos:h.OldStart]...)
+		out = append(out, b[h.NewStart:h.NewStart+h.NewLines]...)
+		pos = h.OldStart + h.OldLines
+	}
+	return append(out, a[pos:]...)
+}
+
+func TestDiffTrees(t *testing.T) {
+	dir := setupRepo(t)
+	repo, err := Open(dir)
+	require.NoError(t, err)
+	defer func() { _ = repo.Close() }()
+
+	h, err := repo.ResolveRevision("HEAD")
+	require.NoError(t, err)
+	head, err := repo.Commit(h)
+	require.NoError(t, err)
+	parent, err := repo.Commit(head.Parents[0])
+	require.NoError(t, err)
+
+	changes, err := repo.DiffTrees(parent.Tree, head.Tree)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "internal/util/extra.go", changes[0].Path)
+	assert.True(t, changes[0].From.IsZero(), "added")
+	assert.Equal(t, "internal/util/util.go", changes[1].Path)
+	assert.False(t, changes[1].From.IsZero())
+	assert.False(t, changes[1].To.IsZero())
+
+	// Against the empty tree every file is added
+	changes, err = repo.DiffTrees(Hash{}, parent.Tree)
+	require.NoError(t, err)
+	assert.Len(t, changes, 3)
+}
@@ -0,0 +1,559 @@
+package gitrepo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrObjectNotFound is returned when an object is in neither loose storage nor a pack
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectType is the type of a git object
+type ObjectType int
+
+// Object types, numbered as in packfiles
+const (
+	ObjectCommit ObjectType = 1
+	ObjectTree   ObjectType = 2
+	ObjectBlob   ObjectType = 3
+	ObjectTag    ObjectType = 4
+
+	objectOfsDelta ObjectType = 6
+	objectRefDelta ObjectType = 7
+)
+
+// String returns the type name used in loose object headers
+func (t ObjectType) String() string {
+	switch t {
+	case ObjectCommit:
+		return "commit"
+	case ObjectTree:
+		return "tree"
+	case ObjectBlob:
+		return "blob"
+	case ObjectTag:
+		return "tag"
+	default:
+		return "unknown"
+	}
+}
+
+func parseObjectType(s string) (ObjectType, error) {
+	switch s {
+	case "commit":
+		return ObjectCommit, nil
+	case "tree":
+		return ObjectTree, nil
+	case "blob":
+		return ObjectBlob, nil
+	case "tag":
+		return ObjectTag, nil
+	}
+	return 0, fmt.Errorf("unknown object type %q", s)
+}
+
+// readObject returns the type and content of an object
+func (r *Repo) readObject(h Hash) (ObjectType, []byte, error) {
+	typ, data, err := r.readLoose(h)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return typ, data, err
+	}
+
+	packs, err := r.loadPacks()
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, p := range packs {
+		if offset, ok := p.index.find(h); ok {
+			return r.readPacked(p, offset)
+		}
+	}
+	return 0, nil, r.notFound(h)
+}
+
+// objectSize returns the size of an object's content without inflating all of it
+func (r *Repo) objectSize(h Hash) (int64, error) {
+	f, err := os.Open(r.loosePath(h))
+	if err == nil {
+		defer func() { _ = f.Close() }()
+		zr, err := zlib.NewReader(f)
+		if err != nil {
+			return 0, fmt.Errorf("object %s: %w", h, err)
+		}
+		defer func() { _ = zr.Close() }()
+		_, size, err := readLooseHeader(bufio.NewReader(zr))
+		if err != nil {
+			return 0, fmt.Errorf("object %s: %w", h, err)
+		}
+		return size, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	packs, err := r.loadPacks()
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range packs {
+		if offset, ok := p.index.find(h); ok {
+			return p.objectSize(offset)
+		}
+	}
+	return 0, r.notFound(h)
+}
+
+// notFound returns the error for a missing object, naming the packs that were
+// skipped since they may hold it
+func (r *Repo) notFound(h Hash) error {
+	err := fmt.Errorf("%w: %s", ErrObjectNotFound, h)
+	if len(r.packsSkipped) > 0 {
+		err = fmt.Errorf("%w; skipped unreadable packs: %w", err, errors.Join(r.packsSkipped...))
+	}
+	return err
+}
+
+func (r *Repo) loosePath(h Hash) string {
+	s := h.String()
+	return filepath.Join(r.commonDir, "objects", s[:2], s[2:])
+}
+
+func (r *Repo) readLoose(h Hash) (ObjectType, []byte, error) {
+	f, err := os.Open(r.loosePath(h))
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	zr, err := zlib.NewReader(f)
+	if err != nil {
+		return 0, nil, fmt.Errorf("object %s: %w", h, err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	br := bufio.NewReader(zr)
+	typ, size, err := readLooseHeader(br)
+	if err != nil {
+		return 0, nil, fmt.Errorf("object %s: %w", h, err)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return 0, nil, fmt.Errorf("object %s: %w", h, err)
+	}
+	return typ, data, nil
+}
+
+// readLooseHeader parses the "<type> <size>\x00" prefix of a loose object
+func readLooseHeader(br *bufio.Reader) (ObjectType, int64, error) {
+	header, err := br.ReadString(0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("read header: %w", err)
+	}
+	name, sizeStr, ok := strings.Cut(strings.TrimSuffix(header, "\x00"), " ")
+	if !ok {
+		return 0, 0, fmt.Errorf("malformed header %q", header)
+	}
+	typ, err := parseObjectType(name)
+	if err != nil {
+		return 0, 0, err
+	}
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil || size < 0 {
+		return 0, 0, fmt.Errorf("malformed header %q", header)
+	}
+	return typ, size, nil
+}
+
+// packFile is an open packfile together with its index
+type packFile struct {
+	path  string
+	file  *os.File
+	index *packIndex
+
+	mu    sync.Mutex
+	cache map[int64]cachedObject // Recently inflated delta bases by offset
+}
+
+type cachedObject struct {
+	typ  ObjectType
+	data []byte
+}
+
+// maxCachedBases bounds the per-pack delta base cache
+const maxCachedBases = 256
+
+// loadPacks opens every pack in objects/pack once
+func (r *Repo) loadPacks() ([]*packFile, error) {
+	r.packsOnce.Do(func() {
+		idxFiles, err := filepath.Glob(filepath.Join(r.commonDir, "objects", "pack", "pack-*.idx"))
+		if err != nil {
+			r.packsErr = err
+			return
+		}
+		sort.Strings(idxFiles)
+		// An unreadable pack only hides its own objects
+		for _, idxPath := range idxFiles {
+			idx, err := readPackIndex(idxPath)
+			if err != nil {
+				r.packsSkipped = append(r.packsSkipped, fmt.Errorf("%s: %w", filepath.Base(idxPath), err))
+				continue
+			}
+			packPath := strings.TrimSuffix(idxPath, ".idx") + ".pack"
+			f, err := os.Open(packPath)
+			if err != nil {
+				r.packsSkipped = append(r.packsSkipped, err)
+				continue
+			}
+			r.packs = append(r.packs, &packFile{
+				path:  packPath,
+				file:  f,
+				index: idx,
+				cache: make(map[int64]cachedObject),
+			})
+		}
+	})
+	return r.packs, r.packsErr
+}
+
+// Close releases the open packfiles
+func (r *Repo) Close() error {
+	var firstErr error
+	for _, p := range r.packs {
+		if err := p.file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// readPacked returns the fully resolved object stored at offset in p
+func (r *Repo) readPacked(p *packFile, offset int64) (ObjectType, []byte, error) {
+	p.mu.Lock()
+	cached, ok := p.cache[offset]
+	p.mu.Unlock()
+	if ok {
+		return cached.typ, cached.data, nil
+	}
+
+	typ, size, dataOffset, err := p.readEntryHeader(offset)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var base Hash
+	var baseOffset int64
+	switch typ {
+	case objectOfsDelta:
+		rel, n, err := p.readOfsDeltaOffset(dataOffset)
+		if err != nil {
+			return 0, nil, err
+		}
+		baseOffset = offset - rel
+		dataOffset += n
+	case objectRefDelta:
+		if _, err := p.file.ReadAt(base[:], dataOffset); err != nil {
+			return 0, nil, fmt.Errorf("%s: read delta base: %w", filepath.Base(p.path), err)
+		}
+		dataOffset += int64(len(base))
+	}
+
+	data, err := p.inflate(dataOffset, size)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	switch typ {
+	case objectOfsDelta, objectRefDelta:
+		var baseType ObjectType
+		var baseData []byte
+		if typ == objectOfsDelta {
+			baseType, baseData, err = r.readPacked(p, baseOffset)
+		} else {
+			baseType, baseData, err = r.readObject(base)
+		}
+		if err != nil {
+			return 0, nil, fmt.Errorf("delta base: %w", err)
+		}
+		if data, err = applyDelta(baseData, data); err != nil {
+			return 0, nil, err
+		}
+		typ = baseType
+	case ObjectCommit, ObjectTree, ObjectBlob, ObjectTag:
+	default:
+		return 0, nil, fmt.Errorf("%s: unknown object type %d at offset %d", filepath.Base(p.path), typ, offset)
+	}
+
+	p.mu.Lock()
+	if len(p.cache) >= maxCachedBases {
+		p.cache = make(map[int64]cachedObject)
+	}
+	p.cache[offset] = cachedObject{typ: typ, data: data}
+	p.mu.Unlock()
+
+	return typ, data, nil
+}
+
+// objectSize returns the resolved size of the object at offset
+func (p *packFile) objectSize(offset int64) (int64, error) {
+	typ, size, dataOffset, err := p.readEntryHeader(offset)
+	if err != nil {
+		return 0, err
+	}
+
+	switch typ {
+	case objectOfsDelta:
+		_, n, err := p.readOfsDeltaOffset(dataOffset)
+		if err != nil {
+			return 0, err
+		}
+		dataOffset += n
+	case objectRefDelta:
+		dataOffset += int64(len(Hash{}))
+	default:
+		return size, nil
+	}
+
+	// The target size is the second varint of the delta header
+	zr, err := zlib.NewReader(io.NewSectionReader(p.file, dataOffset, 1<<62))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", filepath.Base(p.path), err)
+	}
+	defer func() { _ = zr.Close() }()
+	br := bufio.NewReaderSize(zr, 32)
+	if _, err := binary.ReadUvarint(br); err != nil {
+		return 0, err
+	}
+	target, err := binary.ReadUvarint(br)
+	if err != nil {
+		return 0, err
+	}
+	return int64(target), nil
+}
+
+// readEntryHeader parses the type and size varint at the start of a pack entry
+func (p *packFile) readEntryHeader(offset int64) (ObjectType, int64, int64, error) {
+	var buf [16]byte
+	n, err := p.file.ReadAt(buf[:], offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, 0, 0, err
+	}
+	if n == 0 {
+		return 0, 0, 0, fmt.Errorf("%s: offset %d out of range", filepath.Base(p.path), offset)
+	}
+
+	c := buf[0]
+	typ := ObjectType((c >> 4) & 7)
+	size := int64(c & 0x0f)
+	shift := uint(4)
+	i := 1
+	for c&0x80 != 0 {
+		if i >= n {
+			return 0, 0, 0, fmt.Errorf("%s: truncated entry header at offset %d", filepath.Base(p.path), offset)
+		}
+		c = buf[i]
+		size |= int64(c&0x7f) << shift
+		shift += 7
+		i++
+	}
+	return typ, size, offset + int64(i), nil
+}
+
+// readOfsDeltaOffset decodes the negative base offset of an OFS_DELTA entry,
+// returning the distance back to the base and the number of bytes consumed
+func (p *packFile) readOfsDeltaOffset(at int64) (int64, int64, error) {
+	var buf [10]byte
+	n, err := p.file.ReadAt(buf[:], at)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, 0, err
+	}
+
+	i := 0
+	if i >= n {
+		return 0, 0, fmt.Errorf("%s: truncated delta offset", filepath.Base(p.path))
+	}
+	c := buf[i]
+	i++
+	rel := int64(c & 0x7f)
+	for c&0x80 != 0 {
+		if i >= n {
+			return 0, 0, fmt.Errorf("%s: truncated delta offset", filepath.Base(p.path))
+		}
+		c = buf[i]
+		i++
+		rel = ((rel + 1) << 7) | int64(c&0x7f)
+	}
+	return rel, int64(i), nil
+}
+
+// inflate decompresses size bytes of zlib data starting at offset
+func (p *packFile) inflate(offset, size int64) ([]byte, error) {
+	zr, err := zlib.NewReader(io.NewSectionReader(p.file, offset, 1<<62))
+	if err != nil {
+		return nil, fmt.Errorf("%s: offset %d: %w", filepath.Base(p.path), offset, err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(zr, data); err != nil {
+		return nil, fmt.Errorf("%s: offset %d: %w", filepath.Base(p.path), offset, err)
+	}
+	return data, nil
+}
+
+// applyDelta rebuilds an object from its base and a git delta
+func applyDelta(base, delta []byte) ([]byte, error) {
+	r := bytes.NewReader(delta)
+	srcSize, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("delta: %w", err)
+	}
+	if srcSize != uint64(len(base)) {
+		return nil, fmt.Errorf("delta: base size %d, expected %d", len(base), srcSize)
+	}
+	dstSize, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("delta: %w", err)
+	}
+
+	out := make([]byte, 0, dstSize)
+	for r.Len() > 0 {
+		cmd, _ := r.ReadByte()
+		switch {
+		case cmd&0x80 != 0:
+			// Copy from base: bits 0-3 select offset bytes, bits 4-6 size bytes
+			var offset, size uint64
+			for i := uint(0); i < 4; i++ {
+				if cmd&(1<<i) != 0 {
+					b, err := r.ReadByte()
+					if err != nil {
+						return nil, fmt.Errorf("delta: truncated copy")
+					}
+					offset |= uint64(b) << (8 * i)
+				}
+			}
+			for i := uint(0); i < 3; i++ {
+				if cmd&(1<<(4+i)) != 0 {
+					b, err := r.ReadByte()
+					if err != nil {
+						return nil, fmt.Errorf("delta: truncated copy")
+					}
+					size |= uint64(b) << (8 * i)
+				}
+			}
+			if size == 0 {
+				size = 0x10000
+			}
+			if offset+size > uint64(len(base)) {
+				return nil, fmt.Errorf("delta: copy out of range")
+			}
+			out = append(out, base[offset:offset+size]...)
+		case cmd != 0:
+			// Insert the next cmd bytes literally
+			start := len(delta) - r.Len()
+			if int(cmd) > r.Len() {
+				return nil, fmt.Errorf("delta: truncated insert")
+			}
+			out = append(out, delta[start:start+int(cmd)]...)
+			_, _ = r.Seek(int64(cmd), io.SeekCurrent)
+		default:
+			return nil, fmt.Errorf("delta: reserved opcode")
+		}
+	}
+
+	if uint64(len(out)) != dstSize {
+		return nil, fmt.Errorf("delta: result size %d, expected %d", len(out), dstSize)
+	}
+	return out, nil
+}
+
+// packIndex is a version 2 pack index
+type packIndex struct {
+	fanout  [256]uint32
+	names   []byte // Sorted 20-byte object names
+	offsets []byte // 4-byte offsets, MSB set for an index into large
+	large   []byte // 8-byte offsets
+}
+
+func readPackIndex(path string) (*packIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 8+256*4 || !bytes.Equal(data[:4], []byte{0xff, 't', 'O', 'c'}) {
+		return nil, fmt.Errorf("unsupported pack index format")
+	}
+	if v := binary.BigEndian.Uint32(data[4:8]); v != 2 {
+		return nil, fmt.Errorf("unsupported pack index version %d", v)
+	}
+
+	idx := &packIndex{}
+	pos := 8
+	for i := range idx.fanout {
+		idx.fanout[i] = binary.BigEndian.Uint32(data[pos:])
+		pos += 4
+	}
+	count := int(idx.fanout[255])
+
+	need := pos + count*20 + count*4 + count*4
+	if len(data) < need {
+		return nil, fmt.Errorf("truncated pack index")
+	}
+	idx.names = data[pos : pos+count*20]
+	pos += count * 20
+	pos += count * 4 // CRC32 table
+	idx.offsets = data[pos : pos+count*4]
+	pos += count * 4
+	idx.large = data[pos:]
+	return idx, nil
+}
+
+// find returns the pack offset of h
+func (idx *packIndex) find(h Hash) (int64, bool) {
+	lo := 0
+	if h[0] > 0 {
+		lo = int(idx.fanout[h[0]-1])
+	}
+	hi := int(idx.fanout[h[0]])
+
+	i := lo + sort.Search(hi-lo, func(i int) bool {
+		return bytes.Compare(idx.names[(lo+i)*20:(lo+i+1)*20], h[:]) >= 0
+	})
+	if i >= hi || !bytes.Equal(idx.names[i*20:(i+1)*20], h[:]) {
+		return 0, false
+	}
+
+	off := binary.BigEndian.Uint32(idx.offsets[i*4:])
+	if off&0x80000000 == 0 {
+		return int64(off), true
+	}
+	li := int(off&0x7fffffff) * 8
+	if li+8 > len(idx.large) {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(idx.large[li:])), true
+}
+
+// withPrefix returns the object names starting with a lowercase hex prefix
+func (idx *packIndex) withPrefix(prefix string) []Hash {
+	var matches []Hash
+	count := int(idx.fanout[255])
+	for i := 0; i < count; i++ {
+		var h Hash
+		copy(h[:], idx.names[i*20:(i+1)*20])
+		if strings.HasPrefix(h.String(), prefix) {
+			matches = append(matches, h)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,379 @@
+package gitrepo
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNotRepository is returned by Open when no .git directory is found
+var ErrNotRepository = errors.New("not a git repository")
+
+// ErrRevisionNotFound is returned when a revision cannot be resolved
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Hash is a SHA-1 object name
+type Hash [20]byte
+
+// String returns the hash as 40 hexadecimal characters
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// IsZero reports whether h is the all-zero hash
+func (h Hash) IsZero() bool {
+	return h == Hash{}
+}
+
+// ParseHash parses a full 40-character hexadecimal object name
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	if len(s) != 2*len(h) {
+		return h, fmt.Errorf("invalid object name %q", s)
+	}
+	if _, err := hex.Decode(h[:], []byte(s)); err != nil {
+		return h, fmt.Errorf("invalid object name %q", s)
+	}
+	return h, nil
+}
+
+// Repo is a git repository opened for reading. It is safe for concurrent use.
+type Repo struct {
+	workTree  string // Directory containing .git
+	gitDir    string // Per-worktree git directory (HEAD lives here)
+	commonDir string // Shared git directory (objects and refs live here)
+
+	packsOnce    sync.Once
+	packs        []*packFile
+	packsErr     error
+	packsSkipped []error // Packs whose index or data could not be opened
+
+	mu    sync.Mutex
+	trees map[Hash][]TreeEntry
+}
+
+// Open opens the repository whose work tree contains path, searching parent
+// directories for a .git directory or file like the git command does
+func Open(path string) (*Repo, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		info, err := os.Stat(dotGit)
+		if err == nil {
+			gitDir := dotGit
+			if !info.IsDir() {
+				if gitDir, err = readGitFile(dotGit); err != nil {
+					return nil, err
+				}
+			}
+			return openGitDir(dir, gitDir)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("%w: %s", ErrNotRepository, path)
+		}
+		dir = parent
+	}
+}
+
+// readGitFile follows a "gitdir: <path>" file, as used by linked worktrees and submodules
+func readGitFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return "", fmt.Errorf("%w: malformed %s", ErrNotRepository, path)
+	}
+	gitDir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	return gitDir, nil
+}
+
+func openGitDir(workTree, gitDir string) (*Repo, error) {
+	commonDir := gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(commonDir, "objects")); err != nil {
+		return nil, fmt.Errorf("%w: %s has no object database", ErrNotRepository, gitDir)
+	}
+
+	return &Repo{
+		workTree:  workTree,
+		gitDir:    gitDir,
+		commonDir: commonDir,
+		trees:     make(map[Hash][]TreeEntry),
+	}, nil
+}
+
+// WorkTree returns the directory containing the repository's .git
+func (r *Repo) WorkTree() string {
+	return r.workTree
+}
+
+// ResolveRevision resolves a revision to a commit hash. It accepts full and
+// abbreviated object names, HEAD, branch, tag and remote names (short or
+// fully qualified), followed by any number of ^, ^N and ~N suffixes.
+// Annotated tags are peeled to the commit they point at.
+func (r *Repo) ResolveRevision(rev string) (Hash, error) {
+	base, suffix := splitRevision(rev)
+	if base == "" {
+		return Hash{}, fmt.Errorf("%w: %q", ErrRevisionNotFound, rev)
+	}
+
+	h, err := r.resolveBase(base)
+	if err != nil {
+		return Hash{}, err
+	}
+	if h, err = r.peelToCommit(h); err != nil {
+		return Hash{}, err
+	}
+
+	for suffix != "" {
+		op := suffix[0]
+		suffix = suffix[1:]
+		n := 1
+		end := 0
+		for end < len(suffix) && suffix[end] >= '0' && suffix[end] <= '9' {
+			end++
+		}
+		if end > 0 {
+			n, _ = strconv.Atoi(suffix[:end])
+			suffix = suffix[end:]
+		}
+
+		switch op {
+		case '^':
+			if n == 0 {
+				continue
+			}
+			c, err := r.Commit(h)
+			if err != nil {
+				return Hash{}, err
+			}
+			if n > len(c.Parents) {
+				return Hash{}, fmt.Errorf("%w: %q", ErrRevisionNotFound, rev)
+			}
+			h = c.Parents[n-1]
+		case '~':
+			for i := 0; i < n; i++ {
+				c, err := r.Commit(h)
+				if err != nil {
+					return Hash{}, err
+				}
+				if len(c.Parents) == 0 {
+					return Hash{}, fmt.Errorf("%w: %q", ErrRevisionNotFound, rev)
+				}
+				h = c.Parents[0]
+			}
+		}
+	}
+	return h, nil
+}
+
+// splitRevision separates "main~2^2" into "main" and "~2^2"
+func splitRevision(rev string) (base, suffix string) {
+	rev = strings.TrimSpace(rev)
+	i := strings.IndexAny(rev, "^~")
+	if i < 0 {
+		return rev, ""
+	}
+	return rev[:i], rev[i:]
+}
+
+// resolveBase resolves a ref name or object name without suffixes
+func (r *Repo) resolveBase(name string) (Hash, error) {
+	for _, candidate := range []string{
+		name,
+		"refs/" + name,
+		"refs/tags/" + name,
+		"refs/heads/" + name,
+		"refs/remotes/" + name,
+		"refs/remotes/" + name + "/HEAD",
+	} {
+		h, err := r.readRef(candidate, 0)
+		if err == nil {
+			return h, nil
+		}
+		if !errors.Is(err, ErrRevisionNotFound) {
+			return Hash{}, err
+		}
+	}
+
+	if isHex(name) && len(name) >= 4 && len(name) <= 40 {
+		return r.resolvePrefix(name)
+	}
+	return Hash{}, fmt.Errorf("%w: %q", ErrRevisionNotFound, name)
+}
+
+// readRef reads a loose or packed ref, following symbolic refs
+func (r *Repo) readRef(name string, depth int) (Hash, error) {
+	if depth > 5 {
+		return Hash{}, fmt.Errorf("%w: symbolic ref loop at %q", ErrRevisionNotFound, name)
+	}
+	if name != "HEAD" && !strings.HasPrefix(name, "refs/") {
+		return Hash{}, fmt.Errorf("%w: %q", ErrRevisionNotFound, name)
+	}
+	// The name becomes a path under the git directory
+	if !validRefName(name) {
+		return Hash{}, fmt.Errorf("%w: invalid ref name %q", ErrRevisionNotFound, name)
+	}
+
+	// HEAD and other per-worktree refs live in gitDir, shared refs in commonDir
+	for _, dir := range []string{r.gitDir, r.commonDir} {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			continue
+		}
+		content := strings.TrimSpace(string(data))
+		if target, ok := strings.CutPrefix(content, "ref:"); ok {
+			return r.readRef(strings.TrimSpace(target), depth+1)
+		}
+		if h, err := ParseHash(content); err == nil {
+			return h, nil
+		}
+	}
+
+	return r.readPackedRef(name)
+}
+
+// validRefName reports whether name is a well-formed ref name, following the
+// rules of git check-ref-format that keep it inside the refs hierarchy
+func validRefName(name string) bool {
+	if name == "" || name[0] == '/' || strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".") ||
+		strings.Contains(name, "..") || strings.Contains(name, "@{") || name == "@" {
+		return false
+	}
+	for _, component := range strings.Split(name, "/") {
+		if component == "" || component[0] == '.' || strings.HasSuffix(component, ".lock") {
+			return false
+		}
+	}
+	for _, c := range name {
+		if c < 0x20 || c == 0x7f || strings.ContainsRune(" ~^:?*[\\", c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Repo) readPackedRef(name string) (Hash, error) {
+	f, err := os.Open(filepath.Join(r.commonDir, "packed-refs"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Hash{}, fmt.Errorf("%w: %q", ErrRevisionNotFound, name)
+		}
+		return Hash{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || line[0] == '#' || line[0] == '^' {
+			continue
+		}
+		hash, ref, ok := strings.Cut(line, " ")
+		if !ok || ref != name {
+			continue
+		}
+		return ParseHash(hash)
+	}
+	if err := scanner.Err(); err != nil {
+		return Hash{}, err
+	}
+	return Hash{}, fmt.Errorf("%w: %q", ErrRevisionNotFound, name)
+}
+
+// resolvePrefix expands an abbreviated object name, failing if it is ambiguous
+func (r *Repo) resolvePrefix(prefix string) (Hash, error) {
+	prefix = strings.ToLower(prefix)
+	matches := make(map[Hash]struct{})
+
+	entries, err := os.ReadDir(filepath.Join(r.commonDir, "objects", prefix[:2]))
+	if err == nil {
+		for _, e := range entries {
+			name := prefix[:2] + e.Name()
+			if strings.HasPrefix(name, prefix) {
+				if h, err := ParseHash(name); err == nil {
+					matches[h] = struct{}{}
+				}
+			}
+		}
+	}
+
+	packs, err := r.loadPacks()
+	if err != nil {
+		return Hash{}, err
+	}
+	for _, p := range packs {
+		for _, h := range p.index.withPrefix(prefix) {
+			matches[h] = struct{}{}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Hash{}, fmt.Errorf("%w: %q", ErrRevisionNotFound, prefix)
+	case 1:
+		for h := range matches {
+			return h, nil
+		}
+	}
+	return Hash{}, fmt.Errorf("%w: short object name %q is ambiguous", ErrRevisionNotFound, prefix)
+}
+
+// peelToCommit follows annotated tags until it reaches a commit
+func (r *Repo) peelToCommit(h Hash) (Hash, error) {
+	for i := 0; i < 10; i++ {
+		typ, data, err := r.readObject(h)
+		if err != nil {
+			return Hash{}, err
+		}
+		switch typ {
+		case ObjectCommit:
+			return h, nil
+		case ObjectTag:
+			target, err := parseTagTarget(data)
+			if err != nil {
+				return Hash{}, fmt.Errorf("tag %s: %w", h, err)
+			}
+			h = target
+		default:
+			return Hash{}, fmt.Errorf("%s is a %s, not a commit", h, typ)
+		}
+	}
+	return Hash{}, fmt.Errorf("tag chain too deep at %s", h)
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+	return true
+}
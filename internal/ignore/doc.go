@@ -0,0 +1,28 @@
+// Package ignore implements gitignore pattern matching for file discovery.
+//
+// Rules are read, in increasing order of precedence, from the user's global
+// excludes file (core.excludesFile, or $XDG_CONFIG_HOME/git/ignore), the
+// repository's .git/info/exclude, and every .gitignore and .gocontextignore
+// file from the project root down to the file being tested. .gocontextignore
+// uses the same syntax and takes precedence over .gitignore in the same
+// directory, so it can exclude files that git tracks or re-include ("!pattern")
+// files that git ignores.
+//
+// Supported syntax follows gitignore(5): comments, escaped "#" and "!",
+// negation, trailing "/" for directories, anchoring via a leading or inner
+// "/", "*", "?", character classes and "**". As in git, a file cannot be
+// re-included when one of its parent directories is ignored.
+//
+// # Basic Usage
+//
+//	m, err := ignore.New("/path/to/project")
+//	if err != nil {
+//	    return err
+//	}
+//	// During a walk, load each directory's ignore files before testing its entries
+//	_ = m.LoadDir("internal")
+//	if m.Match("internal/gen", true) { ... } // skip directory
+//
+//	// Outside a walk, Ignored also checks parent directories
+//	m.Ignored("build/out/main.go", false)
+package ignore
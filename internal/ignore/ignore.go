@@ -0,0 +1,377 @@
+package ignore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// File names read in every directory, in increasing order of precedence
+const (
+	GitIgnoreFile       = ".gitignore"
+	GoContextIgnoreFile = ".gocontextignore"
+)
+
+// rule is a single compiled ignore pattern
+type rule struct {
+	base    string // Slash-separated directory of the ignore file, "" for the root
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// Matcher decides whether project-relative paths are ignored.
+// A Matcher is not safe for concurrent use.
+type Matcher struct {
+	root         string
+	fsys         fs.FS // Source of per-directory ignore files
+	useGitIgnore bool
+	rules        []rule
+	loaded       map[string]bool
+}
+
+// New creates a Matcher for rootPath, loading global excludes, .git/info/exclude
+// and the root directory's ignore files
+func New(rootPath string) (*Matcher, error) {
+	return newMatcher(rootPath, os.DirFS(rootPath), true)
+}
+
+// NewGoContextOnly creates a Matcher that reads only .gocontextignore files,
+// for projects that opt out of gitignore handling
+func NewGoContextOnly(rootPath string) (*Matcher, error) {
+	return newMatcher(rootPath, os.DirFS(rootPath), false)
+}
+
+// NewFS creates a Matcher that reads per-directory ignore files from fsys, such as
+// the tree of a git revision. Global excludes and .git/info/exclude are still read
+// from rootPath when useGitIgnore is set.
+func NewFS(rootPath string, fsys fs.FS, useGitIgnore bool) (*Matcher, error) {
+	return newMatcher(rootPath, fsys, useGitIgnore)
+}
+
+func newMatcher(rootPath string, fsys fs.FS, useGitIgnore bool) (*Matcher, error) {
+	m := &Matcher{
+		root:         rootPath,
+		fsys:         fsys,
+		useGitIgnore: useGitIgnore,
+		loaded:       make(map[string]bool),
+	}
+
+	if useGitIgnore {
+		if global := globalExcludesFile(); global != "" {
+			if err := m.loadFile(global, ""); err != nil {
+				return nil, err
+			}
+		}
+		if err := m.loadFile(filepath.Join(rootPath, ".git", "info", "exclude"), ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.LoadDir(""); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AddPatterns adds gitignore-style lines as if they were read from an ignore file in dir
+func (m *Matcher) AddPatterns(dir string, lines []string) {
+	base := cleanRel(dir)
+	for _, line := range lines {
+		if r, ok := parseRule(base, line); ok {
+			m.rules = append(m.rules, r)
+		}
+	}
+}
+
+// LoadDir reads the ignore files in a slash-separated directory relative to the root.
+// Loading the same directory twice is a no-op.
+func (m *Matcher) LoadDir(dir string) error {
+	base := cleanRel(dir)
+	if m.loaded[base] {
+		return nil
+	}
+	m.loaded[base] = true
+
+	if m.useGitIgnore {
+		if err := m.loadDirFile(path.Join(base, GitIgnoreFile), base); err != nil {
+			return err
+		}
+	}
+	return m.loadDirFile(path.Join(base, GoContextIgnoreFile), base)
+}
+
+// loadFile appends the rules of one ignore file on disk; a missing file is not an error
+func (m *Matcher) loadFile(file, base string) error {
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read ignore file %s: %w", file, err)
+	}
+	defer func() { _ = f.Close() }()
+	return m.readRules(f, file, base)
+}
+
+// loadDirFile appends the rules of an ignore file inside the project tree
+func (m *Matcher) loadDirFile(name, base string) error {
+	f, err := m.fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read ignore file %s: %w", name, err)
+	}
+	defer func() { _ = f.Close() }()
+	return m.readRules(f, name, base)
+}
+
+func (m *Matcher) readRules(r io.Reader, file, base string) error {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read ignore file %s: %w", file, err)
+	}
+	m.AddPatterns(base, lines)
+	return nil
+}
+
+// Match reports whether the path itself is ignored by the rules loaded so far.
+// It does not consult parent directories; walks skip ignored directories instead.
+func (m *Matcher) Match(relPath string, isDir bool) bool {
+	p := cleanRel(relPath)
+	if p == "" {
+		return false
+	}
+
+	ignored := false
+	for i := range m.rules {
+		r := &m.rules[i]
+		if r.dirOnly && !isDir {
+			continue
+		}
+		sub := p
+		if r.base != "" {
+			if !strings.HasPrefix(p, r.base+"/") {
+				continue
+			}
+			sub = p[len(r.base)+1:]
+		}
+		if r.re.MatchString(sub) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// Ignored reports whether a path or any of its parent directories is ignored,
+// loading ignore files of the directories on the way
+func (m *Matcher) Ignored(relPath string, isDir bool) bool {
+	p := cleanRel(relPath)
+	if p == "" {
+		return false
+	}
+
+	parts := strings.Split(p, "/")
+	dir := ""
+	for i, part := range parts {
+		if err := m.LoadDir(dir); err != nil {
+			return false
+		}
+		dir = path.Join(dir, part)
+		last := i == len(parts)-1
+		if m.Match(dir, isDir || !last) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanRel normalizes a relative path to slash form without "./" or trailing "/"
+func cleanRel(p string) string {
+	p = path.Clean(filepath.ToSlash(p))
+	if p == "." || p == "/" {
+		return ""
+	}
+	return strings.TrimPrefix(p, "./")
+}
+
+// parseRule compiles one line of an ignore file
+func parseRule(base, line string) (rule, bool) {
+	line = trimTrailingSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+
+	r := rule{base: base}
+	switch {
+	case strings.HasPrefix(line, "!"):
+		r.negate = true
+		line = line[1:]
+	case strings.HasPrefix(line, `\!`), strings.HasPrefix(line, `\#`):
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule{}, false
+	}
+
+	// A slash at the start or in the middle anchors the pattern to the ignore file's directory
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if !anchored {
+		line = "**/" + line
+	}
+
+	re, err := regexp.Compile(globToRegexp(line))
+	if err != nil {
+		return rule{}, false // git silently skips malformed patterns
+	}
+	r.re = re
+	return r, true
+}
+
+// trimTrailingSpace removes unescaped trailing spaces
+func trimTrailingSpace(line string) string {
+	line = strings.TrimRight(line, "\r")
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+// globToRegexp translates a gitignore glob into an anchored regular expression
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				atStart := i == 0 || glob[i-1] == '/'
+				i++
+				switch {
+				case atStart && i+1 < len(glob) && glob[i+1] == '/':
+					// "**/" matches zero or more directories
+					i++
+					b.WriteString("(?:.*/)?")
+				case atStart && i+1 == len(glob):
+					// Trailing "/**" matches everything inside
+					b.WriteString(".*")
+				default:
+					// Other consecutive asterisks behave like "*"
+					b.WriteString("[^/]*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// globalExcludesFile returns the user's global gitignore: core.excludesFile from
+// the global git config, else $XDG_CONFIG_HOME/git/ignore
+func globalExcludesFile() string {
+	home, _ := os.UserHomeDir()
+
+	configs := []string{}
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if xdg == "" && home != "" {
+		xdg = filepath.Join(home, ".config")
+	}
+	if xdg != "" {
+		configs = append(configs, filepath.Join(xdg, "git", "config"))
+	}
+	if home != "" {
+		configs = append(configs, filepath.Join(home, ".gitconfig"))
+	}
+
+	// Later files take precedence, matching git's lookup order
+	excludes := ""
+	for _, cfg := range configs {
+		if v := readCoreExcludesFile(cfg); v != "" {
+			excludes = v
+		}
+	}
+	if excludes != "" {
+		if strings.HasPrefix(excludes, "~/") && home != "" {
+			excludes = filepath.Join(home, excludes[2:])
+		}
+		return excludes
+	}
+
+	if xdg == "" {
+		return ""
+	}
+	return filepath.Join(xdg, "git", "ignore")
+}
+
+// readCoreExcludesFile extracts core.excludesFile from a git config file.
+// Only the plain "key = value" form is understood; includes are not followed.
+func readCoreExcludesFile(configPath string) string {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return ""
+	}
+
+	inCore := false
+	value := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			section := strings.ToLower(strings.Trim(line, "[] \t"))
+			inCore = section == "core"
+			continue
+		}
+		if !inCore {
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "excludesfile") {
+			value = strings.Trim(strings.TrimSpace(val), `"`)
+		}
+	}
+	return value
+}
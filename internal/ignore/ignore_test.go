@@ -0,0 +1,145 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// isolateHome points HOME and XDG_CONFIG_HOME at an empty directory so the
+// developer's global git excludes do not leak into tests
+func isolateHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	return home
+}
+
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestMatch_Patterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"*.log", "debug.log", false, true},
+		{"*.log", "logs/debug.log", false, true},
+		{"build/", "build", true, true},
+		{"build/", "build", false, false},
+		{"build/", "pkg/build", true, true},
+		{"/tmp", "tmp", true, true},
+		{"/tmp", "pkg/tmp", true, false},
+		{"doc/frotz", "doc/frotz", true, true},
+		{"doc/frotz", "a/doc/frotz", true, false},
+		{"**/gen", "x/y/gen", true, true},
+		{"gen/**", "gen/a/b.go", false, true},
+		{"a/**/b.go", "a/b.go", false, true},
+		{"a/**/b.go", "a/x/y/b.go", false, true},
+		{"file?.go", "file1.go", false, true},
+		{"file[0-9].go", "filex.go", false, false},
+		{"file[!0-9].go", "filex.go", false, true},
+		{`\#notes`, "#notes", false, true},
+		{`\!important`, "!important", false, true},
+		{"trailing   ", "trailing", false, true},
+		{"# comment", "# comment", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"_"+tt.path, func(t *testing.T) {
+			m := &Matcher{loaded: map[string]bool{}}
+			m.AddPatterns("", []string{tt.pattern})
+			assert.Equal(t, tt.want, m.Match(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestMatch_Negation(t *testing.T) {
+	m := &Matcher{loaded: map[string]bool{}}
+	m.AddPatterns("", []string{"*.go", "!keep.go"})
+
+	assert.True(t, m.Match("drop.go", false))
+	assert.False(t, m.Match("keep.go", false))
+	assert.False(t, m.Match("pkg/keep.go", false))
+}
+
+func TestIgnored_NestedFiles(t *testing.T) {
+	isolateHome(t)
+	root := t.TempDir()
+
+	writeFile(t, root, ".gitignore", "build/\n*.gen.go\n")
+	writeFile(t, root, "pkg/.gitignore", "!api.gen.go\nscratch.go\n")
+	writeFile(t, root, "pkg/sub/.gocontextignore", "fixtures/\n")
+	writeFile(t, root, ".git/info/exclude", "local/\n")
+	writeFile(t, root, ".gocontextignore", "testdata/\n")
+
+	m, err := New(root)
+	require.NoError(t, err)
+
+	assert.True(t, m.Ignored("build/out/main.go", false))
+	assert.True(t, m.Ignored("x.gen.go", false))
+	assert.False(t, m.Ignored("pkg/api.gen.go", false), "nested negation re-includes")
+	assert.True(t, m.Ignored("pkg/other.gen.go", false))
+	assert.True(t, m.Ignored("pkg/scratch.go", false))
+	assert.False(t, m.Ignored("scratch.go", false), "nested rules only apply below their directory")
+	assert.True(t, m.Ignored("pkg/sub/fixtures/a.go", false))
+	assert.True(t, m.Ignored("local/try.go", false))
+	assert.True(t, m.Ignored("internal/testdata/x.go", false))
+	assert.False(t, m.Ignored("main.go", false))
+}
+
+func TestIgnored_ParentDirectoryCannotBeReincluded(t *testing.T) {
+	isolateHome(t)
+	root := t.TempDir()
+	writeFile(t, root, ".gitignore", "build/\n!build/keep.go\n")
+
+	m, err := New(root)
+	require.NoError(t, err)
+	assert.True(t, m.Ignored("build/keep.go", false))
+}
+
+func TestGoContextIgnoreOverridesGitIgnore(t *testing.T) {
+	isolateHome(t)
+	root := t.TempDir()
+	writeFile(t, root, ".gitignore", "generated.go\n")
+	writeFile(t, root, ".gocontextignore", "!generated.go\nmocks/\n")
+
+	m, err := New(root)
+	require.NoError(t, err)
+	assert.False(t, m.Ignored("generated.go", false))
+	assert.True(t, m.Ignored("mocks/db.go", false))
+
+	m, err = NewGoContextOnly(root)
+	require.NoError(t, err)
+	assert.False(t, m.Ignored("generated.go", false))
+	assert.True(t, m.Ignored("mocks/db.go", false))
+}
+
+func TestGlobalExcludes(t *testing.T) {
+	home := isolateHome(t)
+	root := t.TempDir()
+
+	// Default location
+	writeFile(t, home, ".config/git/ignore", "*.tmp.go\n")
+	m, err := New(root)
+	require.NoError(t, err)
+	assert.True(t, m.Ignored("a.tmp.go", false))
+
+	// core.excludesFile takes precedence over the default location
+	writeFile(t, home, ".gitconfig", "[user]\n\tname = x\n[core]\n\texcludesFile = ~/global-ignore\n")
+	writeFile(t, home, "global-ignore", "*.wip.go\n")
+	m, err = New(root)
+	require.NoError(t, err)
+	assert.True(t, m.Ignored("a.wip.go", false))
+	assert.False(t, m.Ignored("a.tmp.go", false))
+}
@@ -0,0 +1,127 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	projectconfig "github.com/dshills/gocontext-mcp/internal/config"
+	"github.com/dshills/gocontext-mcp/internal/ddd"
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// typeKey identifies a declared type by package and name
+type typeKey struct {
+	pkg  string // Import path, or the directory when the file has none
+	name string
+}
+
+// classifyDomain reclassifies the types declared in the project's non-test files
+// with its DDD rules. It runs once every file is indexed: criteria such as the
+// methods a type declares and the interfaces it implements span files.
+func (idx *Indexer) classifyDomain(ctx context.Context, project *storage.Project, config *Config) error {
+	cfg := config.Project
+	if cfg == nil {
+		cfg = projectconfig.Default()
+	}
+	classifier, err := ddd.New(cfg.DDD)
+	if err != nil {
+		return err
+	}
+
+	files, err := idx.storage.ListFiles(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+
+	type declared struct {
+		symbol      *storage.Symbol
+		typ         *ddd.Type
+		key         typeKey
+		packageName string
+	}
+	var decls []declared
+	members := make(map[typeKey]*ddd.Type)
+	member := func(key typeKey) *ddd.Type {
+		if members[key] == nil {
+			members[key] = &ddd.Type{}
+		}
+		return members[key]
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.FilePath, "_test.go") {
+			continue
+		}
+		symbols, err := idx.storage.ListSymbolsByFile(ctx, file.ID)
+		if err != nil {
+			return err
+		}
+
+		dir := path.Dir(filepath.ToSlash(file.FilePath))
+		pkg := file.ImportPath
+		if pkg == "" {
+			pkg = dir
+		}
+		for _, sym := range symbols {
+			switch types.SymbolKind(sym.Kind) {
+			case types.KindStruct, types.KindInterface, types.KindType:
+				decls = append(decls, declared{
+					symbol: sym,
+					typ: &ddd.Type{
+						Name:       sym.Name,
+						Kind:       types.SymbolKind(sym.Kind),
+						ImportPath: file.ImportPath,
+						Dir:        dir,
+						Embeds:     sym.Embeds,
+					},
+					key:         typeKey{pkg, sym.Name},
+					packageName: file.PackageName,
+				})
+			case types.KindField:
+				m := member(typeKey{pkg, sym.Receiver})
+				m.Fields = append(m.Fields, sym.Name)
+			case types.KindMethod:
+				m := member(typeKey{pkg, sym.Receiver})
+				m.Methods = append(m.Methods, sym.Name)
+			}
+		}
+	}
+
+	// Fields and methods may be declared in other files of the package
+	var interfaces []*ddd.Interface
+	for _, d := range decls {
+		if m := members[d.key]; m != nil {
+			d.typ.Fields, d.typ.Methods = m.Fields, m.Methods
+		}
+		if d.typ.Kind == types.KindInterface {
+			interfaces = append(interfaces, &ddd.Interface{
+				Name:        d.typ.Name,
+				PackageName: d.packageName,
+				ImportPath:  d.typ.ImportPath,
+				Methods:     d.typ.Methods,
+				Embeds:      d.typ.Embeds,
+			})
+		}
+	}
+	index := ddd.NewInterfaces(interfaces)
+
+	tx, err := idx.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, d := range decls {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := tx.UpdateSymbolPatterns(ctx, d.symbol.ID, classifier.Classify(d.typ, index)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,205 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"strings"
+
+	projectconfig "github.com/dshills/gocontext-mcp/internal/config"
+	"github.com/dshills/gocontext-mcp/internal/modules"
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// dependencyMode reports whether the config asks for dependency indexing
+func dependencyMode(config *Config) bool {
+	return config.Dependencies == projectconfig.DependenciesDirect ||
+		config.Dependencies == projectconfig.DependenciesAll
+}
+
+// indexDependencies indexes the modules required by the project as separate API-only
+// projects and links them to it. A dependency that fails to index is reported in the
+// statistics and left out of the links; requirements missing from the module cache
+// are listed in stats.DependenciesMissing. Nothing is downloaded.
+func (idx *Indexer) indexDependencies(ctx context.Context, project *storage.Project, config *Config, stats *Statistics) error {
+	if idx.modules == nil {
+		return nil
+	}
+
+	indirect := config.Dependencies == projectconfig.DependenciesAll
+	found, missing, err := idx.modules.ResolveDependencies(project.RootPath, modules.ModCacheDir(), indirect)
+	if err != nil {
+		return err
+	}
+	for _, dep := range missing {
+		stats.DependenciesMissing = append(stats.DependenciesMissing, dep.ID())
+	}
+
+	links := make([]*storage.ProjectDependency, 0, len(found))
+	for _, dep := range found {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		depProject, reused, err := idx.indexReadOnly(ctx, readOnlyModule{
+			dir:       dep.Dir,
+			path:      dep.Path,
+			version:   dep.Version,
+			immutable: !dep.Vendored,
+		}, config, stats)
+		if err != nil {
+			stats.ErrorMessages = append(stats.ErrorMessages, fmt.Sprintf("dependency %s: %v", dep.ID(), err))
+			continue
+		}
+		if reused {
+			stats.DependenciesCurrent++
+		} else {
+			stats.DependenciesIndexed++
+		}
+		links = append(links, &storage.ProjectDependency{DependencyID: depProject.ID, Direct: dep.Direct})
+	}
+	return idx.linkDependencies(ctx, project, links, false)
+}
+
+// indexStdlib indexes the standard library of the Go release matching the project's
+// toolchain or go directive, from the local GOROOT or a downloaded toolchain, and links
+// it to the project. Each release is indexed once and shared by every project using it.
+func (idx *Indexer) indexStdlib(ctx context.Context, project *storage.Project, config *Config, stats *Statistics) error {
+	var want string
+	if idx.modules != nil {
+		want = modules.WantGoVersion(idx.modules.Root())
+	}
+	goroot, err := modules.FindGoRoot(want, modules.ModCacheDir())
+	if err != nil {
+		return err
+	}
+	stats.StdlibVersion = goroot.Version
+	stats.StdlibExact = goroot.Exact
+
+	stdlib, reused, err := idx.indexReadOnly(ctx, readOnlyModule{
+		dir:       goroot.SrcDir(),
+		path:      storage.StdlibModule,
+		version:   goroot.Version,
+		immutable: true,
+		exclude:   []string{"cmd/**"}, // The toolchain's own commands, not importable
+	}, config, stats)
+	if err != nil {
+		return err
+	}
+	stats.StdlibIndexed = !reused
+
+	return idx.linkDependencies(ctx, project, []*storage.ProjectDependency{{DependencyID: stdlib.ID, Direct: true}}, true)
+}
+
+// linkDependencies replaces the project's links to either its stdlib project or its
+// module dependencies, keeping the other kind
+func (idx *Indexer) linkDependencies(ctx context.Context, project *storage.Project, links []*storage.ProjectDependency, stdlib bool) error {
+	existing, err := idx.storage.ListProjectDependencies(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	for _, dep := range existing {
+		if dep.Project.IsStdlib() != stdlib {
+			links = append(links, dep)
+		}
+	}
+	return idx.storage.ReplaceProjectDependencies(ctx, project.ID, links)
+}
+
+// readOnlyModule is a module indexed as an API-only project of its own
+type readOnlyModule struct {
+	dir       string   // Source directory, the project's root path
+	path      string   // Module path
+	version   string   // Version tag of the project
+	immutable bool     // Sources of a version never change, so an indexed copy is reused
+	exclude   []string // Project-relative exclude globs
+}
+
+// indexReadOnly indexes a module's exported API and returns its project, reporting
+// whether an existing index of the same version was reused instead. Unless a reindex
+// is forced, immutable modules already indexed at the version are reused; others go
+// through the usual change detection.
+func (idx *Indexer) indexReadOnly(ctx context.Context, mod readOnlyModule, config *Config, stats *Statistics) (*storage.Project, bool, error) {
+	project, err := idx.getOrCreateProject(ctx, mod.dir)
+	if err != nil {
+		return nil, false, err
+	}
+	if mod.immutable && !config.ForceReindex && project.ModuleVersion == mod.version && !project.LastIndexedAt.IsZero() {
+		return project, true, nil
+	}
+	project.ModuleName = mod.path
+	project.ModuleVersion = mod.version
+	if err := idx.storage.UpdateProject(ctx, project); err != nil {
+		return nil, false, err
+	}
+
+	projectCfg := projectconfig.Default()
+	projectCfg.Index.Exclude = mod.exclude
+	modConfig := &Config{
+		Workers:            config.Workers,
+		BatchSize:          config.BatchSize,
+		EmbeddingBatch:     config.EmbeddingBatch,
+		GenerateEmbeddings: config.GenerateEmbeddings,
+		ForceReindex:       config.ForceReindex,
+		Project:            projectCfg,
+		APIOnly:            true,
+	}
+
+	// Module zips hold a single module, so every package path derives from mod.path;
+	// this also covers vendored copies and pre-module code without a go.mod
+	set := &modules.Set{Modules: []*modules.Module{{Path: mod.path}}}
+	if err := idx.storage.ReplaceModules(ctx, project.ID, []*storage.Module{{ModulePath: mod.path}}); err != nil {
+		return nil, false, err
+	}
+
+	prevSource, prevModules := idx.source, idx.modules
+	idx.source, idx.modules = newWorkTreeSource(mod.dir), set
+	defer func() { idx.source, idx.modules = prevSource, prevModules }()
+
+	files, err := idx.discoverSourceFiles(idx.source, modConfig)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to discover files: %w", err)
+	}
+
+	modStats := &Statistics{ErrorMessages: make([]string, 0)}
+	if err := idx.indexFiles(ctx, project, files, modConfig, modStats); err != nil {
+		return nil, false, err
+	}
+	for _, msg := range modStats.ErrorMessages {
+		stats.ErrorMessages = append(stats.ErrorMessages, fmt.Sprintf("%s@%s: %s", mod.path, mod.version, msg))
+	}
+
+	if err := idx.updateProjectStats(ctx, project); err != nil {
+		return nil, false, err
+	}
+	return project, false, nil
+}
+
+// apiDir reports whether a directory can hold packages importable by other modules
+func apiDir(name string) bool {
+	return name != "internal" && name != "testdata" && !strings.HasPrefix(name, "_")
+}
+
+// exportedSymbols keeps exported symbols; methods and fields also need an exported receiver
+func exportedSymbols(symbols []types.Symbol) []types.Symbol {
+	kept := symbols[:0]
+	for _, sym := range symbols {
+		if !token.IsExported(sym.Name) {
+			continue
+		}
+		if sym.Receiver != "" && !token.IsExported(receiverName(sym.Receiver)) {
+			continue
+		}
+		kept = append(kept, sym)
+	}
+	return kept
+}
+
+// receiverName strips the pointer and type parameters from a receiver type: "*List[T]" → "List"
+func receiverName(recv string) string {
+	recv = strings.TrimLeft(recv, "*")
+	if i := strings.IndexByte(recv, '['); i >= 0 {
+		recv = recv[:i]
+	}
+	return recv
+}
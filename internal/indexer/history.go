@@ -0,0 +1,319 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dshills/gocontext-mcp/internal/gitrepo"
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// DefaultHistoryDepth is the number of first-parent commits followed when
+// computing chunk history
+const DefaultHistoryDepth = 1000
+
+// fileChange is one commit changing a file, From being the blob before the commit
+type fileChange struct {
+	commit *gitrepo.Commit
+	from   gitrepo.Hash
+	to     gitrepo.Hash
+}
+
+// history holds the commits touching each file, newest first, within the history depth
+type history struct {
+	repo    *gitrepo.Repo
+	head    *gitrepo.Commit // Commit the history starts from
+	prefix  string          // Project root relative to the repository root, "" at the top
+	changes map[string][]fileChange
+}
+
+// loadHistory walks first-parent history from the source's revision (HEAD for the
+// working tree) and indexes the changes by file. It returns nil, nil when the
+// project is not in a git repository or the repository has no commits yet.
+func loadHistory(src *source, depth int) (*history, error) {
+	repo := src.repo
+	head := src.commit
+	if repo == nil {
+		var err error
+		if repo, err = gitrepo.Open(src.root); errors.Is(err, gitrepo.ErrNotRepository) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		hash, err := repo.ResolveRevision("HEAD")
+		if errors.Is(err, gitrepo.ErrRevisionNotFound) {
+			_ = repo.Close()
+			return nil, nil
+		}
+		if err == nil {
+			head, err = repo.Commit(hash)
+		}
+		if err != nil {
+			_ = repo.Close()
+			return nil, err
+		}
+	}
+
+	h := &history{repo: repo, head: head, changes: make(map[string][]fileChange)}
+	absRoot, err := filepath.Abs(src.root)
+	if err != nil {
+		h.close(src)
+		return nil, err
+	}
+	if rel, err := filepath.Rel(repo.WorkTree(), absRoot); err == nil && rel != "." {
+		h.prefix = filepath.ToSlash(rel) + "/"
+	}
+
+	if depth <= 0 {
+		depth = DefaultHistoryDepth
+	}
+	commit := head
+	for i := 0; i < depth && commit != nil; i++ {
+		var parent *gitrepo.Commit
+		var parentTree gitrepo.Hash
+		if len(commit.Parents) > 0 {
+			if parent, err = repo.Commit(commit.Parents[0]); err != nil {
+				h.close(src)
+				return nil, err
+			}
+			parentTree = parent.Tree
+		}
+
+		changes, err := repo.DiffTrees(parentTree, commit.Tree)
+		if err != nil {
+			h.close(src)
+			return nil, fmt.Errorf("diff %s: %w", commit.Hash, err)
+		}
+		for _, c := range changes {
+			rel, ok := strings.CutPrefix(c.Path, h.prefix)
+			if !ok || c.To.IsZero() {
+				continue
+			}
+			h.changes[rel] = append(h.changes[rel], fileChange{commit: commit, from: c.From, to: c.To})
+		}
+		commit = parent
+	}
+	return h, nil
+}
+
+// close releases the repository unless it belongs to the source
+func (h *history) close(src *source) {
+	if h.repo != src.repo {
+		_ = h.repo.Close()
+	}
+}
+
+// lineRange is a chunk's lines being followed back through history, 0-based and half-open
+type lineRange struct {
+	start, end int
+	done       bool // Stopped following: the lines were created or rewritten
+	history    types.ChunkHistory
+}
+
+// annotate computes the history of each chunk of a file. content is the file as
+// indexed and modTime its modification time, used for uncommitted changes.
+func (h *history) annotate(relPath string, content []byte, modTime time.Time, chunks []*storage.Chunk) ([]types.ChunkHistory, error) {
+	ranges := make([]*lineRange, len(chunks))
+	for i, c := range chunks {
+		ranges[i] = &lineRange{start: c.StartLine - 1, end: c.EndLine}
+	}
+	result := func() []types.ChunkHistory {
+		out := make([]types.ChunkHistory, len(ranges))
+		for i, r := range ranges {
+			out[i] = r.history
+		}
+		return out
+	}
+
+	lines := splitLines(content)
+	changes := h.changes[relPath]
+
+	// Working tree edits on top of the starting commit
+	committed, err := fs.ReadFile(h.repo.CommitFS(h.head), h.prefix+relPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		for _, r := range ranges {
+			r.history.LastModified = modTime
+		}
+		return result(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if committedLines := splitLines(committed); !slices.Equal(committedLines, lines) {
+		hunks := gitrepo.DiffLines(committedLines, lines)
+		for _, r := range ranges {
+			if touched(r, hunks) {
+				r.history.LastModified = modTime
+			}
+		}
+		followRanges(ranges, hunks)
+		lines = committedLines
+	}
+
+	for i, change := range changes {
+		var old []string
+		if !change.from.IsZero() {
+			data, err := h.repo.Blob(change.from)
+			if err != nil {
+				return nil, err
+			}
+			old = splitLines(data)
+		}
+
+		hunks := gitrepo.DiffLines(old, lines)
+		active := false
+		for _, r := range ranges {
+			if r.done || !touched(r, hunks) {
+				continue
+			}
+			r.history.ChangeCount++
+			if r.history.LastModified.IsZero() {
+				r.history.LastCommit = change.commit.Hash.String()
+				r.history.LastAuthor = change.commit.Author.Name
+				r.history.LastModified = change.commit.Author.When
+			}
+		}
+		followRanges(ranges, hunks)
+		for _, r := range ranges {
+			if change.from.IsZero() {
+				r.done = true
+			}
+			active = active || !r.done
+		}
+		if !active {
+			break
+		}
+
+		// The next change should produce the blob this one started from; otherwise the
+		// file was recreated beyond a gap and there is nothing left to follow
+		if i+1 < len(changes) && changes[i+1].to != change.from {
+			break
+		}
+		lines = old
+	}
+	return result(), nil
+}
+
+// touched reports whether any hunk adds, changes or deletes lines inside the range
+func touched(r *lineRange, hunks []gitrepo.Hunk) bool {
+	for _, h := range hunks {
+		if h.NewLines > 0 && h.NewStart < r.end && h.NewStart+h.NewLines > r.start {
+			return true
+		}
+		// Pure deletions between two lines of the range
+		if h.NewLines == 0 && h.NewStart > r.start && h.NewStart < r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// followRanges maps each active range from the new side of the hunks to the old
+// side. Ranges that no longer cover any old line are done.
+func followRanges(ranges []*lineRange, hunks []gitrepo.Hunk) {
+	for _, r := range ranges {
+		if r.done {
+			continue
+		}
+		r.start = mapLine(r.start, hunks, false)
+		r.end = mapLine(r.end, hunks, true)
+		if r.end <= r.start {
+			r.done = true
+		}
+	}
+}
+
+// mapLine maps a line boundary of the new side to the old side. A boundary inside
+// a hunk snaps to the hunk's old lines, so the range keeps covering what was replaced.
+func mapLine(line int, hunks []gitrepo.Hunk, end bool) int {
+	delta := 0
+	for _, h := range hunks {
+		if line <= h.NewStart {
+			break
+		}
+		if line < h.NewStart+h.NewLines {
+			if end {
+				return h.OldStart + h.OldLines
+			}
+			return h.OldStart
+		}
+		delta = h.OldStart + h.OldLines - (h.NewStart + h.NewLines)
+	}
+	return line + delta
+}
+
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// annotateHistory records git history on every chunk of the project whose history
+// changed. Unchanged files are revisited too, since new commits age their chunks.
+func (idx *Indexer) annotateHistory(ctx context.Context, project *storage.Project, config *Config) error {
+	src := idx.sourceFor(project)
+	h, err := loadHistory(src, config.HistoryDepth)
+	if err != nil || h == nil {
+		return err
+	}
+	defer h.close(src)
+
+	files, err := idx.storage.ListFiles(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := idx.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		chunks, err := tx.ListChunksByFile(ctx, file.ID)
+		if err != nil || len(chunks) == 0 {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		rel := filepath.ToSlash(file.FilePath)
+		content, err := src.readFile(rel)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.FilePath, err)
+		}
+		histories, err := h.annotate(rel, content, file.ModTime, chunks)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.FilePath, err)
+		}
+
+		for i, chunk := range chunks {
+			if sameHistory(chunk.History, histories[i]) {
+				continue
+			}
+			if err := tx.UpdateChunkHistory(ctx, chunk.ID, histories[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// sameHistory compares histories as stored, at whole-second precision
+func sameHistory(a, b types.ChunkHistory) bool {
+	return a.LastCommit == b.LastCommit && a.LastAuthor == b.LastAuthor &&
+		a.ChangeCount == b.ChangeCount && a.LastModified.Unix() == b.LastModified.Unix() &&
+		a.LastModified.IsZero() == b.LastModified.IsZero()
+}
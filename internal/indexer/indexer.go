@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
@@ -18,9 +19,13 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/dshills/gocontext-mcp/internal/chunker"
+	projectconfig "github.com/dshills/gocontext-mcp/internal/config"
 	"github.com/dshills/gocontext-mcp/internal/embedder"
+	"github.com/dshills/gocontext-mcp/internal/ignore"
+	"github.com/dshills/gocontext-mcp/internal/modules"
 	"github.com/dshills/gocontext-mcp/internal/parser"
 	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
 )
 
 // ErrIndexingInProgress indicates that an indexing operation is already running
@@ -33,6 +38,12 @@ type Indexer struct {
 	embedder embedder.Embedder
 	storage  storage.Storage
 
+	// Modules of the project being indexed, set by IndexProject
+	modules *modules.Set
+
+	// Tree being indexed, set by IndexProject for the duration of a run
+	source *source
+
 	// Worker pool configuration
 	workers int
 
@@ -49,6 +60,37 @@ type Config struct {
 	IncludeVendor      bool // Whether to index vendor directory (default: false)
 	GenerateEmbeddings bool // Whether to generate embeddings (default: true)
 	ForceReindex       bool // Whether to force reindex all files ignoring hashes (default: false)
+
+	// Project holds settings from the project's .gocontext.yaml (include/exclude globs,
+	// size limit, chunk strategy, embedding excludes). Loaded from rootPath when nil.
+	Project *projectconfig.ProjectConfig
+
+	// Ref is a git revision (branch, tag, commit, HEAD~1, ...) to index instead of the
+	// working tree. Files are read from the repository's object database and stored as
+	// a separate snapshot project keyed by storage.SnapshotRootPath(rootPath, Ref).
+	Ref string
+
+	// GitHistory records the last commit, author, date and change count of each
+	// chunk's lines, following at most HistoryDepth first-parent commits
+	// (default: DefaultHistoryDepth). Ignored outside git repositories.
+	GitHistory   bool
+	HistoryDepth int
+
+	// Dependencies also indexes required modules found in the local module cache or
+	// vendor directory: projectconfig.DependenciesDirect or DependenciesAll. Each module
+	// is stored as a read-only project keyed by its source directory and tagged with
+	// its version. Ignored when indexing a Ref.
+	Dependencies string
+
+	// Stdlib also indexes the standard library of the Go release the project builds
+	// with (toolchain or go directive), read from the local GOROOT or a toolchain in
+	// the module cache. Each release is stored once and linked to every project using
+	// it. Ignored when indexing a Ref.
+	Stdlib bool
+
+	// APIOnly indexes only exported declarations and skips test files and internal,
+	// testdata and underscore directories. Set for dependency projects.
+	APIOnly bool
 }
 
 // Progress tracks indexing progress
@@ -72,6 +114,14 @@ type Statistics struct {
 	ChunksCreated       int
 	EmbeddingsGenerated int
 	EmbeddingsFailed    int
+	FilesPurged         int      // Previously indexed files removed because they are now ignored (or gone from the revision)
+	GitCommit           string   // Commit that Config.Ref resolved to, empty for the working tree
+	DependenciesIndexed int      // Dependency modules indexed or re-indexed
+	DependenciesCurrent int      // Dependency modules already indexed at the required version
+	DependenciesMissing []string // path@version of requirements whose sources are not on disk
+	StdlibVersion       string   // Go release whose standard library is linked, e.g. "go1.22.3"
+	StdlibExact         bool     // StdlibVersion matches the project's toolchain or go directive
+	StdlibIndexed       bool     // The standard library was indexed by this run rather than reused
 	Duration            time.Duration
 	ErrorMessages       []string
 }
@@ -115,7 +165,24 @@ func (idx *Indexer) IndexProject(ctx context.Context, rootPath string, config *C
 			IncludeVendor:      false,
 			GenerateEmbeddings: true,
 		}
+		if err := applyProjectConfig(rootPath, config); err != nil {
+			return nil, err
+		}
+	}
+	if config.Project == nil {
+		project, err := projectconfig.Load(rootPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load project config: %w", err)
+		}
+		config.Project = project
+	}
+	if config.HistoryDepth <= 0 {
+		config.HistoryDepth = config.Project.Index.HistoryDepth
 	}
+	if config.Project.Embeddings.Enabled != nil && !*config.Project.Embeddings.Enabled {
+		config.GenerateEmbeddings = false
+	}
+	idx.chunker = chunker.NewWithStrategy(chunker.Strategy(config.Project.Index.ChunkStrategy))
 
 	// Initialize embedder if needed and embeddings are requested
 	if config.GenerateEmbeddings && idx.embedder == nil {
@@ -125,6 +192,10 @@ func (idx *Indexer) IndexProject(ctx context.Context, rootPath string, config *C
 			log.Printf("Warning: Failed to initialize embedder: %v. Continuing without embeddings.", err)
 			config.GenerateEmbeddings = false
 		} else {
+			// Reuse the on-disk embedding cache when the storage backend provides one
+			if cache, ok := idx.storage.(embedder.PersistentCache); ok {
+				emb = embedder.NewPersistentCachedEmbedder(emb, cache, embedder.PersistentCacheSizeFromEnv())
+			}
 			idx.embedder = emb
 		}
 	}
@@ -139,29 +210,94 @@ func (idx *Indexer) IndexProject(ctx context.Context, rootPath string, config *C
 		ErrorMessages: make([]string, 0),
 	}
 
+	// Read from a git revision when requested, otherwise from the working tree
+	src := newWorkTreeSource(rootPath)
+	projectKey := rootPath
+	if config.Ref != "" {
+		var err error
+		src, err = newRevisionSource(rootPath, config.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve revision %q: %w", config.Ref, err)
+		}
+		projectKey = storage.SnapshotRootPath(rootPath, config.Ref)
+		stats.GitCommit = src.commit.Hash.String()
+	}
+	idx.source = src
+	defer func() {
+		idx.source = nil
+		_ = src.close()
+	}()
+
 	// Get or create project
-	project, err := idx.getOrCreateProject(ctx, rootPath)
+	project, err := idx.getOrCreateProject(ctx, projectKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get or create project: %w", err)
 	}
+	if src.isRevision() {
+		project.GitRef = config.Ref
+		project.GitCommit = stats.GitCommit
+	}
 
 	// Discover Go files
-	files, err := idx.discoverFiles(rootPath, config)
+	files, err := idx.discoverSourceFiles(src, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover files: %w", err)
 	}
 
+	// Discover go.mod files and go.work members
+	if err := idx.discoverModules(ctx, project, config); err != nil {
+		return nil, fmt.Errorf("failed to discover modules: %w", err)
+	}
+
+	// Drop previously indexed files that ignore rules now exclude
+	purged, err := idx.purgeIgnoredFiles(ctx, project, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge ignored files: %w", err)
+	}
+	stats.FilesPurged = purged
+
 	// Index files concurrently
 	err = idx.indexFiles(ctx, project, files, config, stats)
 	if err != nil {
 		return nil, fmt.Errorf("failed to index files: %w", err)
 	}
 
+	// Record git history on chunks; a failure leaves the index usable
+	if config.GitHistory {
+		if err := idx.annotateHistory(ctx, project, config); err != nil {
+			stats.ErrorMessages = append(stats.ErrorMessages, fmt.Sprintf("git history: %v", err))
+		}
+	}
+
+	// Classify types with the project's DDD rules; a failure keeps the naming conventions
+	if err := idx.classifyDomain(ctx, project, config); err != nil {
+		stats.ErrorMessages = append(stats.ErrorMessages, fmt.Sprintf("ddd rules: %v", err))
+	}
+
+	// Link commands and queries to their handlers, repositories and aggregates
+	if err := idx.linkUseCases(ctx, project); err != nil {
+		stats.ErrorMessages = append(stats.ErrorMessages, fmt.Sprintf("use cases: %v", err))
+	}
+
 	// Update project statistics
 	if err := idx.updateProjectStats(ctx, project); err != nil {
 		return nil, fmt.Errorf("failed to update project stats: %w", err)
 	}
 
+	// Index required modules from the module cache
+	if dependencyMode(config) && !src.isRevision() {
+		if err := idx.indexDependencies(ctx, project, config, stats); err != nil {
+			return nil, fmt.Errorf("failed to index dependencies: %w", err)
+		}
+	}
+
+	// Index the standard library from GOROOT; a failure leaves the project index usable
+	if config.Stdlib && !src.isRevision() {
+		if err := idx.indexStdlib(ctx, project, config, stats); err != nil {
+			stats.ErrorMessages = append(stats.ErrorMessages, fmt.Sprintf("stdlib: %v", err))
+		}
+	}
+
 	stats.Duration = time.Since(startTime)
 	return stats, nil
 }
@@ -198,39 +334,217 @@ func (idx *Indexer) getOrCreateProject(ctx context.Context, rootPath string) (*s
 	return project, nil
 }
 
-// discoverFiles finds all Go files in the project
+// discoverModules records the project's modules and their dependencies, and keeps the
+// module set for assigning files to modules. The project's module name and Go version
+// follow the root module, or the first nested module when there is no root go.mod.
+func (idx *Indexer) discoverModules(ctx context.Context, project *storage.Project, config *Config) error {
+	projectCfg := config.Project
+	if projectCfg == nil {
+		projectCfg = projectconfig.Default()
+	}
+
+	set, err := modules.DiscoverFS(idx.sourceFor(project).fsys, projectCfg.ExcludesDir)
+	if err != nil {
+		return err
+	}
+	idx.modules = set
+
+	stored := make([]*storage.Module, 0, len(set.Modules))
+	for _, mod := range set.Modules {
+		sm := &storage.Module{
+			ModulePath:  mod.Path,
+			Dir:         mod.Dir,
+			GoVersion:   mod.GoVersion,
+			InWorkspace: mod.InWorkspace,
+		}
+		for _, req := range mod.Requires {
+			sm.Dependencies = append(sm.Dependencies, storage.ModuleDependency{
+				Path:           req.Path,
+				Version:        req.Version,
+				Indirect:       req.Indirect,
+				ReplacePath:    req.ReplacePath,
+				ReplaceVersion: req.ReplaceVersion,
+			})
+		}
+		stored = append(stored, sm)
+	}
+	if err := idx.storage.ReplaceModules(ctx, project.ID, stored); err != nil {
+		return err
+	}
+
+	if root := set.Root(); root != nil {
+		project.ModuleName = root.Path
+		project.GoVersion = root.GoVersion
+	}
+	return nil
+}
+
+// fileModule returns the module path and package import path for a project-relative file
+func (idx *Indexer) fileModule(relPath string) (modulePath, importPath string) {
+	if idx.modules == nil {
+		return "", ""
+	}
+	mod := idx.modules.ModuleFor(relPath)
+	if mod == nil {
+		return "", ""
+	}
+	return mod.Path, idx.modules.ImportPath(relPath)
+}
+
+// applyProjectConfig loads the project's .gocontext.yaml into a default config,
+// letting the file override the include_tests and include_vendor defaults
+func applyProjectConfig(rootPath string, config *Config) error {
+	project, err := projectconfig.Load(rootPath)
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	config.Project = project
+	config.IncludeTests = project.IncludeTests(config.IncludeTests)
+	config.IncludeVendor = project.IncludeVendor(config.IncludeVendor)
+	config.GitHistory = project.GitHistory(config.GitHistory)
+	config.HistoryDepth = project.Index.HistoryDepth
+	config.Dependencies = project.DependencyMode(config.Dependencies)
+	config.Stdlib = project.IndexStdlib(config.Stdlib)
+	return nil
+}
+
+// newIgnoreMatcher loads the ignore files that apply to a source
+func newIgnoreMatcher(src *source, project *projectconfig.ProjectConfig) (*ignore.Matcher, error) {
+	return ignore.NewFS(src.root, src.fsys, project.UseGitIgnore())
+}
+
+// sourceFor returns the tree being indexed, defaulting to the project's working directory
+func (idx *Indexer) sourceFor(project *storage.Project) *source {
+	if idx.source != nil {
+		return idx.source
+	}
+	return newWorkTreeSource(project.RootPath)
+}
+
+// purgeIgnoredFiles deletes indexed files that are now excluded by ignore files or
+// project config globs, and for git revisions files the revision no longer contains.
+// Deleting a file cascades to its symbols, chunks and embeddings.
+func (idx *Indexer) purgeIgnoredFiles(ctx context.Context, project *storage.Project, config *Config) (int, error) {
+	files, err := idx.storage.ListFiles(ctx, project.ID)
+	if err != nil {
+		return 0, err
+	}
+	if len(files) == 0 {
+		return 0, nil
+	}
+
+	projectCfg := config.Project
+	if projectCfg == nil {
+		projectCfg = projectconfig.Default()
+	}
+	src := idx.sourceFor(project)
+	ignored, err := newIgnoreMatcher(src, projectCfg)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, file := range files {
+		rel := filepath.ToSlash(file.FilePath)
+		if !ignored.Ignored(rel, false) && projectCfg.ShouldIndex(rel) &&
+			(!src.isRevision() || src.exists(rel)) {
+			continue
+		}
+		if err := idx.storage.DeleteFile(ctx, file.ID); err != nil {
+			return purged, fmt.Errorf("delete %s: %w", file.FilePath, err)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// relativePath returns path relative to rootPath with forward slashes, as matched by config globs
+func relativePath(rootPath, path string) string {
+	rel, err := filepath.Rel(rootPath, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// discoverFiles finds all Go files in the project's working directory
 func (idx *Indexer) discoverFiles(rootPath string, config *Config) ([]string, error) {
+	return idx.discoverSourceFiles(newWorkTreeSource(rootPath), config)
+}
+
+// discoverSourceFiles finds all Go files in a source. Returned paths are joined to the
+// source root, whether or not the source is the working tree.
+func (idx *Indexer) discoverSourceFiles(src *source, config *Config) ([]string, error) {
 	var files []string
 
-	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+	project := config.Project
+	if project == nil {
+		project = projectconfig.Default()
+	}
+
+	ignored, err := newIgnoreMatcher(src, project)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fs.WalkDir(src.fsys, ".", func(rel string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
 		// Skip directories
-		if info.IsDir() {
+		if d.IsDir() {
+			if rel == "." {
+				return nil
+			}
 			// Skip vendor unless explicitly included
-			if !config.IncludeVendor && info.Name() == "vendor" {
-				return filepath.SkipDir
+			if !config.IncludeVendor && d.Name() == "vendor" {
+				return fs.SkipDir
 			}
 			// Skip hidden directories
-			if strings.HasPrefix(info.Name(), ".") {
-				return filepath.SkipDir
+			if strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
 			}
-			return nil
+			// Skip directories excluded as a whole by the project config
+			if project.ExcludesDir(rel) {
+				return fs.SkipDir
+			}
+			// Skip packages that are not part of a dependency's importable API
+			if config.APIOnly && !apiDir(d.Name()) {
+				return fs.SkipDir
+			}
+			// Skip ignored directories, otherwise pick up their ignore files
+			if ignored.Match(rel, true) {
+				return fs.SkipDir
+			}
+			return ignored.LoadDir(rel)
 		}
 
 		// Check if it's a Go file
-		if !strings.HasSuffix(path, ".go") {
+		if !strings.HasSuffix(rel, ".go") {
 			return nil
 		}
 
 		// Skip test files unless explicitly included
-		if !config.IncludeTests && strings.HasSuffix(path, "_test.go") {
+		if !config.IncludeTests && strings.HasSuffix(rel, "_test.go") {
 			return nil
 		}
 
-		files = append(files, path)
+		// Apply ignore files, project include/exclude globs and size limit
+		if ignored.Match(rel, false) || !project.ShouldIndex(rel) {
+			return nil
+		}
+		if project.Index.MaxFileSize > 0 {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if info.Size() > int64(project.Index.MaxFileSize) {
+				return nil
+			}
+		}
+
+		files = append(files, src.path(rel))
 		return nil
 	})
 
@@ -334,8 +648,9 @@ func (idx *Indexer) indexBatch(ctx context.Context, project *storage.Project, fi
 			continue
 		}
 
-		// Collect chunks for embedding
-		if config.GenerateEmbeddings && len(fileChunks) > 0 {
+		// Collect chunks for embedding, skipping paths excluded from embeddings
+		if config.GenerateEmbeddings && len(fileChunks) > 0 &&
+			(config.Project == nil || config.Project.EmbeddingsEnabled(relativePath(idx.sourceFor(project).root, filePath))) {
 			for _, chunk := range fileChunks {
 				allChunks = append(allChunks, chunkWithID{
 					chunk:   chunk,
@@ -378,13 +693,14 @@ func (idx *Indexer) indexFile(ctx context.Context, store storage.Storage, projec
 	filePath string, config *Config, indexed, skipped, failed, symbols, chunks *int32) ([]*storage.Chunk, error) {
 
 	// Compute relative path
-	relPath, err := filepath.Rel(project.RootPath, filePath)
+	src := idx.sourceFor(project)
+	relPath, err := filepath.Rel(src.root, filePath)
 	if err != nil {
 		return nil, err
 	}
 
 	// Compute file hash
-	hash, modTime, sizeBytes, err := computeFileHash(filePath)
+	hash, modTime, sizeBytes, content, err := src.hashFile(relPath)
 	if err != nil {
 		return nil, err
 	}
@@ -412,7 +728,12 @@ func (idx *Indexer) indexFile(ctx context.Context, store storage.Storage, projec
 	}
 
 	// Parse the file
-	parseResult, err := idx.parser.ParseFile(filePath)
+	if content == nil {
+		if content, err = src.readFile(relPath); err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+	parseResult, err := idx.parser.ParseSource(filePath, content)
 	if err != nil {
 		return nil, err
 	}
@@ -422,10 +743,16 @@ func (idx *Indexer) indexFile(ctx context.Context, store storage.Storage, projec
 		ProjectID:   project.ID,
 		FilePath:    relPath,
 		PackageName: parseResult.PackageName,
+		PackageDoc:  parseResult.PackageDoc,
 		ContentHash: hash,
 		ModTime:     modTime,
 		SizeBytes:   sizeBytes,
+
+		BuildConstraint: parseResult.Build.Expr,
+		GOOS:            parseResult.Build.GOOS,
+		GOARCH:          parseResult.Build.GOARCH,
 	}
+	file.ModulePath, file.ImportPath = idx.fileModule(relPath)
 
 	// Check for parse errors
 	if len(parseResult.Errors) > 0 {
@@ -443,32 +770,82 @@ func (idx *Indexer) indexFile(ctx context.Context, store storage.Storage, projec
 			FileID:     file.ID,
 			ImportPath: imp.Path,
 			Alias:      imp.Alias,
+			Line:       imp.Line,
 		}
 		if err := store.UpsertImport(ctx, impRecord); err != nil {
 			return nil, fmt.Errorf("failed to store import: %w", err)
 		}
 	}
 
-	// Store symbols
+	// Store annotations
+	for _, a := range parseResult.Annotations {
+		annotation := &storage.Annotation{
+			FileID: file.ID,
+			Kind:   string(a.Kind),
+			Text:   a.Text,
+			Line:   a.Line,
+			Symbol: a.Symbol,
+			Owner:  a.Owner,
+			Issues: a.Issues,
+		}
+		if err := store.InsertAnnotation(ctx, annotation); err != nil {
+			return nil, fmt.Errorf("failed to store annotation: %w", err)
+		}
+	}
+
+	// Store test functions and the calls linking them to the code under test
+	for _, t := range parseResult.Tests {
+		test := &storage.TestFunction{
+			FileID:    file.ID,
+			Name:      t.Name,
+			Kind:      string(t.Kind),
+			StartLine: t.Start.Line,
+			EndLine:   t.End.Line,
+			Target:    t.Target,
+		}
+		for _, c := range t.Calls {
+			test.Calls = append(test.Calls, storage.TestCall{Package: c.Package, Name: c.Name, Method: c.Method})
+		}
+		if err := store.InsertTestFunction(ctx, test); err != nil {
+			return nil, fmt.Errorf("failed to store test function: %w", err)
+		}
+	}
+
+	// Dependencies keep only their exported API
+	if config.APIOnly {
+		parseResult.Symbols = exportedSymbols(parseResult.Symbols)
+	}
+
+	// Store symbols, remembering where declarations start to link their chunks
 	symbolCount := 0
+	symbolAt := make(map[int]int64)
 	for i := range parseResult.Symbols {
 		sym := storage.FromTypesSymbol(parseResult.Symbols[i], file.ID)
 		if err := store.UpsertSymbol(ctx, sym); err != nil {
 			return nil, fmt.Errorf("failed to store symbol: %w", err)
 		}
+		if _, ok := symbolAt[sym.StartLine]; !ok && sym.Kind != string(types.KindField) {
+			symbolAt[sym.StartLine] = sym.ID
+		}
 		symbolCount++
 	}
 
-	// Create chunks
-	fileChunks, err := idx.chunker.ChunkFile(filePath, parseResult, file.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to chunk file: %w", err)
+	// Create chunks; API-only files without exported symbols get no package chunk
+	var fileChunks []*types.Chunk
+	if !config.APIOnly || len(parseResult.Symbols) > 0 {
+		fileChunks, err = idx.chunker.ChunkSource(content, parseResult, file.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to chunk file: %w", err)
+		}
 	}
 
 	// Store chunks and collect them for return
 	var storedChunks []*storage.Chunk
 	chunkCount := 0
 	for _, chunk := range fileChunks {
+		if id, ok := symbolAt[chunk.StartLine]; ok && chunk.SymbolID == nil {
+			chunk.SymbolID = &id
+		}
 		storageChunk := &storage.Chunk{
 			FileID:        file.ID,
 			SymbolID:      chunk.SymbolID,
@@ -511,6 +888,16 @@ func (idx *Indexer) checkFileChanged(ctx context.Context, store storage.Storage,
 
 	// File exists - check if it has changed
 	if existingFile.ContentHash == hash {
+		// A go.mod or go.work change can move an unchanged file to another module
+		modulePath, importPath := idx.fileModule(relPath)
+		if existingFile.ModulePath != modulePath || existingFile.ImportPath != importPath {
+			existingFile.ModulePath = modulePath
+			existingFile.ImportPath = importPath
+			if err := store.UpsertFile(ctx, existingFile); err != nil {
+				return false, fmt.Errorf("failed to update file module: %w", err)
+			}
+		}
+
 		// File unchanged, skip
 		atomic.AddInt32(skipped, 1)
 		return true, nil
@@ -532,6 +919,16 @@ func (idx *Indexer) checkFileChanged(ctx context.Context, store storage.Storage,
 		return false, fmt.Errorf("failed to delete old imports: %w", err)
 	}
 
+	// Delete annotations
+	if err := store.DeleteAnnotationsByFile(ctx, existingFile.ID); err != nil {
+		return false, fmt.Errorf("failed to delete old annotations: %w", err)
+	}
+
+	// Delete test functions
+	if err := store.DeleteTestsByFile(ctx, existingFile.ID); err != nil {
+		return false, fmt.Errorf("failed to delete old test functions: %w", err)
+	}
+
 	return false, nil
 }
 
@@ -735,22 +1132,9 @@ type goModInfo struct {
 
 // parseGoMod extracts basic info from go.mod file
 func parseGoMod(goModPath string) (*goModInfo, error) {
-	content, err := os.ReadFile(goModPath)
+	mod, err := modules.ParseGoMod(goModPath)
 	if err != nil {
 		return nil, err
 	}
-
-	info := &goModInfo{}
-	lines := strings.Split(string(content), "\n")
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "module ") {
-			info.Module = strings.TrimSpace(strings.TrimPrefix(line, "module"))
-		} else if strings.HasPrefix(line, "go ") {
-			info.GoVersion = strings.TrimSpace(strings.TrimPrefix(line, "go"))
-		}
-	}
-
-	return info, nil
+	return &goModInfo{Module: mod.Path, GoVersion: mod.GoVersion}, nil
 }
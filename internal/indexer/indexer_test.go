@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -15,8 +16,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	projectconfig "github.com/dshills/gocontext-mcp/internal/config"
 	"github.com/dshills/gocontext-mcp/internal/embedder"
 	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
 )
 
 // mockEmbedder implements embedder.Embedder for testing
@@ -133,6 +136,18 @@ func createTestFile(t testing.TB, dir, name, content string) string {
 	return filePath
 }
 
+// findSymbol returns the project's only symbol named name, with its DDD patterns
+func findSymbol(t testing.TB, store storage.Storage, projectID int64, name string) *storage.Symbol {
+	t.Helper()
+
+	symbols, err := store.ListSymbolsByName(context.Background(), projectID, name)
+	require.NoError(t, err)
+	require.Len(t, symbols, 1, name)
+	sym, err := store.GetSymbol(context.Background(), symbols[0].ID)
+	require.NoError(t, err)
+	return sym
+}
+
 // TestNew verifies indexer initialization
 func TestNew(t *testing.T) {
 	store := setupTestStorage(t)
@@ -296,6 +311,404 @@ func TestDiscoverFiles_SkipHiddenDirs(t *testing.T) {
 	assert.False(t, strings.Contains(files[0], ".hidden"))
 }
 
+// TestDiscoverFiles_ProjectConfig tests include/exclude globs and the size limit from .gocontext.yaml
+func TestDiscoverFiles_ProjectConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	createTestFile(t, tmpDir, "main.go", "package main\n")
+	createTestFile(t, tmpDir, "internal/gen/api.go", "package gen\n")
+	createTestFile(t, tmpDir, "internal/app/app.go", "package app\n")
+	createTestFile(t, tmpDir, "internal/app/app.pb.go", "package app\n")
+	createTestFile(t, tmpDir, "internal/app/big.go", "package app\n"+strings.Repeat("// padding\n", 200))
+	createTestFile(t, tmpDir, ".gocontext.yaml", `index:
+  exclude: ["internal/gen/**", "*.pb.go"]
+  max_file_size: 1KB
+`)
+
+	idx := New(setupTestStorage(t))
+	config := &Config{IncludeTests: true}
+	require.NoError(t, applyProjectConfig(tmpDir, config))
+
+	files, err := idx.discoverFiles(tmpDir, config)
+	require.NoError(t, err)
+
+	var rel []string
+	for _, f := range files {
+		rel = append(rel, relativePath(tmpDir, f))
+	}
+	assert.ElementsMatch(t, []string{"main.go", "internal/app/app.go"}, rel)
+}
+
+// TestDiscoverFiles_IgnoreFiles tests .gitignore and .gocontextignore handling during discovery
+func TestDiscoverFiles_IgnoreFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	tmpDir := t.TempDir()
+
+	createTestFile(t, tmpDir, "main.go", "package main\n")
+	createTestFile(t, tmpDir, "build/out.go", "package build\n")
+	createTestFile(t, tmpDir, "pkg/keep.go", "package pkg\n")
+	createTestFile(t, tmpDir, "pkg/scratch.go", "package pkg\n")
+	createTestFile(t, tmpDir, "pkg/mocks/db.go", "package mocks\n")
+	createTestFile(t, tmpDir, ".gitignore", "build/\n")
+	createTestFile(t, tmpDir, "pkg/.gitignore", "scratch.go\n")
+	createTestFile(t, tmpDir, ".gocontextignore", "mocks/\n")
+
+	idx := New(setupTestStorage(t))
+	config := &Config{IncludeTests: true}
+	require.NoError(t, applyProjectConfig(tmpDir, config))
+
+	files, err := idx.discoverFiles(tmpDir, config)
+	require.NoError(t, err)
+
+	var rel []string
+	for _, f := range files {
+		rel = append(rel, relativePath(tmpDir, f))
+	}
+	assert.ElementsMatch(t, []string{"main.go", "pkg/keep.go"}, rel)
+
+	// respect_gitignore: false keeps .gocontextignore only
+	createTestFile(t, tmpDir, ".gocontext.yaml", "index:\n  respect_gitignore: false\n")
+	config = &Config{IncludeTests: true}
+	require.NoError(t, applyProjectConfig(tmpDir, config))
+
+	files, err = idx.discoverFiles(tmpDir, config)
+	require.NoError(t, err)
+	assert.Len(t, files, 4)
+}
+
+// TestIndexProject_PurgesIgnoredFiles tests that files ignored after indexing are removed
+func TestIndexProject_PurgesIgnoredFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	tmpDir := t.TempDir()
+
+	createTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+	createTestFile(t, tmpDir, "tmp/experiment.go", "package tmp\n\nfunc Try() {}\n")
+
+	store := setupTestStorage(t)
+	defer store.Close()
+	idx := New(store)
+	config := &Config{Workers: 1, IncludeTests: true}
+
+	stats, err := idx.IndexProject(context.Background(), tmpDir, config)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.FilesIndexed)
+
+	createTestFile(t, tmpDir, ".gitignore", "tmp/\n")
+	config = &Config{Workers: 1, IncludeTests: true}
+	stats, err = idx.IndexProject(context.Background(), tmpDir, config)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.FilesPurged)
+
+	project, err := store.GetProject(context.Background(), tmpDir)
+	require.NoError(t, err)
+	files, err := store.ListFiles(context.Background(), project.ID)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "main.go", files[0].FilePath)
+}
+
+// TestIndexProject_MultiModule tests that files are assigned to the module that owns them
+func TestIndexProject_MultiModule(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	createTestFile(t, tmpDir, "go.mod", "module example.com/mono\n\ngo 1.22\n")
+	createTestFile(t, tmpDir, "go.work", "go 1.22\n\nuse (\n\t.\n\t./services/billing\n)\n")
+	createTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+	createTestFile(t, tmpDir, "services/billing/go.mod", "module example.com/billing\n\ngo 1.21\n\nrequire example.com/lib v1.0.0\n")
+	createTestFile(t, tmpDir, "services/billing/invoice/invoice.go", "package invoice\n\ntype Invoice struct{}\n")
+
+	store := setupTestStorage(t)
+	defer store.Close()
+	idx := New(store)
+
+	_, err := idx.IndexProject(context.Background(), tmpDir, &Config{Workers: 1, IncludeTests: true})
+	require.NoError(t, err)
+
+	project, err := store.GetProject(context.Background(), tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/mono", project.ModuleName)
+
+	mods, err := store.ListModules(context.Background(), project.ID)
+	require.NoError(t, err)
+	require.Len(t, mods, 2)
+	assert.Equal(t, "example.com/billing", mods[1].ModulePath)
+	assert.True(t, mods[1].InWorkspace)
+	assert.Equal(t, 1, mods[1].FilesCount)
+	require.Len(t, mods[1].Dependencies, 1)
+	assert.Equal(t, "example.com/lib", mods[1].Dependencies[0].Path)
+
+	file, err := store.GetFile(context.Background(), project.ID, filepath.Join("services", "billing", "invoice", "invoice.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/billing", file.ModulePath)
+	assert.Equal(t, "example.com/billing/invoice", file.ImportPath)
+}
+
+// TestIndexProject_Dependencies tests indexing the exported API of required modules
+// from a module cache
+func TestIndexProject_Dependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	ctx := context.Background()
+
+	createTestFile(t, tmpDir, "go.mod", "module example.com/app\n\ngo 1.22\n\nrequire (\n\texample.com/lib v1.2.0\n\texample.com/absent v0.1.0\n\texample.com/text v0.3.0 // indirect\n)\n")
+	createTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+
+	libDir := filepath.Join(cache, "example.com", "lib@v1.2.0")
+	createTestFile(t, libDir, "go.mod", "module example.com/lib\n\ngo 1.21\n")
+	createTestFile(t, libDir, "lib.go", `package lib
+
+// Client talks to the service
+type Client struct {
+	Timeout int
+	retries int
+}
+
+// Do sends a request
+func (c *Client) Do() error { return c.send() }
+
+func (c *Client) send() error { return nil }
+
+type state struct{}
+
+// Name is exported but its receiver is not
+func (s state) Name() string { return "" }
+`)
+	createTestFile(t, libDir, "lib_test.go", "package lib\n\nfunc TestDo() {}\n")
+	createTestFile(t, libDir, "internal/wire/wire.go", "package wire\n\nfunc Encode() {}\n")
+	createTestFile(t, cache, "example.com/text@v0.3.0/text.go", "package text\n\nfunc Width() int { return 0 }\n")
+
+	store := setupTestStorage(t)
+	defer store.Close()
+	idx := New(store)
+
+	stats, err := idx.IndexProject(ctx, tmpDir, &Config{Workers: 1, Dependencies: projectconfig.DependenciesDirect})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.DependenciesIndexed)
+	assert.Equal(t, []string{"example.com/absent@v0.1.0"}, stats.DependenciesMissing)
+
+	project, err := store.GetProject(ctx, tmpDir)
+	require.NoError(t, err)
+	assert.False(t, project.IsDependency())
+
+	deps, err := store.ListProjectDependencies(ctx, project.ID)
+	require.NoError(t, err)
+	require.Len(t, deps, 1, "indirect requirements are skipped in direct mode")
+	assert.True(t, deps[0].Direct)
+	lib := deps[0].Project
+	assert.Equal(t, "example.com/lib@v1.2.0", lib.ModuleID())
+	assert.Equal(t, libDir, lib.RootPath)
+	assert.Equal(t, 1, lib.TotalFiles, "test files and internal packages are skipped")
+
+	symbols, err := store.ListSymbolsByName(ctx, lib.ID, "Client")
+	require.NoError(t, err)
+	assert.Len(t, symbols, 1)
+	for _, name := range []string{"retries", "send", "Name", "Encode"} {
+		symbols, err := store.ListSymbolsByName(ctx, lib.ID, name)
+		require.NoError(t, err)
+		assert.Empty(t, symbols, "%s is not part of the exported API", name)
+	}
+
+	file, err := store.GetFile(ctx, lib.ID, "lib.go")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/lib", file.ImportPath)
+
+	// Module cache directories are immutable: a second run reuses the indexed version
+	stats, err = idx.IndexProject(ctx, tmpDir, &Config{Workers: 1, Dependencies: projectconfig.DependenciesAll})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.DependenciesIndexed)
+	assert.Equal(t, 1, stats.DependenciesCurrent)
+
+	deps, err = store.ListProjectDependencies(ctx, project.ID)
+	require.NoError(t, err)
+	require.Len(t, deps, 2)
+	assert.Equal(t, "example.com/text", deps[1].Project.ModuleName)
+	assert.False(t, deps[1].Direct)
+}
+
+// TestIndexProject_Stdlib tests that the standard library is indexed once per Go
+// release and shared by the projects using it
+func TestIndexProject_Stdlib(t *testing.T) {
+	goroot := t.TempDir()
+	t.Setenv("GOROOT", goroot)
+	t.Setenv("GOMODCACHE", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	ctx := context.Background()
+
+	createTestFile(t, goroot, "VERSION", "go1.22.5\ntime 2024-07-02T00:00:00Z\n")
+	createTestFile(t, goroot, "src/go.mod", "module std\n\ngo 1.22\n")
+	createTestFile(t, goroot, "src/context/context.go", "package context\n\n// Context carries deadlines\ntype Context interface{}\n\nfunc background() {}\n")
+	createTestFile(t, goroot, "src/net/http/server.go", "package http\n\n// Server serves HTTP\ntype Server struct{}\n")
+	createTestFile(t, goroot, "src/internal/bytealg/bytealg.go", "package bytealg\n\nfunc Index() {}\n")
+	createTestFile(t, goroot, "src/cmd/go/main.go", "package main\n\nfunc Main() {}\n")
+	createTestFile(t, goroot, "src/vendor/golang.org/x/net/net.go", "package net\n\nfunc Dial() {}\n")
+
+	store := setupTestStorage(t)
+	defer store.Close()
+	idx := New(store)
+
+	var roots []string
+	for i := 0; i < 2; i++ {
+		dir := t.TempDir()
+		createTestFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+		createTestFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+		roots = append(roots, dir)
+
+		stats, err := idx.IndexProject(ctx, dir, &Config{Workers: 1, Stdlib: true})
+		require.NoError(t, err)
+		assert.Empty(t, stats.ErrorMessages)
+		assert.Equal(t, "go1.22.5", stats.StdlibVersion)
+		assert.True(t, stats.StdlibExact)
+		assert.Equal(t, i == 0, stats.StdlibIndexed, "the second project reuses the stored stdlib")
+	}
+
+	var stdlibIDs []int64
+	for _, root := range roots {
+		project, err := store.GetProject(ctx, root)
+		require.NoError(t, err)
+		deps, err := store.ListProjectDependencies(ctx, project.ID)
+		require.NoError(t, err)
+		require.Len(t, deps, 1)
+		assert.True(t, deps[0].Project.IsStdlib())
+		stdlibIDs = append(stdlibIDs, deps[0].DependencyID)
+	}
+	assert.Equal(t, stdlibIDs[0], stdlibIDs[1])
+
+	stdlib, err := store.GetProject(ctx, filepath.Join(goroot, "src"))
+	require.NoError(t, err)
+	assert.Equal(t, "std@go1.22.5", stdlib.ModuleID())
+	assert.Equal(t, 2, stdlib.TotalFiles, "internal, vendor and cmd are skipped")
+
+	file, err := store.GetFile(ctx, stdlib.ID, filepath.Join("net", "http", "server.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "net/http", file.ImportPath)
+
+	symbols, err := store.ListSymbolsByName(ctx, stdlib.ID, "background")
+	require.NoError(t, err)
+	assert.Empty(t, symbols)
+}
+
+// runGit runs git in dir with a fixed identity, skipping the test if git is not installed
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Test",
+		"GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test",
+		"GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %s: %s", strings.Join(args, " "), out)
+	return strings.TrimSpace(string(out))
+}
+
+// TestIndexProject_GitRef tests indexing a revision without touching the working tree
+func TestIndexProject_GitRef(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+
+	mainV1 := "package main\n\nfunc main() {}\n"
+	runGit(t, tmpDir, "init", "-q", "-b", "main")
+	createTestFile(t, tmpDir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	createTestFile(t, tmpDir, "main.go", mainV1)
+	createTestFile(t, tmpDir, "legacy.go", "package main\n\nfunc Legacy() {}\n")
+	runGit(t, tmpDir, "add", "-A")
+	runGit(t, tmpDir, "commit", "-q", "-m", "First")
+	runGit(t, tmpDir, "branch", "review")
+
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, "legacy.go")))
+	createTestFile(t, tmpDir, "feature.go", "package main\n\nfunc Feature() {}\n")
+	runGit(t, tmpDir, "add", "-A")
+	runGit(t, tmpDir, "commit", "-q", "-m", "Second")
+	head := runGit(t, tmpDir, "rev-parse", "HEAD")
+
+	// Uncommitted changes must not leak into the snapshot
+	createTestFile(t, tmpDir, "dirty.go", "package main\n\nfunc Dirty() {}\n")
+	createTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() { Dirty() }\n")
+
+	store := setupTestStorage(t)
+	defer store.Close()
+	idx := New(store)
+	config := func() *Config { return &Config{Workers: 1, IncludeTests: true, Ref: "review"} }
+
+	stats, err := idx.IndexProject(ctx, tmpDir, config())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.FilesIndexed)
+	assert.Equal(t, runGit(t, tmpDir, "rev-parse", "review"), stats.GitCommit)
+
+	_, err = store.GetProject(ctx, tmpDir)
+	assert.ErrorIs(t, err, storage.ErrNotFound, "the working tree project is not created")
+
+	project, err := store.GetProject(ctx, storage.SnapshotRootPath(tmpDir, "review"))
+	require.NoError(t, err)
+	assert.Equal(t, "review", project.GitRef)
+	assert.Equal(t, stats.GitCommit, project.GitCommit)
+	assert.Equal(t, "example.com/app", project.ModuleName)
+	assert.Equal(t, tmpDir, project.SourcePath())
+
+	file, err := store.GetFile(ctx, project.ID, "main.go")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(mainV1)), file.SizeBytes, "content comes from the commit")
+	assert.Equal(t, "example.com/app", file.ImportPath)
+	_, err = store.GetFile(ctx, project.ID, "legacy.go")
+	require.NoError(t, err)
+
+	// Moving the branch re-indexes the snapshot and drops files the revision no longer has
+	runGit(t, tmpDir, "branch", "-f", "review", "main")
+	stats, err = idx.IndexProject(ctx, tmpDir, config())
+	require.NoError(t, err)
+	assert.Equal(t, head, stats.GitCommit)
+	assert.Equal(t, 1, stats.FilesIndexed, "only feature.go is new")
+	assert.Equal(t, 1, stats.FilesPurged)
+
+	files, err := store.ListFiles(ctx, project.ID)
+	require.NoError(t, err)
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.FilePath)
+	}
+	assert.ElementsMatch(t, []string{"feature.go", "main.go"}, paths)
+
+	_, err = idx.IndexProject(ctx, tmpDir, &Config{Workers: 1, Ref: "no-such-branch"})
+	assert.Error(t, err)
+}
+
+// TestIndexProject_EmbeddingExcludes tests that excluded paths are indexed without embeddings
+func TestIndexProject_EmbeddingExcludes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	createTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+	createTestFile(t, tmpDir, "testdata/fixture.go", "package testdata\n\nfunc Fixture() {}\n")
+	createTestFile(t, tmpDir, ".gocontext.yaml", `embeddings:
+  exclude: ["testdata/**"]
+index:
+  chunk_strategy: file
+`)
+
+	store := setupTestStorage(t)
+	defer store.Close()
+
+	emb := newMockEmbedder()
+	idx := NewWithEmbedder(store, emb)
+
+	stats, err := idx.IndexProject(context.Background(), tmpDir, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, stats.FilesIndexed)
+	assert.Equal(t, 2, stats.ChunksCreated, "file strategy creates one chunk per file")
+	assert.Equal(t, 1, stats.EmbeddingsGenerated)
+	assert.Equal(t, 1, emb.getCallCount())
+}
+
 // TestComputeFileHash tests hash computation
 func TestComputeFileHash(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -466,6 +879,194 @@ func main() {
 	assert.Greater(t, stats.Duration, time.Duration(0))
 }
 
+// TestIndexProject_LinksChunksToSymbols tests that symbol chunks reference their symbol
+func TestIndexProject_LinksChunksToSymbols(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "set.go", `package set
+
+// Set is a generic set
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// Add inserts v
+func (s *Set[T]) Add(v T) {}
+`)
+
+	store := setupTestStorage(t)
+	defer store.Close()
+	idx := New(store)
+
+	ctx := context.Background()
+	_, err := idx.IndexProject(ctx, tmpDir, &Config{Workers: 1, BatchSize: 10})
+	require.NoError(t, err)
+
+	project, err := store.GetProject(ctx, tmpDir)
+	require.NoError(t, err)
+	file, err := store.GetFile(ctx, project.ID, "set.go")
+	require.NoError(t, err)
+	chunks, err := store.ListChunksByFile(ctx, file.ID)
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+
+	var names []string
+	for _, chunk := range chunks {
+		require.NotNil(t, chunk.SymbolID)
+		sym, err := store.GetSymbol(ctx, *chunk.SymbolID)
+		require.NoError(t, err)
+		names = append(names, sym.Name)
+	}
+	assert.ElementsMatch(t, []string{"Set", "Add"}, names)
+
+	// Symbol filters apply to indexed chunks
+	results, err := store.SearchText(ctx, project.ID, "set", 10, &storage.SearchFilters{Constraints: []string{"comparable"}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+}
+
+// TestIndexProject_DDDRules tests that types are classified with the project's rules
+// once every file is indexed
+func TestIndexProject_DDDRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "go.mod", "module example.com/shop\n\ngo 1.22\n")
+	createTestFile(t, tmpDir, ".gocontext.yaml", `ddd:
+  rules:
+    - pattern: entity
+      kind: struct
+      package: domain/**
+      implements: [domain.Identifiable]
+      confidence: 0.9
+`)
+	createTestFile(t, tmpDir, "domain/identity.go", `package domain
+
+// Identifiable has an identity
+type Identifiable interface {
+	Identity() string
+}
+`)
+	createTestFile(t, tmpDir, "domain/user.go", `package domain
+
+// User is a customer account
+type User struct {
+	Email string
+}
+
+// UserSettings holds preferences
+type UserSettings struct{}
+`)
+	createTestFile(t, tmpDir, "domain/user_identity.go", `package domain
+
+// Identity returns the email
+func (u *User) Identity() string { return u.Email }
+`)
+
+	store := setupTestStorage(t)
+	defer store.Close()
+	idx := New(store)
+
+	ctx := context.Background()
+	stats, err := idx.IndexProject(ctx, tmpDir, &Config{Workers: 1, BatchSize: 10})
+	require.NoError(t, err)
+	assert.Empty(t, stats.ErrorMessages)
+
+	project, err := store.GetProject(ctx, tmpDir)
+	require.NoError(t, err)
+	user := findSymbol(t, store, project.ID, "User")
+	assert.True(t, user.IsEntity, "the method making User implement Identifiable is in another file")
+	assert.Equal(t, []types.DDDMatch{{
+		Pattern: "entity", Confidence: 0.9, Reason: "is a struct, in package domain/**, implements domain.Identifiable",
+	}}, user.DDD)
+
+	settings := findSymbol(t, store, project.ID, "UserSettings")
+	assert.False(t, settings.IsEntity, "names containing User are no longer entities")
+	assert.Empty(t, settings.DDD)
+}
+
+// TestIndexProject_UseCases tests that commands are linked to their handlers,
+// repositories and aggregates across packages
+func TestIndexProject_UseCases(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "go.mod", "module example.com/shop\n\ngo 1.22\n")
+	createTestFile(t, tmpDir, "domain/order.go", `package domain
+
+import "context"
+
+// Order is placed by customers
+type Order struct {
+	ID string
+}
+
+// OrderRepository stores orders
+type OrderRepository interface {
+	Load(ctx context.Context, id string) (*Order, error)
+	Save(ctx context.Context, order *Order) error
+}
+`)
+	createTestFile(t, tmpDir, "app/place_order.go", `package app
+
+import (
+	"context"
+
+	"example.com/shop/domain"
+)
+
+// PlaceOrderCommand places an order
+type PlaceOrderCommand struct {
+	OrderID string
+}
+
+// PlaceOrderHandler handles PlaceOrderCommand
+type PlaceOrderHandler struct {
+	orders domain.OrderRepository
+}
+
+// Handle places the order
+func (h *PlaceOrderHandler) Handle(ctx context.Context, cmd PlaceOrderCommand) error {
+	order, err := h.orders.Load(ctx, cmd.OrderID)
+	if err != nil {
+		return err
+	}
+	return h.orders.Save(ctx, order)
+}
+`)
+
+	store := setupTestStorage(t)
+	defer store.Close()
+	idx := New(store)
+
+	ctx := context.Background()
+	stats, err := idx.IndexProject(ctx, tmpDir, &Config{Workers: 1, BatchSize: 10})
+	require.NoError(t, err)
+	assert.Empty(t, stats.ErrorMessages)
+
+	project, err := store.GetProject(ctx, tmpDir)
+	require.NoError(t, err)
+	command := findSymbol(t, store, project.ID, "PlaceOrderCommand")
+	handler := findSymbol(t, store, project.ID, "PlaceOrderHandler")
+	repository := findSymbol(t, store, project.ID, "OrderRepository")
+	order := findSymbol(t, store, project.ID, "Order")
+
+	relations, err := store.ListSymbolRelations(ctx, command.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []*storage.SymbolRelation{{
+		FromID: command.ID, ToID: handler.ID, Kind: "handled_by",
+		Reason: "PlaceOrderHandler.Handle takes PlaceOrderCommand; named after PlaceOrderCommand",
+	}}, relations)
+
+	relations, err = store.ListSymbolRelations(ctx, handler.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []*storage.SymbolRelation{
+		{FromID: handler.ID, ToID: order.ID, Kind: "uses_aggregate", Reason: "through orders.Load"},
+		{FromID: handler.ID, ToID: repository.ID, Kind: "uses_repository", Reason: "field orders; calls Load, Save"},
+	}, relations)
+
+	relations, err = store.ListSymbolRelations(ctx, repository.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []*storage.SymbolRelation{
+		{FromID: repository.ID, ToID: order.ID, Kind: "persists", Reason: "Load returns Order; Save takes Order"},
+	}, relations)
+}
+
 // TestIndexProject_EmptyProject tests indexing empty project
 func TestIndexProject_EmptyProject(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -1002,6 +1603,39 @@ func main() {
 	assert.GreaterOrEqual(t, len(imports), 3) // fmt, os, custom
 }
 
+// TestIndexProject_Annotations tests that annotations are stored and replaced on change
+func TestIndexProject_Annotations(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	createTestFile(t, tmpDir, "main.go", "package main\n\n// TODO(alice): handle flags #7\nfunc main() {}\n")
+
+	store := setupTestStorage(t)
+	defer store.Close()
+	idx := New(store)
+
+	_, err := idx.IndexProject(ctx, tmpDir, &Config{Workers: 1})
+	require.NoError(t, err)
+	project, err := store.GetProject(ctx, tmpDir)
+	require.NoError(t, err)
+
+	annotations, err := store.ListAnnotations(ctx, project.ID, storage.AnnotationFilter{})
+	require.NoError(t, err)
+	require.Len(t, annotations, 1)
+	assert.Equal(t, "todo", annotations[0].Kind)
+	assert.Equal(t, "main", annotations[0].Symbol)
+	assert.Equal(t, "alice", annotations[0].Owner)
+	assert.Equal(t, []string{"#7"}, annotations[0].Issues)
+
+	createTestFile(t, tmpDir, "main.go", "package main\n\n// FIXME: flags\nfunc main() {}\n")
+	_, err = idx.IndexProject(ctx, tmpDir, &Config{Workers: 1})
+	require.NoError(t, err)
+
+	annotations, err = store.ListAnnotations(ctx, project.ID, storage.AnnotationFilter{})
+	require.NoError(t, err)
+	require.Len(t, annotations, 1, "old annotations are replaced")
+	assert.Equal(t, "fixme", annotations[0].Kind)
+}
+
 // TestGenerateEmbeddingsForChunks tests batch embedding generation
 func TestGenerateEmbeddingsForChunks(t *testing.T) {
 	store := setupTestStorage(t)
@@ -1329,3 +1963,74 @@ func TestIndexLock_ConcurrentAcquisition(t *testing.T) {
 		})
 	}
 }
+
+// TestIndexProject_GitHistory tests per-chunk history following lines through commits
+func TestIndexProject_GitHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+
+	commit := func(author, date, msg string) string {
+		runGit(t, tmpDir, "add", "-A")
+		runGit(t, tmpDir, "commit", "-q", "-m", msg, "--author", author, "--date", date)
+		return runGit(t, tmpDir, "rev-parse", "HEAD")
+	}
+
+	runGit(t, tmpDir, "init", "-q", "-b", "main")
+	createTestFile(t, tmpDir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	createTestFile(t, tmpDir, "lib.go", "package lib\n\nfunc A() int {\n\treturn 1\n}\n\nfunc B() int {\n\treturn 2\n}\n")
+	first := commit("Alice <alice@example.com>", "2024-01-01T10:00:00Z", "Add A and B")
+
+	createTestFile(t, tmpDir, "lib.go", "package lib\n\nfunc A() int {\n\treturn 1\n}\n\nfunc B() int {\n\treturn 20\n}\n")
+	second := commit("Bob <bob@example.com>", "2024-02-01T10:00:00Z", "Change B")
+
+	// Inserting C above A shifts A's lines without touching them
+	createTestFile(t, tmpDir, "lib.go", "package lib\n\nfunc C() int {\n\treturn 3\n}\n\nfunc A() int {\n\treturn 1\n}\n\nfunc B() int {\n\treturn 20\n}\n")
+	commit("Carol <carol@example.com>", "2024-03-01T10:00:00Z", "Add C")
+
+	// Uncommitted edit to C
+	createTestFile(t, tmpDir, "lib.go", "package lib\n\nfunc C() int {\n\treturn 30\n}\n\nfunc A() int {\n\treturn 1\n}\n\nfunc B() int {\n\treturn 20\n}\n")
+
+	store := setupTestStorage(t)
+	defer store.Close()
+	stats, err := New(store).IndexProject(ctx, tmpDir, &Config{Workers: 1, GitHistory: true})
+	require.NoError(t, err)
+	assert.Empty(t, stats.ErrorMessages)
+
+	project, err := store.GetProject(ctx, tmpDir)
+	require.NoError(t, err)
+	file, err := store.GetFile(ctx, project.ID, "lib.go")
+	require.NoError(t, err)
+	chunks, err := store.ListChunksByFile(ctx, file.ID)
+	require.NoError(t, err)
+
+	history := func(fn string) types.ChunkHistory {
+		for _, c := range chunks {
+			if strings.Contains(c.Content, "func "+fn+"()") {
+				return c.History
+			}
+		}
+		t.Fatalf("no chunk for %s", fn)
+		return types.ChunkHistory{}
+	}
+
+	a := history("A")
+	assert.Equal(t, first, a.LastCommit)
+	assert.Equal(t, "Alice", a.LastAuthor)
+	assert.Equal(t, 1, a.ChangeCount)
+	assert.True(t, a.LastModified.Equal(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)))
+
+	b := history("B")
+	assert.Equal(t, second, b.LastCommit)
+	assert.Equal(t, "Bob", b.LastAuthor)
+	assert.Equal(t, 2, b.ChangeCount)
+
+	c := history("C")
+	assert.True(t, c.Uncommitted())
+	assert.Equal(t, 1, c.ChangeCount)
+
+	// Projects outside a repository are indexed without history
+	plain := t.TempDir()
+	createTestFile(t, plain, "lib.go", "package lib\n\nfunc A() {}\n")
+	_, err = New(store).IndexProject(ctx, plain, &Config{Workers: 1, GitHistory: true})
+	require.NoError(t, err)
+}
@@ -0,0 +1,115 @@
+package indexer
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dshills/gocontext-mcp/internal/gitrepo"
+)
+
+// source is the tree being indexed: the project's working directory or the tree
+// of a git revision read from the object database
+type source struct {
+	root   string          // Project directory on disk; file paths are built from it
+	fsys   fs.FS           // Project tree, slash-separated paths relative to root
+	commit *gitrepo.Commit // Indexed revision, nil for the working tree
+	repo   *gitrepo.Repo
+}
+
+// newWorkTreeSource returns the working directory at rootPath
+func newWorkTreeSource(rootPath string) *source {
+	return &source{root: rootPath, fsys: os.DirFS(rootPath)}
+}
+
+// newRevisionSource resolves ref in the repository containing rootPath and returns its
+// tree. When rootPath is a subdirectory of the repository, only that subtree is used.
+func newRevisionSource(rootPath, ref string) (*source, error) {
+	repo, err := gitrepo.Open(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := repo.ResolveRevision(ref)
+	if err != nil {
+		_ = repo.Close()
+		return nil, err
+	}
+	commit, err := repo.Commit(hash)
+	if err != nil {
+		_ = repo.Close()
+		return nil, err
+	}
+
+	fsys, err := projectSubtree(repo, repo.CommitFS(commit), rootPath)
+	if err != nil {
+		_ = repo.Close()
+		return nil, fmt.Errorf("%s: %w", ref, err)
+	}
+	return &source{root: rootPath, fsys: fsys, commit: commit, repo: repo}, nil
+}
+
+// projectSubtree narrows a repository tree to rootPath's directory within the repository
+func projectSubtree(repo *gitrepo.Repo, fsys fs.FS, rootPath string) (fs.FS, error) {
+	absRoot, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := filepath.Rel(repo.WorkTree(), absRoot)
+	if err != nil || sub == "." {
+		return fsys, err
+	}
+
+	sub = filepath.ToSlash(sub)
+	if _, err := fs.Stat(fsys, sub); err != nil {
+		return nil, fmt.Errorf("directory %s not found in revision", sub)
+	}
+	return fs.Sub(fsys, sub)
+}
+
+// close releases the repository of a revision source
+func (s *source) close() error {
+	if s.repo == nil {
+		return nil
+	}
+	return s.repo.Close()
+}
+
+// isRevision reports whether the source is a git revision
+func (s *source) isRevision() bool {
+	return s.commit != nil
+}
+
+// path returns the on-disk style path of a slash-separated relative path
+func (s *source) path(rel string) string {
+	return filepath.Join(s.root, filepath.FromSlash(rel))
+}
+
+// readFile returns the content of a file relative to the root
+func (s *source) readFile(relPath string) ([]byte, error) {
+	return fs.ReadFile(s.fsys, filepath.ToSlash(relPath))
+}
+
+// hashFile returns the SHA-256, modification time and size of a file relative to the
+// root. For revisions, whose blobs are read whole anyway, the content is returned too;
+// it is nil for the working tree so unchanged files are not read twice.
+func (s *source) hashFile(relPath string) ([32]byte, time.Time, int64, []byte, error) {
+	if !s.isRevision() {
+		hash, modTime, size, err := computeFileHash(filepath.Join(s.root, relPath))
+		return hash, modTime, size, nil, err
+	}
+
+	content, err := s.readFile(relPath)
+	if err != nil {
+		return [32]byte{}, time.Time{}, 0, nil, err
+	}
+	return sha256.Sum256(content), s.commit.Committer.When, int64(len(content)), content, nil
+}
+
+// exists reports whether a file relative to the root is present in the source
+func (s *source) exists(relPath string) bool {
+	_, err := fs.Stat(s.fsys, filepath.ToSlash(relPath))
+	return err == nil
+}
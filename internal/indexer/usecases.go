@@ -0,0 +1,85 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dshills/gocontext-mcp/internal/cqrs"
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// linkUseCases replaces the project's use case relations: commands and queries
+// to their handlers, and handlers to the repositories and aggregates they use.
+// It runs after classifyDomain, whose pattern flags it relies on.
+func (idx *Indexer) linkUseCases(ctx context.Context, project *storage.Project) error {
+	files, err := idx.storage.ListFiles(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	refs, err := idx.storage.ListSymbolRefs(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	bySymbol := make(map[int64][]types.Ref)
+	for _, ref := range refs {
+		bySymbol[ref.SymbolID] = append(bySymbol[ref.SymbolID], ref.Ref)
+	}
+
+	var symbols []*cqrs.Symbol
+	for _, file := range files {
+		if strings.HasSuffix(file.FilePath, "_test.go") {
+			continue
+		}
+		declared, err := idx.storage.ListSymbolsByFile(ctx, file.ID)
+		if err != nil {
+			return err
+		}
+
+		pkg := file.ImportPath
+		if pkg == "" {
+			pkg = path.Dir(filepath.ToSlash(file.FilePath))
+		}
+		for _, sym := range declared {
+			symbols = append(symbols, &cqrs.Symbol{
+				ID:         sym.ID,
+				Name:       sym.Name,
+				Kind:       types.SymbolKind(sym.Kind),
+				Receiver:   sym.Receiver,
+				Package:    pkg,
+				Command:    sym.IsCommand,
+				Query:      sym.IsQuery,
+				Handler:    sym.IsHandler,
+				Repository: sym.IsRepository,
+				Aggregate:  sym.IsAggregateRoot,
+				Entity:     sym.IsEntity,
+				Refs:       bySymbol[sym.ID],
+			})
+		}
+	}
+
+	links := cqrs.Link(symbols)
+	relations := make([]*storage.SymbolRelation, 0, len(links))
+	for _, link := range links {
+		relations = append(relations, &storage.SymbolRelation{
+			FromID: link.From,
+			ToID:   link.To,
+			Kind:   link.Kind,
+			Reason: link.Reason,
+		})
+	}
+
+	tx, err := idx.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := tx.ReplaceSymbolRelations(ctx, project.ID, relations); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
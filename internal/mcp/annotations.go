@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// Limits for list_annotations
+const (
+	defaultAnnotationLimit = 100
+	maxAnnotationLimit     = 1000
+)
+
+// handleListAnnotations handles the list_annotations tool invocation
+func (s *Server) handleListAnnotations(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid arguments", nil)
+	}
+
+	project, err := s.indexedProject(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := storage.AnnotationFilter{
+		Kinds:       getStringSlice(args, "kinds"),
+		Packages:    getStringSlice(args, "packages"),
+		Owner:       getStringDefault(args, "owner", ""),
+		FilePattern: getStringDefault(args, "file_pattern", ""),
+	}
+	for _, kind := range filter.Kinds {
+		if !types.IsValidAnnotationKind(kind) {
+			return nil, newMCPError(ErrorCodeInvalidParams, "invalid kinds", map[string]interface{}{
+				"param":   "kinds",
+				"value":   kind,
+				"allowed": types.AnnotationKinds,
+			})
+		}
+	}
+
+	limit := getIntDefault(args, "limit", defaultAnnotationLimit)
+	if limit < 1 || limit > maxAnnotationLimit {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid limit", map[string]interface{}{
+			"param":  "limit",
+			"value":  limit,
+			"reason": "must be between 1 and 1000",
+		})
+	}
+
+	annotations, err := s.storage.ListAnnotations(ctx, project.ID, filter)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to list annotations", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	// Counts cover every match; the list is truncated to limit
+	counts := make(map[string]int)
+	for _, a := range annotations {
+		counts[a.Kind]++
+	}
+	total := len(annotations)
+	if len(annotations) > limit {
+		annotations = annotations[:limit]
+	}
+
+	response := map[string]interface{}{
+		"annotations": formatAnnotations(annotations),
+		"counts":      counts,
+		"total":       total,
+		"returned":    len(annotations),
+	}
+	return mcp.NewToolResultText(formatJSON(response)), nil
+}
+
+// formatAnnotations converts annotations to response maps, omitting empty fields
+func formatAnnotations(annotations []*storage.Annotation) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(annotations))
+	for i, a := range annotations {
+		m := map[string]interface{}{
+			"kind":    a.Kind,
+			"text":    a.Text,
+			"file":    a.FilePath,
+			"line":    a.Line,
+			"package": a.PackageName,
+		}
+		if a.Symbol != "" {
+			m["symbol"] = a.Symbol
+		}
+		if a.Owner != "" {
+			m["owner"] = a.Owner
+		}
+		if len(a.Issues) > 0 {
+			m["issues"] = a.Issues
+		}
+		result[i] = m
+	}
+	return result
+}
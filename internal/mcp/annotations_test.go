@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListAnnotations(t *testing.T) {
+	s := newTestServer(t)
+	dir := indexTestProject(t, s, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"store/store.go": `package store
+
+// Open opens the store.
+//
+// Deprecated: use OpenContext.
+func Open() {}
+
+// TODO(alice): add retries #4
+func Save() {}
+`,
+		"api/api.go": "package api\n\n// FIXME(bob): validate input\nfunc Handle() {}\n",
+	})
+
+	response := callTool(t, s.handleListAnnotations, map[string]interface{}{"path": dir})
+	assert.Equal(t, float64(3), response["total"])
+	assert.Equal(t, map[string]interface{}{"deprecated": float64(1), "todo": float64(1), "fixme": float64(1)}, response["counts"])
+
+	response = callTool(t, s.handleListAnnotations, map[string]interface{}{
+		"path":     dir,
+		"kinds":    []interface{}{"todo", "deprecated"},
+		"packages": []interface{}{"store"},
+		"limit":    float64(1),
+	})
+	assert.Equal(t, float64(2), response["total"])
+	annotations := response["annotations"].([]interface{})
+	require.Len(t, annotations, 1)
+	first := annotations[0].(map[string]interface{})
+	assert.Equal(t, "deprecated", first["kind"])
+	assert.Equal(t, "Open", first["symbol"])
+	assert.Equal(t, "store/store.go", first["file"])
+
+	response = callTool(t, s.handleListAnnotations, map[string]interface{}{"path": dir, "owner": "ALICE"})
+	annotations = response["annotations"].([]interface{})
+	require.Len(t, annotations, 1)
+	assert.Equal(t, []interface{}{"#4"}, annotations[0].(map[string]interface{})["issues"])
+
+	_, err := s.handleListAnnotations(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Arguments: map[string]interface{}{"path": dir, "kinds": []interface{}{"bug"}},
+	}})
+	assert.Error(t, err)
+
+	other := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(other, "main.go"), []byte("package main\n"), 0644))
+	_, err = s.handleListAnnotations(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Arguments: map[string]interface{}{"path": other},
+	}})
+	var mcpErr *MCPError
+	require.ErrorAs(t, err, &mcpErr)
+	assert.Equal(t, ErrorCodeNotIndexed, mcpErr.Code)
+}
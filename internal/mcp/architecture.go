@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/dshills/gocontext-mcp/internal/archcheck"
+)
+
+// Limits for check_architecture
+const (
+	defaultViolationLimit = 100
+	maxViolationLimit     = 1000
+)
+
+// handleCheckArchitecture handles the check_architecture tool invocation
+func (s *Server) handleCheckArchitecture(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid arguments", nil)
+	}
+
+	project, err := s.indexedProject(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rules come from the working tree's .gocontext.yaml, also for snapshots
+	path, _ := args["path"].(string)
+	cfg, err := loadProjectConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Architecture.Rules) == 0 {
+		return nil, newMCPError(ErrorCodeInvalidParams, "no architecture rules configured", map[string]interface{}{
+			"path":   path,
+			"reason": "add layers and rules to the architecture section of .gocontext.yaml",
+		})
+	}
+
+	limit := getIntDefault(args, "limit", defaultViolationLimit)
+	if limit < 1 || limit > maxViolationLimit {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid limit", map[string]interface{}{
+			"param":  "limit",
+			"value":  limit,
+			"reason": "must be between 1 and 1000",
+		})
+	}
+
+	checker, err := archcheck.New(cfg.Architecture)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid project configuration", map[string]interface{}{
+			"path":  path,
+			"error": err.Error(),
+		})
+	}
+	files, err := archcheck.LoadFiles(ctx, s.storage, project.ID, getBoolDefault(args, "include_tests", false))
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to load imports", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	violations := checker.Check(files)
+
+	// Count per rule before truncating, in rule order of first violation
+	var rules []map[string]interface{}
+	counts := make(map[string]int)
+	for _, v := range violations {
+		if counts[v.Rule] == 0 {
+			rules = append(rules, map[string]interface{}{"rule": v.Rule})
+		}
+		counts[v.Rule]++
+	}
+	for _, r := range rules {
+		r["violations"] = counts[r["rule"].(string)]
+	}
+
+	total := len(violations)
+	if len(violations) > limit {
+		violations = violations[:limit]
+	}
+
+	response := map[string]interface{}{
+		"passed":        total == 0,
+		"rules_checked": checker.Rules(),
+		"files_checked": len(files),
+		"violations":    formatViolations(violations),
+		"total":         total,
+		"returned":      len(violations),
+	}
+	if len(rules) > 0 {
+		response["by_rule"] = rules
+	}
+	return mcp.NewToolResultText(formatJSON(response)), nil
+}
+
+// formatViolations converts architecture violations to response maps
+func formatViolations(violations []archcheck.Violation) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(violations))
+	for i, v := range violations {
+		m := map[string]interface{}{
+			"rule":    v.Rule,
+			"message": v.Message,
+			"file":    v.File,
+			"line":    v.Line,
+			"package": v.Package,
+			"import":  v.Import,
+		}
+		if v.Reason != "" {
+			m["reason"] = v.Reason
+		}
+		result[i] = m
+	}
+	return result
+}
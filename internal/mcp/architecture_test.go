@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCheckArchitecture(t *testing.T) {
+	s := newTestServer(t)
+	dir := indexTestProject(t, s, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.22\n",
+		".gocontext.yaml": "architecture:\n" +
+			"  layers:\n" +
+			"    - {name: domain, packages: [internal/domain/**]}\n" +
+			"    - {name: infrastructure, packages: [internal/infra/**]}\n" +
+			"  rules:\n" +
+			"    - from: domain\n" +
+			"      deny: [infrastructure]\n" +
+			"      reason: the domain stays persistence-agnostic\n" +
+			"    - name: db access\n" +
+			"      to: internal/infra/db\n" +
+			"      only_from: [internal/infra/**]\n",
+		"internal/domain/order.go": "package domain\n\n" +
+			"import (\n" +
+			"\t\"errors\"\n\n" +
+			"\t\"example.com/shop/internal/infra/db\"\n" +
+			")\n\n" +
+			"var ErrEmpty = errors.New(db.Name)\n",
+		"internal/domain/order_test.go": "package domain\n\n" +
+			"import _ \"example.com/shop/internal/infra/db\"\n",
+		"internal/infra/db/db.go": "package db\n\nconst Name = \"db\"\n",
+		"internal/infra/repo/repo.go": "package repo\n\n" +
+			"import \"example.com/shop/internal/infra/db\"\n\n" +
+			"const Table = db.Name\n",
+	})
+
+	response := callTool(t, s.handleCheckArchitecture, map[string]interface{}{"path": dir})
+	assert.Equal(t, false, response["passed"])
+	assert.Equal(t, float64(2), response["rules_checked"])
+	assert.Equal(t, float64(2), response["total"])
+	violations := response["violations"].([]interface{})
+	require.Len(t, violations, 2)
+	first := violations[0].(map[string]interface{})
+	assert.Equal(t, "domain must not import infrastructure", first["rule"])
+	assert.Equal(t, "domain must not import infrastructure", first["message"])
+	assert.Equal(t, "the domain stays persistence-agnostic", first["reason"])
+	assert.Equal(t, "internal/domain/order.go", first["file"])
+	assert.Equal(t, float64(6), first["line"])
+	assert.Equal(t, "example.com/shop/internal/infra/db", first["import"])
+	second := violations[1].(map[string]interface{})
+	assert.Equal(t, "db access", second["rule"])
+	assert.Equal(t, "internal/infra/db may only be imported by internal/infra/**", second["message"])
+	assert.Len(t, response["by_rule"], 2)
+
+	response = callTool(t, s.handleCheckArchitecture, map[string]interface{}{"path": dir, "include_tests": true, "limit": float64(1)})
+	assert.Equal(t, float64(4), response["total"])
+	assert.Equal(t, float64(1), response["returned"])
+
+	clean := indexTestProject(t, s, map[string]string{
+		"go.mod":          "module example.com/clean\n\ngo 1.22\n",
+		".gocontext.yaml": "architecture:\n  rules:\n    - {from: internal/domain/**, deny: [database/sql]}\n",
+		"internal/domain/order.go": "package domain\n\nimport \"errors\"\n\n" +
+			"var ErrEmpty = errors.New(\"empty\")\n",
+	})
+	response = callTool(t, s.handleCheckArchitecture, map[string]interface{}{"path": clean})
+	assert.Equal(t, true, response["passed"])
+	assert.Empty(t, response["violations"])
+	assert.NotContains(t, response, "by_rule")
+
+	unconfigured := indexTestProject(t, s, map[string]string{"main.go": "package main\n\nfunc main() {}\n"})
+	_, err := s.handleCheckArchitecture(context.Background(), callRequest(map[string]interface{}{"path": unconfigured}))
+	var mcpErr *MCPError
+	require.ErrorAs(t, err, &mcpErr)
+	assert.Equal(t, ErrorCodeInvalidParams, mcpErr.Code)
+}
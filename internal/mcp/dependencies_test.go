@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dshills/gocontext-mcp/internal/embedder"
+	"github.com/dshills/gocontext-mcp/internal/searcher"
+)
+
+// writeTree writes files (relative path -> content) under dir
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	s := newTestServer(t)
+	emb, err := embedder.NewLocalProvider(nil)
+	require.NoError(t, err)
+	s.searcher = searcher.NewSearcher(s.storage, emb)
+
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	writeTree(t, cache, map[string]string{
+		"example.com/retry@v1.4.0/go.mod":   "module example.com/retry\n\ngo 1.21\n",
+		"example.com/retry@v1.4.0/retry.go": "// Package retry retries operations.\npackage retry\n\n// Backoff waits between attempts\ntype Backoff struct{}\n\n// Do retries fn with backoff\nfunc Do(fn func() error) error { return fn() }\n",
+	})
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.22\n\nrequire (\n\texample.com/retry v1.4.0\n\texample.com/gone v0.2.0\n)\n",
+		"main.go": "package main\n\n// run starts the app with backoff\nfunc run() {}\n",
+	})
+
+	indexArgs := map[string]interface{}{"path": dir, "dependencies": "everything"}
+	_, err = s.handleIndexCodebase(context.Background(), callRequest(indexArgs))
+	var mcpErr *MCPError
+	require.ErrorAs(t, err, &mcpErr)
+	assert.Equal(t, ErrorCodeInvalidParams, mcpErr.Code)
+
+	indexArgs["dependencies"] = "direct"
+	response := callTool(t, s.handleIndexCodebase, indexArgs)
+	assert.Equal(t, float64(1), response["dependencies_indexed"])
+	assert.Equal(t, []interface{}{"example.com/gone@v0.2.0"}, response["dependencies_missing"])
+
+	t.Run("search excludes dependencies by default", func(t *testing.T) {
+		response := callTool(t, s.handleSearchCode, map[string]interface{}{
+			"path": dir, "query": "backoff", "search_mode": "keyword",
+		})
+		for _, r := range response["results"].([]interface{}) {
+			assert.NotContains(t, r.(map[string]interface{}), "dependency")
+		}
+
+		response = callTool(t, s.handleSearchCode, map[string]interface{}{
+			"all_projects": true, "query": "backoff", "search_mode": "keyword",
+		})
+		for _, r := range response["results"].([]interface{}) {
+			assert.NotContains(t, r.(map[string]interface{}), "dependency")
+		}
+	})
+
+	t.Run("search includes dependencies on request", func(t *testing.T) {
+		response := callTool(t, s.handleSearchCode, map[string]interface{}{
+			"path": dir, "query": "backoff", "search_mode": "keyword", "include_dependencies": true,
+		})
+		var deps []string
+		for _, r := range response["results"].([]interface{}) {
+			if dep, ok := r.(map[string]interface{})["dependency"].(string); ok {
+				deps = append(deps, dep)
+			}
+		}
+		assert.Contains(t, deps, "example.com/retry@v1.4.0")
+	})
+
+	t.Run("status lists dependencies", func(t *testing.T) {
+		response := callTool(t, s.handleGetStatus, map[string]interface{}{"path": dir})
+		deps := response["dependencies"].([]interface{})
+		require.Len(t, deps, 1)
+		dep := deps[0].(map[string]interface{})
+		assert.Equal(t, "example.com/retry", dep["module"])
+		assert.Equal(t, "v1.4.0", dep["version"])
+		assert.Equal(t, true, dep["direct"])
+	})
+
+	t.Run("package overview resolves dependency import paths", func(t *testing.T) {
+		result, err := s.handleGetPackageOverview(context.Background(), callRequest(map[string]interface{}{
+			"path": dir, "package": "example.com/retry",
+		}))
+		require.NoError(t, err)
+		text := resultText(t, result)
+		assert.Contains(t, text, "module `example.com/retry@v1.4.0`")
+		assert.Contains(t, text, "Backoff")
+	})
+}
+
+func TestStdlib(t *testing.T) {
+	s := newTestServer(t)
+	emb, err := embedder.NewLocalProvider(nil)
+	require.NoError(t, err)
+	s.searcher = searcher.NewSearcher(s.storage, emb)
+
+	goroot := t.TempDir()
+	t.Setenv("GOROOT", goroot)
+	t.Setenv("GOMODCACHE", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	writeTree(t, goroot, map[string]string{
+		"VERSION":                "go1.22.5\n",
+		"src/go.mod":             "module std\n\ngo 1.22\n",
+		"src/net/http/server.go": "// Package http implements HTTP.\npackage http\n\n// ListenAndServe listens and serves\nfunc ListenAndServe(addr string) error { return nil }\n",
+	})
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.21\n",
+		"main.go": "package main\n\n// serve starts listening\nfunc serve() {}\n",
+	})
+
+	response := callTool(t, s.handleIndexCodebase, map[string]interface{}{"path": dir, "stdlib": true})
+	stdlib := response["stdlib"].(map[string]interface{})
+	assert.Equal(t, "go1.22.5", stdlib["version"])
+	assert.Equal(t, false, stdlib["exact"], "go 1.21 is not installed")
+
+	response = callTool(t, s.handleSearchCode, map[string]interface{}{
+		"path": dir, "query": "ListenAndServe", "search_mode": "keyword", "include_stdlib": true,
+	})
+	var deps []string
+	for _, r := range response["results"].([]interface{}) {
+		if dep, ok := r.(map[string]interface{})["dependency"].(string); ok {
+			deps = append(deps, dep)
+		}
+	}
+	assert.Contains(t, deps, "std@go1.22.5")
+
+	response = callTool(t, s.handleSearchCode, map[string]interface{}{
+		"path": dir, "query": "ListenAndServe", "search_mode": "keyword", "include_dependencies": true,
+	})
+	assert.Empty(t, response["results"], "include_dependencies leaves the standard library out")
+
+	response = callTool(t, s.handleGetStatus, map[string]interface{}{"path": dir})
+	assert.Equal(t, "go1.22.5", response["stdlib"].(map[string]interface{})["version"])
+	assert.Empty(t, response["dependencies"])
+
+	result, err := s.handleGetPackageOverview(context.Background(), callRequest(map[string]interface{}{
+		"path": dir, "package": "net/http",
+	}))
+	require.NoError(t, err)
+	text := resultText(t, result)
+	assert.Contains(t, text, "module `std@go1.22.5`")
+	assert.Contains(t, text, "ListenAndServe")
+}
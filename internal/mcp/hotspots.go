@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// Limits for list_hotspots
+const (
+	defaultHotspotLimit = 20
+	maxHotspotLimit     = 500
+)
+
+// Orders of list_hotspots besides the metric names
+const (
+	hotspotSortScore = "score"
+	hotspotSortChurn = "churn"
+)
+
+// hotspot is a function with its combined score
+type hotspot struct {
+	fn    *storage.FunctionMetrics
+	score float64
+}
+
+// handleListHotspots handles the list_hotspots tool invocation
+func (s *Server) handleListHotspots(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid arguments", nil)
+	}
+
+	project, err := s.indexedProject(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := getIntDefault(args, "limit", defaultHotspotLimit)
+	if limit < 1 || limit > maxHotspotLimit {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid limit", map[string]interface{}{
+			"param":  "limit",
+			"value":  limit,
+			"reason": "must be between 1 and 500",
+		})
+	}
+
+	sortBy := getStringDefault(args, "sort_by", hotspotSortScore)
+	if _, ok := (types.Metrics{}).Get(sortBy); !ok && sortBy != hotspotSortScore && sortBy != hotspotSortChurn {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid sort_by", map[string]interface{}{
+			"param":   "sort_by",
+			"value":   sortBy,
+			"allowed": append([]string{hotspotSortScore, hotspotSortChurn}, types.MetricNames...),
+		})
+	}
+
+	filter := storage.MetricsFilter{
+		Packages:     getStringSlice(args, "packages"),
+		FilePattern:  getStringDefault(args, "file_pattern", ""),
+		IncludeTests: getBoolDefault(args, "include_tests", false),
+	}
+	functions, err := s.storage.ListFunctionMetrics(ctx, project.ID, filter)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to list function metrics", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	hasHistory := false
+	for _, fn := range functions {
+		hasHistory = hasHistory || fn.HasHistory
+	}
+	hotspots := scoreHotspots(functions, hasHistory)
+	sortHotspots(hotspots, sortBy)
+
+	total := len(hotspots)
+	if len(hotspots) > limit {
+		hotspots = hotspots[:limit]
+	}
+
+	response := map[string]interface{}{
+		"hotspots":    formatHotspots(hotspots, hasHistory),
+		"total":       total,
+		"returned":    len(hotspots),
+		"has_history": hasHistory,
+	}
+	return mcp.NewToolResultText(formatJSON(response)), nil
+}
+
+// scoreHotspots scores functions between 0 and 1 by complexity, size and, with
+// git history, churn, each normalized against the highest in the project
+func scoreHotspots(functions []*storage.FunctionMetrics, hasHistory bool) []hotspot {
+	var maxCyclomatic, maxCognitive, maxLines, maxChurn int
+	var maxFileSize int64
+	for _, fn := range functions {
+		maxCyclomatic = max(maxCyclomatic, fn.Metrics.Cyclomatic)
+		maxCognitive = max(maxCognitive, fn.Metrics.Cognitive)
+		maxLines = max(maxLines, fn.Metrics.Lines)
+		maxFileSize = max(maxFileSize, fn.FileSize)
+		maxChurn = max(maxChurn, fn.Churn)
+	}
+
+	hotspots := make([]hotspot, len(functions))
+	for i, fn := range functions {
+		complexity := (ratio(fn.Metrics.Cyclomatic, maxCyclomatic) + ratio(fn.Metrics.Cognitive, maxCognitive)) / 2
+		size := (ratio(fn.Metrics.Lines, maxLines) + ratio(fn.FileSize, maxFileSize)) / 2
+		score := 0.75*complexity + 0.25*size
+		if hasHistory {
+			score = 0.6*complexity + 0.2*size + 0.2*ratio(fn.Churn, maxChurn)
+		}
+		hotspots[i] = hotspot{fn: fn, score: math.Round(score*100) / 100}
+	}
+	return hotspots
+}
+
+// ratio returns n relative to the maximum, or 0 when the maximum is 0
+func ratio[T int | int64](n, maximum T) float64 {
+	if maximum == 0 {
+		return 0
+	}
+	return float64(n) / float64(maximum)
+}
+
+// sortHotspots orders hotspots highest first by score, churn or a metric;
+// ties keep file order
+func sortHotspots(hotspots []hotspot, sortBy string) {
+	value := func(h hotspot) float64 {
+		switch sortBy {
+		case hotspotSortScore:
+			return h.score
+		case hotspotSortChurn:
+			return float64(h.fn.Churn)
+		}
+		v, _ := h.fn.Metrics.Get(sortBy)
+		return float64(v)
+	}
+	sort.SliceStable(hotspots, func(i, j int) bool {
+		return value(hotspots[i]) > value(hotspots[j])
+	})
+}
+
+// formatHotspots converts hotspots to response maps
+func formatHotspots(hotspots []hotspot, hasHistory bool) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(hotspots))
+	for i, h := range hotspots {
+		m := map[string]interface{}{
+			"name":      h.fn.Name,
+			"kind":      h.fn.Kind,
+			"package":   h.fn.PackageName,
+			"file":      h.fn.FilePath,
+			"line":      h.fn.StartLine,
+			"metrics":   formatMetrics(h.fn.Metrics),
+			"file_size": h.fn.FileSize,
+			"score":     h.score,
+		}
+		if h.fn.Receiver != "" {
+			m["receiver"] = h.fn.Receiver
+		}
+		if h.fn.ImportPath != "" {
+			m["import_path"] = h.fn.ImportPath
+		}
+		if hasHistory {
+			m["churn"] = h.fn.Churn
+		}
+		result[i] = m
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListHotspots(t *testing.T) {
+	s := newTestServer(t)
+	dir := indexTestProject(t, s, map[string]string{
+		"shop/order.go": "package shop\n\n" +
+			"func Simple() {}\n\n" +
+			"func Branchy(items []int, limit int) int {\n" +
+			"\tn := 0\n\tfor _, i := range items {\n\t\tif i > limit && i%2 == 0 {\n\t\t\tn++\n\t\t}\n\t}\n\treturn n\n}\n",
+		"shop/order_test.go": "package shop\n\nfunc helper(ok bool) int {\n\tif ok {\n\t\treturn 1\n\t}\n\treturn 0\n}\n",
+	})
+
+	response := callTool(t, s.handleListHotspots, map[string]interface{}{"path": dir})
+	assert.Equal(t, float64(2), response["total"])
+	assert.Equal(t, false, response["has_history"])
+	hotspots := response["hotspots"].([]interface{})
+	require.Len(t, hotspots, 2)
+	top := hotspots[0].(map[string]interface{})
+	assert.Equal(t, "Branchy", top["name"])
+	assert.Equal(t, "shop/order.go", top["file"])
+	assert.Equal(t, float64(1), top["score"])
+	assert.NotContains(t, top, "churn")
+	metrics := top["metrics"].(map[string]interface{})
+	assert.Equal(t, float64(4), metrics["cyclomatic"])
+	assert.Equal(t, float64(2), metrics["params"])
+	assert.Less(t, hotspots[1].(map[string]interface{})["score"], top["score"])
+
+	// Tests on request, ordered by a single metric
+	response = callTool(t, s.handleListHotspots, map[string]interface{}{
+		"path": dir, "include_tests": true, "sort_by": "returns", "limit": float64(1),
+	})
+	assert.Equal(t, float64(3), response["total"])
+	assert.Equal(t, float64(1), response["returned"])
+	assert.Equal(t, "helper", response["hotspots"].([]interface{})[0].(map[string]interface{})["name"])
+
+	for _, args := range []map[string]interface{}{{"sort_by": "size"}, {"limit": float64(0)}} {
+		args["path"] = dir
+		_, err := s.handleListHotspots(context.Background(), callRequest(args))
+		var mcpErr *MCPError
+		require.ErrorAs(t, err, &mcpErr, args)
+		assert.Equal(t, ErrorCodeInvalidParams, mcpErr.Code, args)
+	}
+}
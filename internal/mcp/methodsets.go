@@ -0,0 +1,221 @@
+package mcp
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// maxEmbedDepth bounds the search for promoted methods through nested embeds
+const maxEmbedDepth = 8
+
+// promotedMethod is a method a struct or interface gets from an embedded type
+type promotedMethod struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+	From      string `json:"from"` // The embed it is reached through, as written in the type
+}
+
+// packageTypes indexes the type and member symbols of one package
+type packageTypes struct {
+	types   map[string]*storage.Symbol   // Structs, interfaces and other types by name
+	methods map[string][]*storage.Symbol // Methods, including interface methods, by receiver
+	fields  map[string][]string          // Named field names by struct
+}
+
+// newPackageTypes indexes a package's symbols
+func newPackageTypes(symbols []*storage.Symbol) *packageTypes {
+	p := &packageTypes{
+		types:   make(map[string]*storage.Symbol),
+		methods: make(map[string][]*storage.Symbol),
+		fields:  make(map[string][]string),
+	}
+	for _, sym := range symbols {
+		switch types.SymbolKind(sym.Kind) {
+		case types.KindStruct, types.KindInterface, types.KindType:
+			p.types[sym.Name] = sym
+		case types.KindMethod:
+			p.methods[sym.Receiver] = append(p.methods[sym.Receiver], sym)
+		case types.KindField:
+			p.fields[sym.Receiver] = append(p.fields[sym.Receiver], sym.Name)
+		}
+	}
+	return p
+}
+
+// methodSetResolver resolves embedded types across the project's packages,
+// loading the symbols of other packages on demand
+type methodSetResolver struct {
+	s        *Server
+	files    []*storage.File
+	imports  map[int64][]*storage.Import // By file ID
+	packages map[string]*packageTypes    // By import path
+}
+
+// newMethodSetResolver creates a resolver for a project's files and imports
+func newMethodSetResolver(s *Server, files []*storage.File, imports []*storage.Import) *methodSetResolver {
+	r := &methodSetResolver{
+		s:        s,
+		files:    files,
+		imports:  make(map[int64][]*storage.Import),
+		packages: make(map[string]*packageTypes),
+	}
+	for _, imp := range imports {
+		r.imports[imp.FileID] = append(r.imports[imp.FileID], imp)
+	}
+	return r
+}
+
+// embedEntry is a type reached through embedding
+type embedEntry struct {
+	pkg  *packageTypes
+	sym  *storage.Symbol
+	from string
+}
+
+// promoted returns the methods promoted to typ, a type of pkg, following Go's
+// selector rules: the shallowest embedding depth wins, names declared by the type
+// itself or by fields at a shallower depth shadow promoted methods, and a name found
+// through several embeds at the same depth is ambiguous and not promoted. Embeds
+// outside the indexed project are not followed.
+func (r *methodSetResolver) promoted(ctx context.Context, pkg *packageTypes, typ *storage.Symbol) []promotedMethod {
+	seen := make(map[string]bool)
+	for _, m := range pkg.methods[typ.Name] {
+		seen[m.Name] = true
+	}
+	for _, f := range pkg.fields[typ.Name] {
+		seen[f] = true
+	}
+
+	visited := map[*storage.Symbol]bool{typ: true}
+	var current []embedEntry
+	for _, embed := range typ.Embeds {
+		if ePkg, eSym := r.resolve(ctx, pkg, typ, embed); eSym != nil {
+			current = append(current, embedEntry{pkg: ePkg, sym: eSym, from: embed})
+		}
+		seen[embedFieldName(embed)] = true // The embedded field itself
+	}
+
+	var promoted []promotedMethod
+	for depth := 1; len(current) > 0 && depth <= maxEmbedDepth; depth++ {
+		type candidate struct {
+			method *storage.Symbol // Nil for fields, which shadow without promoting
+			from   string
+		}
+		found := make(map[string][]candidate)
+		var order []string
+		add := func(name string, c candidate) {
+			if _, ok := found[name]; !ok {
+				order = append(order, name)
+			}
+			found[name] = append(found[name], c)
+		}
+
+		var next []embedEntry
+		for _, entry := range current {
+			if visited[entry.sym] {
+				continue
+			}
+			visited[entry.sym] = true
+			for _, m := range entry.pkg.methods[entry.sym.Name] {
+				add(m.Name, candidate{method: m, from: entry.from})
+			}
+			for _, f := range entry.pkg.fields[entry.sym.Name] {
+				add(f, candidate{from: entry.from})
+			}
+			for _, embed := range entry.sym.Embeds {
+				add(embedFieldName(embed), candidate{from: entry.from})
+				if ePkg, eSym := r.resolve(ctx, entry.pkg, entry.sym, embed); eSym != nil {
+					next = append(next, embedEntry{pkg: ePkg, sym: eSym, from: entry.from})
+				}
+			}
+		}
+
+		for _, name := range order {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if c := found[name]; len(c) == 1 && c[0].method != nil {
+				promoted = append(promoted, promotedMethod{Name: name, Signature: c[0].method.Signature, From: c[0].from})
+			}
+		}
+		current = next
+	}
+	return promoted
+}
+
+// resolve finds the symbol of a type embedded in owner, a type of pkg: unqualified
+// names in pkg, qualified ones through the imports of the file declaring owner
+func (r *methodSetResolver) resolve(ctx context.Context, pkg *packageTypes, owner *storage.Symbol, embed string) (*packageTypes, *storage.Symbol) {
+	qualifier, name, qualified := strings.Cut(embedName(embed), ".")
+	if !qualified {
+		return pkg, pkg.types[qualifier]
+	}
+
+	for _, imp := range r.imports[owner.FileID] {
+		if imp.Alias != qualifier && (imp.Alias != "" || r.packageName(imp.ImportPath) != qualifier) {
+			continue
+		}
+		if target := r.load(ctx, imp.ImportPath); target != nil {
+			return target, target.types[name]
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
+
+// packageName returns the package name of the project package at an import path,
+// or the path's last element when it is not indexed
+func (r *methodSetResolver) packageName(importPath string) string {
+	for _, file := range r.files {
+		if file.ImportPath == importPath {
+			return file.PackageName
+		}
+	}
+	return path.Base(importPath)
+}
+
+// load returns the types of the project package at an import path, or nil
+func (r *methodSetResolver) load(ctx context.Context, importPath string) *packageTypes {
+	if p, ok := r.packages[importPath]; ok {
+		return p
+	}
+
+	var symbols []*storage.Symbol
+	for _, file := range r.files {
+		if file.ImportPath != importPath || strings.HasSuffix(file.FilePath, "_test.go") {
+			continue
+		}
+		fileSymbols, err := r.s.storage.ListSymbolsByFile(ctx, file.ID)
+		if err != nil {
+			continue
+		}
+		symbols = append(symbols, fileSymbols...)
+	}
+
+	var p *packageTypes
+	if len(symbols) > 0 {
+		p = newPackageTypes(symbols)
+	}
+	r.packages[importPath] = p
+	return p
+}
+
+// embedName strips the pointer and type arguments from an embed: "*pkg.List[T]" → "pkg.List"
+func embedName(embed string) string {
+	embed = strings.TrimLeft(embed, "*")
+	if i := strings.IndexByte(embed, '['); i >= 0 {
+		embed = embed[:i]
+	}
+	return embed
+}
+
+// embedFieldName returns the name of the field an embed declares: "*pkg.List[T]" → "List"
+func embedFieldName(embed string) string {
+	name := embedName(embed)
+	return name[strings.LastIndexByte(name, '.')+1:]
+}
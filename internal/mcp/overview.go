@@ -0,0 +1,673 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/dshills/gocontext-mcp/internal/chunker"
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// Token budget for get_package_overview
+const (
+	defaultOverviewTokens = 2000
+	minOverviewTokens     = 200
+	maxOverviewTokens     = 20000
+)
+
+// Detail levels tried in turn until an overview fits its token budget
+const (
+	detailFull       = iota // Full package doc, first paragraph of symbol docs
+	detailSummaries         // First paragraph of the package doc, one sentence per symbol
+	detailTypesOnly         // Docs for types, functions, constants and errors; none for methods
+	detailSignatures        // Signatures only
+)
+
+// overviewSymbol is a function, method, constant or error in a package overview
+type overviewSymbol struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+	Doc       string `json:"doc,omitempty"`
+}
+
+// overviewType is an exported type with its constructors and exported methods
+type overviewType struct {
+	overviewSymbol
+	Kind           string           `json:"kind"`
+	Embeds         []string         `json:"embeds,omitempty"`
+	Constructors   []overviewSymbol `json:"constructors,omitempty"`
+	Methods        []overviewSymbol `json:"methods,omitempty"`
+	OmittedMethods int              `json:"omitted_methods,omitempty"`
+	Promoted       []promotedMethod `json:"promoted,omitempty"` // Exported methods from embedded types
+}
+
+// packageOverview is the exported API surface of a package, like "go doc" output
+type packageOverview struct {
+	Name         string           `json:"name"`
+	ImportPath   string           `json:"import_path,omitempty"`
+	Module       string           `json:"module,omitempty"` // module@version for packages of an indexed dependency
+	Dir          string           `json:"dir"`
+	Files        int              `json:"files"`
+	Doc          string           `json:"doc,omitempty"`
+	Types        []*overviewType  `json:"types"`
+	Functions    []overviewSymbol `json:"functions"`
+	Constants    []overviewSymbol `json:"constants"`
+	Errors       []overviewSymbol `json:"errors"`
+	Dependencies []string         `json:"dependencies"` // Project packages imported by the package
+	Dependents   []string         `json:"dependents"`   // Project packages importing the package
+	Omitted      map[string]int   `json:"omitted,omitempty"`
+}
+
+// handleGetPackageOverview handles the get_package_overview tool invocation
+func (s *Server) handleGetPackageOverview(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid arguments", nil)
+	}
+
+	project, err := s.indexedProject(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := strings.TrimSpace(getStringDefault(args, "package", ""))
+	if pkg == "" {
+		return nil, newMCPError(ErrorCodeInvalidParams, "package parameter is required", map[string]interface{}{
+			"param":  "package",
+			"reason": "missing or empty",
+		})
+	}
+
+	format := getStringDefault(args, "format", "markdown")
+	if format != "markdown" && format != "json" {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid format", map[string]interface{}{
+			"param":   "format",
+			"value":   format,
+			"allowed": []string{"markdown", "json"},
+		})
+	}
+
+	maxTokens := getIntDefault(args, "max_tokens", defaultOverviewTokens)
+	if maxTokens < minOverviewTokens || maxTokens > maxOverviewTokens {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid max_tokens", map[string]interface{}{
+			"param":  "max_tokens",
+			"value":  maxTokens,
+			"reason": fmt.Sprintf("must be between %d and %d", minOverviewTokens, maxOverviewTokens),
+		})
+	}
+
+	// Import paths of an indexed dependency are answered from the dependency's project,
+	// and packages the project lacks from its standard library, when linked
+	target, stdlib, err := s.packageProject(ctx, project, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	overview, err := s.buildPackageOverview(ctx, target, pkg)
+	if isPackageNotFound(err) && target == project && stdlib != nil {
+		target = stdlib
+		overview, err = s.buildPackageOverview(ctx, target, pkg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if target.IsDependency() {
+		overview.Module = target.ModuleID()
+	}
+	return mcp.NewToolResultText(fitOverview(overview, format, maxTokens)), nil
+}
+
+// buildPackageOverview assembles the overview of a package given by directory, import
+// path or package name, from the indexed non-test files
+func (s *Server) buildPackageOverview(ctx context.Context, project *storage.Project, pkg string) (*packageOverview, error) {
+	files, err := s.storage.ListFiles(ctx, project.ID)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to list files", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	pkgFiles, err := packageFiles(files, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	first := pkgFiles[0]
+	overview := &packageOverview{
+		Name:       first.PackageName,
+		ImportPath: first.ImportPath,
+		Dir:        fileDir(first),
+		Files:      len(pkgFiles),
+	}
+
+	var symbols []*storage.Symbol
+	inPackage := make(map[int64]bool)
+	for _, file := range pkgFiles {
+		inPackage[file.ID] = true
+		// The doc comment conventionally lives in doc.go; otherwise take the first one found
+		if file.PackageDoc != "" && (overview.Doc == "" || filepath.Base(file.FilePath) == "doc.go") {
+			overview.Doc = file.PackageDoc
+		}
+		fileSymbols, err := s.storage.ListSymbolsByFile(ctx, file.ID)
+		if err != nil {
+			return nil, newMCPError(ErrorCodeInternalError, "failed to list symbols", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		symbols = append(symbols, fileSymbols...)
+	}
+	addOverviewSymbols(overview, symbols)
+
+	imports, err := s.storage.ListImportsByProject(ctx, project.ID)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to list imports", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	s.addPromotedMethods(ctx, overview, files, imports, symbols)
+	overview.Dependencies, overview.Dependents = packageDependencies(files, imports, inPackage, overview.ImportPath)
+	return overview, nil
+}
+
+// packageProject returns the dependency of project whose module contains the import
+// path pkg (the longest module path wins, as modules nest), or project itself, along
+// with the project's linked standard library, if any
+func (s *Server) packageProject(ctx context.Context, project *storage.Project, pkg string) (*storage.Project, *storage.Project, error) {
+	deps, err := s.storage.ListProjectDependencies(ctx, project.ID)
+	if err != nil {
+		return nil, nil, newMCPError(ErrorCodeInternalError, "failed to list dependencies", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	best := project
+	var stdlib *storage.Project
+	for _, dep := range deps {
+		if dep.Project.IsStdlib() {
+			stdlib = dep.Project
+			continue
+		}
+		mod := dep.Project.ModuleName
+		if (pkg == mod || strings.HasPrefix(pkg, mod+"/")) && (best == project || len(mod) > len(best.ModuleName)) {
+			best = dep.Project
+		}
+	}
+	return best, stdlib, nil
+}
+
+// isPackageNotFound reports whether err is packageFiles' "package not found" error
+func isPackageNotFound(err error) bool {
+	var mcpErr *MCPError
+	return errors.As(err, &mcpErr) && mcpErr.Message == "package not found"
+}
+
+// packageFiles returns the non-test files of the package matching pkg, tried as a
+// directory relative to the project root, an import path, then a package name
+func packageFiles(files []*storage.File, pkg string) ([]*storage.File, error) {
+	dir := strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(pkg), "./"), "/")
+	if dir == "" {
+		dir = "."
+	}
+
+	byDir := make(map[string][]*storage.File)
+	var dirs []string
+	for _, file := range files {
+		if strings.HasSuffix(file.FilePath, "_test.go") {
+			continue
+		}
+		d := fileDir(file)
+		if _, ok := byDir[d]; !ok {
+			dirs = append(dirs, d)
+		}
+		byDir[d] = append(byDir[d], file)
+	}
+
+	if matched, ok := byDir[dir]; ok {
+		return matched, nil
+	}
+	var candidates []string
+	for _, match := range []func(*storage.File) bool{
+		func(f *storage.File) bool { return f.ImportPath == pkg },
+		func(f *storage.File) bool { return f.PackageName == pkg },
+	} {
+		for _, d := range dirs {
+			if match(byDir[d][0]) {
+				candidates = append(candidates, d)
+			}
+		}
+		if len(candidates) > 0 {
+			break
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, newMCPError(ErrorCodeInvalidParams, "package not found", map[string]interface{}{
+			"param":  "package",
+			"value":  pkg,
+			"reason": "no indexed non-test files in a matching directory, import path or package name",
+		})
+	case 1:
+		return byDir[candidates[0]], nil
+	default:
+		return nil, newMCPError(ErrorCodeInvalidParams, "ambiguous package", map[string]interface{}{
+			"param":      "package",
+			"value":      pkg,
+			"candidates": candidates,
+		})
+	}
+}
+
+// fileDir returns a file's directory relative to the project root, with forward slashes
+func fileDir(file *storage.File) string {
+	return filepath.ToSlash(filepath.Dir(file.FilePath))
+}
+
+// addOverviewSymbols sorts a package's exported symbols into the overview sections.
+// Functions whose first result is an exported type are listed with the type as
+// constructors, as go doc does.
+func addOverviewSymbols(overview *packageOverview, symbols []*storage.Symbol) {
+	typesByName := make(map[string]*overviewType)
+	for _, sym := range symbols {
+		switch types.SymbolKind(sym.Kind) {
+		case types.KindStruct, types.KindInterface, types.KindType:
+			if sym.Scope == string(types.ScopeExported) {
+				t := &overviewType{overviewSymbol: newOverviewSymbol(sym), Kind: sym.Kind, Embeds: sym.Embeds}
+				typesByName[sym.Name] = t
+				overview.Types = append(overview.Types, t)
+			}
+		}
+	}
+
+	for _, sym := range symbols {
+		if sym.Scope != string(types.ScopeExported) {
+			continue
+		}
+		switch types.SymbolKind(sym.Kind) {
+		case types.KindMethod:
+			if t, ok := typesByName[sym.Receiver]; ok {
+				t.Methods = append(t.Methods, newOverviewSymbol(sym))
+			}
+		case types.KindFunction:
+			if t, ok := typesByName[firstResultType(sym.Signature)]; ok {
+				t.Constructors = append(t.Constructors, newOverviewSymbol(sym))
+			} else {
+				overview.Functions = append(overview.Functions, newOverviewSymbol(sym))
+			}
+		case types.KindConst:
+			overview.Constants = append(overview.Constants, newOverviewSymbol(sym))
+		case types.KindVar:
+			if strings.HasPrefix(sym.Name, "Err") {
+				overview.Errors = append(overview.Errors, newOverviewSymbol(sym))
+			}
+		}
+	}
+
+	byName := func(list []overviewSymbol) {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	}
+	sort.SliceStable(overview.Types, func(i, j int) bool { return overview.Types[i].Name < overview.Types[j].Name })
+	for _, t := range overview.Types {
+		byName(t.Constructors)
+		byName(t.Methods)
+	}
+	byName(overview.Functions)
+}
+
+// addPromotedMethods lists the exported methods each type gets from its embeds
+func (s *Server) addPromotedMethods(ctx context.Context, overview *packageOverview, files []*storage.File,
+	imports []*storage.Import, symbols []*storage.Symbol) {
+	pkg := newPackageTypes(symbols)
+	resolver := newMethodSetResolver(s, files, imports)
+	if overview.ImportPath != "" {
+		resolver.packages[overview.ImportPath] = pkg
+	}
+
+	for _, t := range overview.Types {
+		if len(t.Embeds) == 0 {
+			continue
+		}
+		for _, m := range resolver.promoted(ctx, pkg, pkg.types[t.Name]) {
+			if token.IsExported(m.Name) {
+				t.Promoted = append(t.Promoted, m)
+			}
+		}
+	}
+}
+
+// newOverviewSymbol converts a stored symbol, keeping the first paragraph of its doc
+func newOverviewSymbol(sym *storage.Symbol) overviewSymbol {
+	return overviewSymbol{Name: sym.Name, Signature: sym.Signature, Doc: firstParagraph(sym.DocComment)}
+}
+
+// firstResultType returns the type name of a function signature's first result,
+// without pointer, or "" when there is none
+func firstResultType(signature string) string {
+	open := strings.Index(signature, "(")
+	if open < 0 {
+		return ""
+	}
+	depth := 0
+	for i := open; i < len(signature); i++ {
+		switch signature[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth > 0 {
+			continue
+		}
+
+		results := strings.TrimSpace(signature[i+1:])
+		results = strings.TrimPrefix(results, "(")
+		if end := strings.IndexAny(results, ",)"); end >= 0 {
+			results = results[:end]
+		}
+		// Named results read "err error"
+		fields := strings.Fields(results)
+		if len(fields) == 0 {
+			return ""
+		}
+		return strings.TrimPrefix(fields[len(fields)-1], "*")
+	}
+	return ""
+}
+
+// packageDependencies returns the project packages imported by the package's files
+// and the project packages whose non-test files import it
+func packageDependencies(files []*storage.File, imports []*storage.Import, inPackage map[int64]bool,
+	importPath string) ([]string, []string) {
+	dependencies, dependents := []string{}, []string{}
+	if importPath == "" {
+		return dependencies, dependents
+	}
+
+	filesByID := make(map[int64]*storage.File, len(files))
+	projectPackages := make(map[string]bool)
+	for _, file := range files {
+		filesByID[file.ID] = file
+		if file.ImportPath != "" {
+			projectPackages[file.ImportPath] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, imp := range imports {
+		file, ok := filesByID[imp.FileID]
+		if !ok || strings.HasSuffix(file.FilePath, "_test.go") {
+			continue
+		}
+		switch {
+		case inPackage[imp.FileID] && projectPackages[imp.ImportPath] && imp.ImportPath != importPath:
+			if !seen["dep:"+imp.ImportPath] {
+				seen["dep:"+imp.ImportPath] = true
+				dependencies = append(dependencies, imp.ImportPath)
+			}
+		case !inPackage[imp.FileID] && imp.ImportPath == importPath:
+			if !seen["rdep:"+file.ImportPath] {
+				seen["rdep:"+file.ImportPath] = true
+				dependents = append(dependents, file.ImportPath)
+			}
+		}
+	}
+	sort.Strings(dependencies)
+	sort.Strings(dependents)
+	return dependencies, dependents
+}
+
+// fitOverview renders the overview at the highest detail level that fits maxTokens,
+// then drops items from the longest sections until it fits
+func fitOverview(overview *packageOverview, format string, maxTokens int) string {
+	var fitted *packageOverview
+	var text string
+	for level := detailFull; level <= detailSignatures; level++ {
+		fitted = overview.withDetail(level)
+		text = fitted.render(format)
+		if chunker.EstimateTokenCount(text) <= maxTokens {
+			return text
+		}
+	}
+	for chunker.EstimateTokenCount(text) > maxTokens && fitted.trimLongest() {
+		text = fitted.render(format)
+	}
+	return text
+}
+
+// withDetail returns a copy of the overview with docs shortened for a detail level
+func (o *packageOverview) withDetail(level int) *packageOverview {
+	doc := func(text string, member bool) string {
+		switch {
+		case level >= detailSignatures, level >= detailTypesOnly && member:
+			return ""
+		case level >= detailSummaries:
+			return firstSentence(text)
+		}
+		return text
+	}
+	copySymbols := func(list []overviewSymbol, member bool) []overviewSymbol {
+		out := make([]overviewSymbol, len(list))
+		for i, s := range list {
+			out[i] = overviewSymbol{Name: s.Name, Signature: s.Signature, Doc: doc(s.Doc, member)}
+		}
+		return out
+	}
+
+	c := *o
+	if level >= detailSummaries {
+		c.Doc = firstParagraph(o.Doc)
+	}
+	if level >= detailSignatures {
+		c.Doc = firstSentence(o.Doc)
+	}
+	c.Types = make([]*overviewType, len(o.Types))
+	for i, t := range o.Types {
+		c.Types[i] = &overviewType{
+			overviewSymbol: overviewSymbol{Name: t.Name, Signature: t.Signature, Doc: doc(t.Doc, false)},
+			Kind:           t.Kind,
+			Embeds:         t.Embeds,
+			Constructors:   copySymbols(t.Constructors, true),
+			Methods:        copySymbols(t.Methods, true),
+			Promoted:       t.Promoted,
+		}
+	}
+	c.Functions = copySymbols(o.Functions, false)
+	c.Constants = copySymbols(o.Constants, false)
+	c.Errors = copySymbols(o.Errors, false)
+	c.Dependencies = append([]string{}, o.Dependencies...)
+	c.Dependents = append([]string{}, o.Dependents...)
+	c.Omitted = make(map[string]int)
+	return &c
+}
+
+// trimLongest drops the last item of the longest list, counting it as omitted.
+// It reports false when there is nothing left to drop.
+func (o *packageOverview) trimLongest() bool {
+	longest, section := 0, ""
+	consider := func(name string, n int) {
+		if n > longest {
+			longest, section = n, name
+		}
+	}
+	consider("dependents", len(o.Dependents))
+	consider("constants", len(o.Constants))
+	consider("errors", len(o.Errors))
+	consider("functions", len(o.Functions))
+	var widest *overviewType
+	for _, t := range o.Types {
+		if widest == nil || len(t.Methods) > len(widest.Methods) {
+			widest = t
+		}
+	}
+	if widest != nil {
+		consider("methods", len(widest.Methods))
+	}
+	consider("types", len(o.Types))
+	consider("dependencies", len(o.Dependencies))
+
+	switch section {
+	case "":
+		return false
+	case "dependents":
+		o.Dependents = o.Dependents[:longest-1]
+	case "constants":
+		o.Constants = o.Constants[:longest-1]
+	case "errors":
+		o.Errors = o.Errors[:longest-1]
+	case "functions":
+		o.Functions = o.Functions[:longest-1]
+	case "methods":
+		widest.Methods = widest.Methods[:longest-1]
+		widest.OmittedMethods++
+	case "types":
+		o.Types = o.Types[:longest-1]
+	case "dependencies":
+		o.Dependencies = o.Dependencies[:longest-1]
+	}
+	o.Omitted[section]++
+	return true
+}
+
+// render formats the overview as markdown or indented JSON
+func (o *packageOverview) render(format string) string {
+	if format == "json" {
+		data, err := json.MarshalIndent(o, "", "  ")
+		if err != nil {
+			return fmt.Sprintf("%v", o)
+		}
+		return string(data)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# package %s\n\n", o.Name)
+	if o.ImportPath != "" {
+		fmt.Fprintf(&b, "`import \"%s\"` · ", o.ImportPath)
+	}
+	if o.Module != "" {
+		fmt.Fprintf(&b, "module `%s` · ", o.Module)
+	}
+	fmt.Fprintf(&b, "dir `%s` · %d files\n", o.Dir, o.Files)
+	if o.Doc != "" {
+		fmt.Fprintf(&b, "\n%s\n", o.Doc)
+	}
+
+	if len(o.Types) > 0 || o.Omitted["types"] > 0 {
+		b.WriteString("\n## Types\n\n")
+		for _, t := range o.Types {
+			writeOverviewItem(&b, "", t.overviewSymbol)
+			for _, c := range t.Constructors {
+				writeOverviewItem(&b, "  ", c)
+			}
+			for _, m := range t.Methods {
+				writeOverviewItem(&b, "  ", m)
+			}
+			if t.OmittedMethods > 0 {
+				fmt.Fprintf(&b, "  - … %d more methods\n", t.OmittedMethods)
+			}
+			writeEmbeds(&b, t)
+		}
+		writeOmitted(&b, o.Omitted["types"])
+	}
+
+	for _, section := range []struct {
+		title, key string
+		items      []overviewSymbol
+	}{
+		{"Functions", "functions", o.Functions},
+		{"Constants", "constants", o.Constants},
+		{"Errors", "errors", o.Errors},
+	} {
+		if len(section.items) == 0 && o.Omitted[section.key] == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", section.title)
+		for _, item := range section.items {
+			writeOverviewItem(&b, "", item)
+		}
+		writeOmitted(&b, o.Omitted[section.key])
+	}
+
+	for _, section := range []struct {
+		title, key string
+		items      []string
+	}{
+		{"Dependencies", "dependencies", o.Dependencies},
+		{"Dependents", "dependents", o.Dependents},
+	} {
+		if len(section.items) == 0 && o.Omitted[section.key] == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", section.title)
+		for _, item := range section.items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+		writeOmitted(&b, o.Omitted[section.key])
+	}
+	return b.String()
+}
+
+// writeOverviewItem writes a markdown list item with the signature and doc
+func writeOverviewItem(b *strings.Builder, indent string, s overviewSymbol) {
+	fmt.Fprintf(b, "%s- `%s`", indent, signatureLine(s.Signature))
+	if s.Doc != "" {
+		fmt.Fprintf(b, " — %s", strings.ReplaceAll(s.Doc, "\n", " "))
+	}
+	b.WriteString("\n")
+}
+
+// signatureLine shortens a multi-line type signature to its first line:
+// "type Store struct { ... }"
+func signatureLine(sig string) string {
+	first, _, multiline := strings.Cut(sig, "\n")
+	if !multiline {
+		return sig
+	}
+	return first + " ... }"
+}
+
+// writeEmbeds writes a type's embeds, each with the method names it promotes
+func writeEmbeds(b *strings.Builder, t *overviewType) {
+	for _, embed := range t.Embeds {
+		fmt.Fprintf(b, "  - embeds `%s`", embed)
+		var names []string
+		for _, m := range t.Promoted {
+			if m.From == embed {
+				names = append(names, "`"+m.Name+"`")
+			}
+		}
+		if len(names) > 0 {
+			fmt.Fprintf(b, ": promotes %s", strings.Join(names, ", "))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// writeOmitted notes the items dropped from a section to fit the budget
+func writeOmitted(b *strings.Builder, n int) {
+	if n > 0 {
+		fmt.Fprintf(b, "- … %d more\n", n)
+	}
+}
+
+// firstParagraph returns the text up to the first blank line
+func firstParagraph(text string) string {
+	paragraph, _, _ := strings.Cut(strings.TrimSpace(text), "\n\n")
+	return paragraph
+}
+
+// firstSentence returns the first sentence of the first paragraph on one line
+func firstSentence(text string) string {
+	sentence := strings.Join(strings.Fields(firstParagraph(text)), " ")
+	if i := strings.Index(sentence, ". "); i >= 0 {
+		sentence = sentence[:i+1]
+	}
+	return sentence
+}
@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var overviewProject = map[string]string{
+	"go.mod": "module example.com/app\n\ngo 1.22\n",
+	"store/doc.go": `// Package store persists records.
+//
+// It is backed by files on disk.
+package store
+`,
+	"store/store.go": `package store
+
+import (
+	"errors"
+
+	"example.com/app/codec"
+)
+
+// ErrNotFound is returned for missing records.
+var ErrNotFound = errors.New("not found")
+
+// DefaultSize is the initial capacity. It can be tuned.
+const DefaultSize = 16
+
+// Store holds records.
+type Store struct {
+	data map[string][]byte
+}
+
+// New creates a store.
+func New() *Store { return &Store{} }
+
+// Get returns a record.
+func (s *Store) Get(key string) ([]byte, error) { return codec.Decode(s.data[key]) }
+
+func (s *Store) grow() {}
+
+// Open opens a store at path.
+func Open(path string) (*Store, error) { return New(), nil }
+
+// Version reports the format version.
+func Version() int { return 1 }
+
+func helper() {}
+`,
+	"store/store_test.go": "package store\n\nimport \"testing\"\n\nfunc TestGet(t *testing.T) {}\n",
+	"codec/codec.go":      "package codec\n\n// Decode decodes data.\nfunc Decode(data []byte) ([]byte, error) { return data, nil }\n",
+	"api/api.go":          "package api\n\nimport \"example.com/app/store\"\n\nvar S = store.New()\n",
+}
+
+func TestHandleGetPackageOverview(t *testing.T) {
+	s := newTestServer(t)
+	dir := indexTestProject(t, s, overviewProject)
+
+	result, err := s.handleGetPackageOverview(context.Background(), callRequest(map[string]interface{}{
+		"path": dir, "package": "store",
+	}))
+	require.NoError(t, err)
+	markdown := resultText(t, result)
+	assert.Equal(t, "# package store\n\n"+
+		"`import \"example.com/app/store\"` · dir `store` · 2 files\n\n"+
+		"Package store persists records.\n\nIt is backed by files on disk.\n\n"+
+		"## Types\n\n"+
+		"- `type Store struct{ data map[string][]byte }` — Store holds records.\n"+
+		"  - `func New() *Store` — New creates a store.\n"+
+		"  - `func Open(path string) (*Store, error)` — Open opens a store at path.\n"+
+		"  - `func (*Store) Get(key string) ([]byte, error)` — Get returns a record.\n\n"+
+		"## Functions\n\n"+
+		"- `func Version() int` — Version reports the format version.\n\n"+
+		"## Constants\n\n"+
+		"- `const DefaultSize = 16` — DefaultSize is the initial capacity. It can be tuned.\n\n"+
+		"## Errors\n\n"+
+		"- `var ErrNotFound = errors.New(\"not found\")` — ErrNotFound is returned for missing records.\n\n"+
+		"## Dependencies\n\n- example.com/app/codec\n\n"+
+		"## Dependents\n\n- example.com/app/api\n", markdown)
+
+	// Looked up by import path, as JSON
+	result, err = s.handleGetPackageOverview(context.Background(), callRequest(map[string]interface{}{
+		"path": dir, "package": "example.com/app/store", "format": "json",
+	}))
+	require.NoError(t, err)
+	var overview packageOverview
+	require.NoError(t, json.Unmarshal([]byte(resultText(t, result)), &overview))
+	assert.Equal(t, "store", overview.Dir)
+	require.Len(t, overview.Types, 1)
+	assert.Len(t, overview.Types[0].Methods, 1)
+	assert.Equal(t, []string{"example.com/app/api"}, overview.Dependents)
+
+	_, err = s.handleGetPackageOverview(context.Background(), callRequest(map[string]interface{}{
+		"path": dir, "package": "missing",
+	}))
+	assert.Error(t, err)
+}
+
+func TestGetPackageOverview_Embeds(t *testing.T) {
+	s := newTestServer(t)
+	dir := indexTestProject(t, s, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"base/base.go": `package base
+
+// Base tracks identity.
+type Base struct{ ID int64 }
+
+// Key returns the identity key.
+func (b *Base) Key() string { return "" }
+
+// Close releases resources.
+func (b *Base) Close() error { return nil }
+`,
+		"store/store.go": `package store
+
+import (
+	"sync"
+
+	core "example.com/app/base"
+)
+
+// Reader reads records.
+type Reader interface {
+	// Read returns a record.
+	Read(key string) ([]byte, error)
+}
+
+// ReadCloser reads and closes.
+type ReadCloser interface {
+	Reader
+	Close() error
+}
+
+// Store holds records.
+type Store struct {
+	*core.Base
+	sync.Mutex
+	Reader
+}
+
+// Close flushes, then closes the base.
+func (s *Store) Close() error { return nil }
+`,
+	})
+
+	result, err := s.handleGetPackageOverview(context.Background(), callRequest(map[string]interface{}{
+		"path": dir, "package": "store", "format": "json",
+	}))
+	require.NoError(t, err)
+	var overview packageOverview
+	require.NoError(t, json.Unmarshal([]byte(resultText(t, result)), &overview))
+
+	byName := make(map[string]*overviewType)
+	for _, t := range overview.Types {
+		byName[t.Name] = t
+	}
+	require.Contains(t, byName, "Store")
+	store := byName["Store"]
+	assert.Equal(t, []string{"*core.Base", "sync.Mutex", "Reader"}, store.Embeds)
+	assert.Equal(t, []promotedMethod{
+		{Name: "Key", Signature: "func (*Base) Key() string", From: "*core.Base"},
+		{Name: "Read", Signature: "func (Reader) Read(key string) ([]byte, error)", From: "Reader"},
+	}, store.Promoted, "Close is shadowed; sync.Mutex is outside the project")
+
+	rc := byName["ReadCloser"]
+	require.Len(t, rc.Methods, 1)
+	assert.Equal(t, "func (ReadCloser) Close() error", rc.Methods[0].Signature)
+	require.Len(t, rc.Promoted, 1)
+	assert.Equal(t, "Read", rc.Promoted[0].Name)
+
+	result, err = s.handleGetPackageOverview(context.Background(), callRequest(map[string]interface{}{
+		"path": dir, "package": "store",
+	}))
+	require.NoError(t, err)
+	markdown := resultText(t, result)
+	assert.Contains(t, markdown, "  - embeds `*core.Base`: promotes `Key`\n  - embeds `sync.Mutex`\n  - embeds `Reader`: promotes `Read`\n")
+	assert.Contains(t, markdown, "  - `func (Reader) Read(key string) ([]byte, error)` — Read returns a record.\n")
+}
+
+func TestFitOverview(t *testing.T) {
+	overview := &packageOverview{Name: "big", Dir: "big", Files: 1, Doc: "Package big is big.\n\nMore detail here."}
+	for i := 0; i < 200; i++ {
+		overview.Functions = append(overview.Functions, overviewSymbol{
+			Name:      "F",
+			Signature: "func F(ctx context.Context, input string) (string, error)",
+			Doc:       "F does something useful. And more.",
+		})
+	}
+
+	full := fitOverview(overview, "markdown", maxOverviewTokens)
+	assert.Contains(t, full, "More detail here.")
+	assert.Contains(t, full, "F does something useful. And more.")
+
+	small := fitOverview(overview, "markdown", minOverviewTokens)
+	assert.LessOrEqual(t, len(small)/4, minOverviewTokens)
+	assert.NotContains(t, small, "More detail here.")
+	assert.NotContains(t, small, "F does something useful")
+	assert.Contains(t, small, "more\n")
+	assert.True(t, strings.HasPrefix(small, "# package big"))
+	assert.Len(t, overview.Functions, 200, "fitting does not modify the overview")
+}
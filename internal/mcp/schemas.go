@@ -31,6 +31,26 @@ func indexCodebaseTool() mcp.Tool {
 					"description": "If true, index vendor/ directory",
 					"default":     false,
 				},
+				"ref": map[string]interface{}{
+					"type":        "string",
+					"description": "Git revision to index instead of the working tree (branch, tag, commit, HEAD~1). Read from the local .git object database and stored as a separate snapshot searchable with the same ref",
+				},
+				"git_history": map[string]interface{}{
+					"type":        "boolean",
+					"description": "If true, record each chunk's last commit, author, date and change count from the local git history (enables the modified_since filter and recency reranker)",
+					"default":     false,
+				},
+				"dependencies": map[string]interface{}{
+					"type":        "string",
+					"description": "Also index the exported API of required modules found in the local module cache (GOMODCACHE) or vendor directory, as read-only projects tagged with module@version: none, direct, or all (including indirect). Nothing is downloaded",
+					"enum":        []string{"none", "direct", "all"},
+					"default":     "none",
+				},
+				"stdlib": map[string]interface{}{
+					"type":        "boolean",
+					"description": "If true, also index the standard library of the project's Go version (toolchain or go directive) from the local GOROOT or a toolchain in the module cache. Each Go release is indexed once and shared by all projects",
+					"default":     false,
+				},
 			},
 			Required: []string{"path"},
 		},
@@ -49,6 +69,32 @@ func searchCodeTool() mcp.Tool {
 					"type":        "string",
 					"description": "Absolute path to indexed Go project",
 				},
+				"paths": map[string]interface{}{
+					"type":        "array",
+					"description": "Absolute paths of several indexed projects to search together (combined with path)",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"all_projects": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Search every indexed project except dependencies, standard libraries and indexed git revisions; results include the project root",
+					"default":     false,
+				},
+				"include_dependencies": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also search the dependencies indexed for the selected projects (index_codebase dependencies); results from them include the dependency as module@version",
+					"default":     false,
+				},
+				"include_stdlib": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also search the standard library linked to the selected projects (index_codebase stdlib); results from it include the dependency as std@go1.N.P",
+					"default":     false,
+				},
+				"ref": map[string]interface{}{
+					"type":        "string",
+					"description": "Search the snapshot of this git revision indexed with index_codebase ref, instead of the working tree",
+				},
 				"query": map[string]interface{}{
 					"type":        "string",
 					"description": "Search query (natural language or keywords)",
@@ -84,6 +130,17 @@ func searchCodeTool() mcp.Tool {
 								"enum": []string{"aggregate", "entity", "value_object", "repository", "service", "command", "query", "handler"},
 							},
 						},
+						"generic": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Only generic functions and types (those declaring type parameters)",
+						},
+						"constraints": map[string]interface{}{
+							"type":        "array",
+							"description": "Only symbols with a type parameter constrained by one of these, as written (e.g., 'comparable', '~int | ~string'); implies generic",
+							"items": map[string]interface{}{
+								"type": "string",
+							},
+						},
 						"packages": map[string]interface{}{
 							"type":        "array",
 							"description": "Filter by package names",
@@ -97,16 +154,55 @@ func searchCodeTool() mcp.Tool {
 							"minimum":     0.0,
 							"maximum":     1.0,
 						},
+						"modified_since": map[string]interface{}{
+							"type":        "string",
+							"description": "Only chunks whose lines last changed at or after this time: RFC 3339, YYYY-MM-DD, or an age like 48h, 7d, 2w (requires indexing with git_history)",
+						},
+						"min_metrics": map[string]interface{}{
+							"type":        "object",
+							"description": "Only functions and methods reaching every given minimum (e.g., {\"cyclomatic\": 10, \"nesting\": 4})",
+							"properties": map[string]interface{}{
+								"cyclomatic": map[string]interface{}{"type": "integer", "minimum": 0, "description": "Cyclomatic complexity"},
+								"cognitive":  map[string]interface{}{"type": "integer", "minimum": 0, "description": "Cognitive complexity"},
+								"lines":      map[string]interface{}{"type": "integer", "minimum": 0, "description": "Lines of the declaration"},
+								"params":     map[string]interface{}{"type": "integer", "minimum": 0, "description": "Parameters"},
+								"nesting":    map[string]interface{}{"type": "integer", "minimum": 0, "description": "Deepest nesting of control structures"},
+								"returns":    map[string]interface{}{"type": "integer", "minimum": 0, "description": "Return statements"},
+							},
+						},
 					},
 				},
+				"sort_by": map[string]interface{}{
+					"type":        "string",
+					"description": "Order the best candidates by a complexity metric, highest first, instead of relevance",
+					"enum":        []string{"relevance", "cyclomatic", "cognitive", "lines", "params", "nesting", "returns"},
+					"default":     "relevance",
+				},
 				"search_mode": map[string]interface{}{
 					"type":        "string",
 					"description": "Search strategy: hybrid (vector + keyword), vector (semantic only), or keyword (BM25 only)",
 					"enum":        []string{"hybrid", "vector", "keyword"},
 					"default":     "hybrid",
 				},
+				"rerank": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional second-stage reranker for the top candidates: none, lexical (symbol name, signature and exportedness), recency (recently and frequently changed code, requires git_history), or cross_encoder (HTTP rerank API, requires GOCONTEXT_RERANK_URL or JINA_API_KEY)",
+					"enum":        []string{"none", "lexical", "recency", "cross_encoder"},
+					"default":     "none",
+				},
+				"highlight": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Report matched terms and the line numbers inside each result where they occur; results without a term match embed their lines to find the most similar ones",
+					"default":     false,
+				},
+				"snippet_mode": map[string]interface{}{
+					"type":        "string",
+					"description": "full returns whole chunk content; compact returns only matched lines (with one line of context and line numbers)",
+					"enum":        []string{"full", "compact"},
+					"default":     "full",
+				},
 			},
-			Required: []string{"path", "query"},
+			Required: []string{"query"},
 		},
 	}
 }
@@ -123,6 +219,360 @@ func getStatusTool() mcp.Tool {
 					"type":        "string",
 					"description": "Absolute path to Go project",
 				},
+				"ref": map[string]interface{}{
+					"type":        "string",
+					"description": "Report on the snapshot of this git revision instead of the working tree",
+				},
+			},
+			Required: []string{"path"},
+		},
+	}
+}
+
+// listAnnotationsTool returns the tool definition for list_annotations
+func listAnnotationsTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "list_annotations",
+		Description: "List TODO/FIXME/HACK/XXX/NOTE markers, Deprecated: notices and //nolint directives in an indexed Go project, with their symbol, location, owner and issue references",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Absolute path to Go project",
+				},
+				"ref": map[string]interface{}{
+					"type":        "string",
+					"description": "List annotations of the snapshot of this git revision instead of the working tree",
+				},
+				"kinds": map[string]interface{}{
+					"type":        "array",
+					"description": "Annotation kinds to include (default: all)",
+					"items": map[string]interface{}{
+						"type": "string",
+						"enum": []string{"todo", "fixme", "hack", "xxx", "note", "deprecated", "nolint"},
+					},
+				},
+				"packages": map[string]interface{}{
+					"type":        "array",
+					"description": "Filter by package names",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"owner": map[string]interface{}{
+					"type":        "string",
+					"description": "Owner from TODO(owner): markers, case-insensitive",
+				},
+				"file_pattern": map[string]interface{}{
+					"type":        "string",
+					"description": "Glob pattern for file paths (e.g., 'internal/**')",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum annotations to return; counts cover all matches",
+					"minimum":     1,
+					"maximum":     1000,
+					"default":     100,
+				},
+			},
+			Required: []string{"path"},
+		},
+	}
+}
+
+// findTestsTool returns the tool definition for find_tests
+func findTestsTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "find_tests",
+		Description: "Find the Test, Benchmark, Fuzz and Example functions exercising a symbol or the symbols of a file, linked by test name (TestType_Method) and by the functions they call, with go test commands to run them",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Absolute path to Go project",
+				},
+				"ref": map[string]interface{}{
+					"type":        "string",
+					"description": "Search the snapshot of this git revision instead of the working tree",
+				},
+				"symbol": map[string]interface{}{
+					"type":        "string",
+					"description": "Symbol under test: 'Name', 'Type.Method', 'pkg.Name' or 'pkg.Type.Method'",
+				},
+				"file": map[string]interface{}{
+					"type":        "string",
+					"description": "Non-test source file, relative to the project root; finds tests for all its functions, methods and types",
+				},
+				"package": map[string]interface{}{
+					"type":        "string",
+					"description": "Restrict symbol lookup to a package name or import path",
+				},
+				"kinds": map[string]interface{}{
+					"type":        "array",
+					"description": "Test kinds to include (default: all)",
+					"items": map[string]interface{}{
+						"type": "string",
+						"enum": []string{"test", "benchmark", "fuzz", "example"},
+					},
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum tests to return",
+					"minimum":     1,
+					"maximum":     500,
+					"default":     50,
+				},
+			},
+			Required: []string{"path"},
+		},
+	}
+}
+
+// getPackageOverviewTool returns the tool definition for get_package_overview
+func getPackageOverviewTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "get_package_overview",
+		Description: "Summarize a package's API surface like go doc: package doc, exported types with constructors and methods, exported functions, constants and errors, and the project packages it depends on and that depend on it",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Absolute path to Go project",
+				},
+				"ref": map[string]interface{}{
+					"type":        "string",
+					"description": "Describe the snapshot of this git revision instead of the working tree",
+				},
+				"package": map[string]interface{}{
+					"type":        "string",
+					"description": "Package directory relative to the project root (e.g., 'internal/embedder'), import path or package name",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"description": "Output format",
+					"enum":        []string{"markdown", "json"},
+					"default":     "markdown",
+				},
+				"max_tokens": map[string]interface{}{
+					"type":        "integer",
+					"description": "Approximate token budget; docs are shortened, then items dropped, to fit",
+					"minimum":     200,
+					"maximum":     20000,
+					"default":     2000,
+				},
+			},
+			Required: []string{"path", "package"},
+		},
+	}
+}
+
+// findByTagTool returns the tool definition for find_by_tag
+func findByTagTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "find_by_tag",
+		Description: "Find struct fields by struct tag, e.g. which struct maps the db column 'user_id' or which field serializes as JSON 'created_at'",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Absolute path to Go project",
+				},
+				"ref": map[string]interface{}{
+					"type":        "string",
+					"description": "Search the snapshot of this git revision instead of the working tree",
+				},
+				"key": map[string]interface{}{
+					"type":        "string",
+					"description": "Tag key such as 'json', 'db', 'yaml' or 'validate' (default: any key)",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Mapped name: the tag value up to the first comma, e.g. 'user_id' for db:\"user_id\" or 'created_at' for json:\"created_at,omitempty\"",
+				},
+				"packages": map[string]interface{}{
+					"type":        "array",
+					"description": "Filter by package names",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"file_pattern": map[string]interface{}{
+					"type":        "string",
+					"description": "Glob pattern for file paths (e.g., 'internal/**')",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum fields to return; total covers all matches",
+					"minimum":     1,
+					"maximum":     1000,
+					"default":     100,
+				},
+			},
+			Required: []string{"path"},
+		},
+	}
+}
+
+// checkArchitectureTool returns the tool definition for check_architecture
+func checkArchitectureTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "check_architecture",
+		Description: "Check imports against the architecture layer rules of .gocontext.yaml (e.g. domain must not import infrastructure) and report each violation with its file and import line",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Absolute path to Go project",
+				},
+				"ref": map[string]interface{}{
+					"type":        "string",
+					"description": "Check the snapshot of this git revision instead of the working tree",
+				},
+				"include_tests": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also check imports of _test.go files",
+					"default":     false,
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum violations to return; total and by_rule cover all violations",
+					"minimum":     1,
+					"maximum":     1000,
+					"default":     100,
+				},
+			},
+			Required: []string{"path"},
+		},
+	}
+}
+
+// traceUseCaseTool returns the tool definition for trace_use_case
+func traceUseCaseTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "trace_use_case",
+		Description: "Trace a CQRS command or query through the handlers that handle it, the repositories they use and the aggregates those persist (e.g. PlaceOrderCommand → PlaceOrderHandler → OrderRepository → Order), with the reason for each link",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Absolute path to Go project",
+				},
+				"ref": map[string]interface{}{
+					"type":        "string",
+					"description": "Trace in the snapshot of this git revision instead of the working tree",
+				},
+				"symbol": map[string]interface{}{
+					"type":        "string",
+					"description": "Command or query type: 'Name' or 'pkg.Name'",
+				},
+				"package": map[string]interface{}{
+					"type":        "string",
+					"description": "Restrict symbol lookup to a package name or import path",
+				},
+			},
+			Required: []string{"path", "symbol"},
+		},
+	}
+}
+
+// listHotspotsTool returns the tool definition for list_hotspots
+func listHotspotsTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "list_hotspots",
+		Description: "List the functions and methods most worth refactoring, scored by cyclomatic and cognitive complexity, function and file size, and churn when indexed with git history",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Absolute path to Go project",
+				},
+				"ref": map[string]interface{}{
+					"type":        "string",
+					"description": "List hotspots of the snapshot of this git revision instead of the working tree",
+				},
+				"packages": map[string]interface{}{
+					"type":        "array",
+					"description": "Filter by package names",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"file_pattern": map[string]interface{}{
+					"type":        "string",
+					"description": "Glob pattern for file paths (e.g., 'internal/**')",
+				},
+				"include_tests": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Include functions of _test.go files",
+					"default":     false,
+				},
+				"sort_by": map[string]interface{}{
+					"type":        "string",
+					"description": "Order by the combined score, churn or a single metric, highest first",
+					"enum":        []string{"score", "churn", "cyclomatic", "cognitive", "lines", "params", "nesting", "returns"},
+					"default":     "score",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum hotspots to return; total covers all functions",
+					"minimum":     1,
+					"maximum":     500,
+					"default":     20,
+				},
+			},
+			Required: []string{"path"},
+		},
+	}
+}
+
+// findUnusedTool returns the tool definition for find_unused
+func findUnusedTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "find_unused",
+		Description: "Find dead code: unexported declarations unused in their package and exported ones unused anywhere in the indexed module, grouped by package with a confidence and reason for each. Entry points (main, init, tests) are skipped; methods that may implement interfaces and fields reachable by reflection are reported with low confidence. Without type information, fields and methods are mostly matched by name across the project (a use of any Save keeps every Save), so the report can miss dead methods and fields: an empty report is not proof that the code is live",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Absolute path to Go project",
+				},
+				"ref": map[string]interface{}{
+					"type":        "string",
+					"description": "Analyze the snapshot of this git revision instead of the working tree",
+				},
+				"packages": map[string]interface{}{
+					"type":        "array",
+					"description": "Report only these packages, by name or import path; uses are still resolved across the project",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"min_confidence": map[string]interface{}{
+					"type":        "string",
+					"description": "Lowest confidence to report",
+					"enum":        []string{"low", "medium", "high"},
+					"default":     "low",
+				},
+				"include_tests": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also report unused helpers of _test.go files",
+					"default":     false,
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum declarations to return; total and by_confidence cover all matches",
+					"minimum":     1,
+					"maximum":     5000,
+					"default":     200,
+				},
 			},
 			Required: []string{"path"},
 		},
@@ -63,12 +63,20 @@ func NewServer(dbPath string) (*Server, error) {
 		return nil, fmt.Errorf("failed to initialize embedder: %w", err)
 	}
 
+	// Persist embeddings on disk so query and chunk vectors survive restarts
+	emb = embedder.NewPersistentCachedEmbedder(emb, store, embedder.PersistentCacheSizeFromEnv())
+
 	// Create indexer with shared embedder
 	idx := indexer.NewWithEmbedder(store, emb)
 
 	// Create searcher with shared embedder
 	srch := searcher.NewSearcher(store, emb)
 
+	// Enable the cross-encoder reranker when an endpoint is configured
+	if reranker, err := searcher.NewHTTPRerankerFromEnv(); err == nil {
+		srch.RegisterReranker(reranker)
+	}
+
 	// Create MCP server
 	mcpServer := server.NewMCPServer(
 		ServerName,
@@ -107,5 +115,29 @@ func (s *Server) registerTools() error {
 	// Register get_status tool
 	s.mcp.AddTool(getStatusTool(), s.handleGetStatus)
 
+	// Register list_annotations tool
+	s.mcp.AddTool(listAnnotationsTool(), s.handleListAnnotations)
+
+	// Register find_tests tool
+	s.mcp.AddTool(findTestsTool(), s.handleFindTests)
+
+	// Register get_package_overview tool
+	s.mcp.AddTool(getPackageOverviewTool(), s.handleGetPackageOverview)
+
+	// Register find_by_tag tool
+	s.mcp.AddTool(findByTagTool(), s.handleFindByTag)
+
+	// Register check_architecture tool
+	s.mcp.AddTool(checkArchitectureTool(), s.handleCheckArchitecture)
+
+	// Register trace_use_case tool
+	s.mcp.AddTool(traceUseCaseTool(), s.handleTraceUseCase)
+
+	// Register list_hotspots tool
+	s.mcp.AddTool(listHotspotsTool(), s.handleListHotspots)
+
+	// Register find_unused tool
+	s.mcp.AddTool(findUnusedTool(), s.handleFindUnused)
+
 	return nil
 }
@@ -1,12 +1,96 @@
 package mcp
 
 import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	projectconfig "github.com/dshills/gocontext-mcp/internal/config"
+	"github.com/dshills/gocontext-mcp/internal/embedder"
+	"github.com/dshills/gocontext-mcp/internal/indexer"
+	"github.com/dshills/gocontext-mcp/internal/searcher"
+	"github.com/dshills/gocontext-mcp/internal/storage"
 )
 
+// newTestServer returns a server backed by an in-memory database, without embeddings
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	store, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	return &Server{storage: store, indexer: indexer.New(store)}
+}
+
+// indexTestProject writes files (relative path -> content) to a temporary directory
+// and indexes it without embeddings
+func indexTestProject(t *testing.T, s *Server, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	_, err := s.indexer.IndexProject(context.Background(), dir, &indexer.Config{Workers: 1, IncludeTests: true})
+	require.NoError(t, err)
+	return dir
+}
+
+// runGit runs git in dir with a fixed identity, skipping the test if git is not installed
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Test",
+		"GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test",
+		"GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %s: %s", strings.Join(args, " "), out)
+}
+
+// callRequest builds a tool call request with the given arguments
+func callRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+}
+
+// callTool invokes a handler and decodes its JSON response
+func callTool(t *testing.T, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error),
+	args map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	result, err := handler(context.Background(), callRequest(args))
+	require.NoError(t, err)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(resultText(t, result)), &response))
+	return response
+}
+
+// resultText returns the text of a single-content tool result
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	require.Len(t, result.Content, 1)
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	return text.Text
+}
+
 // T085: Regression test for shared embedder instance between indexer and searcher
 // Verifies that NewServer creates a single embedder instance shared by both components
 // Implementation: internal/mcp/server.go (lines 60-70)
@@ -165,3 +249,175 @@ func TestEmbedderSharingPattern(t *testing.T) {
 		t.Log("Embedder sharing pattern documented")
 	})
 }
+
+func TestParseSearchOptions_Highlight(t *testing.T) {
+	on := true
+	tests := []struct {
+		args     map[string]interface{}
+		defaults projectconfig.SearchConfig
+		want     bool
+	}{
+		{map[string]interface{}{}, projectconfig.SearchConfig{}, false},
+		{map[string]interface{}{"highlight": true}, projectconfig.SearchConfig{}, true},
+		{map[string]interface{}{}, projectconfig.SearchConfig{Highlight: &on}, true},
+		{map[string]interface{}{"snippet_mode": "compact"}, projectconfig.SearchConfig{}, true},
+	}
+	for _, tt := range tests {
+		opts, err := parseSearchOptions(tt.args, tt.defaults)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, opts.highlight, tt.args)
+	}
+}
+
+func TestParseModifiedSince(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-06-01T08:30:00+02:00", time.Date(2024, 6, 1, 6, 30, 0, 0, time.UTC)},
+		{"2024-06-01", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"48h", now.Add(-48 * time.Hour)},
+		{"7d", time.Date(2024, 6, 8, 12, 0, 0, 0, time.UTC)},
+		{"2w", time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseModifiedSince(tt.value, now)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %s", got)
+		})
+	}
+
+	for _, bad := range []string{"yesterday", "-7d", "d", "-1h"} {
+		_, err := parseModifiedSince(bad, now)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestSearchCode_DDDPatterns(t *testing.T) {
+	s := newTestServer(t)
+	emb, err := embedder.NewLocalProvider(nil)
+	require.NoError(t, err)
+	s.searcher = searcher.NewSearcher(s.storage, emb)
+
+	dir := indexTestProject(t, s, map[string]string{
+		".gocontext.yaml": "ddd:\n  rules:\n    - pattern: repository\n      name: Store$\n      confidence: 0.6\n",
+		"order.go":        "package shop\n\n// OrderStore persists orders\ntype OrderStore interface {\n\tSave(id string) error\n}\n",
+	})
+
+	response := callTool(t, s.handleSearchCode, map[string]interface{}{
+		"path": dir, "query": "OrderStore", "search_mode": "keyword",
+		"filters": map[string]interface{}{"ddd_patterns": []interface{}{"repository"}},
+	})
+	results := response["results"].([]interface{})
+	require.Len(t, results, 1)
+	symbol := results[0].(map[string]interface{})["symbol"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"pattern": "repository", "confidence": 0.6, "reason": "name matches Store$",
+	}}, symbol["ddd"])
+}
+
+func TestSearchCode_AllProjectsSkipsSnapshots(t *testing.T) {
+	s := newTestServer(t)
+	emb, err := embedder.NewLocalProvider(nil)
+	require.NoError(t, err)
+	s.searcher = searcher.NewSearcher(s.storage, emb)
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.22\n",
+		"main.go": "package main\n\n// Reconcile settles the ledger\nfunc Reconcile() {}\n",
+	})
+	runGit(t, dir, "init", "-q", "-b", "main")
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "First")
+	runGit(t, dir, "tag", "v1")
+
+	callTool(t, s.handleIndexCodebase, map[string]interface{}{"path": dir})
+	callTool(t, s.handleIndexCodebase, map[string]interface{}{"path": dir, "ref": "v1"})
+	other := indexTestProject(t, s, map[string]string{
+		"audit.go": "package audit\n\n// Reconcile checks the books\nfunc Reconcile() {}\n",
+	})
+
+	response := callTool(t, s.handleSearchCode, map[string]interface{}{
+		"all_projects": true, "query": "Reconcile", "search_mode": "keyword",
+	})
+	var projects []string
+	for _, r := range response["results"].([]interface{}) {
+		projects = append(projects, r.(map[string]interface{})["project"].(string))
+	}
+	assert.ElementsMatch(t, []string{dir, other}, projects, "the v1 snapshot repeats the working tree")
+
+	// Named explicitly, the snapshot is still searched
+	response = callTool(t, s.handleSearchCode, map[string]interface{}{
+		"path": dir, "ref": "v1", "query": "Reconcile", "search_mode": "keyword",
+	})
+	assert.Len(t, response["results"], 1)
+}
+
+func TestSearchCode_CompactMatches(t *testing.T) {
+	s := newTestServer(t)
+	emb, err := embedder.NewLocalProvider(nil)
+	require.NoError(t, err)
+	s.searcher = searcher.NewSearcher(s.storage, emb)
+
+	dir := indexTestProject(t, s, map[string]string{
+		"ledger.go": "package ledger\n\n// Reconcile settles the ledger\nfunc Reconcile() {\n\tbalance := 0\n\t_ = balance\n}\n",
+	})
+
+	search := func(snippetMode string) map[string]interface{} {
+		response := callTool(t, s.handleSearchCode, map[string]interface{}{
+			"path": dir, "query": "balance", "search_mode": "keyword", "highlight": true, "snippet_mode": snippetMode,
+		})
+		results := response["results"].([]interface{})
+		require.Len(t, results, 1)
+		return results[0].(map[string]interface{})
+	}
+
+	full := search("full")
+	assert.Contains(t, full["matches"].([]interface{})[0], "text")
+
+	compact := search("compact")
+	assert.Contains(t, compact["snippet"], "balance := 0")
+	assert.NotContains(t, compact, "content")
+	match := compact["matches"].([]interface{})[0].(map[string]interface{})
+	assert.NotContains(t, match, "text", "the snippet already holds the line")
+	assert.Equal(t, float64(5), match["line"])
+}
+
+func TestSearchCode_Metrics(t *testing.T) {
+	s := newTestServer(t)
+	emb, err := embedder.NewLocalProvider(nil)
+	require.NoError(t, err)
+	s.searcher = searcher.NewSearcher(s.storage, emb)
+
+	dir := indexTestProject(t, s, map[string]string{
+		"process.go": "package shop\n\n" +
+			"// ProcessSimple processes nothing\nfunc ProcessSimple() {}\n\n" +
+			"// ProcessBranches processes items\nfunc ProcessBranches(items []int) int {\n" +
+			"\tn := 0\n\tfor _, i := range items {\n\t\tif i > 0 {\n\t\t\tn++\n\t\t}\n\t}\n\treturn n\n}\n",
+	})
+
+	response := callTool(t, s.handleSearchCode, map[string]interface{}{
+		"path": dir, "query": "processes", "search_mode": "keyword", "sort_by": "cyclomatic",
+		"filters": map[string]interface{}{"min_metrics": map[string]interface{}{"nesting": float64(2)}},
+	})
+	results := response["results"].([]interface{})
+	require.Len(t, results, 1)
+	symbol := results[0].(map[string]interface{})["symbol"].(map[string]interface{})
+	assert.Equal(t, "ProcessBranches", symbol["name"])
+	metrics := symbol["metrics"].(map[string]interface{})
+	assert.Equal(t, float64(3), metrics["cyclomatic"])
+	assert.Equal(t, float64(2), metrics["nesting"])
+
+	for _, args := range []map[string]interface{}{
+		{"sort_by": "size"},
+		{"filters": map[string]interface{}{"min_metrics": map[string]interface{}{"depth": float64(1)}}},
+		{"filters": map[string]interface{}{"min_metrics": map[string]interface{}{"lines": float64(-1)}}},
+	} {
+		args["path"], args["query"] = dir, "processes"
+		_, err := s.handleSearchCode(context.Background(), callRequest(args))
+		assert.Error(t, err, args)
+	}
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/dshills/gocontext-mcp/internal/storage"
+)
+
+// Limits for find_by_tag
+const (
+	defaultTagLimit = 100
+	maxTagLimit     = 1000
+)
+
+// handleFindByTag handles the find_by_tag tool invocation
+func (s *Server) handleFindByTag(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid arguments", nil)
+	}
+
+	project, err := s.indexedProject(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := storage.TagFilter{
+		Key:         strings.TrimSpace(getStringDefault(args, "key", "")),
+		Name:        strings.TrimSpace(getStringDefault(args, "name", "")),
+		Packages:    getStringSlice(args, "packages"),
+		FilePattern: getStringDefault(args, "file_pattern", ""),
+	}
+	if filter.Key == "" && filter.Name == "" {
+		return nil, newMCPError(ErrorCodeInvalidParams, "key or name is required", map[string]interface{}{
+			"param": "name",
+		})
+	}
+
+	limit := getIntDefault(args, "limit", defaultTagLimit)
+	if limit < 1 || limit > maxTagLimit {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid limit", map[string]interface{}{
+			"param":  "limit",
+			"value":  limit,
+			"reason": "must be between 1 and 1000",
+		})
+	}
+
+	fields, err := s.storage.FindFieldsByTag(ctx, project.ID, filter)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to find fields by tag", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	total := len(fields)
+	if len(fields) > limit {
+		fields = fields[:limit]
+	}
+
+	response := map[string]interface{}{
+		"fields":   formatTaggedFields(fields),
+		"total":    total,
+		"returned": len(fields),
+	}
+	return mcp.NewToolResultText(formatJSON(response)), nil
+}
+
+// formatTaggedFields converts tagged fields to response maps
+func formatTaggedFields(fields []*storage.TaggedField) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(fields))
+	for i, f := range fields {
+		m := map[string]interface{}{
+			"struct":    f.Receiver,
+			"field":     f.Name,
+			"signature": f.Signature,
+			"key":       f.Tag.Key,
+			"value":     f.Tag.Value,
+			"file":      f.FilePath,
+			"line":      f.StartLine,
+			"package":   f.PackageName,
+		}
+		if f.ImportPath != "" {
+			m["import_path"] = f.ImportPath
+		}
+		result[i] = m
+	}
+	return result
+}
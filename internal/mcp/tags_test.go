@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleFindByTag(t *testing.T) {
+	s := newTestServer(t)
+	dir := indexTestProject(t, s, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"model/user.go": "package model\n\n" +
+			"// User is an account\n" +
+			"type User struct {\n" +
+			"\tID        int64  `json:\"id\" db:\"user_id\"`\n" +
+			"\tCreatedAt string `json:\"created_at,omitempty\" db:\"created_at\"`\n" +
+			"}\n",
+		"billing/order.go": "package billing\n\n" +
+			"type Order struct {\n" +
+			"\tUserID int64 `db:\"user_id\"`\n" +
+			"}\n",
+	})
+
+	response := callTool(t, s.handleFindByTag, map[string]interface{}{"path": dir, "key": "db", "name": "user_id"})
+	assert.Equal(t, float64(2), response["total"])
+	fields := response["fields"].([]interface{})
+	require.Len(t, fields, 2)
+	first := fields[0].(map[string]interface{})
+	assert.Equal(t, "Order", first["struct"])
+	assert.Equal(t, "UserID", first["field"])
+	assert.Equal(t, "billing/order.go", first["file"])
+	assert.Equal(t, "example.com/app/billing", first["import_path"])
+
+	response = callTool(t, s.handleFindByTag, map[string]interface{}{"path": dir, "name": "created_at", "packages": []interface{}{"model"}})
+	fields = response["fields"].([]interface{})
+	require.Len(t, fields, 2, "one result per matching key")
+	assert.Equal(t, "json", fields[0].(map[string]interface{})["key"])
+	assert.Equal(t, "created_at,omitempty", fields[0].(map[string]interface{})["value"])
+
+	response = callTool(t, s.handleFindByTag, map[string]interface{}{"path": dir, "key": "json", "limit": float64(1)})
+	assert.Equal(t, float64(2), response["total"])
+	assert.Equal(t, float64(1), response["returned"])
+
+	_, err := s.handleFindByTag(context.Background(), callRequest(map[string]interface{}{"path": dir}))
+	var mcpErr *MCPError
+	require.ErrorAs(t, err, &mcpErr)
+	assert.Equal(t, ErrorCodeInvalidParams, mcpErr.Code)
+}
@@ -0,0 +1,426 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// Limits for find_tests
+const (
+	defaultFindTestsLimit = 50
+	maxFindTestsLimit     = 500
+)
+
+// Relations linking a test to a symbol
+const (
+	relationName = "name" // The test is named after the symbol
+	relationCall = "call" // The test calls the symbol
+)
+
+// symbolRef is an indexed symbol with the file declaring it
+type symbolRef struct {
+	symbol *storage.Symbol
+	file   *storage.File
+}
+
+// qualifiedName returns "Type.Method" for methods and fields, else the symbol name
+func (r symbolRef) qualifiedName() string {
+	if r.symbol.Receiver != "" {
+		return r.symbol.Receiver + "." + r.symbol.Name
+	}
+	return r.symbol.Name
+}
+
+// linkedTest is a test with the symbols it is linked to
+type linkedTest struct {
+	test      *storage.TestFunction
+	relations map[string]bool
+	symbols   []string
+}
+
+// handleFindTests handles the find_tests tool invocation
+func (s *Server) handleFindTests(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid arguments", nil)
+	}
+
+	project, err := s.indexedProject(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol := strings.TrimSpace(getStringDefault(args, "symbol", ""))
+	file := strings.TrimSpace(getStringDefault(args, "file", ""))
+	if (symbol == "") == (file == "") {
+		return nil, newMCPError(ErrorCodeInvalidParams, "exactly one of symbol or file is required", map[string]interface{}{
+			"param": "symbol",
+		})
+	}
+
+	kinds := getStringSlice(args, "kinds")
+	for _, kind := range kinds {
+		switch types.TestKind(kind) {
+		case types.TestKindTest, types.TestKindBenchmark, types.TestKindFuzz, types.TestKindExample:
+		default:
+			return nil, newMCPError(ErrorCodeInvalidParams, "invalid kinds", map[string]interface{}{
+				"param":   "kinds",
+				"value":   kind,
+				"allowed": []string{"test", "benchmark", "fuzz", "example"},
+			})
+		}
+	}
+
+	limit := getIntDefault(args, "limit", defaultFindTestsLimit)
+	if limit < 1 || limit > maxFindTestsLimit {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid limit", map[string]interface{}{
+			"param":  "limit",
+			"value":  limit,
+			"reason": "must be between 1 and 500",
+		})
+	}
+
+	var targets []symbolRef
+	if symbol != "" {
+		targets, err = s.resolveSymbol(ctx, project, symbol, getStringDefault(args, "package", ""))
+	} else {
+		targets, err = s.fileSymbols(ctx, project, file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	linked, err := s.linkTests(ctx, project, targets)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to find tests", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	if len(kinds) > 0 {
+		filtered := linked[:0]
+		for _, l := range linked {
+			for _, kind := range kinds {
+				if l.test.Kind == kind {
+					filtered = append(filtered, l)
+					break
+				}
+			}
+		}
+		linked = filtered
+	}
+
+	total := len(linked)
+	if len(linked) > limit {
+		linked = linked[:limit]
+	}
+
+	symbols := make([]map[string]interface{}, len(targets))
+	for i, t := range targets {
+		symbols[i] = map[string]interface{}{
+			"name":    t.qualifiedName(),
+			"kind":    t.symbol.Kind,
+			"package": t.symbol.PackageName,
+			"file":    t.file.FilePath,
+			"line":    t.symbol.StartLine,
+		}
+	}
+
+	response := map[string]interface{}{
+		"symbols":  symbols,
+		"tests":    formatLinkedTests(linked),
+		"commands": testCommands(linked),
+		"total":    total,
+		"returned": len(linked),
+	}
+	return mcp.NewToolResultText(formatJSON(response)), nil
+}
+
+// resolveSymbol finds the symbols named "Name", "Type.Name" or "pkg.Name", optionally
+// "pkg.Type.Name". pkg narrows the match to a package name or import path.
+func (s *Server) resolveSymbol(ctx context.Context, project *storage.Project, symbol, pkg string) ([]symbolRef, error) {
+	parts := strings.Split(symbol, ".")
+	name := parts[len(parts)-1]
+	qualifiers := parts[:len(parts)-1]
+
+	symbols, err := s.storage.ListSymbolsByName(ctx, project.ID, name)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to look up symbol", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	var refs []symbolRef
+	files := make(map[int64]*storage.File)
+	for _, sym := range symbols {
+		switch len(qualifiers) {
+		case 0:
+		case 1:
+			if sym.Receiver != qualifiers[0] && (sym.Receiver != "" || sym.PackageName != qualifiers[0]) {
+				continue
+			}
+		case 2:
+			if sym.PackageName != qualifiers[0] || sym.Receiver != qualifiers[1] {
+				continue
+			}
+		default:
+			continue
+		}
+
+		file, ok := files[sym.FileID]
+		if !ok {
+			if file, err = s.storage.GetFileByID(ctx, sym.FileID); err != nil {
+				return nil, newMCPError(ErrorCodeInternalError, "failed to get file", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+			files[sym.FileID] = file
+		}
+		if pkg != "" && file.PackageName != pkg && file.ImportPath != pkg {
+			continue
+		}
+		refs = append(refs, symbolRef{symbol: sym, file: file})
+	}
+
+	if len(refs) == 0 {
+		return nil, newMCPError(ErrorCodeInvalidParams, "symbol not found", map[string]interface{}{
+			"param":  "symbol",
+			"value":  symbol,
+			"reason": "no indexed symbol matches",
+		})
+	}
+	return refs, nil
+}
+
+// fileSymbols returns the functions, methods and types declared in a file of the project
+func (s *Server) fileSymbols(ctx context.Context, project *storage.Project, path string) ([]symbolRef, error) {
+	if strings.HasSuffix(path, "_test.go") {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid file", map[string]interface{}{
+			"param":  "file",
+			"value":  path,
+			"reason": "pass the file under test, not a test file",
+		})
+	}
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(project.RootPath, path); err == nil {
+			path = rel
+		}
+	}
+
+	file, err := s.storage.GetFile(ctx, project.ID, filepath.Clean(path))
+	if err == storage.ErrNotFound {
+		return nil, newMCPError(ErrorCodeInvalidParams, "file not indexed", map[string]interface{}{
+			"param": "file",
+			"value": path,
+		})
+	}
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to get file", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	symbols, err := s.storage.ListSymbolsByFile(ctx, file.ID)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to list symbols", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	var refs []symbolRef
+	for _, sym := range symbols {
+		switch types.SymbolKind(sym.Kind) {
+		case types.KindFunction, types.KindMethod, types.KindStruct, types.KindInterface, types.KindType:
+			refs = append(refs, symbolRef{symbol: sym, file: file})
+		}
+	}
+	return refs, nil
+}
+
+// linkTests finds the tests linked to any of the targets, tests named after a
+// target first
+func (s *Server) linkTests(ctx context.Context, project *storage.Project, targets []symbolRef) ([]*linkedTest, error) {
+	seen := make(map[string]bool)
+	var names []string
+	for _, t := range targets {
+		for _, name := range []string{t.symbol.Name, t.symbol.Receiver} {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	tests, err := s.storage.FindTests(ctx, project.ID, names)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := make(map[int64]map[string]bool)
+	importsOf := func(fileID int64) (map[string]bool, error) {
+		if paths, ok := imports[fileID]; ok {
+			return paths, nil
+		}
+		list, err := s.storage.ListImportsByFile(ctx, fileID)
+		if err != nil {
+			return nil, err
+		}
+		paths := make(map[string]bool, len(list))
+		for _, imp := range list {
+			paths[imp.ImportPath] = true
+		}
+		imports[fileID] = paths
+		return paths, nil
+	}
+
+	var linked []*linkedTest
+	for _, test := range tests {
+		var l *linkedTest
+		for _, target := range targets {
+			relations, err := testRelations(test, target, importsOf)
+			if err != nil {
+				return nil, err
+			}
+			if len(relations) == 0 {
+				continue
+			}
+			if l == nil {
+				l = &linkedTest{test: test, relations: make(map[string]bool)}
+				linked = append(linked, l)
+			}
+			for _, r := range relations {
+				l.relations[r] = true
+			}
+			l.symbols = append(l.symbols, target.qualifiedName())
+		}
+	}
+
+	sort.SliceStable(linked, func(i, j int) bool {
+		return linked[i].relations[relationName] && !linked[j].relations[relationName]
+	})
+	return linked, nil
+}
+
+// testRelations reports how a test is linked to a symbol. Tests are named after
+// symbols of their own package; calls are matched by name and package, and method
+// calls, whose receiver type is unknown, by name within the packages the test imports.
+func testRelations(test *storage.TestFunction, target symbolRef, importsOf func(int64) (map[string]bool, error)) ([]string, error) {
+	var relations []string
+	samePackage := filepath.Dir(test.FilePath) == filepath.Dir(target.file.FilePath)
+	sym := target.symbol
+	qualified := target.qualifiedName()
+
+	if samePackage && test.Target != "" {
+		first, _, _ := strings.Cut(test.Target, ".")
+		if test.Target == qualified || first == qualified || (sym.Receiver != "" && first == sym.Name) {
+			relations = append(relations, relationName)
+		}
+	}
+
+	isMethod := types.SymbolKind(sym.Kind) == types.KindMethod
+	for _, call := range test.Calls {
+		if call.Name != sym.Name || call.Method != isMethod {
+			continue
+		}
+		linked := samePackage && call.Package == ""
+		if !linked && target.file.ImportPath != "" {
+			if isMethod {
+				paths, err := importsOf(test.FileID)
+				if err != nil {
+					return nil, err
+				}
+				linked = paths[target.file.ImportPath]
+			} else {
+				linked = call.Package == target.file.ImportPath
+			}
+		}
+		if linked {
+			relations = append(relations, relationCall)
+			break
+		}
+	}
+	return relations, nil
+}
+
+// formatLinkedTests converts linked tests to response maps
+func formatLinkedTests(linked []*linkedTest) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(linked))
+	for i, l := range linked {
+		var relations []string
+		for _, r := range []string{relationName, relationCall} {
+			if l.relations[r] {
+				relations = append(relations, r)
+			}
+		}
+		result[i] = map[string]interface{}{
+			"name":      l.test.Name,
+			"kind":      l.test.Kind,
+			"file":      l.test.FilePath,
+			"line":      l.test.StartLine,
+			"end_line":  l.test.EndLine,
+			"package":   l.test.PackageName,
+			"relations": relations,
+			"symbols":   l.symbols,
+		}
+	}
+	return result
+}
+
+// testCommands builds one "go test" command per package directory running the tests,
+// relative to the project root
+func testCommands(linked []*linkedTest) []string {
+	type selection struct{ run, bench []string }
+	byDir := make(map[string]*selection)
+	var dirs []string
+	for _, l := range linked {
+		dir := filepath.ToSlash(filepath.Dir(l.test.FilePath))
+		sel, ok := byDir[dir]
+		if !ok {
+			sel = &selection{}
+			byDir[dir] = sel
+			dirs = append(dirs, dir)
+		}
+		if l.test.Kind == string(types.TestKindBenchmark) {
+			sel.bench = appendUnique(sel.bench, l.test.Name)
+		} else {
+			sel.run = appendUnique(sel.run, l.test.Name)
+		}
+	}
+	sort.Strings(dirs)
+
+	commands := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		pkg := "./" + dir
+		if dir == "." {
+			pkg = "."
+		}
+		sel := byDir[dir]
+		run := "^$"
+		if len(sel.run) > 0 {
+			run = "^(" + strings.Join(sel.run, "|") + ")$"
+		}
+		command := fmt.Sprintf("go test %s -run '%s'", pkg, run)
+		if len(sel.bench) > 0 {
+			command += fmt.Sprintf(" -bench '^(%s)$'", strings.Join(sel.bench, "|"))
+		}
+		commands = append(commands, command)
+	}
+	return commands
+}
+
+// appendUnique appends s unless already present
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
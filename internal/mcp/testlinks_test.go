@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleFindTests(t *testing.T) {
+	s := newTestServer(t)
+	dir := indexTestProject(t, s, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"store/store.go": `package store
+
+type Store struct{}
+
+func New() *Store { return &Store{} }
+
+func (s *Store) Load() error { return nil }
+
+func (s *Store) Save() error { return nil }
+`,
+		"store/store_test.go": `package store
+
+import "testing"
+
+func TestStore_Load(t *testing.T) {
+	_ = New().Load()
+}
+
+func BenchmarkSave(b *testing.B) {
+	s := New()
+	for i := 0; i < b.N; i++ {
+		_ = s.Save()
+	}
+}
+`,
+		"api/api_test.go": `package api
+
+import (
+	"testing"
+
+	"example.com/app/store"
+)
+
+func TestHandler(t *testing.T) {
+	_ = store.New().Load()
+}
+`,
+		"other/other_test.go": `package other
+
+import "testing"
+
+type cache struct{}
+
+func (c cache) Load() error { return nil }
+
+func TestCache(t *testing.T) {
+	_ = cache{}.Load()
+}
+`,
+	})
+
+	response := callTool(t, s.handleFindTests, map[string]interface{}{"path": dir, "symbol": "Store.Load"})
+	assert.Equal(t, float64(2), response["total"])
+	tests := response["tests"].([]interface{})
+	first := tests[0].(map[string]interface{})
+	assert.Equal(t, "TestStore_Load", first["name"])
+	assert.Equal(t, []interface{}{"name", "call"}, first["relations"])
+	second := tests[1].(map[string]interface{})
+	assert.Equal(t, "TestHandler", second["name"])
+	assert.Equal(t, []interface{}{"call"}, second["relations"])
+	assert.Equal(t, []interface{}{
+		"go test ./api -run '^(TestHandler)$'",
+		"go test ./store -run '^(TestStore_Load)$'",
+	}, response["commands"])
+
+	response = callTool(t, s.handleFindTests, map[string]interface{}{"path": dir, "file": "store/store.go"})
+	assert.Equal(t, float64(3), response["total"])
+	assert.Equal(t, []interface{}{
+		"go test ./api -run '^(TestHandler)$'",
+		"go test ./store -run '^(TestStore_Load)$' -bench '^(BenchmarkSave)$'",
+	}, response["commands"])
+
+	response = callTool(t, s.handleFindTests, map[string]interface{}{
+		"path": dir, "file": "store/store.go", "kinds": []interface{}{"benchmark"},
+	})
+	tests = response["tests"].([]interface{})
+	require.Len(t, tests, 1)
+	assert.Equal(t, []interface{}{"New", "Store.Save"}, tests[0].(map[string]interface{})["symbols"])
+
+	_, err := s.handleFindTests(context.Background(), callRequest(map[string]interface{}{"path": dir, "symbol": "Missing"}))
+	assert.Error(t, err)
+	_, err = s.handleFindTests(context.Background(), callRequest(map[string]interface{}{"path": dir}))
+	assert.Error(t, err)
+}
@@ -8,13 +8,19 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	projectconfig "github.com/dshills/gocontext-mcp/internal/config"
+	"github.com/dshills/gocontext-mcp/internal/gitrepo"
 	"github.com/dshills/gocontext-mcp/internal/indexer"
 	"github.com/dshills/gocontext-mcp/internal/searcher"
 	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
 )
 
 // MCP error codes
@@ -63,18 +69,44 @@ func (s *Server) handleIndexCodebase(ctx context.Context, request mcp.CallToolRe
 		})
 	}
 
+	// Load .gocontext.yaml; tool arguments override values from the file
+	project, err := loadProjectConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse optional parameters
 	forceReindex, _ := args["force_reindex"].(bool)
-	includeTests := getBoolDefault(args, "include_tests", true)
-	includeVendor := getBoolDefault(args, "include_vendor", false)
+	includeTests := getBoolDefault(args, "include_tests", project.IncludeTests(true))
+	includeVendor := getBoolDefault(args, "include_vendor", project.IncludeVendor(false))
+	ref := strings.TrimSpace(getStringDefault(args, "ref", ""))
+	gitHistory := getBoolDefault(args, "git_history", project.GitHistory(false))
+	dependencies := getStringDefault(args, "dependencies", project.DependencyMode(projectconfig.DependenciesNone))
+	switch dependencies {
+	case projectconfig.DependenciesNone, projectconfig.DependenciesDirect, projectconfig.DependenciesAll:
+	default:
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid dependencies", map[string]interface{}{
+			"param":  "dependencies",
+			"value":  dependencies,
+			"reason": "must be none, direct or all",
+		})
+	}
+	stdlib := getBoolDefault(args, "stdlib", project.IndexStdlib(false))
 
 	// Create indexer config
 	// Note: GenerateEmbeddings defaults to true for full semantic search capability
+	// (embeddings.enabled: false in .gocontext.yaml turns it off)
 	config := &indexer.Config{
 		IncludeTests:       includeTests,
 		IncludeVendor:      includeVendor,
 		GenerateEmbeddings: true, // Default: always generate embeddings for semantic search
 		ForceReindex:       forceReindex,
+		Project:            project,
+		Ref:                ref,
+		GitHistory:         gitHistory,
+		HistoryDepth:       project.Index.HistoryDepth,
+		Dependencies:       dependencies,
+		Stdlib:             stdlib,
 	}
 
 	// Run indexing
@@ -86,6 +118,13 @@ func (s *Server) handleIndexCodebase(ctx context.Context, request mcp.CallToolRe
 				"path": path,
 			})
 		}
+		if errors.Is(err, gitrepo.ErrNotRepository) || errors.Is(err, gitrepo.ErrRevisionNotFound) {
+			return nil, newMCPError(ErrorCodeInvalidParams, "invalid ref", map[string]interface{}{
+				"param":  "ref",
+				"value":  ref,
+				"reason": err.Error(),
+			})
+		}
 		return nil, newMCPError(ErrorCodeInternalError, "indexing failed", map[string]interface{}{
 			"error": err.Error(),
 		})
@@ -97,10 +136,29 @@ func (s *Server) handleIndexCodebase(ctx context.Context, request mcp.CallToolRe
 		"files_indexed":     stats.FilesIndexed,
 		"files_skipped":     stats.FilesSkipped,
 		"files_failed":      stats.FilesFailed,
+		"files_purged":      stats.FilesPurged,
 		"symbols_extracted": stats.SymbolsExtracted,
 		"chunks_created":    stats.ChunksCreated,
 		"duration_ms":       stats.Duration.Milliseconds(),
 	}
+	if ref != "" {
+		response["ref"] = ref
+		response["commit"] = stats.GitCommit
+	}
+	if dependencies != projectconfig.DependenciesNone && ref == "" {
+		response["dependencies_indexed"] = stats.DependenciesIndexed
+		response["dependencies_current"] = stats.DependenciesCurrent
+		if len(stats.DependenciesMissing) > 0 {
+			response["dependencies_missing"] = stats.DependenciesMissing
+		}
+	}
+	if stdlib && stats.StdlibVersion != "" {
+		response["stdlib"] = map[string]interface{}{
+			"version": stats.StdlibVersion,
+			"exact":   stats.StdlibExact,
+			"indexed": stats.StdlibIndexed,
+		}
+	}
 
 	if len(stats.ErrorMessages) > 0 {
 		// Include first few errors
@@ -127,42 +185,70 @@ func (s *Server) handleSearchCode(ctx context.Context, request mcp.CallToolReque
 	}
 
 	// Validate required parameters
-	path, query, err := validateSearchParams(args)
+	query, err := validateSearchQuery(args)
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if project is indexed
-	project, err := s.storage.GetProject(ctx, path)
-	if err == storage.ErrNotFound {
-		return nil, newMCPError(ErrorCodeNotIndexed, "project not indexed", map[string]interface{}{
-			"path":    path,
-			"message": "Run index_codebase tool first to index this project",
-		})
-	}
+	// Resolve the project(s) to search
+	projects, err := s.resolveSearchProjects(ctx, args)
 	if err != nil {
-		return nil, newMCPError(ErrorCodeInternalError, "failed to get project", map[string]interface{}{
-			"error": err.Error(),
-		})
+		return nil, err
+	}
+
+	// Defaults come from .gocontext.yaml when searching a single project
+	defaults := projectconfig.Default().Search
+	if len(projects) == 1 {
+		project, err := loadProjectConfig(projects[0].SourcePath())
+		if err != nil {
+			return nil, err
+		}
+		defaults = project.Search
+	}
+
+	// Add the selected projects' indexed dependencies and standard library
+	includeDeps := getBoolDefault(args, "include_dependencies", false)
+	includeStdlib := getBoolDefault(args, "include_stdlib", false)
+	if includeDeps || includeStdlib {
+		if projects, err = s.withDependencies(ctx, projects, includeDeps, includeStdlib); err != nil {
+			return nil, err
+		}
 	}
 
 	// Parse and validate optional parameters
-	limit, searchMode, searchFilters, err := parseSearchOptions(args)
+	opts, err := parseSearchOptions(args, defaults)
 	if err != nil {
 		return nil, err
 	}
 
+	if !s.searcher.HasReranker(opts.rerank) {
+		return nil, newMCPError(ErrorCodeInvalidParams, "reranker not available", map[string]interface{}{
+			"param":   "rerank",
+			"value":   opts.rerank,
+			"message": "cross_encoder requires GOCONTEXT_RERANK_URL or JINA_API_KEY to be set",
+		})
+	}
+
 	// Sanitize query for SQL FTS to prevent injection
 	sanitizedQuery := sanitizeQueryForFTS(query)
 
 	// Build search request
 	searchReq := searcher.SearchRequest{
 		Query:     sanitizedQuery,
-		Limit:     limit,
-		Mode:      searcher.SearchMode(searchMode),
-		Filters:   searchFilters,
-		ProjectID: project.ID,
+		Limit:     opts.limit,
+		Mode:      searcher.SearchMode(opts.mode),
+		Filters:   opts.filters,
+		ProjectID: projects[0].ID,
 		UseCache:  true, // Enable caching for performance
+		Rerank:    opts.rerank,
+		Highlight: opts.highlight,
+		SortBy:    opts.sortBy,
+	}
+	if len(projects) > 1 {
+		searchReq.ProjectIDs = make([]int64, len(projects))
+		for i, p := range projects {
+			searchReq.ProjectIDs[i] = p.ID
+		}
 	}
 
 	// Perform search
@@ -174,24 +260,16 @@ func (s *Server) handleSearchCode(ctx context.Context, request mcp.CallToolReque
 	}
 
 	// Format response
-	response := formatSearchResponse(query, searchResp)
+	response := formatSearchResponse(query, searchResp, opts.snippetMode)
 
 	return mcp.NewToolResultText(formatJSON(response)), nil
 }
 
-// validateSearchParams validates required search parameters (path and query)
-func validateSearchParams(args map[string]interface{}) (path string, query string, err error) {
-	path, ok := args["path"].(string)
-	if !ok || path == "" {
-		return "", "", newMCPError(ErrorCodeInvalidParams, "path parameter is required", map[string]interface{}{
-			"param":  "path",
-			"reason": "missing or empty",
-		})
-	}
-
-	query, ok = args["query"].(string)
+// validateSearchQuery validates the required query parameter
+func validateSearchQuery(args map[string]interface{}) (string, error) {
+	query, ok := args["query"].(string)
 	if !ok || query == "" {
-		return "", "", newMCPError(ErrorCodeEmptyQuery, "query parameter is required and cannot be empty", map[string]interface{}{
+		return "", newMCPError(ErrorCodeEmptyQuery, "query parameter is required and cannot be empty", map[string]interface{}{
 			"param":  "query",
 			"reason": "missing or empty",
 		})
@@ -200,53 +278,267 @@ func validateSearchParams(args map[string]interface{}) (path string, query strin
 	// Trim whitespace and check if query is empty
 	query = strings.TrimSpace(query)
 	if query == "" {
-		return "", "", newMCPError(ErrorCodeEmptyQuery, "query parameter cannot be whitespace-only", map[string]interface{}{
+		return "", newMCPError(ErrorCodeEmptyQuery, "query parameter cannot be whitespace-only", map[string]interface{}{
 			"param":  "query",
 			"reason": "empty after trimming whitespace",
 		})
 	}
 
-	// Validate path exists and is accessible
-	if err := validatePath(path); err != nil {
-		return "", "", newMCPError(ErrorCodeInvalidParams, "invalid path", map[string]interface{}{
+	return query, nil
+}
+
+// resolveSearchProjects returns the indexed projects selected by path, paths or all_projects
+func (s *Server) resolveSearchProjects(ctx context.Context, args map[string]interface{}) ([]*storage.Project, error) {
+	if getBoolDefault(args, "all_projects", false) {
+		projects, err := s.storage.ListProjects(ctx)
+		if err != nil {
+			return nil, newMCPError(ErrorCodeInternalError, "failed to list projects", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		// Dependencies and the standard library are searched through the projects using
+		// them; git revisions only when named, as they repeat their working tree's code
+		selected := projects[:0]
+		for _, p := range projects {
+			if !p.IsDependency() && !p.IsSnapshot() {
+				selected = append(selected, p)
+			}
+		}
+		projects = selected
+		if len(projects) == 0 {
+			return nil, newMCPError(ErrorCodeNotIndexed, "no indexed projects", map[string]interface{}{
+				"message": "Run index_codebase tool first to index a project",
+			})
+		}
+		return projects, nil
+	}
+
+	paths, err := searchPaths(args)
+	if err != nil {
+		return nil, err
+	}
+	ref := strings.TrimSpace(getStringDefault(args, "ref", ""))
+
+	projects := make([]*storage.Project, 0, len(paths))
+	for _, path := range paths {
+		// Validate path exists and is accessible
+		if err := validatePath(path); err != nil {
+			return nil, newMCPError(ErrorCodeInvalidParams, "invalid path", map[string]interface{}{
+				"param":  "path",
+				"path":   path,
+				"reason": err.Error(),
+			})
+		}
+
+		// Check if project (or its snapshot of ref) is indexed
+		project, err := s.getProject(ctx, path, ref)
+		if err == storage.ErrNotFound {
+			return nil, newMCPError(ErrorCodeNotIndexed, "project not indexed", notIndexedData(path, ref))
+		}
+		if err != nil {
+			return nil, newMCPError(ErrorCodeInternalError, "failed to get project", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
+
+// withDependencies appends the module dependencies and/or standard library linked to
+// each project, once each
+func (s *Server) withDependencies(ctx context.Context, projects []*storage.Project, modules, stdlib bool) ([]*storage.Project, error) {
+	seen := make(map[int64]bool, len(projects))
+	for _, p := range projects {
+		seen[p.ID] = true
+	}
+
+	result := projects
+	for _, p := range projects {
+		deps, err := s.storage.ListProjectDependencies(ctx, p.ID)
+		if err != nil {
+			return nil, newMCPError(ErrorCodeInternalError, "failed to list dependencies", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		for _, dep := range deps {
+			if dep.Project.IsStdlib() && !stdlib || !dep.Project.IsStdlib() && !modules {
+				continue
+			}
+			if !seen[dep.DependencyID] {
+				seen[dep.DependencyID] = true
+				result = append(result, dep.Project)
+			}
+		}
+	}
+	return result, nil
+}
+
+// searchPaths collects project paths from the path and paths parameters
+func searchPaths(args map[string]interface{}) ([]string, error) {
+	var paths []string
+	if path, ok := args["path"].(string); ok && path != "" {
+		paths = append(paths, path)
+	}
+
+	if raw, ok := args["paths"]; ok {
+		list, ok := raw.([]interface{})
+		if !ok {
+			return nil, newMCPError(ErrorCodeInvalidParams, "paths must be an array of strings", map[string]interface{}{
+				"param": "paths",
+			})
+		}
+		for _, item := range list {
+			path, ok := item.(string)
+			if !ok || path == "" {
+				return nil, newMCPError(ErrorCodeInvalidParams, "paths must contain non-empty strings", map[string]interface{}{
+					"param": "paths",
+					"value": item,
+				})
+			}
+			paths = append(paths, path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, newMCPError(ErrorCodeInvalidParams, "path parameter is required", map[string]interface{}{
 			"param":  "path",
-			"reason": err.Error(),
+			"reason": "provide path, paths or all_projects",
 		})
 	}
+	return paths, nil
+}
+
+// searchOptions holds the parsed optional search_code parameters
+type searchOptions struct {
+	limit       int
+	mode        string
+	filters     *storage.SearchFilters
+	rerank      string
+	highlight   bool
+	snippetMode string
+	sortBy      string // Metric name; empty for relevance
+}
+
+// Snippet modes for search_code results
+const (
+	snippetModeFull    = "full"    // Whole chunk content and context
+	snippetModeCompact = "compact" // Matched lines with line numbers only
+)
 
-	return path, query, nil
+// loadProjectConfig reads the project's .gocontext.yaml, returning defaults if there is none
+func loadProjectConfig(rootPath string) (*projectconfig.ProjectConfig, error) {
+	project, err := projectconfig.Load(rootPath)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid project configuration", map[string]interface{}{
+			"path":  rootPath,
+			"error": err.Error(),
+		})
+	}
+	return project, nil
 }
 
-// parseSearchOptions parses and validates optional search parameters
-func parseSearchOptions(args map[string]interface{}) (limit int, searchMode string, filters *storage.SearchFilters, err error) {
+// parseSearchOptions parses and validates optional search parameters.
+// defaults holds per-project values used when a parameter is absent.
+func parseSearchOptions(args map[string]interface{}, defaults projectconfig.SearchConfig) (*searchOptions, error) {
+	opts := &searchOptions{}
+
 	// Parse limit
-	limit = getIntDefault(args, "limit", 10)
-	if limit < 1 || limit > 100 {
-		return 0, "", nil, newMCPError(ErrorCodeInvalidParams, "limit must be between 1 and 100", map[string]interface{}{
+	opts.limit = getIntDefault(args, "limit", intOr(defaults.Limit, 10))
+	if opts.limit < 1 || opts.limit > 100 {
+		return nil, newMCPError(ErrorCodeInvalidParams, "limit must be between 1 and 100", map[string]interface{}{
 			"param": "limit",
-			"value": limit,
+			"value": opts.limit,
 		})
 	}
 
 	// Parse search mode
-	searchMode = getStringDefault(args, "search_mode", "hybrid")
-	if searchMode != "hybrid" && searchMode != "vector" && searchMode != "keyword" {
-		return 0, "", nil, newMCPError(ErrorCodeInvalidParams, "invalid search_mode", map[string]interface{}{
+	opts.mode = getStringDefault(args, "search_mode", stringOr(defaults.Mode, "hybrid"))
+	if opts.mode != "hybrid" && opts.mode != "vector" && opts.mode != "keyword" {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid search_mode", map[string]interface{}{
 			"param":   "search_mode",
-			"value":   searchMode,
+			"value":   opts.mode,
 			"allowed": []string{"hybrid", "vector", "keyword"},
 		})
 	}
 
+	// Parse reranker
+	opts.rerank = getStringDefault(args, "rerank", stringOr(defaults.Rerank, searcher.RerankNone))
+	if !isValidRerank(opts.rerank) {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid rerank", map[string]interface{}{
+			"param":   "rerank",
+			"value":   opts.rerank,
+			"allowed": []string{searcher.RerankNone, searcher.RerankLexical, searcher.RerankRecency, searcher.RerankCrossEncoder},
+		})
+	}
+
+	// Parse highlighting and snippet mode
+	// Off by default: vector hits without a term match embed their lines to locate them
+	opts.highlight = getBoolDefault(args, "highlight", defaults.Highlight != nil && *defaults.Highlight)
+	opts.snippetMode = getStringDefault(args, "snippet_mode", stringOr(defaults.SnippetMode, snippetModeFull))
+	if opts.snippetMode != snippetModeFull && opts.snippetMode != snippetModeCompact {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid snippet_mode", map[string]interface{}{
+			"param":   "snippet_mode",
+			"value":   opts.snippetMode,
+			"allowed": []string{snippetModeFull, snippetModeCompact},
+		})
+	}
+	// Compact snippets are built from line matches
+	if opts.snippetMode == snippetModeCompact {
+		opts.highlight = true
+	}
+
+	// Parse sort order
+	if sortBy := getStringDefault(args, "sort_by", "relevance"); sortBy != "relevance" {
+		if _, ok := (types.Metrics{}).Get(sortBy); !ok {
+			return nil, newMCPError(ErrorCodeInvalidParams, "invalid sort_by", map[string]interface{}{
+				"param":   "sort_by",
+				"value":   sortBy,
+				"allowed": append([]string{"relevance"}, types.MetricNames...),
+			})
+		}
+		opts.sortBy = sortBy
+	}
+
 	// Parse and validate filters
-	filters, err = parseSearchFilters(args)
+	filters, err := parseSearchFilters(args)
 	if err != nil {
-		return 0, "", nil, newMCPError(ErrorCodeInvalidParams, "invalid filters", map[string]interface{}{
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid filters", map[string]interface{}{
 			"error": err.Error(),
 		})
 	}
+	opts.filters = filters
+
+	// Apply the project's min_relevance unless the request sets one
+	if defaults.MinRelevance > 0 && !hasFilter(args, "min_relevance") {
+		if opts.filters == nil {
+			opts.filters = &storage.SearchFilters{}
+		}
+		opts.filters.MinRelevance = defaults.MinRelevance
+	}
 
-	return limit, searchMode, filters, nil
+	return opts, nil
+}
+
+// hasFilter reports whether the filters argument contains key
+func hasFilter(args map[string]interface{}, key string) bool {
+	filtersArg, ok := args["filters"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = filtersArg[key]
+	return ok
+}
+
+// isValidRerank checks if a reranker name is valid
+func isValidRerank(name string) bool {
+	switch name {
+	case searcher.RerankNone, searcher.RerankLexical, searcher.RerankRecency, searcher.RerankCrossEncoder:
+		return true
+	default:
+		return false
+	}
 }
 
 // handleGetStatus handles the get_status tool invocation
@@ -273,8 +565,9 @@ func (s *Server) handleGetStatus(ctx context.Context, request mcp.CallToolReques
 		})
 	}
 
-	// Try to get project
-	project, err := s.storage.GetProject(ctx, path)
+	// Try to get project, or its snapshot of a git revision
+	ref := strings.TrimSpace(getStringDefault(args, "ref", ""))
+	project, err := s.getProject(ctx, path, ref)
 	if err == storage.ErrNotFound {
 		// Project not indexed
 		response := map[string]interface{}{
@@ -282,6 +575,10 @@ func (s *Server) handleGetStatus(ctx context.Context, request mcp.CallToolReques
 			"path":    path,
 			"message": "Project not indexed. Use index_codebase tool to index this project.",
 		}
+		if ref != "" {
+			response["ref"] = ref
+			response["message"] = "Revision not indexed. Use index_codebase tool with ref to index it."
+		}
 		return mcp.NewToolResultText(formatJSON(response)), nil
 	}
 	if err != nil {
@@ -298,15 +595,48 @@ func (s *Server) handleGetStatus(ctx context.Context, request mcp.CallToolReques
 		})
 	}
 
+	modules, err := s.storage.ListModules(ctx, project.ID)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to list modules", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	snapshots, err := s.listSnapshots(ctx, path)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to list snapshots", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	dependencies, err := s.storage.ListProjectDependencies(ctx, project.ID)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to list dependencies", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	projectInfo := map[string]interface{}{
+		"path":            project.RootPath,
+		"module_name":     project.ModuleName,
+		"go_version":      project.GoVersion,
+		"last_indexed_at": project.LastIndexedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if project.IsSnapshot() {
+		projectInfo["ref"] = project.GitRef
+		projectInfo["commit"] = project.GitCommit
+	}
+	if project.IsDependency() {
+		projectInfo["module_version"] = project.ModuleVersion
+	}
+
 	// Format response
 	response := map[string]interface{}{
-		"indexed": true,
-		"project": map[string]interface{}{
-			"path":            project.RootPath,
-			"module_name":     project.ModuleName,
-			"go_version":      project.GoVersion,
-			"last_indexed_at": project.LastIndexedAt.Format("2006-01-02T15:04:05Z07:00"),
-		},
+		"indexed":      true,
+		"modules":      formatModules(modules),
+		"snapshots":    snapshots,
+		"dependencies": formatDependencies(dependencies),
+		"project":      projectInfo,
 		"statistics": map[string]interface{}{
 			"files_count":      status.FilesCount,
 			"symbols_count":    status.SymbolsCount,
@@ -320,12 +650,165 @@ func (s *Server) handleGetStatus(ctx context.Context, request mcp.CallToolReques
 			"fts_indexes_built":    status.Health.FTSIndexesBuilt,
 		},
 	}
+	if stdlib := formatStdlib(dependencies); stdlib != nil {
+		response["stdlib"] = stdlib
+	}
 
 	return mcp.NewToolResultText(formatJSON(response)), nil
 }
 
 // Helper functions
 
+// getProject returns the project indexed from path, or its snapshot of a git revision
+// when ref is set
+func (s *Server) getProject(ctx context.Context, path, ref string) (*storage.Project, error) {
+	if ref != "" {
+		path = storage.SnapshotRootPath(path, ref)
+	}
+	return s.storage.GetProject(ctx, path)
+}
+
+// indexedProject resolves the required path argument, and the optional ref, to an
+// indexed project. The error is an MCP error ready to return from a handler.
+func (s *Server) indexedProject(ctx context.Context, args map[string]interface{}) (*storage.Project, error) {
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return nil, newMCPError(ErrorCodeInvalidParams, "path parameter is required", map[string]interface{}{
+			"param":  "path",
+			"reason": "missing or empty",
+		})
+	}
+	if err := validatePath(path); err != nil {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid path", map[string]interface{}{
+			"param":  "path",
+			"reason": err.Error(),
+		})
+	}
+
+	ref := strings.TrimSpace(getStringDefault(args, "ref", ""))
+	project, err := s.getProject(ctx, path, ref)
+	if err == storage.ErrNotFound {
+		return nil, newMCPError(ErrorCodeNotIndexed, "project not indexed", notIndexedData(path, ref))
+	}
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to get project", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	return project, nil
+}
+
+// getStringSlice returns the string elements of an array argument
+func getStringSlice(args map[string]interface{}, key string) []string {
+	items, ok := args[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// listSnapshots describes the git revisions indexed for the repository at path
+func (s *Server) listSnapshots(ctx context.Context, path string) ([]map[string]interface{}, error) {
+	projects, err := s.storage.ListProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]map[string]interface{}, 0)
+	for _, p := range projects {
+		if !p.IsSnapshot() || p.SourcePath() != path {
+			continue
+		}
+		snapshots = append(snapshots, map[string]interface{}{
+			"ref":             p.GitRef,
+			"commit":          p.GitCommit,
+			"files_count":     p.TotalFiles,
+			"last_indexed_at": p.LastIndexedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+	return snapshots, nil
+}
+
+// notIndexedData is the error data for a project, or revision, that has not been indexed
+func notIndexedData(path, ref string) map[string]interface{} {
+	if ref == "" {
+		return map[string]interface{}{
+			"path":    path,
+			"message": "Run index_codebase tool first to index this project",
+		}
+	}
+	return map[string]interface{}{
+		"path":    path,
+		"ref":     ref,
+		"message": "Run index_codebase tool with this ref first to index the revision",
+	}
+}
+
+// formatModules converts a project's modules to response maps
+func formatModules(modules []*storage.Module) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(modules))
+	for i, mod := range modules {
+		dir := mod.Dir
+		if dir == "" {
+			dir = "."
+		}
+		direct := 0
+		for _, dep := range mod.Dependencies {
+			if !dep.Indirect {
+				direct++
+			}
+		}
+		result[i] = map[string]interface{}{
+			"module_path":         mod.ModulePath,
+			"dir":                 dir,
+			"go_version":          mod.GoVersion,
+			"in_workspace":        mod.InWorkspace,
+			"files_count":         mod.FilesCount,
+			"direct_dependencies": direct,
+			"total_dependencies":  len(mod.Dependencies),
+		}
+	}
+	return result
+}
+
+// formatDependencies converts a project's indexed module dependencies to response maps
+func formatDependencies(deps []*storage.ProjectDependency) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(deps))
+	for _, dep := range deps {
+		if dep.Project.IsStdlib() {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"module":      dep.Project.ModuleName,
+			"version":     dep.Project.ModuleVersion,
+			"direct":      dep.Direct,
+			"path":        dep.Project.RootPath,
+			"files_count": dep.Project.TotalFiles,
+		})
+	}
+	return result
+}
+
+// formatStdlib describes the standard library linked to a project, or returns nil
+func formatStdlib(deps []*storage.ProjectDependency) map[string]interface{} {
+	for _, dep := range deps {
+		if dep.Project.IsStdlib() {
+			return map[string]interface{}{
+				"version":     dep.Project.ModuleVersion,
+				"path":        dep.Project.RootPath,
+				"files_count": dep.Project.TotalFiles,
+			}
+		}
+	}
+	return nil
+}
+
 // newMCPError creates a properly formatted MCP error
 func newMCPError(code int, message string, data interface{}) error {
 	// MCP errors are returned as regular errors, the framework handles encoding
@@ -427,6 +910,22 @@ func getIntDefault(args map[string]interface{}, key string, defaultValue int) in
 	return defaultValue
 }
 
+// intOr returns value, or def when value is zero
+func intOr(value, def int) int {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+// stringOr returns value, or def when value is empty
+func stringOr(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
 // getStringDefault extracts a string parameter with a default value
 func getStringDefault(args map[string]interface{}, key string, defaultValue string) string {
 	if val, ok := args[key].(string); ok {
@@ -477,6 +976,19 @@ func parseSearchFilters(args map[string]interface{}) (*storage.SearchFilters, er
 		}
 	}
 
+	// Parse generic and constraints
+	if generic, ok := filtersArg["generic"].(bool); ok {
+		filters.Generic = generic
+	}
+	if constraints, ok := filtersArg["constraints"].([]interface{}); ok {
+		filters.Constraints = make([]string, 0, len(constraints))
+		for _, c := range constraints {
+			if s, ok := c.(string); ok && s != "" {
+				filters.Constraints = append(filters.Constraints, s)
+			}
+		}
+	}
+
 	// Parse packages
 	if packages, ok := filtersArg["packages"].([]interface{}); ok {
 		filters.Packages = make([]string, 0, len(packages))
@@ -495,9 +1007,121 @@ func parseSearchFilters(args map[string]interface{}) (*storage.SearchFilters, er
 		filters.MinRelevance = minRel
 	}
 
+	// Parse modules
+	if modules, ok := filtersArg["modules"].([]interface{}); ok {
+		filters.Modules = make([]string, 0, len(modules))
+		for _, mod := range modules {
+			if s, ok := mod.(string); ok {
+				filters.Modules = append(filters.Modules, s)
+			}
+		}
+	}
+
+	// Parse build_context
+	if buildArg, ok := filtersArg["build_context"].(map[string]interface{}); ok {
+		buildCtx, err := parseBuildContext(buildArg)
+		if err != nil {
+			return nil, err
+		}
+		filters.BuildContext = buildCtx
+	}
+
+	// Parse modified_since
+	if since, ok := filtersArg["modified_since"].(string); ok && since != "" {
+		t, err := parseModifiedSince(since, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		filters.ModifiedSince = t
+	}
+
+	// Parse min_metrics
+	if metricsArg, ok := filtersArg["min_metrics"].(map[string]interface{}); ok {
+		minimums, err := parseMinMetrics(metricsArg)
+		if err != nil {
+			return nil, err
+		}
+		filters.MinMetrics = minimums
+	}
+
 	return filters, nil
 }
 
+// parseMinMetrics parses minimum metrics keyed by metric name
+func parseMinMetrics(arg map[string]interface{}) (types.Metrics, error) {
+	var m types.Metrics
+	for name, value := range arg {
+		n, ok := value.(float64)
+		if !ok || n < 0 || n != float64(int(n)) {
+			return m, fmt.Errorf("min_metrics.%s must be a non-negative integer", name)
+		}
+		switch name {
+		case types.MetricCyclomatic:
+			m.Cyclomatic = int(n)
+		case types.MetricCognitive:
+			m.Cognitive = int(n)
+		case types.MetricLines:
+			m.Lines = int(n)
+		case types.MetricParams:
+			m.Params = int(n)
+		case types.MetricNesting:
+			m.Nesting = int(n)
+		case types.MetricReturns:
+			m.Returns = int(n)
+		default:
+			return m, fmt.Errorf("unknown metric in min_metrics: %s", name)
+		}
+	}
+	return m, nil
+}
+
+// parseModifiedSince parses an RFC 3339 timestamp, a YYYY-MM-DD date (UTC) or an age
+// relative to now such as "48h", "7d" or "2w"
+func parseModifiedSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+
+	if n := len(value); n > 1 && (value[n-1] == 'd' || value[n-1] == 'w') {
+		if count, err := strconv.Atoi(value[:n-1]); err == nil && count >= 0 {
+			if value[n-1] == 'w' {
+				count *= 7
+			}
+			return now.AddDate(0, 0, -count), nil
+		}
+	}
+	if age, err := time.ParseDuration(value); err == nil && age >= 0 {
+		return now.Add(-age), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid modified_since %q: use RFC 3339, YYYY-MM-DD or an age like 7d", value)
+}
+
+// parseBuildContext parses a build_context filter. GOOS and GOARCH default to the server's platform.
+func parseBuildContext(arg map[string]interface{}) (*types.BuildContext, error) {
+	buildCtx := &types.BuildContext{
+		GOOS:   getStringDefault(arg, "goos", runtime.GOOS),
+		GOARCH: getStringDefault(arg, "goarch", runtime.GOARCH),
+	}
+	if !types.KnownOS[buildCtx.GOOS] {
+		return nil, fmt.Errorf("invalid build_context.goos: %s", buildCtx.GOOS)
+	}
+	if !types.KnownArch[buildCtx.GOARCH] {
+		return nil, fmt.Errorf("invalid build_context.goarch: %s", buildCtx.GOARCH)
+	}
+
+	if tags, ok := arg["tags"].([]interface{}); ok {
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok && s != "" {
+				buildCtx.Tags = append(buildCtx.Tags, s)
+			}
+		}
+	}
+	return buildCtx, nil
+}
+
 // isValidSymbolType checks if a symbol type is valid
 func isValidSymbolType(st string) bool {
 	validTypes := map[string]bool{
@@ -541,47 +1165,156 @@ func sanitizeQueryForFTS(query string) string {
 }
 
 // formatSearchResponse formats a searcher.SearchResponse into the MCP response format
-func formatSearchResponse(query string, resp *searcher.SearchResponse) map[string]interface{} {
+func formatSearchResponse(query string, resp *searcher.SearchResponse, snippetMode string) map[string]interface{} {
 	results := make([]map[string]interface{}, len(resp.Results))
 
 	for i, result := range resp.Results {
+		fileMap := map[string]interface{}{
+			"path":       result.File.Path,
+			"package":    result.File.Package,
+			"start_line": result.File.StartLine,
+			"end_line":   result.File.EndLine,
+		}
+		if result.File.BuildConstraint != "" {
+			fileMap["build_constraint"] = result.File.BuildConstraint
+		}
+		if result.File.Module != "" {
+			fileMap["module"] = result.File.Module
+		}
+
 		resultMap := map[string]interface{}{
 			"chunk_id":        result.ChunkID,
 			"rank":            result.Rank,
 			"relevance_score": result.RelevanceScore,
-			"file": map[string]interface{}{
-				"path":       result.File.Path,
-				"package":    result.File.Package,
-				"start_line": result.File.StartLine,
-				"end_line":   result.File.EndLine,
-			},
-			"content": result.Content,
-			"context": result.Context,
+			"file":            fileMap,
+		}
+
+		if result.ProjectRoot != "" {
+			resultMap["project"] = result.ProjectRoot
+		}
+		if result.Dependency != "" {
+			resultMap["dependency"] = result.Dependency
+		}
+
+		// Compact mode replaces the full chunk with matched lines, falling back
+		// to full content when no line could be located
+		compact := snippetMode == snippetModeCompact && result.Snippet != ""
+		if compact {
+			resultMap["snippet"] = result.Snippet
+		} else {
+			resultMap["content"] = result.Content
+			resultMap["context"] = result.Context
+		}
+
+		// Include hit locations if present; the snippet already shows their text
+		if len(result.Matches) > 0 {
+			matches := make([]map[string]interface{}, len(result.Matches))
+			for j, m := range result.Matches {
+				match := map[string]interface{}{
+					"line":  m.Line,
+					"score": m.Score,
+				}
+				if !compact {
+					match["text"] = m.Text
+				}
+				if len(m.Terms) > 0 {
+					match["terms"] = m.Terms
+				}
+				matches[j] = match
+			}
+			resultMap["matches"] = matches
+		}
+		if len(result.MatchedTerms) > 0 {
+			resultMap["matched_terms"] = result.MatchedTerms
+		}
+
+		// Include git history if the project was indexed with it
+		if h := result.History; h != nil {
+			history := map[string]interface{}{
+				"change_count": h.ChangeCount,
+				"uncommitted":  h.Uncommitted(),
+			}
+			if h.LastCommit != "" {
+				history["commit"] = h.LastCommit
+				history["author"] = h.LastAuthor
+			}
+			if !h.LastModified.IsZero() {
+				history["date"] = h.LastModified.Format(time.RFC3339)
+			}
+			resultMap["history"] = history
 		}
 
 		// Include symbol if present
 		if result.Symbol != nil {
-			resultMap["symbol"] = map[string]interface{}{
+			symbol := map[string]interface{}{
 				"name":        result.Symbol.Name,
 				"kind":        result.Symbol.Kind,
 				"package":     result.Symbol.Package,
 				"signature":   result.Symbol.Signature,
 				"doc_comment": result.Symbol.DocComment,
 			}
+			if result.Symbol.IsGeneric() {
+				symbol["type_params"] = formatTypeParams(result.Symbol.TypeParams)
+			}
+			if len(result.Symbol.DDD) > 0 {
+				symbol["ddd"] = formatDDDMatches(result.Symbol.DDD)
+			}
+			if result.Symbol.Metrics.Cyclomatic > 0 {
+				symbol["metrics"] = formatMetrics(result.Symbol.Metrics)
+			}
+			resultMap["symbol"] = symbol
 		}
 
 		results[i] = resultMap
 	}
 
+	statistics := map[string]interface{}{
+		"total_results":      resp.TotalResults,
+		"returned_results":   len(resp.Results),
+		"search_duration_ms": resp.Duration.Milliseconds(),
+		"cache_hit":          resp.CacheHit,
+	}
+	if resp.Reranker != "" {
+		statistics["reranker"] = resp.Reranker
+	}
+	if resp.Projects > 1 {
+		statistics["projects_searched"] = resp.Projects
+	}
+
 	return map[string]interface{}{
-		"results": results,
-		"statistics": map[string]interface{}{
-			"total_results":      resp.TotalResults,
-			"returned_results":   len(resp.Results),
-			"search_duration_ms": resp.Duration.Milliseconds(),
-			"cache_hit":          resp.CacheHit,
-		},
+		"results":    results,
+		"statistics": statistics,
+	}
+}
+
+// formatMetrics reports the complexity metrics of a function or method
+func formatMetrics(m types.Metrics) map[string]interface{} {
+	return map[string]interface{}{
+		types.MetricCyclomatic: m.Cyclomatic,
+		types.MetricCognitive:  m.Cognitive,
+		types.MetricLines:      m.Lines,
+		types.MetricParams:     m.Params,
+		types.MetricNesting:    m.Nesting,
+		types.MetricReturns:    m.Returns,
+	}
+}
+
+// formatTypeParams lists type parameters with their constraints
+func formatTypeParams(params []types.TypeParam) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(params))
+	for i, p := range params {
+		out[i] = map[string]interface{}{"name": p.Name, "constraint": p.Constraint}
+	}
+	return out
+}
+
+// formatDDDMatches lists detected DDD patterns with their confidence and reason
+func formatDDDMatches(matches []types.DDDMatch) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(matches))
+	for i, m := range matches {
+		out[i] = map[string]interface{}{"pattern": m.Pattern, "confidence": m.Confidence, "reason": m.Reason}
 	}
+	return out
 }
 
 // Validation helpers
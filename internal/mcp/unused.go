@@ -0,0 +1,204 @@
+package mcp
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/dshills/gocontext-mcp/internal/deadcode"
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// Limits for find_unused
+const (
+	defaultUnusedLimit = 200
+	maxUnusedLimit     = 5000
+)
+
+// confidenceRanks orders confidence levels for min_confidence
+var confidenceRanks = map[string]int{
+	deadcode.ConfidenceLow:    0,
+	deadcode.ConfidenceMedium: 1,
+	deadcode.ConfidenceHigh:   2,
+}
+
+// handleFindUnused handles the find_unused tool invocation
+func (s *Server) handleFindUnused(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid arguments", nil)
+	}
+
+	project, err := s.indexedProject(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	minConfidence := getStringDefault(args, "min_confidence", deadcode.ConfidenceLow)
+	minRank, ok := confidenceRanks[minConfidence]
+	if !ok {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid min_confidence", map[string]interface{}{
+			"param":   "min_confidence",
+			"value":   minConfidence,
+			"allowed": []string{deadcode.ConfidenceLow, deadcode.ConfidenceMedium, deadcode.ConfidenceHigh},
+		})
+	}
+
+	limit := getIntDefault(args, "limit", defaultUnusedLimit)
+	if limit < 1 || limit > maxUnusedLimit {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid limit", map[string]interface{}{
+			"param":  "limit",
+			"value":  limit,
+			"reason": "must be between 1 and 5000",
+		})
+	}
+
+	symbols, declared, err := s.loadDeadcodeSymbols(ctx, project)
+	if err != nil {
+		return nil, newMCPError(ErrorCodeInternalError, "failed to load symbols", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	findings := deadcode.Find(symbols, deadcode.Options{
+		IncludeTests: getBoolDefault(args, "include_tests", false),
+	})
+
+	// Analysis covers the project; the filters narrow the report
+	packages := getStringSlice(args, "packages")
+	byConfidence := map[string]int{
+		deadcode.ConfidenceHigh:   0,
+		deadcode.ConfidenceMedium: 0,
+		deadcode.ConfidenceLow:    0,
+	}
+	var matched []deadcode.Finding
+	for _, f := range findings {
+		if confidenceRanks[f.Confidence] >= minRank && matchesPackage(f.Symbol, packages) {
+			matched = append(matched, f)
+			byConfidence[f.Confidence]++
+		}
+	}
+	total := len(matched)
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	groups := groupFindings(matched, declared)
+
+	response := map[string]interface{}{
+		"packages":      groups,
+		"total":         total,
+		"returned":      len(matched),
+		"by_confidence": byConfidence,
+	}
+	return mcp.NewToolResultText(formatJSON(response)), nil
+}
+
+// loadDeadcodeSymbols returns the project's symbols with their references, and
+// the stored symbols by ID
+func (s *Server) loadDeadcodeSymbols(ctx context.Context, project *storage.Project) ([]*deadcode.Symbol, map[int64]*storage.Symbol, error) {
+	files, err := s.storage.ListFiles(ctx, project.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs, err := s.storage.ListSymbolRefs(ctx, project.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	bySymbol := make(map[int64][]types.Ref)
+	for _, ref := range refs {
+		bySymbol[ref.SymbolID] = append(bySymbol[ref.SymbolID], ref.Ref)
+	}
+
+	var symbols []*deadcode.Symbol
+	declared := make(map[int64]*storage.Symbol)
+	for _, file := range files {
+		stored, err := s.storage.ListSymbolsByFile(ctx, file.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pkg := file.ImportPath
+		if pkg == "" {
+			pkg = path.Dir(filepath.ToSlash(file.FilePath))
+		}
+		for _, sym := range stored {
+			declared[sym.ID] = sym
+			symbols = append(symbols, &deadcode.Symbol{
+				ID:          sym.ID,
+				Name:        sym.Name,
+				Kind:        types.SymbolKind(sym.Kind),
+				Receiver:    sym.Receiver,
+				Package:     pkg,
+				PackageName: file.PackageName,
+				File:        file.FilePath,
+				Line:        sym.StartLine,
+				EndLine:     sym.EndLine,
+				Test:        strings.HasSuffix(file.FilePath, "_test.go"),
+				Tagged:      sym.Kind == string(types.KindField) && strings.Contains(sym.Signature, "`"),
+				Refs:        bySymbol[sym.ID],
+			})
+		}
+	}
+	return symbols, declared, nil
+}
+
+// matchesPackage reports whether sym is in one of the packages, given by name
+// or import path; no packages match everything
+func matchesPackage(sym *deadcode.Symbol, packages []string) bool {
+	if len(packages) == 0 {
+		return true
+	}
+	for _, pkg := range packages {
+		if pkg == sym.PackageName || pkg == sym.Package {
+			return true
+		}
+	}
+	return false
+}
+
+// groupFindings groups unused declarations by package, keeping their order
+func groupFindings(findings []deadcode.Finding, declared map[int64]*storage.Symbol) []map[string]interface{} {
+	groups := []map[string]interface{}{}
+	var unused []map[string]interface{}
+	for i, f := range findings {
+		unused = append(unused, formatFinding(f, declared[f.Symbol.ID]))
+		if i+1 < len(findings) && findings[i+1].Symbol.Package == f.Symbol.Package {
+			continue
+		}
+		groups = append(groups, map[string]interface{}{
+			"package":     f.Symbol.PackageName,
+			"import_path": f.Symbol.Package,
+			"unused":      unused,
+		})
+		unused = nil
+	}
+	return groups
+}
+
+// formatFinding converts an unused declaration to a response map
+func formatFinding(f deadcode.Finding, sym *storage.Symbol) map[string]interface{} {
+	m := map[string]interface{}{
+		"name":       f.Symbol.Name,
+		"kind":       f.Symbol.Kind,
+		"file":       f.Symbol.File,
+		"line":       f.Symbol.Line,
+		"confidence": f.Confidence,
+		"reason":     f.Reason,
+	}
+	if f.Symbol.Receiver != "" {
+		m["receiver"] = f.Symbol.Receiver
+	}
+	if sym != nil {
+		m["signature"] = sym.Signature
+	}
+	if f.TestOnly {
+		m["test_only"] = true
+	}
+	if f.Members > 0 {
+		m["members"] = f.Members
+	}
+	return m
+}
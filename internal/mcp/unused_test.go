@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleFindUnused(t *testing.T) {
+	s := newTestServer(t)
+	dir := indexTestProject(t, s, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.22\n",
+		"main.go": "package main\n\n" +
+			"import \"example.com/shop/internal/store\"\n\n" +
+			"func main() {\n\ts := store.Open()\n\t_ = s.Get(\"a\")\n}\n\n" +
+			"func init() {}\n",
+		"internal/store/store.go": "package store\n\n" +
+			"const defaultSize = 16\n\n" +
+			"const maxSize = 1024\n\n" +
+			"type Store struct {\n\titems map[string]string\n\tName string `json:\"name\"`\n}\n\n" +
+			"func Open() *Store {\n\treturn &Store{items: make(map[string]string, defaultSize)}\n}\n\n" +
+			"func (s *Store) Get(key string) string {\n\treturn s.items[key]\n}\n\n" +
+			"func (s *Store) String() string {\n\treturn \"store\"\n}\n\n" +
+			"func (s *Store) reset() {}\n\n" +
+			"func fixture() *Store {\n\treturn Open()\n}\n",
+		"internal/store/store_test.go": "package store\n\n" +
+			"import \"testing\"\n\n" +
+			"func TestStore(t *testing.T) {\n\t_ = fixture()\n}\n",
+	})
+
+	response := callTool(t, s.handleFindUnused, map[string]interface{}{"path": dir})
+	assert.Equal(t, float64(5), response["total"])
+	assert.Equal(t, map[string]interface{}{"high": float64(2), "medium": float64(1), "low": float64(2)}, response["by_confidence"])
+	packages := response["packages"].([]interface{})
+	require.Len(t, packages, 1)
+	group := packages[0].(map[string]interface{})
+	assert.Equal(t, "store", group["package"])
+	assert.Equal(t, "example.com/shop/internal/store", group["import_path"])
+
+	found := make(map[string]map[string]interface{})
+	for _, u := range group["unused"].([]interface{}) {
+		m := u.(map[string]interface{})
+		found[m["name"].(string)] = m
+	}
+	assert.Equal(t, "high", found["maxSize"]["confidence"])
+	assert.Equal(t, "internal/store/store.go", found["maxSize"]["file"])
+	assert.Equal(t, "high", found["reset"]["confidence"])
+	assert.Equal(t, "Store", found["reset"]["receiver"])
+	assert.Equal(t, "low", found["String"]["confidence"])
+	assert.Equal(t, "low", found["Name"]["confidence"])
+	assert.Equal(t, true, found["fixture"]["test_only"])
+
+	// Filtered by confidence and package
+	response = callTool(t, s.handleFindUnused, map[string]interface{}{"path": dir, "min_confidence": "high", "limit": float64(1)})
+	assert.Equal(t, float64(2), response["total"])
+	assert.Equal(t, float64(1), response["returned"])
+	response = callTool(t, s.handleFindUnused, map[string]interface{}{"path": dir, "packages": []interface{}{"main"}})
+	assert.Equal(t, float64(0), response["total"])
+	assert.Empty(t, response["packages"])
+
+	for _, args := range []map[string]interface{}{{"min_confidence": "certain"}, {"limit": float64(0)}} {
+		args["path"] = dir
+		_, err := s.handleFindUnused(context.Background(), callRequest(args))
+		var mcpErr *MCPError
+		require.ErrorAs(t, err, &mcpErr, args)
+		assert.Equal(t, ErrorCodeInvalidParams, mcpErr.Code, args)
+	}
+}
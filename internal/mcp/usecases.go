@@ -0,0 +1,238 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/dshills/gocontext-mcp/internal/cqrs"
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// useCaseTracer follows relations from a command or query, caching the symbols
+// and files it loads
+type useCaseTracer struct {
+	s       *Server
+	symbols map[int64]*storage.Symbol
+	files   map[int64]*storage.File
+}
+
+// handleTraceUseCase handles the trace_use_case tool invocation
+func (s *Server) handleTraceUseCase(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return nil, newMCPError(ErrorCodeInvalidParams, "invalid arguments", nil)
+	}
+
+	project, err := s.indexedProject(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol := strings.TrimSpace(getStringDefault(args, "symbol", ""))
+	if symbol == "" {
+		return nil, newMCPError(ErrorCodeInvalidParams, "symbol is required", map[string]interface{}{
+			"param": "symbol",
+		})
+	}
+	found, err := s.resolveSymbol(ctx, project, symbol, getStringDefault(args, "package", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	// Trace types only, and only the commands and queries among them if any
+	var targets, messages []symbolRef
+	for _, ref := range found {
+		switch types.SymbolKind(ref.symbol.Kind) {
+		case types.KindStruct, types.KindInterface, types.KindType:
+			targets = append(targets, ref)
+			if ref.symbol.IsCommand || ref.symbol.IsQuery {
+				messages = append(messages, ref)
+			}
+		}
+	}
+	if len(messages) > 0 {
+		targets = messages
+	}
+	if len(targets) == 0 {
+		return nil, newMCPError(ErrorCodeInvalidParams, "not a type", map[string]interface{}{
+			"param":  "symbol",
+			"value":  symbol,
+			"reason": "trace a command or query type",
+		})
+	}
+
+	tracer := &useCaseTracer{
+		s:       s,
+		symbols: make(map[int64]*storage.Symbol),
+		files:   make(map[int64]*storage.File),
+	}
+	useCases := make([]map[string]interface{}, 0, len(targets))
+	for _, target := range targets {
+		tracer.files[target.file.ID] = target.file
+		useCase, err := tracer.trace(ctx, target.symbol)
+		if err != nil {
+			return nil, newMCPError(ErrorCodeInternalError, "failed to trace use case", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		useCases = append(useCases, useCase)
+	}
+
+	response := map[string]interface{}{
+		"use_cases": useCases,
+		"total":     len(useCases),
+	}
+	return mcp.NewToolResultText(formatJSON(response)), nil
+}
+
+// trace returns a command or query with its handlers, their repositories and
+// aggregates, and the flows through them
+func (t *useCaseTracer) trace(ctx context.Context, message *storage.Symbol) (map[string]interface{}, error) {
+	useCase, err := t.describe(ctx, message, "")
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case message.IsCommand:
+		useCase["type"] = "command"
+	case message.IsQuery:
+		useCase["type"] = "query"
+	}
+
+	handlers := []map[string]interface{}{}
+	flows := []string{}
+	handledBy, err := t.related(ctx, message.ID, cqrs.RelationHandledBy)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range handledBy {
+		handler, err := t.describe(ctx, h.symbol, h.reason)
+		if err != nil {
+			return nil, err
+		}
+		handlerFlow := message.Name + " → " + h.symbol.Name
+
+		repositories := []map[string]interface{}{}
+		uses, err := t.related(ctx, h.symbol.ID, cqrs.RelationUsesRepository)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range uses {
+			repository, err := t.describe(ctx, r.symbol, r.reason)
+			if err != nil {
+				return nil, err
+			}
+			persists, err := t.related(ctx, r.symbol.ID, cqrs.RelationPersists)
+			if err != nil {
+				return nil, err
+			}
+			if repository["aggregates"], err = t.describeAll(ctx, persists); err != nil {
+				return nil, err
+			}
+			repositories = append(repositories, repository)
+
+			if len(persists) == 0 {
+				flows = append(flows, handlerFlow+" → "+r.symbol.Name)
+			}
+			for _, a := range persists {
+				flows = append(flows, handlerFlow+" → "+r.symbol.Name+" → "+a.symbol.Name)
+			}
+		}
+		handler["repositories"] = repositories
+
+		aggregates, err := t.related(ctx, h.symbol.ID, cqrs.RelationUsesAggregate)
+		if err != nil {
+			return nil, err
+		}
+		if handler["aggregates"], err = t.describeAll(ctx, aggregates); err != nil {
+			return nil, err
+		}
+		if len(uses) == 0 {
+			if len(aggregates) == 0 {
+				flows = append(flows, handlerFlow)
+			}
+			for _, a := range aggregates {
+				flows = append(flows, handlerFlow+" → "+a.symbol.Name)
+			}
+		}
+		handlers = append(handlers, handler)
+	}
+
+	useCase["handlers"] = handlers
+	useCase["flows"] = flows
+	return useCase, nil
+}
+
+// relatedSymbol is the target of a relation
+type relatedSymbol struct {
+	symbol *storage.Symbol
+	reason string
+}
+
+// related returns the targets of a symbol's relations of one kind
+func (t *useCaseTracer) related(ctx context.Context, symbolID int64, kind string) ([]relatedSymbol, error) {
+	relations, err := t.s.storage.ListSymbolRelations(ctx, symbolID)
+	if err != nil {
+		return nil, err
+	}
+	var related []relatedSymbol
+	for _, r := range relations {
+		if r.Kind != kind {
+			continue
+		}
+		sym, ok := t.symbols[r.ToID]
+		if !ok {
+			if sym, err = t.s.storage.GetSymbol(ctx, r.ToID); err != nil {
+				return nil, err
+			}
+			t.symbols[r.ToID] = sym
+		}
+		related = append(related, relatedSymbol{symbol: sym, reason: r.Reason})
+	}
+	return related, nil
+}
+
+// describe returns the location and signature of a symbol in the trace, with
+// the reason it was linked when it is not the traced symbol
+func (t *useCaseTracer) describe(ctx context.Context, sym *storage.Symbol, reason string) (map[string]interface{}, error) {
+	file, ok := t.files[sym.FileID]
+	if !ok {
+		var err error
+		if file, err = t.s.storage.GetFileByID(ctx, sym.FileID); err != nil {
+			return nil, err
+		}
+		t.files[sym.FileID] = file
+	}
+
+	m := map[string]interface{}{
+		"name":      sym.Name,
+		"kind":      sym.Kind,
+		"package":   file.PackageName,
+		"file":      file.FilePath,
+		"line":      sym.StartLine,
+		"signature": sym.Signature,
+	}
+	if file.ImportPath != "" {
+		m["import_path"] = file.ImportPath
+	}
+	if reason != "" {
+		m["reason"] = reason
+	}
+	return m, nil
+}
+
+// describeAll describes the targets of relations
+func (t *useCaseTracer) describeAll(ctx context.Context, related []relatedSymbol) ([]map[string]interface{}, error) {
+	result := make([]map[string]interface{}, 0, len(related))
+	for _, r := range related {
+		m, err := t.describe(ctx, r.symbol, r.reason)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleTraceUseCase(t *testing.T) {
+	s := newTestServer(t)
+	dir := indexTestProject(t, s, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.22\n",
+		"domain/order.go": "package domain\n\n" +
+			"import \"context\"\n\n" +
+			"type Order struct{ ID string }\n\n" +
+			"type OrderRepository interface {\n" +
+			"\tLoad(ctx context.Context, id string) (*Order, error)\n" +
+			"\tSave(ctx context.Context, order *Order) error\n" +
+			"}\n",
+		"app/place_order.go": "package app\n\n" +
+			"import (\n\t\"context\"\n\n\t\"example.com/shop/domain\"\n)\n\n" +
+			"type PlaceOrderCommand struct{ OrderID string }\n\n" +
+			"type PlaceOrderHandler struct{ orders domain.OrderRepository }\n\n" +
+			"func (h *PlaceOrderHandler) Handle(ctx context.Context, cmd PlaceOrderCommand) error {\n" +
+			"\torder, err := h.orders.Load(ctx, cmd.OrderID)\n" +
+			"\tif err != nil {\n\t\treturn err\n\t}\n" +
+			"\treturn h.orders.Save(ctx, order)\n" +
+			"}\n\n" +
+			"func PlaceOrder() {}\n",
+		"app/get_order.go": "package app\n\n" +
+			"type GetOrderQuery struct{ ID string }\n",
+	})
+
+	response := callTool(t, s.handleTraceUseCase, map[string]interface{}{"path": dir, "symbol": "PlaceOrderCommand"})
+	assert.Equal(t, float64(1), response["total"])
+	useCase := response["use_cases"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "PlaceOrderCommand", useCase["name"])
+	assert.Equal(t, "command", useCase["type"])
+	assert.Equal(t, "app/place_order.go", useCase["file"])
+	assert.Equal(t, []interface{}{"PlaceOrderCommand → PlaceOrderHandler → OrderRepository → Order"}, useCase["flows"])
+
+	handlers := useCase["handlers"].([]interface{})
+	require.Len(t, handlers, 1)
+	handler := handlers[0].(map[string]interface{})
+	assert.Equal(t, "PlaceOrderHandler", handler["name"])
+	assert.Equal(t, "PlaceOrderHandler.Handle takes PlaceOrderCommand; named after PlaceOrderCommand", handler["reason"])
+	repositories := handler["repositories"].([]interface{})
+	require.Len(t, repositories, 1)
+	repository := repositories[0].(map[string]interface{})
+	assert.Equal(t, "OrderRepository", repository["name"])
+	assert.Equal(t, "example.com/shop/domain", repository["import_path"])
+	assert.Equal(t, "field orders; calls Load, Save", repository["reason"])
+	aggregates := repository["aggregates"].([]interface{})
+	require.Len(t, aggregates, 1)
+	assert.Equal(t, "Load returns Order; Save takes Order", aggregates[0].(map[string]interface{})["reason"])
+	assert.Len(t, handler["aggregates"], 1)
+
+	// A query without handlers
+	response = callTool(t, s.handleTraceUseCase, map[string]interface{}{"path": dir, "symbol": "app.GetOrderQuery"})
+	useCase = response["use_cases"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "query", useCase["type"])
+	assert.Empty(t, useCase["handlers"])
+	assert.Empty(t, useCase["flows"])
+
+	for _, symbol := range []string{"", "PlaceOrder", "Missing"} {
+		_, err := s.handleTraceUseCase(context.Background(), callRequest(map[string]interface{}{"path": dir, "symbol": symbol}))
+		var mcpErr *MCPError
+		require.ErrorAs(t, err, &mcpErr, symbol)
+		assert.Equal(t, ErrorCodeInvalidParams, mcpErr.Code, symbol)
+	}
+}
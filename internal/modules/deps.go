@@ -0,0 +1,187 @@
+package modules
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// Dependency is a required module resolved to its sources on disk
+type Dependency struct {
+	Path     string // Module path as imported by the project
+	Version  string // Version whose sources are in Dir; the replacement's version when replaced
+	Dir      string // Absolute source directory
+	Direct   bool   // Required without "// indirect" by at least one project module
+	Vendored bool   // Dir is under a vendor directory rather than the module cache
+}
+
+// ID returns "path@version"
+func (d Dependency) ID() string {
+	return d.Path + "@" + d.Version
+}
+
+// ModCacheDir returns the module cache directory: $GOMODCACHE, else the first
+// GOPATH entry's pkg/mod, else $HOME/go/pkg/mod. It does not run the go command.
+func ModCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if gopath := filepath.SplitList(os.Getenv("GOPATH")); len(gopath) > 0 && gopath[0] != "" {
+		return filepath.Join(gopath[0], "pkg", "mod")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, "go", "pkg", "mod")
+}
+
+// ResolveDependencies maps the requirements of every module in the set to source
+// directories. Modules with a vendor/modules.txt resolve to their vendor directory;
+// others resolve to modCache. Indirect requirements are included only when indirect
+// is true. Requirements replaced by a local directory or satisfied by a module of
+// the project itself are skipped.
+//
+// Requirements whose sources are not on disk are returned in missing; nothing is
+// downloaded.
+func (s *Set) ResolveDependencies(rootPath, modCache string, indirect bool) (found, missing []Dependency, err error) {
+	own := make(map[string]bool, len(s.Modules))
+	for _, mod := range s.Modules {
+		own[mod.Path] = true
+	}
+
+	byID := make(map[string]*Dependency)
+	missingIDs := make(map[string]*Dependency)
+	for _, mod := range s.Modules {
+		modDir := filepath.Join(rootPath, filepath.FromSlash(mod.Dir))
+		vendored, err := readVendoredModules(filepath.Join(modDir, "vendor", "modules.txt"))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, req := range mod.Requires {
+			if req.Indirect && !indirect {
+				continue
+			}
+			if own[req.Path] || (req.ReplacePath != "" && req.ReplaceVersion == "") {
+				continue
+			}
+
+			dep := Dependency{Path: req.Path, Version: req.Version, Direct: !req.Indirect}
+			srcPath := req.Path
+			if req.ReplacePath != "" {
+				srcPath, dep.Version = req.ReplacePath, req.ReplaceVersion
+			}
+
+			if vendored != nil {
+				dep.Vendored = true
+				dep.Dir = filepath.Join(modDir, "vendor", filepath.FromSlash(req.Path))
+				if v, ok := vendored[req.Path]; ok && v != "" {
+					dep.Version = v
+				}
+			} else {
+				dep.Dir, err = modCachePath(modCache, srcPath, dep.Version)
+				if err != nil {
+					return nil, nil, fmt.Errorf("%s: %w", mod.Path, err)
+				}
+			}
+
+			target := byID
+			if info, statErr := os.Stat(dep.Dir); statErr != nil || !info.IsDir() {
+				target = missingIDs
+			}
+			if existing, ok := target[dep.ID()]; ok {
+				existing.Direct = existing.Direct || dep.Direct
+				continue
+			}
+			target[dep.ID()] = &dep
+		}
+	}
+
+	return sortedDependencies(byID), sortedDependencies(missingIDs), nil
+}
+
+// modCachePath returns the directory holding path@version in the module cache
+func modCachePath(modCache, path, version string) (string, error) {
+	if modCache == "" {
+		return "", fmt.Errorf("module cache directory unknown")
+	}
+	escPath, err := module.EscapePath(path)
+	if err != nil {
+		return "", err
+	}
+	escVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(modCache, filepath.FromSlash(escPath)+"@"+escVersion), nil
+}
+
+// readVendoredModules parses vendor/modules.txt into module path → version.
+// It returns nil without error when the file does not exist.
+func readVendoredModules(modulesTxt string) (map[string]string, error) {
+	data, err := os.ReadFile(modulesTxt)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Module lines look like "# path version" or "# path [version] => replacement [version]";
+	// "## explicit" annotations and package lines are ignored
+	vendored := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "# "))
+		if len(fields) == 0 {
+			continue
+		}
+		version := ""
+		if arrow := indexOf(fields, "=>"); arrow >= 0 {
+			// The vendored sources are the replacement's
+			if rest := fields[arrow+1:]; len(rest) == 2 {
+				version = rest[1]
+			}
+		} else if len(fields) > 1 {
+			version = fields[1]
+		}
+		vendored[fields[0]] = version
+	}
+	return vendored, scanner.Err()
+}
+
+// indexOf returns the index of s in fields, or -1
+func indexOf(fields []string, s string) int {
+	for i, f := range fields {
+		if f == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortedDependencies returns the map's values ordered by module path and version
+func sortedDependencies(deps map[string]*Dependency) []Dependency {
+	out := make([]Dependency, 0, len(deps))
+	for _, dep := range deps {
+		out = append(out, *dep)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Version < out[j].Version
+	})
+	return out
+}
@@ -0,0 +1,111 @@
+package modules
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDependencies(t *testing.T) {
+	root := t.TempDir()
+	cache := t.TempDir()
+
+	writeFile(t, root, "go.mod", `module example.com/app
+
+go 1.22
+
+require (
+	example.com/lib v1.2.0
+	example.com/local v0.1.0
+	example.com/tools v0.0.1
+	github.com/BurntSushi/toml v1.3.2
+	example.com/pinned v0.3.0
+	golang.org/x/text v0.14.0 // indirect
+	example.com/absent v1.0.0
+)
+
+replace example.com/local => ../local
+
+replace example.com/pinned => example.com/fork v0.3.1
+`)
+	writeFile(t, root, "tools/go.mod", `module example.com/tools
+
+go 1.22
+
+require example.com/lib v1.2.0 // indirect
+`)
+	writeFile(t, cache, "example.com/lib@v1.2.0/lib.go", "package lib\n")
+	writeFile(t, cache, "github.com/!burnt!sushi/toml@v1.3.2/toml.go", "package toml\n")
+	writeFile(t, cache, "example.com/fork@v0.3.1/fork.go", "package fork\n")
+	writeFile(t, cache, "golang.org/x/text@v0.14.0/text.go", "package text\n")
+
+	set, err := Discover(root, nil)
+	require.NoError(t, err)
+
+	found, missing, err := set.ResolveDependencies(root, cache, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Dependency{
+		{Path: "example.com/lib", Version: "v1.2.0", Dir: filepath.Join(cache, "example.com/lib@v1.2.0"), Direct: true},
+		{Path: "example.com/pinned", Version: "v0.3.1", Dir: filepath.Join(cache, "example.com/fork@v0.3.1"), Direct: true},
+		{Path: "github.com/BurntSushi/toml", Version: "v1.3.2", Dir: filepath.Join(cache, "github.com/!burnt!sushi/toml@v1.3.2"), Direct: true},
+	}, found, "local replacements, project modules and indirect requirements are skipped")
+	require.Len(t, missing, 1)
+	assert.Equal(t, "example.com/absent@v1.0.0", missing[0].ID())
+
+	found, _, err = set.ResolveDependencies(root, cache, true)
+	require.NoError(t, err)
+	require.Len(t, found, 4)
+	assert.Equal(t, "golang.org/x/text@v0.14.0", found[3].ID())
+	assert.False(t, found[3].Direct)
+	assert.True(t, found[0].Direct, "direct in one module wins over indirect in another")
+}
+
+func TestResolveDependencies_Vendor(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "go.mod", `module example.com/app
+
+go 1.22
+
+require (
+	example.com/lib v1.2.0
+	example.com/gone v0.1.0
+)
+`)
+	writeFile(t, root, "vendor/modules.txt", `# example.com/lib v1.2.0 => example.com/fork v1.2.1
+## explicit; go 1.21
+example.com/lib
+# example.com/gone v0.1.0
+## explicit
+`)
+	writeFile(t, root, "vendor/example.com/lib/lib.go", "package lib\n")
+
+	set, err := Discover(root, nil)
+	require.NoError(t, err)
+
+	found, missing, err := set.ResolveDependencies(root, "", false)
+	require.NoError(t, err)
+
+	require.Len(t, found, 1)
+	assert.Equal(t, Dependency{
+		Path:     "example.com/lib",
+		Version:  "v1.2.1",
+		Dir:      filepath.Join(root, "vendor", "example.com", "lib"),
+		Direct:   true,
+		Vendored: true,
+	}, found[0])
+	require.Len(t, missing, 1)
+	assert.Equal(t, "example.com/gone", missing[0].Path)
+}
+
+func TestModCacheDir(t *testing.T) {
+	t.Setenv("GOMODCACHE", "/cache/mod")
+	assert.Equal(t, "/cache/mod", ModCacheDir())
+
+	t.Setenv("GOMODCACHE", "")
+	t.Setenv("GOPATH", "/gopath"+string(filepath.ListSeparator)+"/other")
+	assert.Equal(t, filepath.Join("/gopath", "pkg", "mod"), ModCacheDir())
+}
@@ -0,0 +1,27 @@
+// Package modules discovers the Go modules that make up a project.
+//
+// A repository may contain several modules: a root go.mod, nested go.mod files
+// for tools or services, and a go.work file tying some of them into a workspace.
+// Discover finds all of them, parses go.mod files with golang.org/x/mod/modfile
+// (module path, go version, require and replace directives) and returns a Set
+// that maps any project-relative file path to the module that owns it.
+//
+// # Basic Usage
+//
+//	set, err := modules.Discover("/path/to/repo", nil)
+//	if err != nil {
+//	    return err
+//	}
+//	mod := set.ModuleFor("services/billing/internal/invoice/invoice.go")
+//	// mod.Path == "example.com/billing", mod.Dir == "services/billing"
+//	importPath := set.ImportPath("services/billing/internal/invoice/invoice.go")
+//	// "example.com/billing/internal/invoice"
+//
+// # Dependencies
+//
+// ResolveDependencies maps require directives to source directories in the
+// local module cache (see ModCacheDir) or in a module's vendor directory, so
+// third-party APIs can be indexed offline:
+//
+//	deps, missing, err := set.ResolveDependencies("/path/to/repo", modules.ModCacheDir(), false)
+package modules
@@ -0,0 +1,251 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Module describes one go.mod file in the project
+type Module struct {
+	Path        string // Module path from the module directive
+	Dir         string // Slash-separated directory relative to the project root, "" for the root
+	GoVersion   string
+	Toolchain   string // Toolchain directive, e.g. "go1.22.3"; empty when absent
+	InWorkspace bool   // Listed in a use directive of the root go.work
+	Requires    []Require
+}
+
+// Require is a module requirement with its replacement applied, if any
+type Require struct {
+	Path     string
+	Version  string
+	Indirect bool
+
+	// Replacement from a replace directive; ReplaceVersion is empty for local directories
+	ReplacePath    string
+	ReplaceVersion string
+}
+
+// Set is the collection of modules in a project
+type Set struct {
+	Modules []*Module // Sorted by Dir
+	GoWork  bool      // Whether a go.work file was found at the root
+}
+
+// ParseGoMod parses a go.mod file
+func ParseGoMod(goModPath string) (*Module, error) {
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseGoMod(goModPath, data)
+}
+
+// parseGoMod parses go.mod contents; goModPath is used in error messages
+func parseGoMod(goModPath string, data []byte) (*Module, error) {
+	f, err := modfile.Parse(goModPath, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if f.Module == nil {
+		return nil, fmt.Errorf("%s: missing module directive", goModPath)
+	}
+
+	mod := &Module{Path: f.Module.Mod.Path}
+	if f.Go != nil {
+		mod.GoVersion = f.Go.Version
+	}
+	if f.Toolchain != nil {
+		mod.Toolchain = f.Toolchain.Name
+	}
+
+	for _, r := range f.Require {
+		req := Require{
+			Path:     r.Mod.Path,
+			Version:  r.Mod.Version,
+			Indirect: r.Indirect,
+		}
+		// A version-specific replace wins over a path-wide one
+		for _, rep := range f.Replace {
+			if rep.Old.Path != r.Mod.Path || (rep.Old.Version != "" && rep.Old.Version != r.Mod.Version) {
+				continue
+			}
+			if req.ReplacePath == "" || rep.Old.Version != "" {
+				req.ReplacePath = rep.New.Path
+				req.ReplaceVersion = rep.New.Version
+			}
+		}
+		mod.Requires = append(mod.Requires, req)
+	}
+	return mod, nil
+}
+
+// ParseGoWork returns the slash-separated directories listed in a go.work file's
+// use directives, relative to the go.work file
+func ParseGoWork(goWorkPath string) ([]string, error) {
+	data, err := os.ReadFile(goWorkPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseGoWork(goWorkPath, data)
+}
+
+func parseGoWork(goWorkPath string, data []byte) ([]string, error) {
+	f, err := modfile.ParseWork(goWorkPath, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]string, 0, len(f.Use))
+	for _, use := range f.Use {
+		dirs = append(dirs, path.Clean(filepath.ToSlash(use.Path)))
+	}
+	return dirs, nil
+}
+
+// Discover finds every go.mod below rootPath and the modules listed in a root go.work.
+// skipDir, if non-nil, is called with slash-separated relative directories and
+// prunes the walk when it returns true. Like the go command, vendor and testdata
+// directories and directories starting with "." or "_" are never searched.
+func Discover(rootPath string, skipDir func(rel string) bool) (*Set, error) {
+	return DiscoverFS(os.DirFS(rootPath), skipDir)
+}
+
+// DiscoverFS is like Discover but walks fsys, such as the tree of a git revision
+func DiscoverFS(fsys fs.FS, skipDir func(rel string) bool) (*Set, error) {
+	set := &Set{}
+	byDir := make(map[string]*Module)
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel := p
+		if rel == "." {
+			rel = ""
+		}
+
+		if d.IsDir() {
+			if rel == "" {
+				return nil
+			}
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return fs.SkipDir
+			}
+			if skipDir != nil && skipDir(rel) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if d.Name() != "go.mod" {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		mod, err := parseGoMod(p, data)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", rel, err)
+		}
+		mod.Dir = path.Dir(rel)
+		if mod.Dir == "." {
+			mod.Dir = ""
+		}
+		byDir[mod.Dir] = mod
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uses, err := readGoWork(fsys)
+	switch {
+	case err == nil:
+		set.GoWork = true
+		for _, dir := range uses {
+			if dir == "." {
+				dir = ""
+			}
+			if mod, ok := byDir[dir]; ok {
+				mod.InWorkspace = true
+			}
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("parse go.work: %w", err)
+	}
+
+	for _, mod := range byDir {
+		set.Modules = append(set.Modules, mod)
+	}
+	sort.Slice(set.Modules, func(i, j int) bool {
+		return set.Modules[i].Dir < set.Modules[j].Dir
+	})
+	return set, nil
+}
+
+// Root returns the module at the project root, or the first module found, or nil
+func (s *Set) Root() *Module {
+	if len(s.Modules) == 0 {
+		return nil
+	}
+	return s.Modules[0] // Sorted by Dir, so the root ("") comes first when present
+}
+
+// ModuleFor returns the module owning a slash-separated project-relative file path:
+// the module with the longest directory prefix. It returns nil if none matches.
+func (s *Set) ModuleFor(relPath string) *Module {
+	relPath = filepath.ToSlash(relPath)
+	var best *Module
+	for _, mod := range s.Modules {
+		if mod.Dir != "" && relPath != mod.Dir && !strings.HasPrefix(relPath, mod.Dir+"/") {
+			continue
+		}
+		if best == nil || len(mod.Dir) > len(best.Dir) {
+			best = mod
+		}
+	}
+	return best
+}
+
+// ImportPath returns the import path of the package containing a file,
+// or "" if the file belongs to no module
+func (s *Set) ImportPath(relPath string) string {
+	relPath = filepath.ToSlash(relPath)
+	mod := s.ModuleFor(relPath)
+	if mod == nil {
+		return ""
+	}
+
+	dir := path.Dir(relPath)
+	if dir == "." {
+		dir = ""
+	}
+	sub := strings.TrimPrefix(strings.TrimPrefix(dir, mod.Dir), "/")
+	if mod.Path == StdModule {
+		return sub // Standard library import paths carry no module prefix
+	}
+	if sub == "" {
+		return mod.Path
+	}
+	return mod.Path + "/" + sub
+}
+
+// readGoWork parses the go.work file at the root of fsys
+func readGoWork(fsys fs.FS) ([]string, error) {
+	data, err := fs.ReadFile(fsys, "go.work")
+	if err != nil {
+		return nil, err
+	}
+	return parseGoWork("go.work", data)
+}
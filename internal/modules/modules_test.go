@@ -0,0 +1,104 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestParseGoMod(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", `module example.com/app
+
+go 1.22
+
+require (
+	example.com/lib v1.2.0
+	example.com/pinned v0.3.0
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace example.com/lib => ../lib
+
+replace (
+	example.com/pinned => example.com/fork v0.3.1
+	example.com/pinned v0.3.0 => example.com/exact v0.3.2
+)
+`)
+
+	mod, err := ParseGoMod(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com/app", mod.Path)
+	assert.Equal(t, "1.22", mod.GoVersion)
+	require.Len(t, mod.Requires, 3)
+
+	assert.Equal(t, Require{Path: "example.com/lib", Version: "v1.2.0", ReplacePath: "../lib"}, mod.Requires[0])
+	assert.Equal(t, Require{Path: "example.com/pinned", Version: "v0.3.0", ReplacePath: "example.com/exact", ReplaceVersion: "v0.3.2"}, mod.Requires[1])
+	assert.Equal(t, Require{Path: "golang.org/x/text", Version: "v0.14.0", Indirect: true}, mod.Requires[2])
+}
+
+func TestParseGoMod_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "go 1.22\n")
+	_, err := ParseGoMod(filepath.Join(dir, "go.mod"))
+	assert.Error(t, err)
+
+	_, err = ParseGoMod(filepath.Join(dir, "missing.mod"))
+	assert.Error(t, err)
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/mono\n\ngo 1.22\n")
+	writeFile(t, root, "go.work", "go 1.22\n\nuse (\n\t.\n\t./services/billing\n)\n")
+	writeFile(t, root, "services/billing/go.mod", "module example.com/billing\n\ngo 1.21\n")
+	writeFile(t, root, "tools/go.mod", "module example.com/tools\n\ngo 1.22\n")
+	writeFile(t, root, "vendor/example.com/dep/go.mod", "module example.com/dep\n")
+	writeFile(t, root, "internal/testdata/go.mod", "module example.com/fixture\n")
+	writeFile(t, root, "generated/go.mod", "module example.com/generated\n")
+
+	set, err := Discover(root, func(rel string) bool { return rel == "generated" })
+	require.NoError(t, err)
+
+	assert.True(t, set.GoWork)
+	require.Len(t, set.Modules, 3)
+	assert.Equal(t, "", set.Modules[0].Dir)
+	assert.Equal(t, "services/billing", set.Modules[1].Dir)
+	assert.Equal(t, "tools", set.Modules[2].Dir)
+	assert.True(t, set.Modules[0].InWorkspace)
+	assert.True(t, set.Modules[1].InWorkspace)
+	assert.False(t, set.Modules[2].InWorkspace)
+	assert.Equal(t, "example.com/mono", set.Root().Path)
+
+	assert.Equal(t, "example.com/billing", set.ModuleFor("services/billing/internal/invoice/invoice.go").Path)
+	assert.Equal(t, "example.com/mono", set.ModuleFor("services/other/x.go").Path)
+	assert.Equal(t, "example.com/mono", set.ModuleFor("main.go").Path)
+
+	assert.Equal(t, "example.com/billing/internal/invoice", set.ImportPath("services/billing/internal/invoice/invoice.go"))
+	assert.Equal(t, "example.com/billing", set.ImportPath("services/billing/main.go"))
+	assert.Equal(t, "example.com/mono", set.ImportPath("main.go"))
+	assert.Equal(t, "example.com/mono/services/other", set.ImportPath("services/other/x.go"))
+}
+
+func TestDiscover_NoRootModule(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "a/go.mod", "module example.com/a\n")
+
+	set, err := Discover(root, nil)
+	require.NoError(t, err)
+	assert.False(t, set.GoWork)
+	assert.Equal(t, "example.com/a", set.Root().Path)
+	assert.Nil(t, set.ModuleFor("b/main.go"))
+	assert.Empty(t, set.ImportPath("b/main.go"))
+}
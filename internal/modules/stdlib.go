@@ -0,0 +1,142 @@
+package modules
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/version"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// StdModule is the module path of the standard library in GOROOT/src/go.mod
+const StdModule = "std"
+
+// ErrNoGoRoot is returned when no Go installation with readable sources is found
+var ErrNoGoRoot = errors.New("no GOROOT with standard library sources found")
+
+// GoRoot is a Go installation whose standard library sources can be indexed
+type GoRoot struct {
+	Dir     string // GOROOT directory; sources are in Dir/src
+	Version string // From the VERSION file, e.g. "go1.22.3"
+	Exact   bool   // Matches the requested version (see FindGoRoot)
+}
+
+// SrcDir returns the directory holding the standard library packages
+func (g GoRoot) SrcDir() string {
+	return filepath.Join(g.Dir, "src")
+}
+
+// WantGoVersion returns the Go version a module builds with: its toolchain directive
+// when set, otherwise its go directive, as "go1.N[.P]". It returns "" for nil.
+func WantGoVersion(mod *Module) string {
+	if mod == nil {
+		return ""
+	}
+	if version.IsValid(mod.Toolchain) {
+		return mod.Toolchain
+	}
+	if mod.GoVersion == "" {
+		return ""
+	}
+	return "go" + mod.GoVersion
+}
+
+// FindGoRoot looks for the local Go installation best matching want ("go1.22" or
+// "go1.22.3"). Candidates are $GOROOT (or "go env GOROOT"), toolchains downloaded
+// into modCache by GOTOOLCHAIN and golang.org/dl SDKs in $HOME/sdk; nothing is
+// downloaded. An exact version match wins, then the newest release of the same
+// language version. Otherwise the first candidate is returned with Exact unset.
+func FindGoRoot(want, modCache string) (GoRoot, error) {
+	var candidates []GoRoot
+	seen := make(map[string]bool)
+	for _, dir := range goRootDirs(modCache) {
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		v, err := readGoVersion(dir)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(dir, "src")); err != nil || !info.IsDir() {
+			continue
+		}
+		candidates = append(candidates, GoRoot{Dir: dir, Version: v})
+	}
+	if len(candidates) == 0 {
+		return GoRoot{}, ErrNoGoRoot
+	}
+
+	if version.IsValid(want) {
+		for _, c := range candidates {
+			if c.Version == want {
+				c.Exact = true
+				return c, nil
+			}
+		}
+		// "go 1.22" in go.mod means go1.22.0 or later patch releases of go1.22
+		if version.Lang(want) == want || strings.HasSuffix(want, ".0") {
+			var best *GoRoot
+			for i, c := range candidates {
+				if version.Lang(c.Version) == version.Lang(want) && version.Compare(c.Version, want) >= 0 &&
+					(best == nil || version.Compare(c.Version, best.Version) > 0) {
+					best = &candidates[i]
+				}
+			}
+			if best != nil {
+				best.Exact = true
+				return *best, nil
+			}
+		}
+	}
+	return candidates[0], nil
+}
+
+// goRootDirs lists candidate GOROOT directories, the active installation first
+func goRootDirs(modCache string) []string {
+	var dirs []string
+	if dir := os.Getenv("GOROOT"); dir != "" {
+		dirs = append(dirs, dir)
+	} else if out, err := exec.Command("go", "env", "GOROOT").Output(); err == nil {
+		if dir := strings.TrimSpace(string(out)); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	var extra []string
+	if modCache != "" {
+		pattern := filepath.Join(modCache, "golang.org", "toolchain@v0.0.1-go*."+runtime.GOOS+"-"+runtime.GOARCH)
+		matches, _ := filepath.Glob(pattern)
+		extra = append(extra, matches...)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		matches, _ := filepath.Glob(filepath.Join(home, "sdk", "go1*"))
+		extra = append(extra, matches...)
+	}
+	sort.Strings(extra)
+	return append(dirs, extra...)
+}
+
+// readGoVersion returns the release version from a GOROOT's VERSION file
+func readGoVersion(goroot string) (string, error) {
+	f, err := os.Open(filepath.Join(goroot, "VERSION"))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return "", fmt.Errorf("%s: empty VERSION file", goroot)
+	}
+	v := strings.TrimSpace(scanner.Text())
+	if !version.IsValid(v) {
+		return "", fmt.Errorf("%s: not a release version: %q", goroot, v)
+	}
+	return v, nil
+}
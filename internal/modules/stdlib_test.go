@@ -0,0 +1,75 @@
+package modules
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeGoRoot creates a minimal Go installation reporting version v
+func writeGoRoot(t *testing.T, dir, v string) {
+	t.Helper()
+	writeFile(t, dir, "VERSION", v+"\ntime 2024-01-01T00:00:00Z\n")
+	writeFile(t, dir, "src/go.mod", "module std\n")
+}
+
+func TestFindGoRoot(t *testing.T) {
+	goroot := t.TempDir()
+	cache := t.TempDir()
+	t.Setenv("GOROOT", goroot)
+	t.Setenv("HOME", t.TempDir())
+
+	writeGoRoot(t, goroot, "go1.25.4")
+	platform := "." + runtime.GOOS + "-" + runtime.GOARCH
+	writeGoRoot(t, filepath.Join(cache, "golang.org", "toolchain@v0.0.1-go1.22.1"+platform), "go1.22.1")
+	writeGoRoot(t, filepath.Join(cache, "golang.org", "toolchain@v0.0.1-go1.22.3"+platform), "go1.22.3")
+
+	tests := []struct {
+		want    string
+		version string
+		exact   bool
+	}{
+		{"go1.25.4", "go1.25.4", true},
+		{"go1.22.1", "go1.22.1", true},
+		{"go1.22", "go1.22.3", true},    // Newest patch of the language version
+		{"go1.22.0", "go1.22.3", true},  // go directive "1.22.0"
+		{"go1.22.2", "go1.25.4", false}, // Patch not installed: active GOROOT
+		{"go1.21", "go1.25.4", false},   // Version not installed
+		{"", "go1.25.4", false},         // No go directive
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			root, err := FindGoRoot(tt.want, cache)
+			require.NoError(t, err)
+			assert.Equal(t, tt.version, root.Version)
+			assert.Equal(t, tt.exact, root.Exact)
+		})
+	}
+
+	root, err := FindGoRoot("go1.25.4", cache)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(goroot, "src"), root.SrcDir())
+}
+
+func TestFindGoRoot_None(t *testing.T) {
+	t.Setenv("GOROOT", t.TempDir()) // No VERSION file
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := FindGoRoot("go1.22", t.TempDir())
+	assert.ErrorIs(t, err, ErrNoGoRoot)
+}
+
+func TestWantGoVersion(t *testing.T) {
+	assert.Equal(t, "go1.22.3", WantGoVersion(&Module{GoVersion: "1.22", Toolchain: "go1.22.3"}))
+	assert.Equal(t, "go1.22", WantGoVersion(&Module{GoVersion: "1.22", Toolchain: "default"}))
+	assert.Equal(t, "", WantGoVersion(&Module{}))
+	assert.Equal(t, "", WantGoVersion(nil))
+}
+
+func TestImportPath_Std(t *testing.T) {
+	set := &Set{Modules: []*Module{{Path: StdModule}}}
+	assert.Equal(t, "net/http", set.ImportPath("net/http/server.go"))
+}
@@ -0,0 +1,220 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+var (
+	// markerRE matches "TODO", "TODO: text", "FIXME(alice): text" at the start of a comment line
+	markerRE = regexp.MustCompile(`^(TODO|FIXME|HACK|XXX|NOTE)\b(?:\(([^)]*)\))?:?\s*(.*)$`)
+
+	// issueRE matches "#123", tracker keys like "GH-45" and issue or pull request URLs
+	issueRE = regexp.MustCompile(`(?:^|[^\w/])(#\d+|[A-Z][A-Z0-9]+-\d+)\b|https?://\S+/(?:issues|pull)/\d+`)
+)
+
+// commentLine is one line of a comment with its line number
+type commentLine struct {
+	text string
+	line int
+	raw  string // Original "//..." comment, empty for block comment lines
+}
+
+// extractAnnotations finds marker comments, Deprecated: paragraphs and //nolint
+// directives, attributing each to the symbol it documents or is enclosed by
+func extractAnnotations(fset *token.FileSet, file *ast.File, symbols []types.Symbol) []types.Annotation {
+	var annotations []types.Annotation
+	docs := docGroups(file)
+
+	for _, group := range file.Comments {
+		lines := groupLines(fset, group)
+		var symbol string
+		if docs[group] {
+			symbol = documentedSymbol(symbols, lines[len(lines)-1].line)
+		}
+
+		for i := 0; i < len(lines); i++ {
+			l := lines[i]
+			var a types.Annotation
+
+			if nolint, ok := parseNolint(l.raw); ok {
+				a = nolint
+			} else if m := markerRE.FindStringSubmatch(l.text); m != nil {
+				a = types.Annotation{Kind: types.AnnotationKind(strings.ToLower(m[1])), Text: m[3]}
+				a.Owner, a.Issues = parseMarkerOwner(m[2])
+			} else if text, ok := strings.CutPrefix(l.text, "Deprecated:"); ok {
+				a = types.Annotation{Kind: types.AnnotationDeprecated, Text: strings.TrimSpace(text)}
+			} else {
+				continue
+			}
+
+			// Markers and deprecation notices run to the end of their paragraph
+			if a.Kind != types.AnnotationNolint {
+				for i+1 < len(lines) && continuesParagraph(lines[i+1].text) {
+					i++
+					a.Text = strings.TrimSpace(a.Text + " " + lines[i].text)
+				}
+			}
+
+			a.Line = l.line
+			a.Symbol = symbol
+			if a.Symbol == "" {
+				a.Symbol = enclosingSymbol(symbols, l.line)
+			}
+			a.Issues = appendIssues(a.Issues, a.Text)
+			annotations = append(annotations, a)
+		}
+	}
+
+	return annotations
+}
+
+// groupLines splits a comment group into lines, stripping comment markers
+func groupLines(fset *token.FileSet, group *ast.CommentGroup) []commentLine {
+	var lines []commentLine
+	for _, c := range group.List {
+		line := fset.Position(c.Slash).Line
+		if body, ok := strings.CutPrefix(c.Text, "//"); ok {
+			lines = append(lines, commentLine{text: strings.TrimSpace(body), line: line, raw: c.Text})
+			continue
+		}
+
+		body := strings.TrimSuffix(strings.TrimPrefix(c.Text, "/*"), "*/")
+		for i, text := range strings.Split(body, "\n") {
+			text = strings.TrimSpace(text)
+			text = strings.TrimSpace(strings.TrimPrefix(text, "*"))
+			lines = append(lines, commentLine{text: text, line: line + i})
+		}
+	}
+	return lines
+}
+
+// continuesParagraph reports whether a comment line extends the previous annotation
+func continuesParagraph(text string) bool {
+	return text != "" && !markerRE.MatchString(text) && !strings.HasPrefix(text, "Deprecated:") &&
+		!strings.HasPrefix(text, "nolint")
+}
+
+// parseNolint parses a golangci-lint "//nolint" or "//nolint:a,b // reason" directive
+func parseNolint(raw string) (types.Annotation, bool) {
+	rest, ok := strings.CutPrefix(raw, "//nolint")
+	if !ok || (rest != "" && rest[0] != ':' && rest[0] != ' ' && rest[0] != '\t') {
+		return types.Annotation{}, false
+	}
+
+	linters := "all"
+	if list, ok := strings.CutPrefix(rest, ":"); ok {
+		end := strings.IndexAny(list, " \t")
+		if end < 0 {
+			end = len(list)
+		}
+		linters, rest = list[:end], list[end:]
+	}
+
+	text := linters
+	if _, reason, ok := strings.Cut(rest, "//"); ok && strings.TrimSpace(reason) != "" {
+		text += ": " + strings.TrimSpace(reason)
+	}
+	return types.Annotation{Kind: types.AnnotationNolint, Text: text}, true
+}
+
+// parseMarkerOwner splits the parenthesized part of "TODO(alice, #12)" into an owner and issues
+func parseMarkerOwner(s string) (string, []string) {
+	var owner string
+	var issues []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case issueRE.MatchString(part):
+			issues = appendIssues(issues, part)
+		case owner == "":
+			owner = strings.TrimPrefix(part, "@")
+		}
+	}
+	return owner, issues
+}
+
+// appendIssues adds the issue references found in text that are not already listed
+func appendIssues(issues []string, text string) []string {
+	for _, m := range issueRE.FindAllStringSubmatch(text, -1) {
+		ref := m[1]
+		if ref == "" {
+			ref = m[0]
+		}
+		found := false
+		for _, existing := range issues {
+			found = found || existing == ref
+		}
+		if !found {
+			issues = append(issues, ref)
+		}
+	}
+	return issues
+}
+
+// docGroups returns the comment groups attached to declarations as doc comments
+func docGroups(file *ast.File) map[*ast.CommentGroup]bool {
+	docs := make(map[*ast.CommentGroup]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		var doc *ast.CommentGroup
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			doc = n.Doc
+		case *ast.GenDecl:
+			doc = n.Doc
+		case *ast.TypeSpec:
+			doc = n.Doc
+		case *ast.ValueSpec:
+			doc = n.Doc
+		case *ast.Field:
+			doc = n.Doc
+		}
+		if doc != nil {
+			docs[doc] = true
+		}
+		return true
+	})
+	return docs
+}
+
+// documentedSymbol returns the symbol documented by a comment ending at endLine,
+// i.e. declared on the next line
+func documentedSymbol(symbols []types.Symbol, endLine int) string {
+	for i := range symbols {
+		if symbols[i].Start.Line == endLine+1 {
+			return qualifiedName(&symbols[i])
+		}
+	}
+	return ""
+}
+
+// enclosingSymbol returns the innermost symbol whose declaration contains line
+func enclosingSymbol(symbols []types.Symbol, line int) string {
+	var best *types.Symbol
+	for i := range symbols {
+		s := &symbols[i]
+		if s.Start.Line > line || s.End.Line < line {
+			continue
+		}
+		if best == nil || s.End.Line-s.Start.Line < best.End.Line-best.Start.Line {
+			best = s
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return qualifiedName(best)
+}
+
+// qualifiedName returns "Receiver.Name" for methods and fields, else the name
+func qualifiedName(s *types.Symbol) string {
+	if s.Receiver != "" {
+		return s.Receiver + "." + s.Name
+	}
+	return s.Name
+}
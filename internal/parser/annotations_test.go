@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+const annotatedSource = `package store
+
+// TODO(alice): split this file, see #12
+// once the cache lands.
+
+// Store persists things.
+//
+// Deprecated: use NewStore instead.
+// Will be removed in v2.
+type Store struct {
+	name string // FIXME: not validated (JIRA-7)
+	size int
+}
+
+// Load reads from disk.
+func (s *Store) Load() error {
+	/* HACK(#99): retry until the lock
+	   is released */
+	return nil //nolint:errcheck,gosec // checked by caller
+}
+
+func helper() {
+	// A TODO in prose is not a marker.
+	// NOTE this is fine
+	//nolint
+	// XXX
+}
+`
+
+func TestParseSource_Annotations(t *testing.T) {
+	result, err := New().ParseSource("store.go", []byte(annotatedSource))
+	require.NoError(t, err)
+
+	assert.Equal(t, []types.Annotation{
+		{Kind: types.AnnotationTodo, Text: "split this file, see #12 once the cache lands.", Line: 3, Owner: "alice", Issues: []string{"#12"}},
+		{Kind: types.AnnotationDeprecated, Text: "use NewStore instead. Will be removed in v2.", Line: 8, Symbol: "Store"},
+		{Kind: types.AnnotationFixme, Text: "not validated (JIRA-7)", Line: 11, Symbol: "Store.name", Issues: []string{"JIRA-7"}},
+		{Kind: types.AnnotationHack, Text: "retry until the lock is released", Line: 17, Symbol: "Store.Load", Issues: []string{"#99"}},
+		{Kind: types.AnnotationNolint, Text: "errcheck,gosec: checked by caller", Line: 19, Symbol: "Store.Load"},
+		{Kind: types.AnnotationNote, Text: "this is fine", Line: 24, Symbol: "helper"},
+		{Kind: types.AnnotationNolint, Text: "all", Line: 25, Symbol: "helper"},
+		{Kind: types.AnnotationXXX, Text: "", Line: 26, Symbol: "helper"},
+	}, result.Annotations)
+}
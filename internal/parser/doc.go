@@ -27,15 +27,19 @@
 //
 // # Domain-Driven Design (DDD) Pattern Detection
 //
-// The parser automatically detects common DDD patterns based on naming conventions:
-//
-//	symbol.IsRepository  // "*Repository" suffix
-//	symbol.IsService     // "*Service" suffix
-//	symbol.IsEntity      // "*Entity" suffix or struct with "ID" field
-//	symbol.IsAggregate   // "*Aggregate" suffix
-//	symbol.IsCommand     // "*Command" suffix (CQRS)
-//	symbol.IsQuery       // "*Query" suffix (CQRS)
-//	symbol.IsHandler     // "*Handler" suffix (CQRS)
+// The parser marks common DDD patterns on types by naming convention, using the
+// built-in rules of package ddd:
+//
+//	symbol.IsRepository     // "*Repository" or "*Repo" suffix
+//	symbol.IsService        // "*Service" suffix
+//	symbol.IsEntity         // "*Entity" suffix, or an aggregate
+//	symbol.IsAggregateRoot  // "*Aggregate" or "*AggregateRoot" suffix
+//	symbol.IsCommand        // "*Command" or "*Cmd" suffix (CQRS)
+//	symbol.IsQuery          // "*Query" suffix (CQRS)
+//	symbol.IsHandler        // "*Handler" suffix (CQRS)
+//
+// symbol.DDD holds each pattern's confidence and reason. The indexer reclassifies
+// types with the project's own rules once the whole project is parsed.
 //
 // # Error Handling
 //
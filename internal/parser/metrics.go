@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// metricsWalker computes the complexity metrics of a function body
+type metricsWalker struct {
+	name      string // Function or method name, to detect recursion
+	method    bool   // Declared with a receiver
+	recv      string // Receiver name of a method, empty if unnamed
+	recursive bool
+	literals  int // Depth of function literals being walked
+	metrics   types.Metrics
+}
+
+// functionMetrics measures a function or method declaration
+func (e *symbolExtractor) functionMetrics(funcDecl *ast.FuncDecl) types.Metrics {
+	w := &metricsWalker{name: funcDecl.Name.Name, method: funcDecl.Recv != nil, recv: receiverName(funcDecl.Recv)}
+	w.metrics.Lines = e.fset.Position(funcDecl.End()).Line - e.fset.Position(funcDecl.Pos()).Line + 1
+	if funcDecl.Type.Params != nil {
+		for _, field := range funcDecl.Type.Params.List {
+			w.metrics.Params += max(1, len(field.Names))
+		}
+	}
+	if funcDecl.Body == nil {
+		return w.metrics
+	}
+
+	w.metrics.Cyclomatic = 1
+	w.walk(funcDecl.Body, 0)
+	if w.recursive {
+		w.metrics.Cognitive++
+	}
+	return w.metrics
+}
+
+// walk measures the statements and expressions of node at a nesting level
+func (w *metricsWalker) walk(node ast.Node, nesting int) {
+	if node == nil {
+		return
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt:
+			w.ifStmt(n, nesting, false)
+			return false
+		case *ast.ForStmt:
+			w.control(nesting)
+			w.walk(n.Init, nesting)
+			w.walk(n.Cond, nesting)
+			w.walk(n.Post, nesting)
+			w.nested(n.Body, nesting+1)
+			return false
+		case *ast.RangeStmt:
+			w.control(nesting)
+			w.walk(n.X, nesting)
+			w.nested(n.Body, nesting+1)
+			return false
+		case *ast.SwitchStmt:
+			w.metrics.Cognitive += 1 + nesting
+			w.walk(n.Init, nesting)
+			w.walk(n.Tag, nesting)
+			w.nested(n.Body, nesting+1)
+			return false
+		case *ast.TypeSwitchStmt:
+			w.metrics.Cognitive += 1 + nesting
+			w.walk(n.Init, nesting)
+			w.walk(n.Assign, nesting)
+			w.nested(n.Body, nesting+1)
+			return false
+		case *ast.SelectStmt:
+			w.metrics.Cognitive += 1 + nesting
+			w.nested(n.Body, nesting+1)
+			return false
+		case *ast.CaseClause:
+			if n.List != nil { // Not default
+				w.metrics.Cyclomatic++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil { // Not default
+				w.metrics.Cyclomatic++
+			}
+		case *ast.FuncLit:
+			w.literals++
+			w.nested(n.Body, nesting+1)
+			w.literals--
+			return false
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				w.logical(n, nesting)
+				return false
+			}
+		case *ast.BranchStmt:
+			if n.Tok == token.GOTO || n.Label != nil && n.Tok != token.FALLTHROUGH {
+				w.metrics.Cognitive++
+			}
+		case *ast.ReturnStmt:
+			if w.literals == 0 {
+				w.metrics.Returns++
+			}
+		case *ast.CallExpr:
+			w.checkRecursion(n)
+		}
+		return true
+	})
+}
+
+// control counts a loop: a decision point that breaks the flow at a nesting level
+func (w *metricsWalker) control(nesting int) {
+	w.metrics.Cyclomatic++
+	w.metrics.Cognitive += 1 + nesting
+}
+
+// nested walks a body one level deeper
+func (w *metricsWalker) nested(body *ast.BlockStmt, nesting int) {
+	w.metrics.Nesting = max(w.metrics.Nesting, nesting)
+	if body != nil {
+		w.walk(body, nesting)
+	}
+}
+
+// ifStmt counts an if statement and its else chain; else branches add to the
+// cognitive complexity without a nesting penalty
+func (w *metricsWalker) ifStmt(n *ast.IfStmt, nesting int, elseIf bool) {
+	w.metrics.Cyclomatic++
+	if elseIf {
+		w.metrics.Cognitive++
+	} else {
+		w.metrics.Cognitive += 1 + nesting
+	}
+	w.walk(n.Init, nesting)
+	w.walk(n.Cond, nesting)
+	w.nested(n.Body, nesting+1)
+
+	switch e := n.Else.(type) {
+	case *ast.IfStmt:
+		w.ifStmt(e, nesting, true)
+	case *ast.BlockStmt:
+		w.metrics.Cognitive++
+		w.nested(e, nesting+1)
+	}
+}
+
+// logical counts the operators of a chain of && and ||: each is a decision
+// point, and each run of the same operator breaks the flow once
+func (w *metricsWalker) logical(expr *ast.BinaryExpr, nesting int) {
+	var ops []token.Token
+	var operands []ast.Expr
+	var flatten func(ast.Expr)
+	flatten = func(e ast.Expr) {
+		if b, ok := e.(*ast.BinaryExpr); ok && (b.Op == token.LAND || b.Op == token.LOR) {
+			flatten(b.X)
+			ops = append(ops, b.Op)
+			flatten(b.Y)
+			return
+		}
+		operands = append(operands, e)
+	}
+	flatten(expr)
+
+	for i, op := range ops {
+		w.metrics.Cyclomatic++
+		if i == 0 || op != ops[i-1] {
+			w.metrics.Cognitive++
+		}
+	}
+	for _, operand := range operands {
+		w.walk(operand, nesting)
+	}
+}
+
+// checkRecursion notes a call of the function itself, or of the method on its
+// own receiver
+func (w *metricsWalker) checkRecursion(call *ast.CallExpr) {
+	switch fun := unwrapCallee(call.Fun).(type) {
+	case *ast.Ident:
+		if !w.method && fun.Name == w.name {
+			w.recursive = true
+		}
+	case *ast.SelectorExpr:
+		if x, ok := fun.X.(*ast.Ident); ok && w.recv != "" && x.Name == w.recv && fun.Sel.Name == w.name {
+			w.recursive = true
+		}
+	}
+}
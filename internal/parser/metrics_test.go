@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+func TestParseSource_Metrics(t *testing.T) {
+	content := `package app
+
+import "errors"
+
+func simple() {}
+
+func classify(items []int, strict bool) (int, error) {
+	total := 0
+	for _, item := range items {
+		if item < 0 && strict {
+			return 0, errors.New("negative")
+		} else if item == 0 {
+			continue
+		} else {
+			total += item
+		}
+	}
+	switch {
+	case total > 100 || total < -100:
+		return 100, nil
+	default:
+	}
+	return total, nil
+}
+
+func (n *Node) Walk(visit func(*Node) bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			return
+		}
+	}()
+	for _, child := range n.children {
+		child.Walk(visit)
+	}
+}
+
+func fact(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return n * fact(n-1)
+}
+
+func find(grid [][]int) (x, y int) {
+outer:
+	for i := range grid {
+		for j, v := range grid[i] {
+			if v == 0 {
+				x, y = i, j
+				break outer
+			}
+		}
+	}
+	return
+}
+
+type Visitor interface {
+	Visit(a, b int) error
+}
+`
+
+	result, err := New().ParseSource("app.go", []byte(content))
+	require.NoError(t, err)
+
+	metrics := make(map[string]types.Metrics)
+	for _, sym := range result.Symbols {
+		metrics[sym.Name] = sym.Metrics
+	}
+
+	assert.Equal(t, types.Metrics{Cyclomatic: 1, Lines: 1}, metrics["simple"])
+	assert.Equal(t, types.Metrics{Cyclomatic: 7, Cognitive: 8, Lines: 18, Params: 2, Nesting: 2, Returns: 3}, metrics["classify"])
+	assert.Equal(t, types.Metrics{Cyclomatic: 3, Cognitive: 3, Lines: 10, Params: 1, Nesting: 2}, metrics["Walk"],
+		"returns of function literals are not counted and child.Walk is not recursion")
+	assert.Equal(t, types.Metrics{Cyclomatic: 2, Cognitive: 2, Lines: 6, Params: 1, Nesting: 1, Returns: 2}, metrics["fact"])
+	assert.Equal(t, types.Metrics{Cyclomatic: 4, Cognitive: 7, Lines: 12, Params: 1, Nesting: 3, Returns: 1}, metrics["find"])
+	assert.Zero(t, metrics["Visit"])
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/parser"
 	"go/token"
 	"os"
@@ -25,13 +26,21 @@ func New() *Parser {
 
 // ParseFile parses a Go source file and extracts symbols, imports, and package information
 func (p *Parser) ParseFile(filePath string) (*types.ParseResult, error) {
-	result := &types.ParseResult{}
-
 	// Read the file
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	return p.ParseSource(filePath, content)
+}
+
+// ParseSource parses Go source that was read elsewhere, such as a blob from a
+// git revision. filePath is used for positions and filename build constraints.
+func (p *Parser) ParseSource(filePath string, content []byte) (*types.ParseResult, error) {
+	result := &types.ParseResult{}
+
+	// Record build constraints; they are read from the header even if parsing fails
+	result.Build = types.ParseBuildConstraint(filePath, content)
 
 	// Parse the file with comments for doc extraction
 	file, err := parser.ParseFile(p.fset, filePath, content, parser.ParseComments)
@@ -48,6 +57,9 @@ func (p *Parser) ParseFile(filePath string) (*types.ParseResult, error) {
 		if file.Name != nil {
 			result.PackageName = file.Name.Name
 		}
+		if file.Doc != nil {
+			result.PackageDoc = strings.TrimSpace(file.Doc.Text())
+		}
 
 		// Extract imports
 		result.Imports = p.extractImports(file)
@@ -58,11 +70,20 @@ func (p *Parser) ParseFile(filePath string) (*types.ParseResult, error) {
 			file:        file,
 			filePath:    filePath,
 			packageName: result.PackageName,
+			packages:    importNames(result.Imports),
 			symbols:     make([]types.Symbol, 0),
 		}
 
 		ast.Inspect(file, extractor.visit)
 		result.Symbols = extractor.symbols
+
+		// Extract TODO/FIXME markers, Deprecated: notices and //nolint directives
+		result.Annotations = extractAnnotations(p.fset, file, result.Symbols)
+
+		// Link test functions to the code they exercise
+		if strings.HasSuffix(filePath, "_test.go") {
+			result.Tests = extractTests(p.fset, file, result.Imports)
+		}
 	}
 
 	return result, nil
@@ -75,6 +96,7 @@ func (p *Parser) extractImports(file *ast.File) []types.Import {
 	for _, imp := range file.Imports {
 		importSpec := types.Import{
 			Path: strings.Trim(imp.Path.Value, `"`),
+			Line: p.fset.Position(imp.Pos()).Line,
 		}
 
 		// Check for alias
@@ -94,7 +116,9 @@ type symbolExtractor struct {
 	file        *ast.File
 	filePath    string
 	packageName string
+	packages    map[string]string // Imported package names, to import paths
 	symbols     []types.Symbol
+	consts      map[*ast.Ident]constant.Value // Resolved on first use by constValue
 }
 
 // visit is called for each AST node during traversal
@@ -129,11 +153,24 @@ func (e *symbolExtractor) extractFunction(funcDecl *ast.FuncDecl) {
 		sym.Receiver = e.extractReceiverType(funcDecl.Recv.List[0].Type)
 	} else {
 		sym.Kind = types.KindFunction
+		sym.TypeParams = e.extractTypeParams(funcDecl.Type.TypeParams)
 	}
 
 	// Extract function signature
 	sym.Signature = e.extractFunctionSignature(funcDecl)
 
+	// Record the types and functions it refers to
+	refs := newRefCollector(e.packages, funcDecl.Type.TypeParams, receiverTypeParams(funcDecl.Recv))
+	refs.recv = receiverName(funcDecl.Recv)
+	refs.signatureRefs(funcDecl.Type)
+	refs.bodyRefs(funcDecl.Body)
+	if funcDecl.Body != nil {
+		refs.declare(funcDecl.Type)
+		refs.useRefs(funcDecl.Body)
+	}
+	sym.Refs = refs.refs
+	sym.Metrics = e.functionMetrics(funcDecl)
+
 	// Determine scope
 	sym.Scope = e.determineScope(sym.Name)
 
@@ -145,12 +182,16 @@ func (e *symbolExtractor) extractFunction(funcDecl *ast.FuncDecl) {
 
 // extractGenDecl extracts type, const, and var declarations
 func (e *symbolExtractor) extractGenDecl(genDecl *ast.GenDecl) {
+	var source *ast.ValueSpec // Const specs without values repeat the last one with values
 	for _, spec := range genDecl.Specs {
 		switch s := spec.(type) {
 		case *ast.TypeSpec:
 			e.extractTypeSpec(s, genDecl.Doc)
 		case *ast.ValueSpec:
-			e.extractValueSpec(s, genDecl.Doc, genDecl.Tok)
+			if genDecl.Tok != token.CONST || len(s.Values) > 0 || source == nil {
+				source = s
+			}
+			e.extractValueSpec(s, source, genDecl.Doc, genDecl.Tok)
 		}
 	}
 }
@@ -164,39 +205,144 @@ func (e *symbolExtractor) extractTypeSpec(typeSpec *ast.TypeSpec, doc *ast.Comme
 		Scope:      e.determineScope(typeSpec.Name.Name),
 		Start:      e.positionFromToken(typeSpec.Pos()),
 		End:        e.positionFromToken(typeSpec.End()),
+		TypeParams: e.extractTypeParams(typeSpec.TypeParams),
+		Signature:  e.typeSignature(typeSpec),
 	}
 
-	// Determine the specific type
+	// Determine the specific type. Types refer to their constraints, embeds and
+	// underlying type; fields and interface methods keep their own references.
+	refs := newRefCollector(e.packages, typeSpec.TypeParams)
+	if typeSpec.TypeParams != nil {
+		refs.typeRefs(typeSpec.TypeParams, types.RefType)
+	}
 	switch t := typeSpec.Type.(type) {
 	case *ast.StructType:
 		sym.Kind = types.KindStruct
-		sym.Signature = e.extractStructSignature(typeSpec.Name.Name, t)
+		sym.Embeds = e.extractStructEmbeds(t)
+		refs.embedRefs(t.Fields)
 	case *ast.InterfaceType:
 		sym.Kind = types.KindInterface
-		sym.Signature = e.extractInterfaceSignature(typeSpec.Name.Name, t)
+		sym.Embeds = e.extractInterfaceEmbeds(t)
+		refs.embedRefs(t.Methods)
 	default:
 		sym.Kind = types.KindType
-		sym.Signature = fmt.Sprintf("type %s", typeSpec.Name.Name)
+		refs.typeRefs(typeSpec.Type, types.RefType)
 	}
+	sym.Refs = refs.refs
 
 	// Detect DDD patterns
 	detectDDDPatterns(&sym)
 
 	e.symbols = append(e.symbols, sym)
 
-	// Extract struct fields as separate symbols
-	if structType, ok := typeSpec.Type.(*ast.StructType); ok {
-		e.extractStructFields(typeSpec.Name.Name, structType)
+	// Extract struct fields and interface methods as separate symbols
+	switch t := typeSpec.Type.(type) {
+	case *ast.StructType:
+		e.extractStructFields(typeSpec.Name.Name, typeSpec.TypeParams, t)
+	case *ast.InterfaceType:
+		e.extractInterfaceMethods(typeSpec, t)
+	}
+}
+
+// extractStructEmbeds lists the types embedded in a struct
+func (e *symbolExtractor) extractStructEmbeds(structType *ast.StructType) []string {
+	if structType.Fields == nil {
+		return nil
+	}
+
+	var embeds []string
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			embeds = append(embeds, e.exprToString(field.Type))
+		}
+	}
+	return embeds
+}
+
+// extractInterfaceEmbeds lists the interfaces embedded in an interface; type set
+// terms such as "~int | ~string" are constraints, not embeds
+func (e *symbolExtractor) extractInterfaceEmbeds(interfaceType *ast.InterfaceType) []string {
+	if interfaceType.Methods == nil {
+		return nil
+	}
+
+	var embeds []string
+	for _, field := range interfaceType.Methods.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+		switch field.Type.(type) {
+		case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+			embeds = append(embeds, e.exprToString(field.Type))
+		}
+	}
+	return embeds
+}
+
+// extractInterfaceMethods extracts the methods of an interface as method symbols
+// whose receiver is the interface
+func (e *symbolExtractor) extractInterfaceMethods(typeSpec *ast.TypeSpec, interfaceType *ast.InterfaceType) {
+	if interfaceType.Methods == nil {
+		return
+	}
+
+	// Generic interfaces read "Getter[T]" in method signatures, as receivers do
+	recv := typeSpec.Name.Name
+	if params := e.extractTypeParams(typeSpec.TypeParams); len(params) > 0 {
+		names := make([]string, len(params))
+		for i, p := range params {
+			names[i] = p.Name
+		}
+		recv += "[" + strings.Join(names, ", ") + "]"
+	}
+
+	for _, field := range interfaceType.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+		doc := field.Doc
+		if doc == nil {
+			doc = field.Comment
+		}
+		refs := newRefCollector(e.packages, typeSpec.TypeParams)
+		refs.signatureRefs(funcType)
+		for _, name := range field.Names {
+			sym := types.Symbol{
+				Name:       name.Name,
+				Kind:       types.KindMethod,
+				Package:    e.packageName,
+				DocComment: e.extractDocComment(doc),
+				Scope:      e.determineScope(name.Name),
+				Receiver:   typeSpec.Name.Name,
+				Start:      e.positionFromToken(field.Pos()),
+				End:        e.positionFromToken(field.End()),
+				Signature:  fmt.Sprintf("func (%s) %s%s", recv, name.Name, e.funcTypeToString(funcType)),
+				Refs:       refs.refs,
+			}
+			e.symbols = append(e.symbols, sym)
+		}
 	}
 }
 
-// extractStructFields extracts field symbols from a struct
-func (e *symbolExtractor) extractStructFields(structName string, structType *ast.StructType) {
+// extractStructFields extracts field symbols from a struct, with the types of
+// each field as references
+func (e *symbolExtractor) extractStructFields(structName string, typeParams *ast.FieldList, structType *ast.StructType) {
 	if structType.Fields == nil {
 		return
 	}
 
 	for _, field := range structType.Fields.List {
+		// The tag is kept as written in the signature, so keyword search matches it
+		var tag string
+		var tags []types.StructTag
+		if field.Tag != nil {
+			tag = " " + field.Tag.Value
+			tags = parseStructTag(field.Tag.Value)
+		}
+		refs := newRefCollector(e.packages, typeParams)
+		refs.typeRefs(field.Type, types.RefType)
+
 		for _, name := range field.Names {
 			fieldSym := types.Symbol{
 				Name:      name.Name,
@@ -206,7 +352,9 @@ func (e *symbolExtractor) extractStructFields(structName string, structType *ast
 				Scope:     e.determineScope(name.Name),
 				Start:     e.positionFromToken(field.Pos()),
 				End:       e.positionFromToken(field.End()),
-				Signature: fmt.Sprintf("%s %s", name.Name, e.exprToString(field.Type)),
+				Signature: fmt.Sprintf("%s %s%s", name.Name, e.exprToString(field.Type), tag),
+				Tags:      tags,
+				Refs:      refs.refs,
 			}
 
 			e.symbols = append(e.symbols, fieldSym)
@@ -214,8 +362,9 @@ func (e *symbolExtractor) extractStructFields(structName string, structType *ast
 	}
 }
 
-// extractValueSpec extracts const and var declarations
-func (e *symbolExtractor) extractValueSpec(valueSpec *ast.ValueSpec, doc *ast.CommentGroup, tok token.Token) {
+// extractValueSpec extracts const and var declarations; source is the spec whose type
+// and values apply, which differs from valueSpec for implicitly repeated constants
+func (e *symbolExtractor) extractValueSpec(valueSpec, source *ast.ValueSpec, doc *ast.CommentGroup, tok token.Token) {
 	var kind types.SymbolKind
 	if tok == token.CONST {
 		kind = types.KindConst
@@ -223,7 +372,15 @@ func (e *symbolExtractor) extractValueSpec(valueSpec *ast.ValueSpec, doc *ast.Co
 		kind = types.KindVar
 	}
 
-	for _, name := range valueSpec.Names {
+	// Every name refers to the type and all the values of the spec
+	refs := newRefCollector(e.packages)
+	refs.typeRefs(source.Type, types.RefType)
+	refs.exprRefs(source.Values)
+	for _, value := range source.Values {
+		refs.useRefs(value)
+	}
+
+	for i, name := range valueSpec.Names {
 		sym := types.Symbol{
 			Name:       name.Name,
 			Kind:       kind,
@@ -232,34 +389,64 @@ func (e *symbolExtractor) extractValueSpec(valueSpec *ast.ValueSpec, doc *ast.Co
 			Scope:      e.determineScope(name.Name),
 			Start:      e.positionFromToken(valueSpec.Pos()),
 			End:        e.positionFromToken(valueSpec.End()),
-		}
-
-		// Build signature
-		if valueSpec.Type != nil {
-			sym.Signature = fmt.Sprintf("%s %s", name.Name, e.exprToString(valueSpec.Type))
-		} else if len(valueSpec.Values) > 0 {
-			sym.Signature = fmt.Sprintf("%s = ...", name.Name)
-		} else {
-			sym.Signature = name.Name
+			Signature:  e.valueSignature(tok, valueSpec, source, i),
+			Refs:       refs.refs,
 		}
 
 		e.symbols = append(e.symbols, sym)
 	}
 }
 
-// extractReceiverType extracts the receiver type name from a method
+// extractReceiverType extracts the receiver type name from a method; the type
+// parameters of a generic receiver are dropped: "*List[T]" → "List"
 func (e *symbolExtractor) extractReceiverType(expr ast.Expr) string {
 	switch t := expr.(type) {
 	case *ast.StarExpr:
-		if ident, ok := t.X.(*ast.Ident); ok {
-			return ident.Name
-		}
+		return e.extractReceiverType(t.X)
+	case *ast.IndexExpr:
+		return e.extractReceiverType(t.X)
+	case *ast.IndexListExpr:
+		return e.extractReceiverType(t.X)
 	case *ast.Ident:
 		return t.Name
 	}
 	return ""
 }
 
+// extractTypeParams extracts the type parameters of a generic function or type
+func (e *symbolExtractor) extractTypeParams(fieldList *ast.FieldList) []types.TypeParam {
+	if fieldList == nil || len(fieldList.List) == 0 {
+		return nil
+	}
+
+	var params []types.TypeParam
+	for _, field := range fieldList.List {
+		constraint := e.exprToString(field.Type)
+		for _, name := range field.Names {
+			params = append(params, types.TypeParam{Name: name.Name, Constraint: constraint})
+		}
+	}
+	return params
+}
+
+// typeParamsToString renders a type parameter list as written, e.g. "[K comparable, V any]";
+// it returns "" for non-generic declarations
+func (e *symbolExtractor) typeParamsToString(fieldList *ast.FieldList) string {
+	if fieldList == nil || len(fieldList.List) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(fieldList.List))
+	for _, field := range fieldList.List {
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		parts = append(parts, strings.Join(names, ", ")+" "+e.exprToString(field.Type))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
 // extractFunctionSignature builds a function signature string
 func (e *symbolExtractor) extractFunctionSignature(funcDecl *ast.FuncDecl) string {
 	var sig strings.Builder
@@ -274,19 +461,28 @@ func (e *symbolExtractor) extractFunctionSignature(funcDecl *ast.FuncDecl) strin
 	}
 
 	sig.WriteString(funcDecl.Name.Name)
+	sig.WriteString(e.typeParamsToString(funcDecl.Type.TypeParams))
+	sig.WriteString(e.funcTypeToString(funcDecl.Type))
+
+	return sig.String()
+}
+
+// funcTypeToString renders a function's parameters and results: "(p []byte) (n int, err error)"
+func (e *symbolExtractor) funcTypeToString(funcType *ast.FuncType) string {
+	var sig strings.Builder
 
 	// Parameters
 	sig.WriteString("(")
-	if funcDecl.Type.Params != nil {
-		sig.WriteString(e.fieldListToString(funcDecl.Type.Params))
+	if funcType.Params != nil {
+		sig.WriteString(e.fieldListToString(funcType.Params))
 	}
 	sig.WriteString(")")
 
 	// Results
-	if funcDecl.Type.Results != nil {
-		results := e.fieldListToString(funcDecl.Type.Results)
+	if funcType.Results != nil {
+		results := e.fieldListToString(funcType.Results)
 		if results != "" {
-			if funcDecl.Type.Results.NumFields() > 1 {
+			if funcType.Results.NumFields() > 1 || len(funcType.Results.List[0].Names) > 0 {
 				sig.WriteString(" (")
 				sig.WriteString(results)
 				sig.WriteString(")")
@@ -300,24 +496,6 @@ func (e *symbolExtractor) extractFunctionSignature(funcDecl *ast.FuncDecl) strin
 	return sig.String()
 }
 
-// extractStructSignature builds a struct signature string
-func (e *symbolExtractor) extractStructSignature(name string, structType *ast.StructType) string {
-	fieldCount := 0
-	if structType.Fields != nil {
-		fieldCount = structType.Fields.NumFields()
-	}
-	return fmt.Sprintf("type %s struct { ... } // %d fields", name, fieldCount)
-}
-
-// extractInterfaceSignature builds an interface signature string
-func (e *symbolExtractor) extractInterfaceSignature(name string, interfaceType *ast.InterfaceType) string {
-	methodCount := 0
-	if interfaceType.Methods != nil {
-		methodCount = interfaceType.Methods.NumFields()
-	}
-	return fmt.Sprintf("type %s interface { ... } // %d methods", name, methodCount)
-}
-
 // fieldListToString converts a field list to a string representation
 func (e *symbolExtractor) fieldListToString(fieldList *ast.FieldList) string {
 	if fieldList == nil || len(fieldList.List) == 0 {
@@ -339,34 +517,12 @@ func (e *symbolExtractor) fieldListToString(fieldList *ast.FieldList) string {
 	return strings.Join(parts, ", ")
 }
 
-// exprToString converts an expression to a string representation
+// exprToString renders an expression as gofmt would, on one line where the syntax allows
 func (e *symbolExtractor) exprToString(expr ast.Expr) string {
 	if expr == nil {
 		return ""
 	}
-
-	switch t := expr.(type) {
-	case *ast.Ident:
-		return t.Name
-	case *ast.StarExpr:
-		return "*" + e.exprToString(t.X)
-	case *ast.ArrayType:
-		return "[]" + e.exprToString(t.Elt)
-	case *ast.MapType:
-		return fmt.Sprintf("map[%s]%s", e.exprToString(t.Key), e.exprToString(t.Value))
-	case *ast.ChanType:
-		return "chan " + e.exprToString(t.Value)
-	case *ast.FuncType:
-		return "func(...)"
-	case *ast.InterfaceType:
-		return "interface{}"
-	case *ast.SelectorExpr:
-		return e.exprToString(t.X) + "." + t.Sel.Name
-	case *ast.Ellipsis:
-		return "..." + e.exprToString(t.Elt)
-	default:
-		return "..."
-	}
+	return render(lineless, expr)
 }
 
 // extractDocComment extracts documentation from a comment group
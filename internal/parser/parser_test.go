@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/dshills/gocontext-mcp/pkg/types"
@@ -57,12 +59,12 @@ func NewUser(id int, name string) *User {
 	assert.Empty(t, result.Errors)
 
 	// Check imports
-	importPaths := make(map[string]bool)
+	importLines := make(map[string]int)
 	for _, imp := range result.Imports {
-		importPaths[imp.Path] = true
+		importLines[imp.Path] = imp.Line
 	}
-	assert.True(t, importPaths["fmt"])
-	assert.True(t, importPaths["strings"])
+	assert.Equal(t, 4, importLines["fmt"])
+	assert.Equal(t, 5, importLines["strings"])
 
 	// Check symbols
 	symbolNames := make(map[string]bool)
@@ -216,6 +218,146 @@ type MyInt = int
 	assert.True(t, symbolNames["MyInt"])
 }
 
+func TestParseSource_Generics(t *testing.T) {
+	content := `package testpkg
+
+// Number is satisfied by integer and float types
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+// List is a generic linked list
+type List[T any] struct {
+	head *node[T]
+}
+
+// Cache maps keys to values
+type Cache[K comparable, V any] struct {
+	items map[K]V
+}
+
+// Pair holds two values of the same type
+type Pair[A, B fmt.Stringer] [2]A
+
+// Push adds v to the front of the list
+func (l *List[T]) Push(v T) {}
+
+// Get returns the value stored under k
+func (c *Cache[K, V]) Get(k K) (V, bool) { var v V; return v, false }
+
+// Sum adds numbers
+func Sum[T Number](values ...T) T { var s T; return s }
+
+// Keys returns the keys of m
+func Keys[M ~map[K]V, K comparable, V any](m M) []K { return nil }
+`
+
+	p := New()
+	result, err := p.ParseSource("generics.go", []byte(content))
+	require.NoError(t, err)
+
+	symbols := make(map[string]types.Symbol)
+	for _, sym := range result.Symbols {
+		symbols[sym.Name] = sym
+	}
+
+	tests := []struct {
+		name       string
+		signature  string
+		receiver   string
+		typeParams []types.TypeParam
+	}{
+		{"Number", "type Number interface{ ~int | ~int64 | ~float64 }", "", nil},
+		{"List", "type List[T any] struct{ head *node[T] }", "", []types.TypeParam{{Name: "T", Constraint: "any"}}},
+		{"Cache", "type Cache[K comparable, V any] struct{ items map[K]V }", "", []types.TypeParam{
+			{Name: "K", Constraint: "comparable"}, {Name: "V", Constraint: "any"},
+		}},
+		{"Pair", "type Pair[A, B fmt.Stringer] [2]A", "", []types.TypeParam{
+			{Name: "A", Constraint: "fmt.Stringer"}, {Name: "B", Constraint: "fmt.Stringer"},
+		}},
+		{"Push", "func (*List[T]) Push(v T)", "List", nil},
+		{"Get", "func (*Cache[K, V]) Get(k K) (V, bool)", "Cache", nil},
+		{"Sum", "func Sum[T Number](values ...T) T", "", []types.TypeParam{{Name: "T", Constraint: "Number"}}},
+		{"Keys", "func Keys[M ~map[K]V, K comparable, V any](m M) []K", "", []types.TypeParam{
+			{Name: "M", Constraint: "~map[K]V"}, {Name: "K", Constraint: "comparable"}, {Name: "V", Constraint: "any"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sym, ok := symbols[tt.name]
+			require.True(t, ok)
+			assert.Equal(t, tt.signature, sym.Signature)
+			assert.Equal(t, tt.receiver, sym.Receiver)
+			assert.Equal(t, tt.typeParams, sym.TypeParams)
+			assert.Equal(t, tt.typeParams != nil, sym.IsGeneric())
+		})
+	}
+
+	assert.Equal(t, "items map[K]V", symbols["items"].Signature)
+	assert.Equal(t, "head *node[T]", symbols["head"].Signature)
+}
+
+func TestParseSource_InterfaceMethodsAndEmbeds(t *testing.T) {
+	content := `package store
+
+// ReadWriter reads and writes records
+type ReadWriter interface {
+	io.Closer
+	Reader
+
+	// Write stores a record
+	Write(ctx context.Context, r Record) (id int64, err error)
+	Flush() error // Flush syncs to disk
+}
+
+// Getter is a generic lookup
+type Getter[K comparable, V any] interface {
+	Get(k K) (V, bool)
+}
+
+// Number is a type set
+type Number interface {
+	~int | ~float64
+}
+
+// Store embeds its base and a lock
+type Store struct {
+	*Base
+	sync.Mutex
+	cache Cache[string, int]
+	name  string
+}
+`
+
+	result, err := New().ParseSource("store.go", []byte(content))
+	require.NoError(t, err)
+
+	symbols := make(map[string]types.Symbol)
+	for _, sym := range result.Symbols {
+		symbols[sym.Name] = sym
+	}
+
+	rw := symbols["ReadWriter"]
+	assert.Equal(t, "type ReadWriter interface {\n\tio.Closer\n\tReader\n\tWrite(ctx context.Context, r Record) (id int64, err error)\n\tFlush() error\n}", rw.Signature)
+	assert.Equal(t, []string{"io.Closer", "Reader"}, rw.Embeds)
+
+	write := symbols["Write"]
+	assert.Equal(t, types.KindMethod, write.Kind)
+	assert.Equal(t, "ReadWriter", write.Receiver)
+	assert.Equal(t, "func (ReadWriter) Write(ctx context.Context, r Record) (id int64, err error)", write.Signature)
+	assert.Equal(t, "Write stores a record", write.DocComment)
+	assert.Equal(t, "Flush syncs to disk", symbols["Flush"].DocComment)
+	assert.NoError(t, write.Validate())
+
+	get := symbols["Get"]
+	assert.Equal(t, "Getter", get.Receiver)
+	assert.Equal(t, "func (Getter[K, V]) Get(k K) (V, bool)", get.Signature)
+
+	assert.Nil(t, symbols["Number"].Embeds, "type set terms are not embeds")
+	assert.Equal(t, []string{"*Base", "sync.Mutex"}, symbols["Store"].Embeds)
+	assert.Equal(t, "type Store struct {\n\t*Base\n\tsync.Mutex\n\tcache Cache[string, int]\n\tname  string\n}", symbols["Store"].Signature)
+}
+
 func TestParseFile_WithComments(t *testing.T) {
 	tmpDir := t.TempDir()
 	testFile := filepath.Join(tmpDir, "comments.go")
@@ -341,6 +483,103 @@ var SingleVar = 42
 	assert.Equal(t, types.KindVar, symbolKinds["SingleVar"])
 }
 
+func TestParseSource_ValueSignatures(t *testing.T) {
+	content := `package chunk
+
+import (
+	"errors"
+	"time"
+)
+
+// ChunkType classifies a chunk
+type ChunkType int
+
+const (
+	ChunkFunction ChunkType = iota
+	ChunkMethod
+	ChunkType_
+)
+
+const (
+	_  = iota
+	KB = 1 << (10 * iota)
+	MB
+)
+
+const (
+	MaxBatchSize = 100
+	Ratio        = 0.25
+	Name         = "chunker"
+	Timeout      = 5 * time.Second
+	Limit        = MaxBatchSize * 2
+)
+
+var (
+	ErrEmpty       = errors.New("empty chunk")
+	defaultWeights = map[string]float64{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8, "i": 9, "j": 10, "k": 11, "l": 12, "m": 13, "n": 14}
+	count          int
+	lo, hi         = split()
+)
+
+type Handler func(ctx Context) error
+`
+
+	result, err := New().ParseSource("chunk.go", []byte(content))
+	require.NoError(t, err)
+
+	signatures := make(map[string]string)
+	for _, sym := range result.Symbols {
+		signatures[sym.Name] = sym.Signature
+	}
+
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{"ChunkType", "type ChunkType int"},
+		{"ChunkFunction", "const ChunkFunction ChunkType = 0 // iota"},
+		{"ChunkMethod", "const ChunkMethod ChunkType = 1 // iota"},
+		{"ChunkType_", "const ChunkType_ ChunkType = 2 // iota"},
+		{"KB", "const KB = 1024 // 1 << (10 * iota)"},
+		{"MB", "const MB = 1048576 // 1 << (10 * iota)"},
+		{"MaxBatchSize", "const MaxBatchSize = 100"},
+		{"Ratio", "const Ratio = 0.25"},
+		{"Name", `const Name = "chunker"`},
+		{"Timeout", "const Timeout = 5 * time.Second"},
+		{"Limit", "const Limit = 200 // MaxBatchSize * 2"},
+		{"ErrEmpty", `var ErrEmpty = errors.New("empty chunk")`},
+		{"defaultWeights", "var defaultWeights = map[string]float64{...}"},
+		{"count", "var count int"},
+		{"lo", "var lo = split()"},
+		{"hi", "var hi = split()"},
+		{"Handler", "type Handler func(ctx Context) error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.signature, signatures[tt.name])
+		})
+	}
+}
+
+func TestParseSource_LargeStructSignature(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("package big\n\n// Big has many fields\ntype Big struct {\n")
+	for i := range 25 {
+		fmt.Fprintf(&b, "\t// F%d is documented\n\tF%d int // trailing\n", i, i)
+	}
+	b.WriteString("\tNested struct {\n\t\t// Inner is documented\n\t\tInner string\n\t}\n")
+	b.WriteString("}\n")
+
+	result, err := New().ParseSource("big.go", []byte(b.String()))
+	require.NoError(t, err)
+
+	sig := result.Symbols[0].Signature
+	assert.True(t, strings.HasPrefix(sig, "type Big struct {\n\tF0  int\n\tF1  int\n"), sig)
+	assert.True(t, strings.HasSuffix(sig, "\tF19 int\n\t// ... 6 more fields\n}"), sig)
+	assert.NotContains(t, sig, "documented")
+	assert.NotContains(t, sig, "trailing")
+}
+
 func TestExtractImports_NoImports(t *testing.T) {
 	tmpDir := t.TempDir()
 	testFile := filepath.Join(tmpDir, "no_imports.go")
@@ -687,3 +926,78 @@ func ValidFunc() {
 		})
 	}
 }
+
+func TestParseFile_BuildConstraints(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    types.BuildConstraint
+	}{
+		{
+			name:    "go:build line",
+			file:    "driver_cgo.go",
+			content: "// Copyright notice\n\n//go:build sqlite_vec && cgo\n\npackage storage\n",
+			want:    types.BuildConstraint{Expr: "sqlite_vec && cgo"},
+		},
+		{
+			name:    "legacy +build lines",
+			file:    "legacy.go",
+			content: "// +build purego !sqlite_vec\n// +build go1.20\n\npackage storage\n",
+			want:    types.BuildConstraint{Expr: "(purego || !sqlite_vec) && go1.20"},
+		},
+		{
+			name:    "file name suffixes",
+			file:    "poll_linux_arm64_test.go",
+			content: "package poll\n",
+			want:    types.BuildConstraint{GOOS: "linux", GOARCH: "arm64"},
+		},
+		{
+			name:    "constraint after package clause is ignored",
+			file:    "plain.go",
+			content: "package plain\n\n//go:build ignore\n",
+			want:    types.BuildConstraint{},
+		},
+		{
+			name:    "no suffix without underscore",
+			file:    "windows.go",
+			content: "package sys\n",
+			want:    types.BuildConstraint{},
+		},
+	}
+
+	p := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, tt.file)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			result, err := p.ParseFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Build)
+		})
+	}
+}
+
+func TestBuildContext_Matches(t *testing.T) {
+	linux := types.BuildContext{GOOS: "linux", GOARCH: "amd64"}
+	vec := types.BuildContext{GOOS: "linux", GOARCH: "amd64", Tags: []string{"sqlite_vec"}}
+
+	cgoFile := types.BuildConstraint{Expr: "sqlite_vec"}
+	pureFile := types.BuildConstraint{Expr: "purego || !sqlite_vec"}
+
+	assert.False(t, linux.Matches(cgoFile))
+	assert.True(t, linux.Matches(pureFile))
+	assert.True(t, vec.Matches(cgoFile))
+	assert.False(t, vec.Matches(pureFile))
+
+	assert.True(t, linux.Matches(types.BuildConstraint{}))
+	assert.True(t, linux.Matches(types.BuildConstraint{Expr: "unix && go1.21"}))
+	assert.False(t, linux.Matches(types.BuildConstraint{GOOS: "windows"}))
+	assert.False(t, linux.Matches(types.BuildConstraint{GOARCH: "arm64"}))
+	assert.True(t, types.BuildContext{GOOS: "android", GOARCH: "arm64"}.Matches(types.BuildConstraint{GOOS: "linux"}))
+
+	assert.Equal(t, "linux && amd64 && (a || b)", types.BuildConstraint{GOOS: "linux", GOARCH: "amd64", Expr: "a || b"}.String())
+}
@@ -0,0 +1,344 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// predeclaredTypes are never linked to indexed symbols
+var predeclaredTypes = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true, "complex64": true, "complex128": true,
+	"error": true, "float32": true, "float64": true, "int": true, "int8": true, "int16": true,
+	"int32": true, "int64": true, "rune": true, "string": true, "uint": true, "uint8": true,
+	"uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+}
+
+// predeclaredValues are never linked to indexed symbols
+var predeclaredValues = map[string]bool{"true": true, "false": true, "nil": true, "iota": true}
+
+// refCollector gathers the distinct names a declaration refers to
+type refCollector struct {
+	packages   map[string]string // Package names in scope, to import paths
+	typeParams map[string]bool   // Type parameters in scope, which are not references
+	recv       string            // Receiver name of a method, empty otherwise
+	locals     map[string]bool   // Names declared in the function, which hide package-level names
+	refs       []types.Ref
+	seen       map[types.Ref]bool
+	named      map[types.Ref]bool // Package and name of recorded refs, kind aside
+	members    map[string]bool    // Names of fields and methods recorded
+}
+
+// newRefCollector starts collecting references for a declaration with the given
+// type parameters
+func newRefCollector(packages map[string]string, typeParams ...*ast.FieldList) *refCollector {
+	r := &refCollector{
+		packages:   packages,
+		typeParams: make(map[string]bool),
+		locals:     make(map[string]bool),
+		seen:       make(map[types.Ref]bool),
+		named:      make(map[types.Ref]bool),
+		members:    make(map[string]bool),
+	}
+	for _, list := range typeParams {
+		if list == nil {
+			continue
+		}
+		for _, field := range list.List {
+			for _, name := range field.Names {
+				r.typeParams[name.Name] = true
+			}
+		}
+	}
+	return r
+}
+
+// add records a reference once
+func (r *refCollector) add(ref types.Ref) {
+	if !r.seen[ref] {
+		r.seen[ref] = true
+		r.refs = append(r.refs, ref)
+	}
+	if ref.Kind == types.RefMethod || ref.Kind == types.RefSelect {
+		r.members[ref.Name] = true
+	} else {
+		r.named[types.Ref{Package: ref.Package, Name: ref.Name}] = true
+	}
+}
+
+// use records a use of a name not already recorded with another kind
+func (r *refCollector) use(ref types.Ref) {
+	if ref.Kind == types.RefSelect && r.members[ref.Name] ||
+		ref.Kind == types.RefUse && r.named[types.Ref{Package: ref.Package, Name: ref.Name}] {
+		return
+	}
+	r.add(ref)
+}
+
+// typeRefs records the named types in a type expression: "*domain.Order",
+// "[]Item" and "map[ID]Order" all refer to their element types
+func (r *refCollector) typeRefs(node ast.Node, kind types.RefKind) {
+	if node == nil {
+		return
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch t := n.(type) {
+		case *ast.Field:
+			// Parameter and field names are not references
+			r.typeRefs(t.Type, kind)
+			return false
+		case *ast.SelectorExpr:
+			if x, ok := t.X.(*ast.Ident); ok {
+				if importPath, ok := r.packages[x.Name]; ok {
+					r.add(types.Ref{Kind: kind, Package: importPath, Name: t.Sel.Name})
+				}
+			}
+			return false
+		case *ast.Ident:
+			if !predeclaredTypes[t.Name] && !r.typeParams[t.Name] {
+				r.add(types.Ref{Kind: kind, Name: t.Name})
+			}
+		case *ast.ArrayType:
+			// The length is a constant, not a type
+			if t.Len != nil {
+				r.useRefs(t.Len)
+			}
+			r.typeRefs(t.Elt, kind)
+			return false
+		}
+		return true
+	})
+}
+
+// embedRefs records the embedded types of a struct, and the embeds and type
+// set terms of an interface
+func (r *refCollector) embedRefs(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			r.typeRefs(field.Type, types.RefType)
+		}
+	}
+}
+
+// signatureRefs records type parameter constraints, parameter and result types
+func (r *refCollector) signatureRefs(funcType *ast.FuncType) {
+	if funcType.TypeParams != nil {
+		r.typeRefs(funcType.TypeParams, types.RefType)
+	}
+	if funcType.Params != nil {
+		r.typeRefs(funcType.Params, types.RefParam)
+	}
+	if funcType.Results != nil {
+		r.typeRefs(funcType.Results, types.RefResult)
+	}
+}
+
+// bodyRefs records the calls in a function body and the types it names in
+// composite literals, declarations, conversions and new or make
+func (r *refCollector) bodyRefs(body *ast.BlockStmt) {
+	if body == nil {
+		return
+	}
+	r.inspectRefs(body)
+}
+
+// exprRefs records the calls and types in initializers, as bodyRefs does
+func (r *refCollector) exprRefs(exprs []ast.Expr) {
+	for _, expr := range exprs {
+		r.inspectRefs(expr)
+	}
+}
+
+// inspectRefs records the calls in node and the types it names in composite
+// literals, declarations, conversions and new or make
+func (r *refCollector) inspectRefs(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CompositeLit:
+			r.typeRefs(node.Type, types.RefType)
+		case *ast.ValueSpec:
+			r.typeRefs(node.Type, types.RefType)
+		case *ast.TypeAssertExpr:
+			r.typeRefs(node.Type, types.RefType)
+		case *ast.CallExpr:
+			r.callRefs(node)
+		}
+		return true
+	})
+}
+
+// callRefs records the function or method a call invokes
+func (r *refCollector) callRefs(call *ast.CallExpr) {
+	switch fun := unwrapCallee(call.Fun).(type) {
+	case *ast.Ident:
+		if fun.Name == "new" || fun.Name == "make" {
+			if len(call.Args) > 0 {
+				r.typeRefs(call.Args[0], types.RefType)
+			}
+			return
+		}
+		if !builtins[fun.Name] && !predeclaredTypes[fun.Name] && !r.typeParams[fun.Name] {
+			r.add(types.Ref{Kind: types.RefCall, Name: fun.Name})
+		}
+	case *ast.SelectorExpr:
+		if x, ok := fun.X.(*ast.Ident); ok {
+			if importPath, ok := r.packages[x.Name]; ok {
+				r.add(types.Ref{Kind: types.RefCall, Package: importPath, Name: fun.Sel.Name})
+				return
+			}
+		}
+		ref := types.Ref{Kind: types.RefMethod, Name: fun.Sel.Name}
+		// h.orders.Save(...) goes through the receiver's orders field
+		if field, ok := fun.X.(*ast.SelectorExpr); ok && r.recv != "" {
+			if x, ok := field.X.(*ast.Ident); ok && x.Name == r.recv {
+				ref.Via = field.Sel.Name
+			}
+		}
+		r.add(ref)
+	case *ast.ArrayType, *ast.MapType, *ast.StarExpr, *ast.ChanType, *ast.FuncType:
+		// Conversions to composite types
+		r.typeRefs(fun, types.RefType)
+	}
+}
+
+// useRefs records the names in node not recorded by the other passes: values,
+// constants and types used otherwise, qualified names such as http.DefaultClient,
+// and the fields and methods selected or set by key. Names declared anywhere in
+// node hide package-level names throughout the function.
+func (r *refCollector) useRefs(node ast.Node) {
+	r.declare(node)
+	r.walkUses(node)
+}
+
+// declare records the names node declares: variables, constants, types,
+// parameters, results and labels
+func (r *refCollector) declare(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				for _, lhs := range n.Lhs {
+					r.declareIdent(lhs)
+				}
+			}
+		case *ast.RangeStmt:
+			if n.Tok == token.DEFINE {
+				r.declareIdent(n.Key)
+				r.declareIdent(n.Value)
+			}
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				r.locals[name.Name] = true
+			}
+		case *ast.TypeSpec:
+			r.locals[n.Name.Name] = true
+		case *ast.FuncType:
+			for _, list := range []*ast.FieldList{n.TypeParams, n.Params, n.Results} {
+				if list == nil {
+					continue
+				}
+				for _, field := range list.List {
+					for _, name := range field.Names {
+						r.locals[name.Name] = true
+					}
+				}
+			}
+		case *ast.LabeledStmt:
+			r.locals[n.Label.Name] = true
+		}
+		return true
+	})
+}
+
+// declareIdent records a declared name
+func (r *refCollector) declareIdent(expr ast.Expr) {
+	if ident, ok := expr.(*ast.Ident); ok {
+		r.locals[ident.Name] = true
+	}
+}
+
+// walkUses records the uses in node
+func (r *refCollector) walkUses(node ast.Node) {
+	if node == nil {
+		return
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Field:
+			// Parameter and field names are declarations
+			r.walkUses(n.Type)
+			return false
+		case *ast.SelectorExpr:
+			if x, ok := n.X.(*ast.Ident); ok && !r.locals[x.Name] {
+				if importPath, ok := r.packages[x.Name]; ok {
+					r.use(types.Ref{Kind: types.RefUse, Package: importPath, Name: n.Sel.Name})
+					return false
+				}
+			}
+			r.use(types.Ref{Kind: types.RefSelect, Name: n.Sel.Name})
+			r.walkUses(n.X)
+			return false
+		case *ast.KeyValueExpr:
+			// A field name, or a constant of a map or array literal
+			if key, ok := n.Key.(*ast.Ident); ok {
+				r.use(types.Ref{Kind: types.RefSelect, Name: key.Name})
+			}
+		case *ast.BranchStmt:
+			return false
+		case *ast.LabeledStmt:
+			r.walkUses(n.Stmt)
+			return false
+		case *ast.Ident:
+			if r.usable(n.Name) {
+				r.use(types.Ref{Kind: types.RefUse, Name: n.Name})
+			}
+		}
+		return true
+	})
+}
+
+// usable reports whether an unqualified name may refer to a package-level
+// declaration
+func (r *refCollector) usable(name string) bool {
+	return name != "_" && name != r.recv && !r.locals[name] && !r.typeParams[name] &&
+		!predeclaredTypes[name] && !predeclaredValues[name] && !builtins[name]
+}
+
+// receiverName returns the name of a method's receiver, empty if unnamed
+func receiverName(recv *ast.FieldList) string {
+	if recv == nil || len(recv.List) == 0 || len(recv.List[0].Names) == 0 {
+		return ""
+	}
+	return recv.List[0].Names[0].Name
+}
+
+// receiverTypeParams returns the type parameters a generic receiver declares,
+// "T" in func (l *List[T]) Push, as a field list
+func receiverTypeParams(recv *ast.FieldList) *ast.FieldList {
+	if recv == nil || len(recv.List) == 0 {
+		return nil
+	}
+	expr := recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	var indices []ast.Expr
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		indices = []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		indices = t.Indices
+	}
+
+	params := &ast.FieldList{}
+	for _, index := range indices {
+		if ident, ok := index.(*ast.Ident); ok {
+			params.List = append(params.List, &ast.Field{Names: []*ast.Ident{ident}})
+		}
+	}
+	return params
+}
@@ -0,0 +1,164 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+func TestParseSource_Refs(t *testing.T) {
+	content := `package app
+
+import (
+	"context"
+
+	"example.com/shop/internal/domain"
+)
+
+type PlaceOrderHandler struct {
+	orders domain.OrderRepository
+	clock  func() Clock
+	cache  map[domain.OrderID]*domain.Order
+}
+
+func (h *PlaceOrderHandler) Handle(ctx context.Context, cmd PlaceOrderCommand) (*domain.Order, error) {
+	order, err := h.orders.Load(ctx, cmd.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	var lines []domain.Line
+	lines = append(lines, domain.Line{SKU: cmd.SKU})
+	order.Place(lines)
+	if err := validate(order); err != nil {
+		return nil, err
+	}
+	_ = make(map[string]Receipt)
+	_ = Status(1)
+	return order, h.orders.Save(ctx, order)
+}
+
+type Store[T any] interface {
+	Get(ctx context.Context, id string) (T, error)
+}
+
+func Map[T, U any](items []T, f func(T) U) []U {
+	return nil
+}
+`
+
+	result, err := New().ParseSource("app.go", []byte(content))
+	require.NoError(t, err)
+
+	refs := make(map[string][]types.Ref)
+	for _, sym := range result.Symbols {
+		refs[sym.Receiver+"."+sym.Name] = sym.Refs
+	}
+
+	domainPath := "example.com/shop/internal/domain"
+	assert.Equal(t, []types.Ref{
+		{Kind: types.RefParam, Package: "context", Name: "Context"},
+		{Kind: types.RefParam, Name: "PlaceOrderCommand"},
+		{Kind: types.RefResult, Package: domainPath, Name: "Order"},
+		{Kind: types.RefMethod, Name: "Load", Via: "orders"},
+		{Kind: types.RefType, Package: domainPath, Name: "Line"},
+		{Kind: types.RefMethod, Name: "Place"},
+		{Kind: types.RefCall, Name: "validate"},
+		{Kind: types.RefType, Name: "Receipt"},
+		{Kind: types.RefCall, Name: "Status"},
+		{Kind: types.RefMethod, Name: "Save", Via: "orders"},
+		{Kind: types.RefSelect, Name: "orders"},
+		{Kind: types.RefSelect, Name: "OrderID"},
+		{Kind: types.RefSelect, Name: "SKU"},
+		{Kind: types.RefUse, Name: "SKU"},
+	}, refs["PlaceOrderHandler.Handle"])
+
+	assert.Equal(t, []types.Ref{{Kind: types.RefType, Package: domainPath, Name: "OrderRepository"}}, refs["PlaceOrderHandler.orders"])
+	assert.Equal(t, []types.Ref{{Kind: types.RefType, Name: "Clock"}}, refs["PlaceOrderHandler.clock"])
+	assert.Equal(t, []types.Ref{
+		{Kind: types.RefType, Package: domainPath, Name: "OrderID"},
+		{Kind: types.RefType, Package: domainPath, Name: "Order"},
+	}, refs["PlaceOrderHandler.cache"])
+
+	// Type parameters are not references
+	assert.Equal(t, []types.Ref{{Kind: types.RefParam, Package: "context", Name: "Context"}}, refs["Store.Get"])
+	assert.Empty(t, refs[".Map"])
+	assert.Empty(t, refs[".PlaceOrderHandler"])
+}
+
+func TestParseSource_UseRefs(t *testing.T) {
+	content := `package app
+
+import "net/http"
+
+const bufSize = 64
+
+var defaultClient = newClient(http.DefaultTransport)
+
+var handlers = map[string]http.HandlerFunc{"health": health}
+
+type ID string
+
+type Set[K Key] map[K]struct{}
+
+type Service struct {
+	Base
+	buf [bufSize]byte
+}
+
+func (s *Service) Run(client *http.Client) {
+	config := loadConfig()
+	if client == nil {
+		client = defaultClient
+	}
+	run := s.start
+	_ = Options{Timeout: timeout, Retries: config.retries}
+loop:
+	for range handlers {
+		break loop
+	}
+	run()
+}
+`
+
+	result, err := New().ParseSource("app.go", []byte(content))
+	require.NoError(t, err)
+
+	refs := make(map[string][]types.Ref)
+	for _, sym := range result.Symbols {
+		refs[sym.Receiver+"."+sym.Name] = sym.Refs
+	}
+
+	assert.Empty(t, refs[".bufSize"])
+	assert.Equal(t, []types.Ref{
+		{Kind: types.RefCall, Name: "newClient"},
+		{Kind: types.RefUse, Package: "net/http", Name: "DefaultTransport"},
+	}, refs[".defaultClient"])
+	assert.Equal(t, []types.Ref{
+		{Kind: types.RefType, Package: "net/http", Name: "HandlerFunc"},
+		{Kind: types.RefUse, Name: "health"},
+	}, refs[".handlers"])
+	assert.Empty(t, refs[".ID"])
+	assert.Equal(t, []types.Ref{{Kind: types.RefType, Name: "Key"}}, refs[".Set"])
+	assert.Equal(t, []types.Ref{{Kind: types.RefType, Name: "Base"}}, refs[".Service"])
+	assert.Equal(t, []types.Ref{{Kind: types.RefUse, Name: "bufSize"}}, refs["Service.buf"])
+
+	// Locals, the receiver, parameters and labels hide package-level names
+	assert.Equal(t, []types.Ref{
+		{Kind: types.RefParam, Package: "net/http", Name: "Client"},
+		{Kind: types.RefCall, Name: "loadConfig"},
+		{Kind: types.RefType, Name: "Options"},
+		{Kind: types.RefCall, Name: "run"}, // Calls are recorded syntactically
+		{Kind: types.RefUse, Name: "defaultClient"},
+		{Kind: types.RefSelect, Name: "start"},
+		{Kind: types.RefSelect, Name: "Timeout"},
+		{Kind: types.RefUse, Name: "Timeout"}, // Keys may be constants of a named map type
+		{Kind: types.RefUse, Name: "timeout"},
+		{Kind: types.RefSelect, Name: "Retries"},
+		{Kind: types.RefUse, Name: "Retries"},
+		{Kind: types.RefSelect, Name: "retries"},
+		{Kind: types.RefUse, Name: "handlers"},
+	}, refs["Service.Run"])
+}
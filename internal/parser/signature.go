@@ -0,0 +1,228 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/printer"
+	"go/token"
+	gotypes "go/types"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxSignatureMembers caps the fields or interface elements rendered in a type signature
+	maxSignatureMembers = 20
+	// maxSignatureValue caps the length of a rendered const or var value
+	maxSignatureValue = 120
+)
+
+// printerConfig matches gofmt's output
+var printerConfig = printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+
+// lineless has no files, so positions carry no line information and the printer
+// keeps expressions on one line where the syntax allows
+var lineless = token.NewFileSet()
+
+// render prints a node as gofmt would
+func render(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printerConfig.Fprint(&buf, fset, node); err != nil {
+		return "..."
+	}
+	return buf.String()
+}
+
+// typeSignature renders a type declaration as gofmt would, without comments or
+// blank lines:
+//
+//	type Store struct {
+//		mu    sync.Mutex
+//		items map[string][]byte
+//	}
+//
+// Struct and interface bodies are cut after maxSignatureMembers entries, noting how
+// many were left out.
+func (e *symbolExtractor) typeSignature(typeSpec *ast.TypeSpec) string {
+	spec := *typeSpec
+	spec.Doc, spec.Comment = nil, nil
+
+	omitted, noun := 0, ""
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		structType := *t
+		structType.Fields, omitted = trimFieldList(t.Fields, maxSignatureMembers)
+		spec.Type, noun = &structType, "fields"
+	case *ast.InterfaceType:
+		interfaceType := *t
+		interfaceType.Methods, omitted = trimFieldList(t.Methods, maxSignatureMembers)
+		spec.Type, noun = &interfaceType, "methods"
+	}
+
+	sig := "type " + render(lineless, &spec)
+	if omitted > 0 {
+		i := strings.LastIndex(sig, "}")
+		sig = sig[:i] + fmt.Sprintf("\t// ... %d more %s\n", omitted, noun) + sig[i:]
+	}
+	return sig
+}
+
+// trimFieldList copies a field list without comments, keeping the first limit
+// entries; it returns the copy and the number of names left out. Comments are also
+// dropped from the fields of anonymous structs and interfaces, which are kept whole.
+func trimFieldList(fieldList *ast.FieldList, limit int) (*ast.FieldList, int) {
+	if fieldList == nil {
+		return nil, 0
+	}
+
+	trimmed := *fieldList
+	trimmed.List = make([]*ast.Field, 0, min(len(fieldList.List), limit))
+	omitted := 0
+	for i, field := range fieldList.List {
+		if i >= limit {
+			omitted += max(len(field.Names), 1)
+			continue
+		}
+		f := *field
+		f.Doc, f.Comment = nil, nil
+		switch t := f.Type.(type) {
+		case *ast.StructType:
+			structType := *t
+			structType.Fields, _ = trimFieldList(t.Fields, len(t.Fields.List))
+			f.Type = &structType
+		case *ast.InterfaceType:
+			interfaceType := *t
+			interfaceType.Methods, _ = trimFieldList(t.Methods, len(t.Methods.List))
+			f.Type = &interfaceType
+		}
+		trimmed.List = append(trimmed.List, &f)
+	}
+	return &trimmed, omitted
+}
+
+// valueSignature renders the declaration of one name in a const or var spec. Resolved
+// constants show their value, followed by the expression when that differs:
+//
+//	const MaxBatchSize = 100
+//	const KindMethod ChunkType = 2 // iota
+//	var ErrNotFound = errors.New("not found")
+//
+// A const spec without values repeats the type and expressions of source, the last
+// spec in its group that had them.
+func (e *symbolExtractor) valueSignature(tok token.Token, spec, source *ast.ValueSpec, i int) string {
+	name := spec.Names[i]
+
+	var sig strings.Builder
+	sig.WriteString(tok.String())
+	sig.WriteString(" ")
+	sig.WriteString(name.Name)
+	if source.Type != nil {
+		sig.WriteString(" ")
+		sig.WriteString(e.exprToString(source.Type))
+	}
+
+	expr := ""
+	switch {
+	case i < len(source.Values):
+		expr = valueToString(source.Values[i])
+	case len(source.Values) == 1:
+		// var a, b = f()
+		expr = valueToString(source.Values[0])
+	}
+
+	if tok == token.CONST {
+		if value := e.constValue(name); value != nil {
+			sig.WriteString(" = ")
+			sig.WriteString(constantToString(value))
+			if expr != "" && expr != constantToString(value) {
+				sig.WriteString(" // ")
+				sig.WriteString(expr)
+			}
+			return sig.String()
+		}
+	}
+
+	if expr != "" {
+		sig.WriteString(" = ")
+		sig.WriteString(expr)
+	}
+	return sig.String()
+}
+
+// constValue returns the value of a declared constant, or nil when it can't be
+// resolved. The file is type-checked on its own the first time a value is needed.
+// Imports are not followed, so constants depending on another package, or on another
+// file of this one, stay unresolved.
+func (e *symbolExtractor) constValue(name *ast.Ident) constant.Value {
+	if e.consts == nil {
+		e.consts = make(map[*ast.Ident]constant.Value)
+
+		info := &gotypes.Info{Defs: make(map[*ast.Ident]gotypes.Object)}
+		conf := gotypes.Config{
+			IgnoreFuncBodies: true,
+			Importer:         noImporter{},
+			Error:            func(error) {}, // Keep checking past unresolved imports
+		}
+		_, _ = conf.Check(e.packageName, e.fset, []*ast.File{e.file}, info)
+
+		for ident, obj := range info.Defs {
+			if c, ok := obj.(*gotypes.Const); ok && c.Val().Kind() != constant.Unknown {
+				e.consts[ident] = c.Val()
+			}
+		}
+	}
+	return e.consts[name]
+}
+
+// noImporter fails every import, keeping type-checking local to one file
+type noImporter struct{}
+
+// Import implements types.Importer
+func (noImporter) Import(path string) (*gotypes.Package, error) {
+	return nil, fmt.Errorf("import %q not followed", path)
+}
+
+// constantToString renders a constant value as Go source
+func constantToString(value constant.Value) string {
+	switch value.Kind() {
+	case constant.Float, constant.Complex:
+		return value.String()
+	default:
+		return truncate(value.ExactString(), maxSignatureValue)
+	}
+}
+
+// valueToString renders a value expression on one line; long composite and function
+// literals are reduced to their type: "map[string]int{...}"
+func valueToString(expr ast.Expr) string {
+	s := render(lineless, expr)
+	if len(s) <= maxSignatureValue && !strings.Contains(s, "\n") {
+		return s
+	}
+
+	switch v := expr.(type) {
+	case *ast.CompositeLit:
+		if v.Type != nil {
+			return render(lineless, v.Type) + "{...}"
+		}
+	case *ast.FuncLit:
+		return render(lineless, v.Type) + " {...}"
+	}
+	if i := strings.Index(s, "\n"); i >= 0 {
+		s = s[:i] + " ..."
+	}
+	return truncate(s, maxSignatureValue)
+}
+
+// truncate shortens s to at most n bytes on a rune boundary, marking the cut with "..."
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// parseStructTag splits a struct tag literal such as `json:"id" db:"user_id"` into
+// its keys, following the conventional format read by reflect.StructTag.Lookup.
+// Parsing stops at the first malformed pair, keeping the keys before it.
+func parseStructTag(literal string) []types.StructTag {
+	tag, err := strconv.Unquote(literal)
+	if err != nil {
+		return nil
+	}
+
+	var tags []types.StructTag
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			break
+		}
+
+		// Key: non-space, non-control characters up to the colon
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		// Quoted value, backslash escapes allowed
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			break
+		}
+		tag = tag[i+1:]
+
+		tags = append(tags, types.StructTag{Key: key, Value: value})
+	}
+	return tags
+}
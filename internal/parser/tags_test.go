@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+func TestParseStructTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		literal string
+		want    []types.StructTag
+	}{
+		{"single", "`json:\"id\"`", []types.StructTag{{Key: "json", Value: "id"}}},
+		{"several", "`json:\"created_at,omitempty\" db:\"created_at\"  validate:\"required\"`", []types.StructTag{
+			{Key: "json", Value: "created_at,omitempty"},
+			{Key: "db", Value: "created_at"},
+			{Key: "validate", Value: "required"},
+		}},
+		{"escaped quote", "`regexp:\"\\\"[a-z]+\\\"\"`", []types.StructTag{{Key: "regexp", Value: `"[a-z]+"`}}},
+		{"interpreted literal", `"json:\"name\""`, []types.StructTag{{Key: "json", Value: "name"}}},
+		{"malformed tail", "`json:\"id\" broken`", []types.StructTag{{Key: "json", Value: "id"}}},
+		{"not a tag", "`just text`", nil},
+		{"empty", "``", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseStructTag(tt.literal))
+		})
+	}
+
+	assert.Equal(t, "created_at", types.StructTag{Key: "json", Value: "created_at,omitempty"}.Name())
+	assert.Equal(t, "-", types.StructTag{Key: "json", Value: "-"}.Name())
+}
+
+func TestParseSource_StructTags(t *testing.T) {
+	content := "package model\n\n" +
+		"type User struct {\n" +
+		"\tID        int64  `json:\"id\" db:\"user_id\"`\n" +
+		"\tFirst, Last string `yaml:\"name\"`\n" +
+		"\tpassword  string\n" +
+		"}\n"
+
+	result, err := New().ParseSource("model.go", []byte(content))
+	require.NoError(t, err)
+
+	fields := make(map[string]types.Symbol)
+	for _, sym := range result.Symbols {
+		if sym.Kind == types.KindField {
+			fields[sym.Name] = sym
+		}
+	}
+	require.Len(t, fields, 4)
+
+	assert.Equal(t, "ID int64 `json:\"id\" db:\"user_id\"`", fields["ID"].Signature)
+	assert.Equal(t, []types.StructTag{{Key: "json", Value: "id"}, {Key: "db", Value: "user_id"}}, fields["ID"].Tags)
+	assert.Equal(t, []types.StructTag{{Key: "yaml", Value: "name"}}, fields["Last"].Tags, "names sharing a field share its tag")
+	assert.Equal(t, "password string", fields["password"].Signature)
+	assert.Nil(t, fields["password"].Tags)
+}
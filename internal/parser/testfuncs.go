@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// testPrefixes maps test function name prefixes to their kinds, per "go test"
+var testPrefixes = []struct {
+	prefix string
+	kind   types.TestKind
+}{
+	{"Test", types.TestKindTest},
+	{"Benchmark", types.TestKindBenchmark},
+	{"Fuzz", types.TestKindFuzz},
+	{"Example", types.TestKindExample},
+}
+
+// builtins are predeclared functions, never linked to indexed symbols
+var builtins = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true, "copy": true,
+	"delete": true, "imag": true, "len": true, "make": true, "max": true, "min": true, "new": true,
+	"panic": true, "print": true, "println": true, "real": true, "recover": true,
+}
+
+// majorVersionRE matches the major version element of a module path, e.g. "v2"
+var majorVersionRE = regexp.MustCompile(`^v[0-9]+$`)
+
+// extractTests finds the test functions of a _test.go file with the functions they call
+func extractTests(fset *token.FileSet, file *ast.File, imports []types.Import) []types.TestFunc {
+	packages := importNames(imports)
+
+	var tests []types.TestFunc
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Body == nil {
+			continue
+		}
+		kind, suffix, ok := testKind(fn)
+		if !ok {
+			continue
+		}
+
+		start, end := fset.Position(fn.Pos()), fset.Position(fn.End())
+		test := types.TestFunc{
+			Name:   fn.Name.Name,
+			Kind:   kind,
+			Start:  types.Position{Line: start.Line, Column: start.Column},
+			End:    types.Position{Line: end.Line, Column: end.Column},
+			Target: testTarget(kind, suffix),
+		}
+		var param string
+		if params := fn.Type.Params.List; len(params) == 1 && len(params[0].Names) == 1 {
+			param = params[0].Names[0].Name
+		}
+		test.Calls = extractCalls(fn.Body, packages, param)
+		tests = append(tests, test)
+	}
+	return tests
+}
+
+// testKind recognizes "go test" functions: TestXxx(t *testing.T), BenchmarkXxx(b *testing.B),
+// FuzzXxx(f *testing.F) and ExampleXxx(), returning the kind and the name after the prefix
+func testKind(fn *ast.FuncDecl) (types.TestKind, string, bool) {
+	name := fn.Name.Name
+	if name == "TestMain" {
+		return "", "", false
+	}
+	for _, p := range testPrefixes {
+		suffix, ok := strings.CutPrefix(name, p.prefix)
+		if !ok {
+			continue
+		}
+		// "Testify" is not a test; the prefix must end the word
+		if r, _ := utf8.DecodeRuneInString(suffix); suffix != "" && unicode.IsLower(r) {
+			return "", "", false
+		}
+		params := fn.Type.Params.NumFields()
+		if (p.kind == types.TestKindExample) != (params == 0) || params > 1 {
+			return "", "", false
+		}
+		return p.kind, suffix, true
+	}
+	return "", "", false
+}
+
+// testTarget derives the symbol a test is named after. "SQLiteStorage_GetChunk" names
+// SQLiteStorage.GetChunk, though for tests the second part may equally be a case
+// ("Parse_Empty"); both readings are tried when matching. Examples follow the godoc
+// convention: "T_M" is method T.M, a lowercase suffix ("F_second") is dropped and a
+// leading underscore marks a package example with no target.
+func testTarget(kind types.TestKind, suffix string) string {
+	if kind == types.TestKindExample && strings.HasPrefix(suffix, "_") {
+		return ""
+	}
+
+	var parts []string
+	for _, part := range strings.Split(suffix, "_") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	switch {
+	case len(parts) == 0:
+		return ""
+	case len(parts) == 1:
+		return parts[0]
+	case kind == types.TestKindExample:
+		if r, _ := utf8.DecodeRuneInString(parts[1]); !unicode.IsUpper(r) {
+			return parts[0]
+		}
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// extractCalls lists the distinct calls in a test body. Calls on the test's own
+// *testing.T (or B, F) parameter and builtins are skipped.
+func extractCalls(body *ast.BlockStmt, packages map[string]string, param string) []types.TestCall {
+	var calls []types.TestCall
+	seen := make(map[types.TestCall]bool)
+	add := func(c types.TestCall) {
+		if !seen[c] {
+			seen[c] = true
+			calls = append(calls, c)
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		switch fun := unwrapCallee(call.Fun).(type) {
+		case *ast.Ident:
+			if !builtins[fun.Name] {
+				add(types.TestCall{Name: fun.Name})
+			}
+		case *ast.SelectorExpr:
+			if x, ok := fun.X.(*ast.Ident); ok {
+				if importPath, ok := packages[x.Name]; ok {
+					add(types.TestCall{Package: importPath, Name: fun.Sel.Name})
+					break
+				}
+				if x.Name == param {
+					break
+				}
+			}
+			add(types.TestCall{Name: fun.Sel.Name, Method: true})
+		}
+		return true
+	})
+	return calls
+}
+
+// unwrapCallee strips parentheses and generic instantiations from a called expression
+func unwrapCallee(expr ast.Expr) ast.Expr {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		default:
+			return expr
+		}
+	}
+}
+
+// importNames maps the names imported packages are referred to by to their import
+// paths. Without type information, unaliased packages are assumed to be named after
+// the last path element, skipping a major version ("/v2"), a ".v3" suffix and a
+// "go-" prefix.
+func importNames(imports []types.Import) map[string]string {
+	names := make(map[string]string, len(imports))
+	for _, imp := range imports {
+		name := imp.Alias
+		if name == "" {
+			elem := path.Base(imp.Path)
+			if majorVersionRE.MatchString(elem) && path.Dir(imp.Path) != "." {
+				elem = path.Base(path.Dir(imp.Path))
+			}
+			name, _, _ = strings.Cut(elem, ".")
+			name = strings.ReplaceAll(strings.TrimPrefix(name, "go-"), "-", "_")
+		}
+		if name != "_" && name != "." {
+			names[name] = imp.Path
+		}
+	}
+	return names
+}
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+const testSource = `package storage_test
+
+import (
+	"fmt"
+	"testing"
+
+	sq "example.com/app/internal/storage"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMain(m *testing.M) {}
+
+func TestSQLiteStorage_GetChunk(t *testing.T) {
+	s := sq.NewSQLiteStorage(":memory:")
+	t.Run("missing", func(t *testing.T) {
+		_, err := s.GetChunk(1)
+		check(t, err)
+	})
+	_ = len(yaml.Marshal(s))
+}
+
+func BenchmarkSearch(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		(sq.Search[int])(nil)
+	}
+}
+
+func FuzzParse_Empty(f *testing.F) {}
+
+func ExampleStore_Load() { fmt.Println() }
+
+func ExampleStore_second() {}
+
+func Example_package() {}
+
+func Testify(t *testing.T) {}
+
+func TestHelperArgs(t *testing.T, n int) {}
+
+func check(t *testing.T, err error) {}
+`
+
+func TestParseSource_Tests(t *testing.T) {
+	result, err := New().ParseSource("storage_test.go", []byte(testSource))
+	require.NoError(t, err)
+
+	var names, targets []string
+	kinds := make(map[string]types.TestKind)
+	for _, test := range result.Tests {
+		names = append(names, test.Name)
+		targets = append(targets, test.Target)
+		kinds[test.Name] = test.Kind
+	}
+	assert.Equal(t, []string{"TestSQLiteStorage_GetChunk", "BenchmarkSearch", "FuzzParse_Empty",
+		"ExampleStore_Load", "ExampleStore_second", "Example_package"}, names)
+	assert.Equal(t, []string{"SQLiteStorage.GetChunk", "Search", "Parse.Empty", "Store.Load", "Store", ""}, targets)
+	assert.Equal(t, types.TestKindBenchmark, kinds["BenchmarkSearch"])
+	assert.Equal(t, types.TestKindFuzz, kinds["FuzzParse_Empty"])
+	assert.Equal(t, types.TestKindExample, kinds["Example_package"])
+
+	test := result.Tests[0]
+	assert.Equal(t, 13, test.Start.Line)
+	assert.Equal(t, 20, test.End.Line)
+	assert.Equal(t, []types.TestCall{
+		{Package: "example.com/app/internal/storage", Name: "NewSQLiteStorage"},
+		{Name: "GetChunk", Method: true},
+		{Name: "check"},
+		{Package: "gopkg.in/yaml.v3", Name: "Marshal"},
+	}, test.Calls)
+	assert.Equal(t, []types.TestCall{{Package: "example.com/app/internal/storage", Name: "Search"}}, result.Tests[1].Calls)
+
+	result, err = New().ParseSource("storage.go", []byte(testSource))
+	require.NoError(t, err)
+	assert.Empty(t, result.Tests)
+}
@@ -94,8 +94,15 @@
 //   - SymbolTypes: function, method, struct, interface, type
 //   - Packages: Package names to include
 //   - DDDPatterns: repository, service, entity, aggregate, etc.
+//   - Generic: Only generic functions and types
+//   - Constraints: Type parameter constraints such as "comparable"
+//   - MinMetrics: Minimum complexity of functions and methods (cyclomatic,
+//     cognitive, lines, params, nesting, returns)
 //   - MinScore: Minimum relevance score (0.0-1.0)
 //
+// SortBy orders the best candidates by one of those metrics instead of
+// relevance, e.g. SortBy: types.MetricCognitive for the hardest code on a topic.
+//
 // # Relevance Scoring
 //
 // Relevance scores are normalized to [0, 1]:
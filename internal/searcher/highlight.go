@@ -0,0 +1,364 @@
+package searcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/dshills/gocontext-mcp/internal/embedder"
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/internal/tokenize"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+const (
+	// maxLineMatches caps the number of line matches reported per result
+	maxLineMatches = 10
+
+	// maxSimilarityLines caps how many lines of a chunk are embedded for line similarity
+	maxSimilarityLines = 40
+
+	// maxSimilarityMatches is the number of best lines reported for similarity-only hits
+	maxSimilarityMatches = 3
+
+	// snippetContextLines is the number of lines kept around each match in snippets
+	snippetContextLines = 1
+
+	// maxStemSuffix is the longest suffix tolerated when matching a term against a word
+	// ("chunk" matches "chunks", "index" matches "indexer")
+	maxStemSuffix = 3
+)
+
+// annotateMatches fills MatchedTerms, Matches and Snippet for each result.
+// Keyword hits are located with FTS5 highlight() plus identifier-part matching;
+// results without any term on a line fall back to per-line embedding similarity.
+// Failures are logged and leave results without hit locations.
+func (s *Searcher) annotateMatches(ctx context.Context, req SearchRequest, results []types.SearchResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	terms := uniqueTerms(req.Query)
+
+	var highlighted map[int64]string
+	if req.Mode != SearchModeVector {
+		chunkIDs := make([]int64, len(results))
+		for i, r := range results {
+			chunkIDs[i] = r.ChunkID
+		}
+		h, err := s.storage.HighlightChunks(ctx, chunkIDs, req.Query)
+		if err != nil {
+			log.Printf("Warning: failed to highlight search results: %v", err)
+		} else {
+			highlighted = h
+		}
+	}
+
+	var unmatched []int
+	for i := range results {
+		r := &results[i]
+		r.Matches = lexicalLineMatches(r.Content, highlighted[r.ChunkID], startLine(r), terms)
+		if len(r.Matches) == 0 {
+			unmatched = append(unmatched, i)
+		}
+	}
+
+	if req.Mode != SearchModeKeyword && len(unmatched) > 0 {
+		if err := s.similarityLineMatches(ctx, req.Query, results, unmatched); err != nil {
+			log.Printf("Warning: failed to score result lines by similarity: %v", err)
+		}
+	}
+
+	for i := range results {
+		r := &results[i]
+		r.MatchedTerms = matchedTerms(r.Matches, terms)
+		r.Snippet = buildSnippet(r.Content, startLine(r), r.Matches)
+	}
+}
+
+// startLine returns the file line number of the first content line of a result
+func startLine(r *types.SearchResult) int {
+	if r.File == nil || r.File.StartLine < 1 {
+		return 1
+	}
+	return r.File.StartLine
+}
+
+// lexicalLineMatches finds lines containing query terms. highlighted is the FTS5
+// highlight() output for the same content, or empty if the chunk had no FTS hit.
+func lexicalLineMatches(content, highlighted string, firstLine int, terms []string) []types.LineMatch {
+	if len(terms) == 0 || content == "" {
+		return nil
+	}
+
+	lines := strings.Split(content, "\n")
+	var hlLines []string
+	if highlighted != "" {
+		hlLines = strings.Split(highlighted, "\n")
+		if len(hlLines) != len(lines) {
+			hlLines = nil // Content changed shape; rely on identifier matching only
+		}
+	}
+
+	var matches []types.LineMatch
+	for i, line := range lines {
+		found := make(map[string]bool)
+		var lineTerms []string
+		add := func(term string) {
+			if !found[term] {
+				found[term] = true
+				lineTerms = append(lineTerms, term)
+			}
+		}
+
+		if hlLines != nil {
+			for _, token := range highlightedTokens(hlLines[i]) {
+				add(queryTermFor(token, terms))
+			}
+		}
+		for _, word := range tokenize.Words(line) {
+			for _, term := range terms {
+				if wordMatchesTerm(word, term) {
+					add(term)
+				}
+			}
+		}
+
+		if len(lineTerms) == 0 {
+			continue
+		}
+		matches = append(matches, types.LineMatch{
+			Line:  firstLine + i,
+			Text:  strings.TrimSpace(line),
+			Terms: lineTerms,
+			Score: math.Min(1, float64(len(lineTerms))/float64(len(terms))),
+		})
+	}
+
+	return topLineMatches(matches, maxLineMatches)
+}
+
+// highlightedTokens extracts the lowercase tokens wrapped in highlight markers
+func highlightedTokens(line string) []string {
+	var tokens []string
+	for {
+		start := strings.Index(line, storage.HighlightStart)
+		if start < 0 {
+			return tokens
+		}
+		line = line[start+len(storage.HighlightStart):]
+		end := strings.Index(line, storage.HighlightEnd)
+		if end < 0 {
+			return tokens
+		}
+		if token := strings.ToLower(line[:end]); token != "" {
+			tokens = append(tokens, token)
+		}
+		line = line[end+len(storage.HighlightEnd):]
+	}
+}
+
+// queryTermFor maps an FTS5 hit back to the query term it matched.
+// Stemmed hits that match no term verbatim are reported as-is.
+func queryTermFor(token string, terms []string) string {
+	for _, term := range terms {
+		if wordMatchesTerm(token, term) {
+			return term
+		}
+	}
+	return token
+}
+
+// wordMatchesTerm reports whether a word, or one of its identifier parts, matches a query term
+func wordMatchesTerm(word, term string) bool {
+	if partMatchesTerm(strings.ToLower(word), term) {
+		return true
+	}
+	for _, part := range tokenize.SplitIdentifier(word) {
+		if partMatchesTerm(part, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// partMatchesTerm compares a lowercase word part with a query term, tolerating short suffixes
+func partMatchesTerm(part, term string) bool {
+	if part == term {
+		return true
+	}
+	return len(term) >= 3 && strings.HasPrefix(part, term) && len(part)-len(term) <= maxStemSuffix
+}
+
+// similarityLineMatches embeds the lines of the results at idx and reports the lines
+// most similar to the query. Used for vector hits that share no terms with the query.
+func (s *Searcher) similarityLineMatches(ctx context.Context, query string, results []types.SearchResult, idx []int) error {
+	type lineRef struct {
+		result int
+		line   int
+		text   string
+	}
+
+	var refs []lineRef
+	for _, i := range idx {
+		lines := strings.Split(results[i].Content, "\n")
+		count := 0
+		for j, line := range lines {
+			text := strings.TrimSpace(line)
+			if !isSignificantLine(text) {
+				continue
+			}
+			refs = append(refs, lineRef{result: i, line: startLine(&results[i]) + j, text: text})
+			count++
+			if count >= maxSimilarityLines {
+				break
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	queryEmb, err := s.embedder.GenerateEmbedding(ctx, embedder.EmbeddingRequest{Text: query})
+	if err != nil {
+		return fmt.Errorf("embed query: %w", err)
+	}
+
+	vectors := make([][]float32, 0, len(refs))
+	for start := 0; start < len(refs); start += embedder.MaxBatchSize {
+		end := min(start+embedder.MaxBatchSize, len(refs))
+		texts := make([]string, 0, end-start)
+		for _, ref := range refs[start:end] {
+			texts = append(texts, ref.text)
+		}
+
+		resp, err := s.embedder.GenerateBatch(ctx, embedder.BatchEmbeddingRequest{Texts: texts})
+		if err != nil {
+			return fmt.Errorf("embed lines: %w", err)
+		}
+		if len(resp.Embeddings) != len(texts) {
+			return fmt.Errorf("embed lines: expected %d embeddings, got %d", len(texts), len(resp.Embeddings))
+		}
+		for _, emb := range resp.Embeddings {
+			vectors = append(vectors, emb.Vector)
+		}
+	}
+
+	byResult := make(map[int][]types.LineMatch)
+	for i, ref := range refs {
+		score := cosine(queryEmb.Vector, vectors[i])
+		if score <= 0 {
+			continue
+		}
+		byResult[ref.result] = append(byResult[ref.result], types.LineMatch{
+			Line:  ref.line,
+			Text:  ref.text,
+			Score: math.Min(1, score),
+		})
+	}
+
+	for i, matches := range byResult {
+		results[i].Matches = topLineMatches(matches, maxSimilarityMatches)
+	}
+	return nil
+}
+
+// isSignificantLine skips blank lines and lone punctuation such as closing braces
+func isSignificantLine(text string) bool {
+	return len(tokenize.Words(text)) > 0
+}
+
+// topLineMatches keeps the n highest-scoring matches, returned in line order
+func topLineMatches(matches []types.LineMatch, n int) []types.LineMatch {
+	if len(matches) > n {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].Score > matches[j].Score
+		})
+		matches = matches[:n]
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Line < matches[j].Line
+	})
+	return matches
+}
+
+// matchedTerms returns the distinct terms found across all line matches, in query order
+// followed by any stemmed FTS5 hits
+func matchedTerms(matches []types.LineMatch, terms []string) []string {
+	found := make(map[string]bool)
+	for _, m := range matches {
+		for _, t := range m.Terms {
+			found[t] = true
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(found))
+	for _, t := range terms {
+		if found[t] {
+			result = append(result, t)
+			delete(found, t)
+		}
+	}
+	extra := make([]string, 0, len(found))
+	for t := range found {
+		extra = append(extra, t)
+	}
+	sort.Strings(extra)
+	return append(result, extra...)
+}
+
+// buildSnippet renders matched lines with snippetContextLines of context, prefixed
+// with file line numbers. Non-adjacent ranges are separated by "...".
+func buildSnippet(content string, firstLine int, matches []types.LineMatch) string {
+	if len(matches) == 0 || content == "" {
+		return ""
+	}
+
+	lines := strings.Split(content, "\n")
+	keep := make([]bool, len(lines))
+	for _, m := range matches {
+		idx := m.Line - firstLine
+		for j := idx - snippetContextLines; j <= idx+snippetContextLines; j++ {
+			if j >= 0 && j < len(lines) {
+				keep[j] = true
+			}
+		}
+	}
+
+	var b strings.Builder
+	prev := -1
+	for i, line := range lines {
+		if !keep[i] {
+			continue
+		}
+		if prev >= 0 && i > prev+1 {
+			b.WriteString("...\n")
+		}
+		fmt.Fprintf(&b, "%d: %s\n", firstLine+i, line)
+		prev = i
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// cosine returns the cosine similarity of two vectors, or 0 if either is zero
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
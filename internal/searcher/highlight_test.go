@@ -0,0 +1,174 @@
+package searcher
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dshills/gocontext-mcp/internal/embedder"
+	"github.com/dshills/gocontext-mcp/internal/storage"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+const highlightContent = `func (s *Store) DeleteChunksBatch(ids []int64) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	// remove every chunk in one statement
+	return s.exec(ids)
+}`
+
+func TestLexicalLineMatches(t *testing.T) {
+	terms := uniqueTerms("delete chunk")
+	matches := lexicalLineMatches(highlightContent, "", 10, terms)
+
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 line matches, got %d: %+v", len(matches), matches)
+	}
+
+	first := matches[0]
+	if first.Line != 10 {
+		t.Errorf("first match line = %d, want 10", first.Line)
+	}
+	if !reflect.DeepEqual(first.Terms, []string{"delete", "chunk"}) {
+		t.Errorf("first match terms = %v", first.Terms)
+	}
+	if first.Score != 1 {
+		t.Errorf("first match score = %f, want 1", first.Score)
+	}
+
+	second := matches[1]
+	if second.Line != 14 || second.Text != "// remove every chunk in one statement" {
+		t.Errorf("unexpected second match: %+v", second)
+	}
+	if !reflect.DeepEqual(second.Terms, []string{"chunk"}) {
+		t.Errorf("second match terms = %v", second.Terms)
+	}
+}
+
+func TestLexicalLineMatches_FTSHighlight(t *testing.T) {
+	content := "func run() {\n\tworkers.Running()\n}"
+	highlighted := "func run() {\n\tworkers." + storage.HighlightStart + "Running" + storage.HighlightEnd + "()\n}"
+
+	// "runs" shares a porter stem with "Running" but no prefix, so only FTS5 finds it
+	matches := lexicalLineMatches(content, highlighted, 1, uniqueTerms("runs"))
+	if len(matches) != 1 || matches[0].Line != 2 {
+		t.Fatalf("expected a single match on line 2, got %+v", matches)
+	}
+	if !reflect.DeepEqual(matches[0].Terms, []string{"running"}) {
+		t.Errorf("terms = %v, want stemmed hit [running]", matches[0].Terms)
+	}
+}
+
+func TestBuildSnippet(t *testing.T) {
+	matches := []types.LineMatch{{Line: 10}, {Line: 14}}
+	got := buildSnippet(highlightContent, 10, matches)
+	want := strings.Join([]string{
+		"10: func (s *Store) DeleteChunksBatch(ids []int64) (int, error) {",
+		"11: \tif len(ids) == 0 {",
+		"...",
+		"13: \t}",
+		"14: \t// remove every chunk in one statement",
+		"15: \treturn s.exec(ids)",
+	}, "\n")
+	if got != want {
+		t.Errorf("buildSnippet() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := buildSnippet(highlightContent, 10, nil); got != "" {
+		t.Errorf("expected empty snippet without matches, got %q", got)
+	}
+}
+
+func TestMatchedTerms(t *testing.T) {
+	matches := []types.LineMatch{
+		{Terms: []string{"chunk"}},
+		{Terms: []string{"running", "delete"}},
+	}
+	got := matchedTerms(matches, []string{"delete", "chunk"})
+	want := []string{"delete", "chunk", "running"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchedTerms() = %v, want %v", got, want)
+	}
+}
+
+// topicEmbedder embeds texts mentioning "token" along one axis and everything else along another
+type topicEmbedder struct{}
+
+func (e *topicEmbedder) vector(text string) []float32 {
+	if strings.Contains(strings.ToLower(text), "token") || strings.Contains(text, "credential") {
+		return []float32{1, 0}
+	}
+	return []float32{0, 1}
+}
+
+func (e *topicEmbedder) GenerateEmbedding(ctx context.Context, req embedder.EmbeddingRequest) (*embedder.Embedding, error) {
+	return &embedder.Embedding{Vector: e.vector(req.Text), Dimension: 2}, nil
+}
+
+func (e *topicEmbedder) GenerateBatch(ctx context.Context, req embedder.BatchEmbeddingRequest) (*embedder.BatchEmbeddingResponse, error) {
+	resp := &embedder.BatchEmbeddingResponse{}
+	for _, text := range req.Texts {
+		resp.Embeddings = append(resp.Embeddings, &embedder.Embedding{Vector: e.vector(text), Dimension: 2})
+	}
+	return resp, nil
+}
+
+func (e *topicEmbedder) Dimension() int   { return 2 }
+func (e *topicEmbedder) Provider() string { return "topic" }
+func (e *topicEmbedder) Model() string    { return "topic" }
+func (e *topicEmbedder) Close() error     { return nil }
+
+func TestSimilarityLineMatches(t *testing.T) {
+	s := &Searcher{embedder: &topicEmbedder{}}
+	results := []types.SearchResult{{
+		ChunkID: 1,
+		File:    &types.FileInfo{Path: "auth.go", StartLine: 20},
+		Content: "func check(r *http.Request) bool {\n\th := r.Header.Get(\"Authorization\")\n\treturn verifyToken(h)\n}",
+	}}
+
+	if err := s.similarityLineMatches(context.Background(), "credential validation", results, []int{0}); err != nil {
+		t.Fatalf("similarityLineMatches() error = %v", err)
+	}
+
+	matches := results[0].Matches
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 similarity match, got %+v", matches)
+	}
+	if matches[0].Line != 22 || matches[0].Score != 1 || len(matches[0].Terms) != 0 {
+		t.Errorf("unexpected similarity match: %+v", matches[0])
+	}
+}
+
+func TestSearchWithHighlight(t *testing.T) {
+	search, store, project := setupTestSearcher(t)
+	ctx := context.Background()
+
+	_, chunk := createTestFileAndChunk(t, store, project, "store.go", highlightContent)
+	addTestEmbedding(t, store, chunk.ID)
+
+	resp, err := search.Search(ctx, SearchRequest{
+		Query:     "chunk",
+		Mode:      SearchModeKeyword,
+		ProjectID: project.ID,
+		Highlight: true,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(resp.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(resp.Results))
+	}
+
+	r := resp.Results[0]
+	if !reflect.DeepEqual(r.MatchedTerms, []string{"chunk"}) {
+		t.Errorf("MatchedTerms = %v, want [chunk]", r.MatchedTerms)
+	}
+	if len(r.Matches) != 2 || r.Matches[0].Line != r.File.StartLine {
+		t.Errorf("unexpected matches: %+v", r.Matches)
+	}
+	if !strings.Contains(r.Snippet, "DeleteChunksBatch") {
+		t.Errorf("snippet missing matched line: %q", r.Snippet)
+	}
+}
@@ -0,0 +1,418 @@
+package searcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dshills/gocontext-mcp/internal/tokenize"
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+// Reranker names accepted in SearchRequest.Rerank
+const (
+	RerankNone         = "none"          // Keep first-stage order
+	RerankLexical      = "lexical"       // Built-in symbol/signature reranker
+	RerankRecency      = "recency"       // Boosts recently and frequently changed code (git history)
+	RerankCrossEncoder = "cross_encoder" // HTTP cross-encoder reranker
+)
+
+// DefaultRerankDepth is the minimum number of first-stage candidates passed to a reranker
+const DefaultRerankDepth = 30
+
+// Environment variables for the HTTP cross-encoder reranker
+const (
+	EnvRerankURL    = "GOCONTEXT_RERANK_URL"
+	EnvRerankAPIKey = "GOCONTEXT_RERANK_API_KEY"
+	EnvRerankModel  = "GOCONTEXT_RERANK_MODEL"
+)
+
+// Jina defaults used when only JINA_API_KEY is configured
+const (
+	DefaultJinaRerankURL   = "https://api.jina.ai/v1/rerank"
+	DefaultJinaRerankModel = "jina-reranker-v2-base-multilingual"
+)
+
+// ErrRerankerNotConfigured is returned when no cross-encoder endpoint is configured
+var ErrRerankerNotConfigured = errors.New("reranker not configured")
+
+// Reranker reorders first-stage search candidates
+type Reranker interface {
+	// Name returns the identifier used to select this reranker
+	Name() string
+
+	// Rerank returns candidates reordered by relevance to query.
+	// RelevanceScore of each returned result must be in [0, 1].
+	Rerank(ctx context.Context, query string, candidates []types.SearchResult) ([]types.SearchResult, error)
+}
+
+// Lexical reranker weights
+const (
+	lexicalWeightName      = 0.35
+	lexicalWeightSignature = 0.20
+	lexicalWeightContent   = 0.15
+	lexicalWeightExported  = 0.05
+	lexicalWeightPrior     = 0.25
+)
+
+// LexicalReranker scores candidates by symbol-name match, signature overlap and exportedness,
+// blended with the first-stage score
+type LexicalReranker struct{}
+
+// NewLexicalReranker creates the built-in lexical/structural reranker
+func NewLexicalReranker() *LexicalReranker {
+	return &LexicalReranker{}
+}
+
+// Name returns the reranker identifier
+func (l *LexicalReranker) Name() string {
+	return RerankLexical
+}
+
+// Rerank reorders candidates using lexical and structural signals
+func (l *LexicalReranker) Rerank(ctx context.Context, query string, candidates []types.SearchResult) ([]types.SearchResult, error) {
+	terms := uniqueTerms(query)
+	if len(terms) == 0 || len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	// Normalize first-stage scores so RRF and similarity scores are comparable
+	maxPrior := 0.0
+	for _, c := range candidates {
+		maxPrior = math.Max(maxPrior, c.RelevanceScore)
+	}
+
+	reranked := make([]types.SearchResult, len(candidates))
+	copy(reranked, candidates)
+
+	for i := range reranked {
+		r := &reranked[i]
+
+		var nameScore, sigScore, exported float64
+		if r.Symbol != nil {
+			nameScore = nameMatchScore(terms, r.Symbol.Name)
+			sigScore = termOverlap(terms, r.Symbol.Signature)
+			if r.Symbol.IsExported() {
+				exported = 1
+			}
+		}
+		contentScore := termOverlap(terms, r.Content)
+
+		prior := 0.0
+		if maxPrior > 0 {
+			prior = r.RelevanceScore / maxPrior
+		}
+
+		score := lexicalWeightName*nameScore +
+			lexicalWeightSignature*sigScore +
+			lexicalWeightContent*contentScore +
+			lexicalWeightExported*exported +
+			lexicalWeightPrior*prior
+		r.RelevanceScore = math.Min(1, math.Max(0, score))
+	}
+
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].RelevanceScore > reranked[j].RelevanceScore
+	})
+
+	return reranked, nil
+}
+
+// Recency reranker weights and scales
+const (
+	recencyWeightPrior   = 0.7
+	recencyWeightAge     = 0.2
+	recencyWeightChurn   = 0.1
+	recencyHalfLife      = 30 * 24 * time.Hour
+	recencyChurnSaturate = 10 // Change count scoring full churn
+)
+
+// RecencyReranker favors code whose lines changed recently or often, blended with
+// the first-stage score. Candidates without git history keep only their prior.
+type RecencyReranker struct {
+	now func() time.Time
+}
+
+// NewRecencyReranker creates the git history reranker
+func NewRecencyReranker() *RecencyReranker {
+	return &RecencyReranker{now: time.Now}
+}
+
+// Name returns the reranker identifier
+func (r *RecencyReranker) Name() string {
+	return RerankRecency
+}
+
+// Rerank reorders candidates by first-stage score, age and churn
+func (r *RecencyReranker) Rerank(ctx context.Context, query string, candidates []types.SearchResult) ([]types.SearchResult, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	maxPrior := 0.0
+	for _, c := range candidates {
+		maxPrior = math.Max(maxPrior, c.RelevanceScore)
+	}
+
+	now := r.now()
+	reranked := make([]types.SearchResult, len(candidates))
+	copy(reranked, candidates)
+
+	for i := range reranked {
+		c := &reranked[i]
+
+		prior := 0.0
+		if maxPrior > 0 {
+			prior = c.RelevanceScore / maxPrior
+		}
+
+		var age, churn float64
+		if h := c.History; h != nil {
+			if !h.LastModified.IsZero() {
+				elapsed := math.Max(0, float64(now.Sub(h.LastModified)))
+				age = math.Exp2(-elapsed / float64(recencyHalfLife))
+			}
+			churn = math.Min(1, math.Log1p(float64(h.ChangeCount))/math.Log1p(recencyChurnSaturate))
+		}
+
+		score := recencyWeightPrior*prior + recencyWeightAge*age + recencyWeightChurn*churn
+		c.RelevanceScore = math.Min(1, math.Max(0, score))
+	}
+
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].RelevanceScore > reranked[j].RelevanceScore
+	})
+
+	return reranked, nil
+}
+
+// nameMatchScore rewards exact symbol-name matches and partial identifier-part matches
+func nameMatchScore(terms []string, name string) float64 {
+	lowerName := strings.ToLower(name)
+	for _, term := range terms {
+		if term == lowerName {
+			return 1
+		}
+	}
+	return termOverlap(terms, name)
+}
+
+// termOverlap returns the fraction of query terms present among the identifier parts of text
+func termOverlap(terms []string, text string) float64 {
+	if len(terms) == 0 || text == "" {
+		return 0
+	}
+
+	parts := make(map[string]bool)
+	for _, p := range splitTerms(text) {
+		parts[p] = true
+	}
+
+	matched := 0
+	for _, term := range terms {
+		if parts[term] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(terms))
+}
+
+// uniqueTerms splits a query into distinct lowercase terms, including whole words
+// so that an exact identifier like "GetChunk" can match a symbol name
+func uniqueTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	add := func(t string) {
+		if t != "" && !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+
+	for _, word := range tokenize.Words(query) {
+		add(strings.ToLower(word))
+		for _, part := range tokenize.SplitIdentifier(word) {
+			add(part)
+		}
+	}
+	return terms
+}
+
+// splitTerms splits text into lowercase identifier parts
+func splitTerms(text string) []string {
+	var parts []string
+	for _, word := range tokenize.Words(text) {
+		parts = append(parts, strings.ToLower(word))
+		parts = append(parts, tokenize.SplitIdentifier(word)...)
+	}
+	return parts
+}
+
+// HTTPReranker calls a Jina/Cohere-style cross-encoder rerank API
+type HTTPReranker struct {
+	endpoint   string
+	apiKey     string
+	model      string
+	httpClient *http.Client
+}
+
+// NewHTTPReranker creates a cross-encoder reranker for the given endpoint
+func NewHTTPReranker(endpoint, apiKey, model string) *HTTPReranker {
+	return &HTTPReranker{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		model:    model,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// NewHTTPRerankerFromEnv creates a cross-encoder reranker from environment variables.
+// GOCONTEXT_RERANK_URL selects the endpoint; if unset and JINA_API_KEY is present,
+// the Jina rerank API is used.
+func NewHTTPRerankerFromEnv() (*HTTPReranker, error) {
+	endpoint := os.Getenv(EnvRerankURL)
+	apiKey := os.Getenv(EnvRerankAPIKey)
+	model := os.Getenv(EnvRerankModel)
+
+	if endpoint == "" {
+		jinaKey := os.Getenv("JINA_API_KEY")
+		if jinaKey == "" {
+			return nil, fmt.Errorf("%w: set %s or JINA_API_KEY", ErrRerankerNotConfigured, EnvRerankURL)
+		}
+		endpoint = DefaultJinaRerankURL
+		if apiKey == "" {
+			apiKey = jinaKey
+		}
+		if model == "" {
+			model = DefaultJinaRerankModel
+		}
+	}
+
+	return NewHTTPReranker(endpoint, apiKey, model), nil
+}
+
+// Name returns the reranker identifier
+func (h *HTTPReranker) Name() string {
+	return RerankCrossEncoder
+}
+
+// Rerank scores each candidate against the query with the remote cross-encoder
+func (h *HTTPReranker) Rerank(ctx context.Context, query string, candidates []types.SearchResult) ([]types.SearchResult, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	documents := make([]string, len(candidates))
+	for i, c := range candidates {
+		documents[i] = rerankDocument(c)
+	}
+
+	reqBody := map[string]interface{}{
+		"query":     query,
+		"documents": documents,
+		"top_n":     len(documents),
+	}
+	if h.model != "" {
+		reqBody["model"] = h.model
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal rerank request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", h.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create rerank request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.apiKey)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rerank api call: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("rerank api error %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var apiResp struct {
+		Results []struct {
+			Index          int     `json:"index"`
+			RelevanceScore float64 `json:"relevance_score"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("decode rerank response: %w", err)
+	}
+
+	reranked := make([]types.SearchResult, 0, len(candidates))
+	used := make([]bool, len(candidates))
+	for _, r := range apiResp.Results {
+		if r.Index < 0 || r.Index >= len(candidates) || used[r.Index] {
+			continue
+		}
+		used[r.Index] = true
+		result := candidates[r.Index]
+		result.RelevanceScore = normalizeRerankScore(r.RelevanceScore)
+		reranked = append(reranked, result)
+	}
+
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].RelevanceScore > reranked[j].RelevanceScore
+	})
+
+	// Keep candidates the API did not score, in first-stage order, after scored ones
+	for i, c := range candidates {
+		if !used[i] {
+			c.RelevanceScore = 0
+			reranked = append(reranked, c)
+		}
+	}
+
+	return reranked, nil
+}
+
+// rerankDocument builds the text sent to the cross-encoder for a candidate
+func rerankDocument(r types.SearchResult) string {
+	var doc strings.Builder
+	if r.File != nil {
+		doc.WriteString("// ")
+		doc.WriteString(r.File.Path)
+		doc.WriteString("\n")
+	}
+	if r.Symbol != nil && r.Symbol.DocComment != "" {
+		doc.WriteString("// ")
+		doc.WriteString(r.Symbol.DocComment)
+		doc.WriteString("\n")
+	}
+	doc.WriteString(r.Content)
+	return doc.String()
+}
+
+// normalizeRerankScore maps raw cross-encoder scores into [0, 1].
+// Scores already in range are kept; logits are passed through a sigmoid.
+func normalizeRerankScore(score float64) float64 {
+	if score >= 0 && score <= 1 {
+		return score
+	}
+	return 1 / (1 + math.Exp(-score))
+}
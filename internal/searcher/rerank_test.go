@@ -0,0 +1,272 @@
+package searcher
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
+)
+
+func rerankCandidate(id int64, name, signature, content string, score float64) types.SearchResult {
+	scope := types.ScopeUnexported
+	if name != "" && name[0] >= 'A' && name[0] <= 'Z' {
+		scope = types.ScopeExported
+	}
+	return types.SearchResult{
+		ChunkID:        id,
+		Rank:           int(id),
+		RelevanceScore: score,
+		Symbol: &types.Symbol{
+			Name:      name,
+			Kind:      types.KindFunction,
+			Signature: signature,
+			Scope:     scope,
+		},
+		File:    &types.FileInfo{Path: "file.go"},
+		Content: content,
+	}
+}
+
+func TestLexicalReranker_PromotesSymbolNameMatch(t *testing.T) {
+	candidates := []types.SearchResult{
+		rerankCandidate(1, "helper", "func helper()", "// deletes stuff", 0.033),
+		rerankCandidate(2, "listFiles", "func listFiles() []*File", "rows.Next()", 0.032),
+		rerankCandidate(3, "DeleteChunksBatch", "func DeleteChunksBatch(ids []int64) (int, error)", "DELETE FROM chunks", 0.030),
+	}
+
+	reranked, err := NewLexicalReranker().Rerank(context.Background(), "delete chunks batch", candidates)
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+
+	if reranked[0].ChunkID != 3 {
+		t.Errorf("top result = chunk %d, want 3 (DeleteChunksBatch)", reranked[0].ChunkID)
+	}
+	for i, r := range reranked {
+		if r.RelevanceScore < 0 || r.RelevanceScore > 1 {
+			t.Errorf("result %d score %f out of [0,1]", i, r.RelevanceScore)
+		}
+		if i > 0 && r.RelevanceScore > reranked[i-1].RelevanceScore {
+			t.Errorf("results not sorted by score at %d", i)
+		}
+	}
+
+	// Input slice must not be reordered in place
+	if candidates[0].ChunkID != 1 {
+		t.Error("Rerank modified input order")
+	}
+}
+
+func TestLexicalReranker_ExactNameWins(t *testing.T) {
+	candidates := []types.SearchResult{
+		rerankCandidate(1, "getChunkInternal", "func getChunkInternal()", "", 0.5),
+		rerankCandidate(2, "GetChunk", "func GetChunk(id int64) (*Chunk, error)", "", 0.4),
+	}
+
+	reranked, err := NewLexicalReranker().Rerank(context.Background(), "GetChunk", candidates)
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if reranked[0].ChunkID != 2 {
+		t.Errorf("top result = chunk %d, want exact match 2", reranked[0].ChunkID)
+	}
+}
+
+func TestRecencyReranker(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	withHistory := func(c types.SearchResult, age time.Duration, changes int) types.SearchResult {
+		c.History = &types.ChunkHistory{LastCommit: "abc", LastModified: now.Add(-age), ChangeCount: changes}
+		return c
+	}
+	candidates := []types.SearchResult{
+		withHistory(rerankCandidate(1, "Stale", "", "", 0.50), 2*365*24*time.Hour, 1),
+		rerankCandidate(2, "Unknown", "", "", 0.49),
+		withHistory(rerankCandidate(3, "Churned", "", "", 0.45), 24*time.Hour, 12),
+	}
+
+	reranker := &RecencyReranker{now: func() time.Time { return now }}
+	reranked, err := reranker.Rerank(context.Background(), "query", candidates)
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+
+	var order []int64
+	for _, r := range reranked {
+		order = append(order, r.ChunkID)
+		if r.RelevanceScore < 0 || r.RelevanceScore > 1 {
+			t.Errorf("chunk %d score %f out of [0,1]", r.ChunkID, r.RelevanceScore)
+		}
+	}
+	if !reflect.DeepEqual(order, []int64{3, 1, 2}) {
+		t.Errorf("order = %v, want [3 1 2]", order)
+	}
+	if candidates[0].ChunkID != 1 {
+		t.Error("Rerank modified input order")
+	}
+}
+
+func TestHTTPReranker_Rerank(t *testing.T) {
+	var gotReq struct {
+		Model     string   `json:"model"`
+		Query     string   `json:"query"`
+		Documents []string `json:"documents"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// Score the last document highest, omit the first one
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{
+				{"index": 2, "relevance_score": 0.9},
+				{"index": 1, "relevance_score": 0.2},
+			},
+		})
+	}))
+	defer server.Close()
+
+	reranker := NewHTTPReranker(server.URL, "secret", "test-reranker")
+	candidates := []types.SearchResult{
+		rerankCandidate(1, "a", "", "alpha", 0.3),
+		rerankCandidate(2, "b", "", "beta", 0.2),
+		rerankCandidate(3, "c", "", "gamma", 0.1),
+	}
+
+	reranked, err := reranker.Rerank(context.Background(), "find gamma", candidates)
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+
+	if gotReq.Query != "find gamma" || gotReq.Model != "test-reranker" || len(gotReq.Documents) != 3 {
+		t.Errorf("unexpected request: %+v", gotReq)
+	}
+
+	gotOrder := []int64{reranked[0].ChunkID, reranked[1].ChunkID, reranked[2].ChunkID}
+	if !reflect.DeepEqual(gotOrder, []int64{3, 2, 1}) {
+		t.Errorf("order = %v, want [3 2 1]", gotOrder)
+	}
+	if reranked[0].RelevanceScore != 0.9 {
+		t.Errorf("top score = %f, want 0.9", reranked[0].RelevanceScore)
+	}
+}
+
+func TestHTTPReranker_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	reranker := NewHTTPReranker(server.URL, "", "")
+	_, err := reranker.Rerank(context.Background(), "q", []types.SearchResult{rerankCandidate(1, "a", "", "x", 0.1)})
+	if err == nil {
+		t.Fatal("expected error for non-200 response")
+	}
+}
+
+func TestNewHTTPRerankerFromEnv(t *testing.T) {
+	t.Setenv(EnvRerankURL, "")
+	t.Setenv("JINA_API_KEY", "")
+	if _, err := NewHTTPRerankerFromEnv(); err == nil {
+		t.Error("expected error when no reranker is configured")
+	}
+
+	t.Setenv("JINA_API_KEY", "jina-key")
+	r, err := NewHTTPRerankerFromEnv()
+	if err != nil {
+		t.Fatalf("NewHTTPRerankerFromEnv() error = %v", err)
+	}
+	if r.endpoint != DefaultJinaRerankURL || r.apiKey != "jina-key" || r.model != DefaultJinaRerankModel {
+		t.Errorf("unexpected Jina defaults: %+v", r)
+	}
+}
+
+func TestNormalizeRerankScore(t *testing.T) {
+	if got := normalizeRerankScore(0.7); got != 0.7 {
+		t.Errorf("in-range score changed: %f", got)
+	}
+	if got := normalizeRerankScore(5); got <= 0.5 || got >= 1 {
+		t.Errorf("logit not squashed: %f", got)
+	}
+	if got := normalizeRerankScore(-5); got <= 0 || got >= 0.5 {
+		t.Errorf("negative logit not squashed: %f", got)
+	}
+}
+
+// failingReranker always returns an error
+type failingReranker struct{}
+
+func (f *failingReranker) Name() string { return "failing" }
+
+func (f *failingReranker) Rerank(ctx context.Context, query string, candidates []types.SearchResult) ([]types.SearchResult, error) {
+	return nil, context.DeadlineExceeded
+}
+
+func TestSearchWithRerank(t *testing.T) {
+	search, store, project := setupTestSearcher(t)
+	ctx := context.Background()
+
+	for _, c := range []struct{ path, content string }{
+		{"a.go", "func alpha() { chunk chunk chunk }"},
+		{"b.go", "func beta() { chunk }"},
+	} {
+		_, chunk := createTestFileAndChunk(t, store, project, c.path, c.content)
+		addTestEmbedding(t, store, chunk.ID)
+	}
+
+	t.Run("lexical", func(t *testing.T) {
+		resp, err := search.Search(ctx, SearchRequest{
+			Query:     "chunk",
+			Limit:     1,
+			Mode:      SearchModeKeyword,
+			ProjectID: project.ID,
+			Rerank:    RerankLexical,
+		})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if resp.Reranker != RerankLexical {
+			t.Errorf("Reranker = %q, want lexical", resp.Reranker)
+		}
+		if len(resp.Results) != 1 || resp.Results[0].Rank != 1 {
+			t.Errorf("expected exactly one result ranked 1, got %+v", resp.Results)
+		}
+	})
+
+	t.Run("unknown reranker", func(t *testing.T) {
+		_, err := search.Search(ctx, SearchRequest{
+			Query:     "chunk",
+			ProjectID: project.ID,
+			Rerank:    RerankCrossEncoder,
+		})
+		if err == nil {
+			t.Error("expected error for unregistered reranker")
+		}
+	})
+
+	t.Run("failing reranker falls back", func(t *testing.T) {
+		search.RegisterReranker(&failingReranker{})
+		resp, err := search.Search(ctx, SearchRequest{
+			Query:     "chunk",
+			Limit:     1,
+			Mode:      SearchModeKeyword,
+			ProjectID: project.ID,
+			Rerank:    "failing",
+		})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if resp.Reranker != "" || len(resp.Results) != 1 {
+			t.Errorf("expected first-stage fallback, got reranker=%q results=%d", resp.Reranker, len(resp.Results))
+		}
+	})
+}
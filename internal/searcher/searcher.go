@@ -4,6 +4,9 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"log"
+	"math"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -32,9 +35,14 @@ type SearchRequest struct {
 	Mode        SearchMode
 	Filters     *storage.SearchFilters
 	ProjectID   int64
-	UseCache    bool // Whether to use query cache
+	ProjectIDs  []int64 // Search several projects at once; overrides ProjectID when set
+	UseCache    bool    // Whether to use query cache
 	CacheTTL    time.Duration
 	RRFConstant float64 // k value for Reciprocal Rank Fusion (default 60)
+	Rerank      string  // Second-stage reranker name (default "none")
+	RerankDepth int     // Number of first-stage candidates passed to the reranker
+	Highlight   bool    // Locate matched terms and lines within each result
+	SortBy      string  // Order the best candidates by this metric (types.MetricNames), highest first; empty keeps relevance order
 }
 
 // SearchResponse contains search results and metadata
@@ -46,6 +54,8 @@ type SearchResponse struct {
 	CacheHit      bool
 	VectorResults int
 	TextResults   int
+	Reranker      string // Reranker applied to the results, empty if none
+	Projects      int    // Number of projects searched
 }
 
 // cacheEntry represents a cached search response with expiration time
@@ -56,10 +66,11 @@ type cacheEntry struct {
 
 // Searcher coordinates search operations across vector and text search
 type Searcher struct {
-	storage  storage.Storage
-	embedder embedder.Embedder
-	cache    *lru.Cache[[32]byte, *cacheEntry]
-	cacheMu  sync.RWMutex
+	storage   storage.Storage
+	embedder  embedder.Embedder
+	cache     *lru.Cache[[32]byte, *cacheEntry]
+	cacheMu   sync.RWMutex
+	rerankers map[string]Reranker
 }
 
 // NewSearcher creates a new Searcher instance
@@ -72,11 +83,31 @@ func NewSearcher(storage storage.Storage, embedder embedder.Embedder) *Searcher
 		panic(fmt.Sprintf("failed to create LRU cache: %v", err))
 	}
 
-	return &Searcher{
-		storage:  storage,
-		embedder: embedder,
-		cache:    cache,
+	s := &Searcher{
+		storage:   storage,
+		embedder:  embedder,
+		cache:     cache,
+		rerankers: make(map[string]Reranker),
 	}
+	s.RegisterReranker(NewLexicalReranker())
+	s.RegisterReranker(NewRecencyReranker())
+
+	return s
+}
+
+// RegisterReranker makes a reranker selectable by its name in SearchRequest.Rerank.
+// It must be called before the searcher is used concurrently.
+func (s *Searcher) RegisterReranker(r Reranker) {
+	s.rerankers[r.Name()] = r
+}
+
+// HasReranker reports whether a reranker with the given name is registered
+func (s *Searcher) HasReranker(name string) bool {
+	if name == "" || name == RerankNone {
+		return true
+	}
+	_, ok := s.rerankers[name]
+	return ok
 }
 
 // Search performs a search based on the request parameters
@@ -103,24 +134,48 @@ func (s *Searcher) Search(ctx context.Context, req SearchRequest) (*SearchRespon
 		}
 	}
 
-	// Perform search based on mode
-	var response *SearchResponse
+	// Retrieve first-stage candidates for one or several projects
+	var stage *stageResult
 	var err error
-
-	switch req.Mode {
-	case SearchModeHybrid:
-		response, err = s.hybridSearch(ctx, req)
-	case SearchModeVector:
-		response, err = s.vectorSearch(ctx, req)
-	case SearchModeKeyword:
-		response, err = s.keywordSearch(ctx, req)
-	default:
-		return nil, fmt.Errorf("unsupported search mode: %s", req.Mode)
+	if len(req.ProjectIDs) > 1 {
+		stage, err = s.crossProjectStage(ctx, req)
+	} else {
+		stage, err = s.runStage(ctx, req, nil)
+	}
+	if err != nil {
+		return nil, err
 	}
 
+	fetchReq := req
+	if req.SortBy != "" {
+		// Sort the best candidates rather than only the first page
+		fetchReq.Limit = req.RerankDepth
+	}
+	results, reranker, err := s.fetchAndRerank(ctx, fetchReq, stage.ranked)
 	if err != nil {
 		return nil, err
 	}
+	if req.SortBy != "" {
+		results = sortByMetric(results, req.SortBy, req.Limit)
+	}
+	if len(req.ProjectIDs) > 0 {
+		if err := s.setProjectRoots(ctx, results); err != nil {
+			return nil, err
+		}
+	}
+
+	response := &SearchResponse{
+		Results:       results,
+		TotalResults:  len(results),
+		VectorResults: stage.vectorResults,
+		TextResults:   stage.textResults,
+		Reranker:      reranker,
+		Projects:      max(1, len(req.ProjectIDs)),
+	}
+
+	if req.Highlight {
+		s.annotateMatches(ctx, req, response.Results)
+	}
 
 	response.Duration = time.Since(startTime)
 	response.SearchMode = req.Mode
@@ -142,14 +197,13 @@ type searchResult struct {
 }
 
 // runVectorSearch executes vector search in a goroutine
-func (s *Searcher) runVectorSearch(ctx context.Context, req SearchRequest, resultChan chan<- searchResult) {
+func (s *Searcher) runVectorSearch(ctx context.Context, req SearchRequest, queryVector []float32, resultChan chan<- searchResult) {
 	var res searchResult
-	embReq := embedder.EmbeddingRequest{Text: req.Query}
-	embedding, err := s.embedder.GenerateEmbedding(ctx, embReq)
+	vector, err := s.embedQuery(ctx, req.Query, queryVector)
 	if err != nil {
-		res.err = fmt.Errorf("failed to generate query embedding: %w", err)
+		res.err = err
 	} else {
-		res.vectorResults, res.err = s.storage.SearchVector(ctx, req.ProjectID, embedding.Vector, req.Limit*2, req.Filters)
+		res.vectorResults, res.err = s.storage.SearchVector(ctx, req.ProjectID, vector, hybridCandidateLimit(req), req.Filters)
 	}
 	select {
 	case resultChan <- res:
@@ -160,19 +214,145 @@ func (s *Searcher) runVectorSearch(ctx context.Context, req SearchRequest, resul
 // runTextSearch executes text search in a goroutine
 func (s *Searcher) runTextSearch(ctx context.Context, req SearchRequest, resultChan chan<- searchResult) {
 	var res searchResult
-	res.textResults, res.err = s.storage.SearchText(ctx, req.ProjectID, req.Query, req.Limit*2, req.Filters)
+	res.textResults, res.err = s.storage.SearchText(ctx, req.ProjectID, req.Query, hybridCandidateLimit(req), req.Filters)
 	select {
 	case resultChan <- res:
 	case <-ctx.Done():
 	}
 }
 
+// stageResult holds first-stage candidates before fetching and reranking
+type stageResult struct {
+	ranked        []rankedResult
+	vectorResults int
+	textResults   int
+}
+
+// runStage retrieves first-stage candidates for req.ProjectID using the requested mode.
+// queryVector is reused when non-nil, otherwise the query is embedded as needed.
+func (s *Searcher) runStage(ctx context.Context, req SearchRequest, queryVector []float32) (*stageResult, error) {
+	switch req.Mode {
+	case SearchModeHybrid:
+		return s.hybridSearch(ctx, req, queryVector)
+	case SearchModeVector:
+		return s.vectorSearch(ctx, req, queryVector)
+	case SearchModeKeyword:
+		return s.keywordSearch(ctx, req)
+	default:
+		return nil, fmt.Errorf("unsupported search mode: %s", req.Mode)
+	}
+}
+
+// crossProjectStage runs the first stage for every project concurrently and fuses the
+// per-project rankings with RRF. Ties (e.g. the top hit of each project) are broken by
+// first-stage score. A project that fails is logged and skipped unless all of them fail.
+func (s *Searcher) crossProjectStage(ctx context.Context, req SearchRequest) (*stageResult, error) {
+	// Embed the query once and share it across projects
+	var queryVector []float32
+	if req.Mode != SearchModeKeyword {
+		vector, err := s.embedQuery(ctx, req.Query, nil)
+		if err != nil && req.Mode == SearchModeVector {
+			return nil, err
+		}
+		queryVector = vector // nil on hybrid failure: each project falls back to text search
+	}
+
+	stages := make([]*stageResult, len(req.ProjectIDs))
+	errs := make([]error, len(req.ProjectIDs))
+	var wg sync.WaitGroup
+	for i, projectID := range req.ProjectIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sub := req
+			sub.ProjectID = projectID
+			sub.ProjectIDs = nil
+			stages[i], errs[i] = s.runStage(ctx, sub, queryVector)
+		}()
+	}
+	wg.Wait()
+
+	scores := make(map[int64]float64)
+	priors := make(map[int64]float64)
+	fused := &stageResult{}
+	succeeded := 0
+	for i, stage := range stages {
+		if errs[i] != nil {
+			log.Printf("Warning: search failed for project %d: %v", req.ProjectIDs[i], errs[i])
+			continue
+		}
+		succeeded++
+		fused.vectorResults += stage.vectorResults
+		fused.textResults += stage.textResults
+		for _, r := range stage.ranked {
+			scores[r.chunkID] += 1.0 / (req.RRFConstant + float64(r.rank))
+			priors[r.chunkID] = math.Max(priors[r.chunkID], r.score)
+		}
+	}
+	if succeeded == 0 {
+		return nil, fmt.Errorf("search failed for all %d projects: %w", len(req.ProjectIDs), errs[0])
+	}
+
+	ranked := make([]rankedResult, 0, len(scores))
+	for chunkID, score := range scores {
+		ranked = append(ranked, rankedResult{chunkID: chunkID, score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		if priors[ranked[i].chunkID] != priors[ranked[j].chunkID] {
+			return priors[ranked[i].chunkID] > priors[ranked[j].chunkID]
+		}
+		return ranked[i].chunkID < ranked[j].chunkID
+	})
+
+	fused.ranked = normalizeRRF(ranked, 1, req.RRFConstant, req.Filters)
+	return fused, nil
+}
+
+// setProjectRoots fills ProjectRoot, and Dependency for dependency projects, on results from their ProjectID
+func (s *Searcher) setProjectRoots(ctx context.Context, results []types.SearchResult) error {
+	projects, err := s.storage.ListProjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	byID := make(map[int64]*storage.Project, len(projects))
+	for _, p := range projects {
+		byID[p.ID] = p
+	}
+	for i := range results {
+		p, ok := byID[results[i].ProjectID]
+		if !ok {
+			continue
+		}
+		results[i].ProjectRoot = p.RootPath
+		if p.IsDependency() {
+			results[i].Dependency = p.ModuleID()
+		}
+	}
+	return nil
+}
+
+// embedQuery returns queryVector if already computed, otherwise embeds the query
+func (s *Searcher) embedQuery(ctx context.Context, query string, queryVector []float32) ([]float32, error) {
+	if queryVector != nil {
+		return queryVector, nil
+	}
+	embedding, err := s.embedder.GenerateEmbedding(ctx, embedder.EmbeddingRequest{Text: query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+	}
+	return embedding.Vector, nil
+}
+
 // hybridSearch combines vector and BM25 search using Reciprocal Rank Fusion
-func (s *Searcher) hybridSearch(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
+func (s *Searcher) hybridSearch(ctx context.Context, req SearchRequest, queryVector []float32) (*stageResult, error) {
 	vectorChan := make(chan searchResult, 1)
 	textChan := make(chan searchResult, 1)
 
-	go s.runVectorSearch(ctx, req, vectorChan)
+	go s.runVectorSearch(ctx, req, queryVector, vectorChan)
 	go s.runTextSearch(ctx, req, textChan)
 
 	// Wait for both searches
@@ -194,32 +374,24 @@ func (s *Searcher) hybridSearch(ctx context.Context, req SearchRequest) (*Search
 		return nil, fmt.Errorf("both searches failed: vector=%w, text=%v", vectorRes.err, textRes.err)
 	}
 
-	// Apply RRF and fetch results
+	// Apply RRF across the vector and text lists
 	rrf := s.applyRRF(vectorRes.vectorResults, textRes.textResults, req.RRFConstant)
-	results, err := s.fetchResults(ctx, rrf, req.Limit)
-	if err != nil {
-		return nil, err
-	}
 
-	return &SearchResponse{
-		Results:       results,
-		TotalResults:  len(results),
-		VectorResults: len(vectorRes.vectorResults),
-		TextResults:   len(textRes.textResults),
+	return &stageResult{
+		ranked:        normalizeRRF(rrf, 2, req.RRFConstant, req.Filters),
+		vectorResults: len(vectorRes.vectorResults),
+		textResults:   len(textRes.textResults),
 	}, nil
 }
 
 // vectorSearch performs only vector similarity search
-func (s *Searcher) vectorSearch(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
-	embReq := embedder.EmbeddingRequest{
-		Text: req.Query,
-	}
-	embedding, err := s.embedder.GenerateEmbedding(ctx, embReq)
+func (s *Searcher) vectorSearch(ctx context.Context, req SearchRequest, queryVector []float32) (*stageResult, error) {
+	vector, err := s.embedQuery(ctx, req.Query, queryVector)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+		return nil, err
 	}
 
-	vectorResults, err := s.storage.SearchVector(ctx, req.ProjectID, embedding.Vector, req.Limit, req.Filters)
+	vectorResults, err := s.storage.SearchVector(ctx, req.ProjectID, vector, candidateLimit(req), req.Filters)
 	if err != nil {
 		return nil, err
 	}
@@ -234,21 +406,15 @@ func (s *Searcher) vectorSearch(ctx context.Context, req SearchRequest) (*Search
 		}
 	}
 
-	results, err := s.fetchResults(ctx, rankedResults, req.Limit)
-	if err != nil {
-		return nil, err
-	}
-
-	return &SearchResponse{
-		Results:       results,
-		TotalResults:  len(results),
-		VectorResults: len(vectorResults),
+	return &stageResult{
+		ranked:        rankedResults,
+		vectorResults: len(vectorResults),
 	}, nil
 }
 
 // keywordSearch performs only BM25 text search
-func (s *Searcher) keywordSearch(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
-	textResults, err := s.storage.SearchText(ctx, req.ProjectID, req.Query, req.Limit, req.Filters)
+func (s *Searcher) keywordSearch(ctx context.Context, req SearchRequest) (*stageResult, error) {
+	textResults, err := s.storage.SearchText(ctx, req.ProjectID, req.Query, candidateLimit(req), req.Filters)
 	if err != nil {
 		return nil, err
 	}
@@ -263,15 +429,9 @@ func (s *Searcher) keywordSearch(ctx context.Context, req SearchRequest) (*Searc
 		}
 	}
 
-	results, err := s.fetchResults(ctx, rankedResults, req.Limit)
-	if err != nil {
-		return nil, err
-	}
-
-	return &SearchResponse{
-		Results:      results,
-		TotalResults: len(results),
-		TextResults:  len(textResults),
+	return &stageResult{
+		ranked:      rankedResults,
+		textResults: len(textResults),
 	}, nil
 }
 
@@ -322,6 +482,80 @@ func (s *Searcher) applyRRF(vectorResults []storage.VectorResult, textResults []
 	return results
 }
 
+// normalizeRRF scales scores fused from lists ranked lists into [0, 1], where 1 means
+// ranked first in every list, and drops results below the minimum relevance filter
+// so that MinRelevance applies to the score reported for fused results.
+func normalizeRRF(results []rankedResult, lists int, k float64, filters *storage.SearchFilters) []rankedResult {
+	if k == 0 {
+		k = 60 // Default RRF constant
+	}
+	maxScore := float64(lists) / (k + 1)
+
+	normalized := results[:0]
+	for _, r := range results {
+		r.score /= maxScore
+		if filters != nil && filters.MinRelevance > 0 && r.score < filters.MinRelevance {
+			continue
+		}
+		normalized = append(normalized, r)
+	}
+
+	for i := range normalized {
+		normalized[i].rank = i + 1
+	}
+	return normalized
+}
+
+// candidateLimit returns how many first-stage results to retrieve for single-mode searches
+func candidateLimit(req SearchRequest) int {
+	if req.Rerank == "" || req.Rerank == RerankNone {
+		return req.Limit
+	}
+	return req.RerankDepth
+}
+
+// hybridCandidateLimit returns how many results each hybrid sub-search retrieves before fusion
+func hybridCandidateLimit(req SearchRequest) int {
+	limit := req.Limit * 2
+	if req.Rerank != "" && req.Rerank != RerankNone && req.RerankDepth > limit {
+		limit = req.RerankDepth
+	}
+	return limit
+}
+
+// fetchAndRerank loads the top candidates, applies the requested reranker and truncates to the limit.
+// If the reranker fails, first-stage order is kept so search still succeeds.
+func (s *Searcher) fetchAndRerank(ctx context.Context, req SearchRequest, ranked []rankedResult) ([]types.SearchResult, string, error) {
+	reranker, ok := s.rerankers[req.Rerank]
+	if !ok {
+		results, err := s.fetchResults(ctx, ranked, req.Limit)
+		return results, "", err
+	}
+
+	candidates, err := s.fetchResults(ctx, ranked, req.RerankDepth)
+	if err != nil {
+		return nil, "", err
+	}
+
+	reranked, err := reranker.Rerank(ctx, req.Query, candidates)
+	if err != nil {
+		log.Printf("Warning: %s reranker failed, keeping first-stage order: %v", reranker.Name(), err)
+		if len(candidates) > req.Limit {
+			candidates = candidates[:req.Limit]
+		}
+		return candidates, "", nil
+	}
+
+	if len(reranked) > req.Limit {
+		reranked = reranked[:req.Limit]
+	}
+	for i := range reranked {
+		reranked[i].Rank = i + 1
+	}
+
+	return reranked, reranker.Name(), nil
+}
+
 // fetchResults retrieves full chunk data and metadata for ranked results
 func (s *Searcher) fetchResults(ctx context.Context, ranked []rankedResult, limit int) ([]types.SearchResult, error) {
 	if limit > len(ranked) {
@@ -360,16 +594,24 @@ func (s *Searcher) fetchResults(ctx context.Context, ranked []rankedResult, limi
 			ChunkID:        rr.chunkID,
 			Rank:           rr.rank,
 			RelevanceScore: rr.score,
+			ProjectID:      file.ProjectID,
 			Symbol:         symbol,
 			File: &types.FileInfo{
 				Path:      file.FilePath,
 				Package:   file.PackageName,
 				StartLine: chunk.StartLine,
 				EndLine:   chunk.EndLine,
+
+				BuildConstraint: file.Constraint().String(),
+				Module:          file.ModulePath,
 			},
 			Content: chunk.Content,
 			Context: fmt.Sprintf("%s\n\n%s", chunk.ContextBefore, chunk.ContextAfter),
 		}
+		if !chunk.History.IsZero() {
+			history := chunk.History
+			result.History = &history
+		}
 
 		results = append(results, result)
 	}
@@ -403,6 +645,46 @@ func (s *Searcher) validateRequest(req *SearchRequest) error {
 		req.CacheTTL = 1 * time.Hour // Default TTL
 	}
 
+	if req.Rerank == "" {
+		req.Rerank = RerankNone
+	}
+
+	// Deduplicate project IDs; a single project is searched directly
+	if len(req.ProjectIDs) > 0 {
+		seen := make(map[int64]bool, len(req.ProjectIDs))
+		ids := make([]int64, 0, len(req.ProjectIDs))
+		for _, id := range req.ProjectIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		req.ProjectIDs = ids
+		if len(ids) == 1 {
+			req.ProjectID = ids[0]
+		}
+	}
+
+	if !s.HasReranker(req.Rerank) {
+		return fmt.Errorf("reranker %q is not available", req.Rerank)
+	}
+
+	if req.SortBy != "" {
+		if _, ok := (types.Metrics{}).Get(req.SortBy); !ok {
+			return fmt.Errorf("unknown sort metric %q", req.SortBy)
+		}
+	}
+
+	if req.RerankDepth < req.Limit*3 {
+		req.RerankDepth = req.Limit * 3
+	}
+	if req.RerankDepth < DefaultRerankDepth {
+		req.RerankDepth = DefaultRerankDepth
+	}
+	if req.RerankDepth > 100 {
+		req.RerankDepth = 100 // Same cap as Limit
+	}
+
 	return nil
 }
 
@@ -472,6 +754,8 @@ func copySearchResponse(src *SearchResponse) *SearchResponse {
 		CacheHit:      src.CacheHit,
 		VectorResults: src.VectorResults,
 		TextResults:   src.TextResults,
+		Reranker:      src.Reranker,
+		Projects:      src.Projects,
 		Results:       make([]types.SearchResult, len(src.Results)),
 	}
 
@@ -481,19 +765,42 @@ func copySearchResponse(src *SearchResponse) *SearchResponse {
 			ChunkID:        result.ChunkID,
 			Rank:           result.Rank,
 			RelevanceScore: result.RelevanceScore,
+			ProjectID:      result.ProjectID,
+			ProjectRoot:    result.ProjectRoot,
+			Dependency:     result.Dependency,
 			Content:        result.Content,
 			Context:        result.Context,
+			MatchedTerms:   append([]string(nil), result.MatchedTerms...),
+			Snippet:        result.Snippet,
 		}
 
-		// Copy Symbol pointer if it exists
-		// Note: Symbol contains only primitive types and nested Position structs,
-		// so shallow copy is sufficient. If Symbol is modified to include slice/map
-		// fields in the future, this must be updated to deep copy those fields.
+		// Deep copy line matches so cached entries cannot be mutated through the copy
+		if result.Matches != nil {
+			dst.Results[i].Matches = make([]types.LineMatch, len(result.Matches))
+			for j, m := range result.Matches {
+				m.Terms = append([]string(nil), m.Terms...)
+				dst.Results[i].Matches[j] = m
+			}
+		}
+
+		// Copy Symbol pointer if it exists, with its slices so cached entries cannot
+		// be mutated through the copy. Their elements hold only strings and numbers.
+		// New slice or map fields on Symbol must be copied here too.
 		if result.Symbol != nil {
 			symbolCopy := *result.Symbol
+			symbolCopy.TypeParams = slices.Clone(symbolCopy.TypeParams)
+			symbolCopy.Tags = slices.Clone(symbolCopy.Tags)
+			symbolCopy.Embeds = slices.Clone(symbolCopy.Embeds)
+			symbolCopy.Refs = slices.Clone(symbolCopy.Refs)
+			symbolCopy.DDD = slices.Clone(symbolCopy.DDD)
 			dst.Results[i].Symbol = &symbolCopy
 		}
 
+		if result.History != nil {
+			historyCopy := *result.History
+			dst.Results[i].History = &historyCopy
+		}
+
 		// Copy FileInfo pointer if it exists
 		// Note: FileInfo contains only primitive types, so shallow copy is sufficient.
 		// If FileInfo is modified to include slice/map fields in the future, this must
@@ -516,6 +823,9 @@ func computeQueryHash(req SearchRequest) [32]byte {
 	data.WriteString(string(req.Mode))
 	data.WriteString("|")
 	data.WriteString(fmt.Sprintf("%d", req.ProjectID))
+	for _, id := range req.ProjectIDs {
+		data.WriteString(fmt.Sprintf(",%d", id))
+	}
 
 	// Add filters with stable serialization
 	if req.Filters != nil {
@@ -528,12 +838,59 @@ func computeQueryHash(req SearchRequest) [32]byte {
 		data.WriteString("|")
 		data.WriteString(strings.Join(req.Filters.Packages, ","))
 		data.WriteString("|")
+		data.WriteString(strings.Join(req.Filters.Modules, ","))
+		data.WriteString("|")
 		data.WriteString(fmt.Sprintf("%.2f", req.Filters.MinRelevance))
+		if req.Filters.Generic || len(req.Filters.Constraints) > 0 {
+			data.WriteString(fmt.Sprintf("|generic:%t:", req.Filters.Generic))
+			data.WriteString(strings.Join(req.Filters.Constraints, ";"))
+		}
+		if !req.Filters.ModifiedSince.IsZero() {
+			data.WriteString("|since:")
+			data.WriteString(req.Filters.ModifiedSince.UTC().Format(time.RFC3339))
+		}
+		if bc := req.Filters.BuildContext; bc != nil {
+			data.WriteString("|build:")
+			data.WriteString(bc.GOOS + "/" + bc.GOARCH + "/" + strings.Join(bc.Tags, ","))
+		}
+		if m := req.Filters.MinMetrics; m != (types.Metrics{}) {
+			data.WriteString(fmt.Sprintf("|metrics:%d,%d,%d,%d,%d,%d", m.Cyclomatic, m.Cognitive, m.Lines, m.Params, m.Nesting, m.Returns))
+		}
 	}
 
+	// Reranking changes result order, so it is part of the cache key
+	data.WriteString("|rerank:")
+	data.WriteString(req.Rerank)
+	data.WriteString(fmt.Sprintf("|highlight:%t", req.Highlight))
+	data.WriteString("|sort:")
+	data.WriteString(req.SortBy)
+
 	return sha256.Sum256([]byte(data.String()))
 }
 
+// sortByMetric orders results by a metric of their symbol, highest first, keeping
+// relevance order among equals, and returns the first limit. Results without a
+// function or method sort last.
+func sortByMetric(results []types.SearchResult, metric string, limit int) []types.SearchResult {
+	value := func(r types.SearchResult) int {
+		if r.Symbol == nil {
+			return -1
+		}
+		v, _ := r.Symbol.Metrics.Get(metric)
+		return v
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return value(results[i]) > value(results[j])
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	for i := range results {
+		results[i].Rank = i + 1
+	}
+	return results
+}
+
 // sortRankedResults sorts results by score in descending order
 func sortRankedResults(results []rankedResult) {
 	sort.Slice(results, func(i, j int) bool {
@@ -244,6 +244,22 @@ func TestValidateRequest(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "MetricSortBy",
+			req: SearchRequest{
+				Query:  "test",
+				SortBy: types.MetricCognitive,
+			},
+			expectError: false,
+		},
+		{
+			name: "UnknownSortBy",
+			req: SearchRequest{
+				Query:  "test",
+				SortBy: "complexity",
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -509,6 +525,32 @@ func TestSortRankedResults(t *testing.T) {
 	}
 }
 
+// TestSortByMetric tests ordering results by a symbol metric
+func TestSortByMetric(t *testing.T) {
+	result := func(chunkID int64, cyclomatic int) types.SearchResult {
+		r := types.SearchResult{ChunkID: chunkID, Rank: int(chunkID)}
+		if cyclomatic > 0 {
+			r.Symbol = &types.Symbol{Metrics: types.Metrics{Cyclomatic: cyclomatic}}
+		}
+		return r
+	}
+	results := []types.SearchResult{result(1, 3), result(2, 0), result(3, 9), result(4, 3), result(5, 12)}
+
+	sorted := sortByMetric(results, types.MetricCyclomatic, 4)
+	var ids []int64
+	for i, r := range sorted {
+		ids = append(ids, r.ChunkID)
+		if r.Rank != i+1 {
+			t.Errorf("position %d: expected rank %d, got %d", i, i+1, r.Rank)
+		}
+	}
+	// Equal metrics keep relevance order; results without a symbol sort last
+	expected := []int64{5, 3, 1, 4}
+	if fmt.Sprint(ids) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, ids)
+	}
+}
+
 // TestComputeQueryHash tests query hash computation
 func TestComputeQueryHash(t *testing.T) {
 	tests := []struct {
@@ -741,6 +783,34 @@ func TestInvalidateCache(t *testing.T) {
 	}
 }
 
+// TestCopySearchResponse tests that copies do not share the symbol's slices
+func TestCopySearchResponse(t *testing.T) {
+	src := &SearchResponse{Results: []types.SearchResult{{
+		Symbol: &types.Symbol{
+			Name:       "Repo",
+			TypeParams: []types.TypeParam{{Name: "T", Constraint: "any"}},
+			Tags:       []types.StructTag{{Key: "json", Value: "repo"}},
+			Embeds:     []string{"Base"},
+			Refs:       []types.Ref{{Kind: types.RefType, Name: "Order"}},
+			DDD:        []types.DDDMatch{{Pattern: "repository", Confidence: 0.9}},
+		},
+	}}}
+
+	dst := copySearchResponse(src)
+	sym := dst.Results[0].Symbol
+	sym.TypeParams[0].Name = "U"
+	sym.Tags[0].Value = "changed"
+	sym.Embeds[0] = "Other"
+	sym.Refs[0].Name = "Changed"
+	sym.DDD[0].Pattern = "entity"
+
+	orig := src.Results[0].Symbol
+	if orig.TypeParams[0].Name != "T" || orig.Tags[0].Value != "repo" || orig.Embeds[0] != "Base" ||
+		orig.Refs[0].Name != "Order" || orig.DDD[0].Pattern != "repository" {
+		t.Errorf("cached symbol mutated through the copy: %+v", orig)
+	}
+}
+
 // TestEvictLRU tests LRU eviction (stubbed)
 func TestEvictLRU(t *testing.T) {
 	cache, err := lru.New[[32]byte, *cacheEntry](10)
@@ -874,6 +944,64 @@ func TestSearchModeHybrid(t *testing.T) {
 }
 
 // TestSearchWithUnsupportedMode tests error handling for invalid mode
+// TestSearchModeHybrid_MinRelevance tests that hybrid scores are normalized and
+// that the minimum relevance applies to them
+func TestSearchModeHybrid_MinRelevance(t *testing.T) {
+	search, store, project := setupTestSearcher(t)
+	ctx := context.Background()
+
+	// Chunks as similar to the query as possible, so the vector stage keeps them
+	query, err := search.embedder.GenerateEmbedding(ctx, embedder.EmbeddingRequest{Text: "Test"})
+	if err != nil {
+		t.Fatalf("GenerateEmbedding failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		content := fmt.Sprintf("func Test%d() {}", i)
+		_, chunk := createTestFileAndChunk(t, store, project, fmt.Sprintf("test%d.go", i), content)
+		if err := store.UpsertEmbedding(ctx, &storage.Embedding{
+			ChunkID:   chunk.ID,
+			Vector:    storage.SerializeVector(query.Vector),
+			Dimension: len(query.Vector),
+			Provider:  "test",
+			Model:     "test-model",
+		}); err != nil {
+			t.Fatalf("UpsertEmbedding failed: %v", err)
+		}
+	}
+
+	req := SearchRequest{
+		Query:       "Test",
+		Limit:       10,
+		Mode:        SearchModeHybrid,
+		ProjectID:   project.ID,
+		RRFConstant: 60,
+	}
+
+	// Ranked first in one list scores 0.5, first in both 1
+	resp, err := search.Search(ctx, req)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(resp.Results) == 0 {
+		t.Fatal("expected results")
+	}
+	if top := resp.Results[0].RelevanceScore; top < 0.5 || top > 1 {
+		t.Errorf("top score %f, want normalized to [0.5, 1]", top)
+	}
+
+	// The minimum applies to the fused score, not only to each stage
+	req.Filters = &storage.SearchFilters{MinRelevance: 0.6}
+	resp, err = search.Search(ctx, req)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	for _, r := range resp.Results {
+		if r.RelevanceScore < 0.6 {
+			t.Errorf("chunk %d: score %f below min_relevance 0.6", r.ChunkID, r.RelevanceScore)
+		}
+	}
+}
+
 func TestSearchWithUnsupportedMode(t *testing.T) {
 	search, _, project := setupTestSearcher(t)
 	ctx := context.Background()
@@ -1477,7 +1605,7 @@ func TestT031_ChannelTimeoutHandling(t *testing.T) {
 
 		done := make(chan bool, 1)
 		go func() {
-			search.runVectorSearch(ctx, req, resultChan)
+			search.runVectorSearch(ctx, req, nil, resultChan)
 			done <- true
 		}()
 
@@ -1555,3 +1683,77 @@ func TestT031_ChannelTimeoutHandling(t *testing.T) {
 		}
 	})
 }
+
+// TestSearch_CrossProject tests fan-out and RRF fusion across several projects
+func TestSearch_CrossProject(t *testing.T) {
+	search, store, libProject := setupTestSearcher(t)
+	ctx := context.Background()
+
+	svcProject := &storage.Project{
+		RootPath:     "/test/service",
+		ModuleName:   "github.com/test/service",
+		IndexVersion: "1.0.0",
+	}
+	if err := store.CreateProject(ctx, svcProject); err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+
+	_, libChunk := createTestFileAndChunk(t, store, libProject, "money.go", "type Money struct { amount int64 }")
+	_, svcChunk := createTestFileAndChunk(t, store, svcProject, "billing.go", "func Charge(m lib.Money) error { return nil }")
+	_, _ = createTestFileAndChunk(t, store, svcProject, "other.go", "func unrelated() {}")
+	addTestEmbedding(t, store, libChunk.ID)
+	addTestEmbedding(t, store, svcChunk.ID)
+
+	for _, mode := range []SearchMode{SearchModeKeyword, SearchModeHybrid} {
+		t.Run(string(mode), func(t *testing.T) {
+			resp, err := search.Search(ctx, SearchRequest{
+				Query:      "Money",
+				Mode:       mode,
+				ProjectIDs: []int64{libProject.ID, svcProject.ID, libProject.ID},
+			})
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if resp.Projects != 2 {
+				t.Errorf("Projects = %d, want 2 (duplicates removed)", resp.Projects)
+			}
+
+			roots := make(map[int64]string)
+			for _, r := range resp.Results {
+				roots[r.ChunkID] = r.ProjectRoot
+			}
+			if roots[libChunk.ID] != libProject.RootPath {
+				t.Errorf("library result root = %q, want %q", roots[libChunk.ID], libProject.RootPath)
+			}
+			if roots[svcChunk.ID] != svcProject.RootPath {
+				t.Errorf("service result root = %q, want %q", roots[svcChunk.ID], svcProject.RootPath)
+			}
+
+			for i, r := range resp.Results {
+				if r.Rank != i+1 {
+					t.Errorf("result %d has rank %d", i, r.Rank)
+				}
+				if r.RelevanceScore <= 0 || r.RelevanceScore > 1 {
+					t.Errorf("result %d score %f out of (0,1]", i, r.RelevanceScore)
+				}
+			}
+		})
+	}
+
+	t.Run("single project id searched directly", func(t *testing.T) {
+		resp, err := search.Search(ctx, SearchRequest{
+			Query:      "Money",
+			Mode:       SearchModeKeyword,
+			ProjectIDs: []int64{svcProject.ID},
+		})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(resp.Results) != 1 || resp.Results[0].ChunkID != svcChunk.ID {
+			t.Fatalf("expected only the service chunk, got %+v", resp.Results)
+		}
+		if resp.Results[0].ProjectRoot != svcProject.RootPath {
+			t.Errorf("ProjectRoot = %q, want %q", resp.Results[0].ProjectRoot, svcProject.RootPath)
+		}
+	})
+}
@@ -99,6 +99,9 @@
 //	}
 //
 // FTS5 indexes are automatically updated when chunks are inserted.
+// Each chunk and symbol also stores identifier_terms, the camelCase and
+// snake_case parts of its compound identifiers, so "chunk" matches
+// DeleteChunksBatch. Queries are split the same way before matching.
 //
 // # Query Patterns
 //
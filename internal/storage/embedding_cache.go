@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Embedding cache operations
+//
+// These methods back embedder.PersistentCache. They are intentionally not part of
+// the Storage interface: the cache is keyed by text content rather than by project
+// data, and it is only ever accessed outside of indexing transactions.
+
+// cacheBatchSize bounds the text hashes per statement, well below SQLite's
+// limit on bound parameters
+const cacheBatchSize = 500
+
+// GetCachedEmbeddings looks up cached vectors by provider, model and text hash,
+// returning the hits by hash. Hits refresh their LRU timestamp in a single update
+// per batch of hashes; missing hashes are simply absent from the result.
+func (s *SQLiteStorage) GetCachedEmbeddings(ctx context.Context, provider, model string, textHashes []string) (map[string][]float32, error) {
+	hits := make(map[string][]float32)
+	now := time.Now().UnixNano()
+	for start := 0; start < len(textHashes); start += cacheBatchSize {
+		batch := textHashes[start:min(start+cacheBatchSize, len(textHashes))]
+		args := make([]interface{}, 0, len(batch)+3)
+		args = append(args, now, provider, model)
+		for _, hash := range batch {
+			args = append(args, hash)
+		}
+
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT text_hash, vector FROM embedding_cache
+			WHERE provider = ? AND model = ? AND text_hash IN (`+placeholders(len(batch))+`)
+		`, args[1:]...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedding cache: %w", err)
+		}
+		found := 0
+		for rows.Next() {
+			var hash string
+			var blob []byte
+			if err := rows.Scan(&hash, &blob); err != nil {
+				_ = rows.Close()
+				return nil, fmt.Errorf("failed to read embedding cache: %w", err)
+			}
+			hits[hash] = deserializeVector(blob)
+			found++
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read embedding cache: %w", err)
+		}
+		if found == 0 {
+			continue
+		}
+
+		_, err = s.db.ExecContext(ctx, `
+			UPDATE embedding_cache
+			SET last_used_at = ?, hit_count = hit_count + 1
+			WHERE provider = ? AND model = ? AND text_hash IN (`+placeholders(len(batch))+`)
+		`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh embedding cache: %w", err)
+		}
+	}
+	return hits, nil
+}
+
+// PutCachedEmbeddings stores vectors by text hash in the embedding cache in one
+// transaction, replacing any existing entries
+func (s *SQLiteStorage) PutCachedEmbeddings(ctx context.Context, provider, model string, vectors map[string][]float32) error {
+	if len(vectors) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO embedding_cache (provider, model, text_hash, vector, dimension, last_used_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(provider, model, text_hash) DO UPDATE SET
+			vector = excluded.vector,
+			dimension = excluded.dimension,
+			last_used_at = excluded.last_used_at
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to write embedding cache: %w", err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	now := time.Now().UnixNano()
+	for hash, vector := range vectors {
+		if _, err := stmt.ExecContext(ctx, provider, model, hash, serializeVector(vector), len(vector), now); err != nil {
+			return fmt.Errorf("failed to write embedding cache: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// PruneCachedEmbeddings evicts least recently used entries until at most maxEntries remain.
+// Returns the number of evicted entries.
+func (s *SQLiteStorage) PruneCachedEmbeddings(ctx context.Context, maxEntries int) (int, error) {
+	if maxEntries < 0 {
+		maxEntries = 0
+	}
+
+	query := `
+		DELETE FROM embedding_cache
+		WHERE rowid IN (
+			SELECT rowid FROM embedding_cache
+			ORDER BY last_used_at ASC
+			LIMIT max(0, (SELECT COUNT(*) FROM embedding_cache) - ?)
+		)
+	`
+	result, err := s.db.ExecContext(ctx, query, maxEntries)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune embedding cache: %w", err)
+	}
+
+	evicted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(evicted), nil
+}
+
+// CountCachedEmbeddings returns the number of entries in the embedding cache
+func (s *SQLiteStorage) CountCachedEmbeddings(ctx context.Context) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM embedding_cache").Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddingCache_PutGet(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	vector := []float32{0.1, 0.2, 0.3}
+
+	hits, err := storage.GetCachedEmbeddings(ctx, "jina", "v3", []string{"hash1"})
+	require.NoError(t, err)
+	assert.Empty(t, hits, "empty cache should miss")
+
+	require.NoError(t, storage.PutCachedEmbeddings(ctx, "jina", "v3", map[string][]float32{
+		"hash1": vector,
+		"hash2": {0.4},
+	}))
+
+	hits, err = storage.GetCachedEmbeddings(ctx, "jina", "v3", []string{"hash1", "missing", "hash2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]float32{"hash1": vector, "hash2": {0.4}}, hits)
+
+	// Same hash under a different model is a separate entry
+	hits, err = storage.GetCachedEmbeddings(ctx, "jina", "v2", []string{"hash1"})
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+
+	// Overwrite replaces the vector
+	require.NoError(t, storage.PutCachedEmbeddings(ctx, "jina", "v3", map[string][]float32{"hash1": {1, 2}}))
+	hits, err = storage.GetCachedEmbeddings(ctx, "jina", "v3", []string{"hash1"})
+	require.NoError(t, err)
+	assert.Equal(t, []float32{1, 2}, hits["hash1"])
+}
+
+func TestEmbeddingCache_LargeBatch(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	vectors := make(map[string][]float32)
+	hashes := make([]string, 0, 2*cacheBatchSize+1)
+	for i := 0; i < cap(hashes); i++ {
+		hash := fmt.Sprintf("h%d", i)
+		hashes = append(hashes, hash)
+		if i%2 == 0 {
+			vectors[hash] = []float32{float32(i)}
+		}
+	}
+	require.NoError(t, storage.PutCachedEmbeddings(ctx, "local", "m", vectors))
+
+	hits, err := storage.GetCachedEmbeddings(ctx, "local", "m", hashes)
+	require.NoError(t, err)
+	assert.Equal(t, vectors, hits)
+}
+
+func TestEmbeddingCache_PruneEvictsLeastRecentlyUsed(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, storage.PutCachedEmbeddings(ctx, "local", "m", map[string][]float32{
+			fmt.Sprintf("h%d", i): {float32(i)},
+		}))
+	}
+
+	// Touch the oldest entry so it survives eviction
+	hits, err := storage.GetCachedEmbeddings(ctx, "local", "m", []string{"h0"})
+	require.NoError(t, err)
+	require.Contains(t, hits, "h0")
+
+	evicted, err := storage.PruneCachedEmbeddings(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, evicted)
+
+	count, err := storage.CountCachedEmbeddings(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	hits, err = storage.GetCachedEmbeddings(ctx, "local", "m", []string{"h0", "h1", "h4"})
+	require.NoError(t, err)
+	assert.Contains(t, hits, "h0")
+	assert.NotContains(t, hits, "h1")
+	assert.Contains(t, hits, "h4")
+
+	// Pruning within the limit is a no-op
+	evicted, err = storage.PruneCachedEmbeddings(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, evicted)
+}
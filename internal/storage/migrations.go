@@ -4,13 +4,24 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
+
+	srcparser "github.com/dshills/gocontext-mcp/internal/parser"
+	"github.com/dshills/gocontext-mcp/internal/tokenize"
+	"github.com/dshills/gocontext-mcp/pkg/types"
 )
 
 const (
 	// CurrentSchemaVersion tracks the database schema version
-	CurrentSchemaVersion = "1.0.1"
+	CurrentSchemaVersion = "1.0.19"
 )
 
 // Migration represents a database schema migration
@@ -18,6 +29,10 @@ type Migration struct {
 	Version string
 	Up      string
 	Down    string
+
+	// Backfill optionally populates data that cannot be computed in SQL.
+	// It runs in the same transaction, after Up.
+	Backfill func(ctx context.Context, tx *sql.Tx) error
 }
 
 // AllMigrations contains all database migrations in order
@@ -27,6 +42,102 @@ var AllMigrations = []Migration{
 		Up:      migrationV101Up,
 		Down:    migrationV101Down,
 	},
+	{
+		Version: "1.0.2",
+		Up:      migrationV102Up,
+		Down:    migrationV102Down,
+	},
+	{
+		Version:  "1.0.3",
+		Up:       migrationV103Up,
+		Down:     migrationV103Down,
+		Backfill: backfillIdentifierTerms,
+	},
+	{
+		Version:  "1.0.4",
+		Up:       migrationV104Up,
+		Down:     migrationV104Down,
+		Backfill: backfillBuildConstraints,
+	},
+	{
+		Version: "1.0.5",
+		Up:      migrationV105Up,
+		Down:    migrationV105Down,
+	},
+	{
+		Version: "1.0.6",
+		Up:      migrationV106Up,
+		Down:    migrationV106Down,
+	},
+	{
+		Version: "1.0.7",
+		Up:      migrationV107Up,
+		Down:    migrationV107Down,
+	},
+	{
+		Version: "1.0.8",
+		Up:      migrationV108Up,
+		Down:    migrationV108Down,
+	},
+	{
+		Version: "1.0.9",
+		Up:      migrationV109Up,
+		Down:    migrationV109Down,
+	},
+	{
+		Version:  "1.0.10",
+		Up:       migrationV110Up,
+		Down:     migrationV110Down,
+		Backfill: backfillPackageDocs,
+	},
+	{
+		Version: "1.0.11",
+		Up:      migrationV111Up,
+		Down:    migrationV111Down,
+	},
+	{
+		Version: "1.0.12",
+		Up:      migrationV112Up,
+		Down:    migrationV112Down,
+	},
+	{
+		Version: "1.0.13",
+		Up:      migrationV113Up,
+		Down:    migrationV113Down,
+	},
+	{
+		Version: "1.0.14",
+		Up:      migrationV114Up,
+		Down:    migrationV114Down,
+	},
+	{
+		Version: "1.0.15",
+		Up:      migrationV115Up,
+		Down:    migrationV115Down,
+	},
+	{
+		Version:  "1.0.16",
+		Up:       migrationV116Up,
+		Down:     migrationV116Down,
+		Backfill: backfillImportLines,
+	},
+	{
+		Version: "1.0.17",
+		Up:      migrationV117Up,
+		Down:    migrationV117Down,
+	},
+	{
+		Version:  "1.0.18",
+		Up:       migrationV118Up,
+		Down:     migrationV118Down,
+		Backfill: backfillSymbolMetrics,
+	},
+	{
+		Version:  "1.0.19",
+		Up:       migrationV119Up,
+		Down:     migrationV119Down,
+		Backfill: backfillSymbolRefs,
+	},
 }
 
 const migrationV101Up = `
@@ -248,6 +359,805 @@ DROP TABLE IF EXISTS projects;
 DROP TABLE IF EXISTS schema_version;
 `
 
+const migrationV102Up = `
+-- Persistent embedding cache shared by all providers.
+-- Keyed by (provider, model, text hash) so cached vectors survive restarts.
+-- last_used_at holds Unix nanoseconds to keep LRU ordering exact.
+CREATE TABLE IF NOT EXISTS embedding_cache (
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL,
+    text_hash TEXT NOT NULL,
+    vector BLOB NOT NULL,
+    dimension INTEGER NOT NULL,
+    hit_count INTEGER DEFAULT 0,
+    last_used_at INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, model, text_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_embedding_cache_last_used ON embedding_cache(last_used_at);
+`
+
+const migrationV102Down = `
+DROP INDEX IF EXISTS idx_embedding_cache_last_used;
+DROP TABLE IF EXISTS embedding_cache;
+`
+
+const migrationV103Up = `
+-- Identifier-aware full-text search.
+-- identifier_terms holds camelCase/snake_case parts of compound identifiers
+-- (computed in Go, see tokenize.IdentifierTerms) so "chunk" matches DeleteChunksBatch.
+-- The porter stemmer lets singular query terms match plural identifier parts.
+ALTER TABLE symbols ADD COLUMN identifier_terms TEXT;
+ALTER TABLE chunks ADD COLUMN identifier_terms TEXT;
+
+DROP TRIGGER IF EXISTS symbols_ai;
+DROP TRIGGER IF EXISTS symbols_ad;
+DROP TRIGGER IF EXISTS symbols_au;
+DROP TABLE IF EXISTS symbols_fts;
+
+CREATE VIRTUAL TABLE symbols_fts USING fts5(
+    symbol_id UNINDEXED,
+    name, signature, doc_comment, identifier_terms,
+    tokenize = 'porter unicode61'
+);
+
+CREATE TRIGGER symbols_ai AFTER INSERT ON symbols BEGIN
+    INSERT INTO symbols_fts(symbol_id, name, signature, doc_comment, identifier_terms)
+    VALUES (new.id, new.name, new.signature, new.doc_comment, new.identifier_terms);
+END;
+
+CREATE TRIGGER symbols_ad AFTER DELETE ON symbols BEGIN
+    DELETE FROM symbols_fts WHERE symbol_id = old.id;
+END;
+
+CREATE TRIGGER symbols_au AFTER UPDATE ON symbols BEGIN
+    UPDATE symbols_fts SET
+        name = new.name,
+        signature = new.signature,
+        doc_comment = new.doc_comment,
+        identifier_terms = new.identifier_terms
+    WHERE symbol_id = new.id;
+END;
+
+DROP TRIGGER IF EXISTS chunks_ai;
+DROP TRIGGER IF EXISTS chunks_ad;
+DROP TRIGGER IF EXISTS chunks_au;
+DROP TABLE IF EXISTS chunks_fts;
+
+CREATE VIRTUAL TABLE chunks_fts USING fts5(
+    chunk_id UNINDEXED,
+    content, context_before, context_after, identifier_terms,
+    tokenize = 'porter unicode61'
+);
+
+CREATE TRIGGER chunks_ai AFTER INSERT ON chunks BEGIN
+    INSERT INTO chunks_fts(chunk_id, content, context_before, context_after, identifier_terms)
+    VALUES (new.id, new.content, new.context_before, new.context_after, new.identifier_terms);
+END;
+
+CREATE TRIGGER chunks_ad AFTER DELETE ON chunks BEGIN
+    DELETE FROM chunks_fts WHERE chunk_id = old.id;
+END;
+
+CREATE TRIGGER chunks_au AFTER UPDATE ON chunks BEGIN
+    UPDATE chunks_fts SET
+        content = new.content,
+        context_before = new.context_before,
+        context_after = new.context_after,
+        identifier_terms = new.identifier_terms
+    WHERE chunk_id = new.id;
+END;
+
+-- Rebuild FTS contents from existing rows; identifier_terms is filled by the backfill
+INSERT INTO symbols_fts(symbol_id, name, signature, doc_comment, identifier_terms)
+SELECT id, name, signature, doc_comment, identifier_terms FROM symbols;
+
+INSERT INTO chunks_fts(chunk_id, content, context_before, context_after, identifier_terms)
+SELECT id, content, context_before, context_after, identifier_terms FROM chunks;
+`
+
+const migrationV103Down = `
+DROP TRIGGER IF EXISTS symbols_ai;
+DROP TRIGGER IF EXISTS symbols_ad;
+DROP TRIGGER IF EXISTS symbols_au;
+DROP TABLE IF EXISTS symbols_fts;
+
+CREATE VIRTUAL TABLE symbols_fts USING fts5(
+    symbol_id UNINDEXED,
+    name, signature, doc_comment
+);
+
+CREATE TRIGGER symbols_ai AFTER INSERT ON symbols BEGIN
+    INSERT INTO symbols_fts(symbol_id, name, signature, doc_comment)
+    VALUES (new.id, new.name, new.signature, new.doc_comment);
+END;
+
+CREATE TRIGGER symbols_ad AFTER DELETE ON symbols BEGIN
+    DELETE FROM symbols_fts WHERE symbol_id = old.id;
+END;
+
+CREATE TRIGGER symbols_au AFTER UPDATE ON symbols BEGIN
+    UPDATE symbols_fts SET
+        name = new.name,
+        signature = new.signature,
+        doc_comment = new.doc_comment
+    WHERE symbol_id = new.id;
+END;
+
+DROP TRIGGER IF EXISTS chunks_ai;
+DROP TRIGGER IF EXISTS chunks_ad;
+DROP TRIGGER IF EXISTS chunks_au;
+DROP TABLE IF EXISTS chunks_fts;
+
+CREATE VIRTUAL TABLE chunks_fts USING fts5(
+    chunk_id UNINDEXED,
+    content, context_before, context_after
+);
+
+CREATE TRIGGER chunks_ai AFTER INSERT ON chunks BEGIN
+    INSERT INTO chunks_fts(chunk_id, content, context_before, context_after)
+    VALUES (new.id, new.content, new.context_before, new.context_after);
+END;
+
+CREATE TRIGGER chunks_ad AFTER DELETE ON chunks BEGIN
+    DELETE FROM chunks_fts WHERE chunk_id = old.id;
+END;
+
+CREATE TRIGGER chunks_au AFTER UPDATE ON chunks BEGIN
+    UPDATE chunks_fts SET
+        content = new.content,
+        context_before = new.context_before,
+        context_after = new.context_after
+    WHERE chunk_id = new.id;
+END;
+
+INSERT INTO symbols_fts(symbol_id, name, signature, doc_comment)
+SELECT id, name, signature, doc_comment FROM symbols;
+
+INSERT INTO chunks_fts(chunk_id, content, context_before, context_after)
+SELECT id, content, context_before, context_after FROM chunks;
+
+ALTER TABLE symbols DROP COLUMN identifier_terms;
+ALTER TABLE chunks DROP COLUMN identifier_terms;
+`
+
+// backfillIdentifierTerms computes identifier_terms for rows indexed before 1.0.3.
+// The update triggers propagate the values into the FTS tables.
+func backfillIdentifierTerms(ctx context.Context, tx *sql.Tx) error {
+	if err := backfillTerms(ctx, tx,
+		"SELECT id, name || ' ' || COALESCE(signature, '') FROM symbols",
+		"UPDATE symbols SET identifier_terms = ? WHERE id = ?",
+	); err != nil {
+		return fmt.Errorf("symbols: %w", err)
+	}
+	if err := backfillTerms(ctx, tx,
+		"SELECT id, content FROM chunks",
+		"UPDATE chunks SET identifier_terms = ? WHERE id = ?",
+	); err != nil {
+		return fmt.Errorf("chunks: %w", err)
+	}
+	return nil
+}
+
+// backfillTerms reads (id, text) rows with selectQuery and writes their identifier terms with updateQuery
+func backfillTerms(ctx context.Context, tx *sql.Tx, selectQuery, updateQuery string) error {
+	rows, err := tx.QueryContext(ctx, selectQuery)
+	if err != nil {
+		return err
+	}
+
+	terms := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var text string
+		if err := rows.Scan(&id, &text); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		terms[id] = tokenize.IdentifierTerms(text)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, updateQuery)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for id, t := range terms {
+		if _, err := stmt.ExecContext(ctx, t, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const migrationV104Up = `
+-- Build constraints per file: the //go:build expression plus GOOS/GOARCH
+-- implied by file name suffixes such as _linux.go or _windows_amd64.go
+ALTER TABLE files ADD COLUMN build_constraint TEXT NOT NULL DEFAULT '';
+ALTER TABLE files ADD COLUMN goos TEXT NOT NULL DEFAULT '';
+ALTER TABLE files ADD COLUMN goarch TEXT NOT NULL DEFAULT '';
+`
+
+const migrationV104Down = `
+ALTER TABLE files DROP COLUMN goarch;
+ALTER TABLE files DROP COLUMN goos;
+ALTER TABLE files DROP COLUMN build_constraint;
+`
+
+// backfillBuildConstraints records build constraints for files indexed before 1.0.4.
+// File headers are read from disk; files that no longer exist get name-based constraints only.
+func backfillBuildConstraints(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT f.id, f.file_path, p.root_path
+		FROM files f
+		INNER JOIN projects p ON f.project_id = p.id
+	`)
+	if err != nil {
+		return err
+	}
+
+	constraints := make(map[int64]types.BuildConstraint)
+	for rows.Next() {
+		var id int64
+		var filePath, rootPath string
+		if err := rows.Scan(&id, &filePath, &rootPath); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		fullPath := filepath.Join(rootPath, filePath)
+		src, _ := os.ReadFile(fullPath)
+		constraints[id] = types.ParseBuildConstraint(fullPath, src)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "UPDATE files SET build_constraint = ?, goos = ?, goarch = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for id, c := range constraints {
+		if _, err := stmt.ExecContext(ctx, c.Expr, c.GOOS, c.GOARCH, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const migrationV105Up = `
+-- Modules discovered in a project (root go.mod, nested go.mod files, go.work members)
+CREATE TABLE IF NOT EXISTS modules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    module_path TEXT NOT NULL,
+    dir TEXT NOT NULL,
+    go_version TEXT NOT NULL DEFAULT '',
+    in_workspace BOOLEAN DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE(project_id, dir)
+);
+
+-- Requirements of each module, with replace directives applied
+CREATE TABLE IF NOT EXISTS module_dependencies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    module_id INTEGER NOT NULL,
+    path TEXT NOT NULL,
+    version TEXT NOT NULL,
+    indirect BOOLEAN DEFAULT 0,
+    replace_path TEXT NOT NULL DEFAULT '',
+    replace_version TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_module_dependencies_module ON module_dependencies(module_id);
+
+-- Owning module and package import path of each file
+ALTER TABLE files ADD COLUMN module_path TEXT NOT NULL DEFAULT '';
+ALTER TABLE files ADD COLUMN import_path TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_files_module ON files(project_id, module_path);
+`
+
+const migrationV105Down = `
+DROP INDEX IF EXISTS idx_files_module;
+ALTER TABLE files DROP COLUMN import_path;
+ALTER TABLE files DROP COLUMN module_path;
+DROP INDEX IF EXISTS idx_module_dependencies_module;
+DROP TABLE IF EXISTS module_dependencies;
+DROP TABLE IF EXISTS modules;
+`
+
+const migrationV106Up = `
+-- Projects indexed from a git revision instead of the working tree
+ALTER TABLE projects ADD COLUMN git_ref TEXT NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN git_commit TEXT NOT NULL DEFAULT '';
+`
+
+const migrationV106Down = `
+ALTER TABLE projects DROP COLUMN git_commit;
+ALTER TABLE projects DROP COLUMN git_ref;
+`
+
+const migrationV107Up = `
+-- Git history of each chunk's lines (populated when indexing with git history)
+ALTER TABLE chunks ADD COLUMN last_commit TEXT NOT NULL DEFAULT '';
+ALTER TABLE chunks ADD COLUMN last_author TEXT NOT NULL DEFAULT '';
+ALTER TABLE chunks ADD COLUMN last_modified TIMESTAMP;
+ALTER TABLE chunks ADD COLUMN change_count INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_chunks_last_modified ON chunks(last_modified);
+`
+
+const migrationV107Down = `
+DROP INDEX IF EXISTS idx_chunks_last_modified;
+ALTER TABLE chunks DROP COLUMN change_count;
+ALTER TABLE chunks DROP COLUMN last_modified;
+ALTER TABLE chunks DROP COLUMN last_author;
+ALTER TABLE chunks DROP COLUMN last_commit;
+`
+
+const migrationV108Up = `
+-- Marker comments: TODO/FIXME/HACK/XXX/NOTE, Deprecated: paragraphs, //nolint directives
+CREATE TABLE IF NOT EXISTS annotations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    file_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    text TEXT NOT NULL DEFAULT '',
+    line INTEGER NOT NULL,
+    symbol_name TEXT NOT NULL DEFAULT '',
+    owner TEXT NOT NULL DEFAULT '',
+    issues TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_annotations_file ON annotations(file_id);
+CREATE INDEX IF NOT EXISTS idx_annotations_kind ON annotations(kind);
+`
+
+const migrationV108Down = `
+DROP INDEX IF EXISTS idx_annotations_kind;
+DROP INDEX IF EXISTS idx_annotations_file;
+DROP TABLE IF EXISTS annotations;
+`
+
+const migrationV109Up = `
+-- Test, Benchmark, Fuzz and Example functions with the symbol they are named after
+CREATE TABLE IF NOT EXISTS test_functions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    file_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    start_line INTEGER NOT NULL,
+    end_line INTEGER NOT NULL,
+    target_name TEXT NOT NULL DEFAULT '',   -- "SQLiteStorage" for TestSQLiteStorage_GetChunk
+    target_member TEXT NOT NULL DEFAULT '', -- "GetChunk" for TestSQLiteStorage_GetChunk
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE
+);
+
+-- Functions and methods called from test functions
+CREATE TABLE IF NOT EXISTS test_calls (
+    test_id INTEGER NOT NULL,
+    package TEXT NOT NULL DEFAULT '', -- Import path, empty for the test's own package
+    name TEXT NOT NULL,
+    is_method BOOLEAN NOT NULL DEFAULT 0,
+    FOREIGN KEY (test_id) REFERENCES test_functions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_test_functions_file ON test_functions(file_id);
+CREATE INDEX IF NOT EXISTS idx_test_functions_target_name ON test_functions(target_name);
+CREATE INDEX IF NOT EXISTS idx_test_functions_target_member ON test_functions(target_member);
+CREATE INDEX IF NOT EXISTS idx_test_calls_test ON test_calls(test_id);
+CREATE INDEX IF NOT EXISTS idx_test_calls_name ON test_calls(name);
+`
+
+const migrationV109Down = `
+DROP INDEX IF EXISTS idx_test_calls_name;
+DROP INDEX IF EXISTS idx_test_calls_test;
+DROP INDEX IF EXISTS idx_test_functions_target_member;
+DROP INDEX IF EXISTS idx_test_functions_target_name;
+DROP INDEX IF EXISTS idx_test_functions_file;
+DROP TABLE IF EXISTS test_calls;
+DROP TABLE IF EXISTS test_functions;
+`
+
+const migrationV110Up = `
+-- Package doc comment of each file, for package overviews
+ALTER TABLE files ADD COLUMN package_doc TEXT NOT NULL DEFAULT '';
+`
+
+const migrationV110Down = `
+ALTER TABLE files DROP COLUMN package_doc;
+`
+
+const migrationV111Up = `
+-- Required modules indexed from the module cache as read-only projects
+ALTER TABLE projects ADD COLUMN module_version TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS project_dependencies (
+    project_id INTEGER NOT NULL,
+    dependency_id INTEGER NOT NULL,
+    direct BOOLEAN NOT NULL DEFAULT 0,
+    PRIMARY KEY (project_id, dependency_id),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (dependency_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_dependencies_dependency ON project_dependencies(dependency_id);
+`
+
+const migrationV111Down = `
+DROP INDEX IF EXISTS idx_project_dependencies_dependency;
+DROP TABLE IF EXISTS project_dependencies;
+ALTER TABLE projects DROP COLUMN module_version;
+`
+
+const migrationV112Up = `
+-- Type parameters of generic functions and types: comma-separated names and
+-- semicolon-separated constraints, in declaration order
+ALTER TABLE symbols ADD COLUMN type_params TEXT NOT NULL DEFAULT '';
+ALTER TABLE symbols ADD COLUMN type_constraints TEXT NOT NULL DEFAULT '';
+`
+
+const migrationV112Down = `
+ALTER TABLE symbols DROP COLUMN type_constraints;
+ALTER TABLE symbols DROP COLUMN type_params;
+`
+
+const migrationV113Up = `
+-- Struct tag keys of field symbols; name is the value up to the first comma
+CREATE TABLE IF NOT EXISTS symbol_tags (
+    symbol_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    name TEXT NOT NULL,
+    FOREIGN KEY (symbol_id) REFERENCES symbols(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_symbol_tags_symbol ON symbol_tags(symbol_id);
+CREATE INDEX IF NOT EXISTS idx_symbol_tags_name ON symbol_tags(name, key);
+`
+
+const migrationV113Down = `
+DROP INDEX IF EXISTS idx_symbol_tags_name;
+DROP INDEX IF EXISTS idx_symbol_tags_symbol;
+DROP TABLE IF EXISTS symbol_tags;
+`
+
+const migrationV114Up = `
+-- Types embedded in structs and interfaces, semicolon-separated as written
+ALTER TABLE symbols ADD COLUMN embeds TEXT NOT NULL DEFAULT '';
+`
+
+const migrationV114Down = `
+ALTER TABLE symbols DROP COLUMN embeds;
+`
+
+const migrationV115Up = `
+-- DDD patterns detected on types, with the confidence and the criteria that matched
+CREATE TABLE IF NOT EXISTS symbol_patterns (
+    symbol_id INTEGER NOT NULL,
+    pattern TEXT NOT NULL,
+    confidence REAL NOT NULL,
+    reason TEXT NOT NULL,
+    PRIMARY KEY (symbol_id, pattern),
+    FOREIGN KEY (symbol_id) REFERENCES symbols(id) ON DELETE CASCADE
+);
+`
+
+const migrationV115Down = `
+DROP TABLE IF EXISTS symbol_patterns;
+`
+
+const migrationV116Up = `
+-- Line of each import spec, for reporting architecture violations
+ALTER TABLE imports ADD COLUMN line INTEGER NOT NULL DEFAULT 0;
+`
+
+const migrationV116Down = `
+ALTER TABLE imports DROP COLUMN line;
+`
+
+const migrationV117Up = `
+-- Types and functions referred to by functions, methods and fields
+CREATE TABLE IF NOT EXISTS symbol_refs (
+    symbol_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,                 -- param, result, type, call or method
+    package TEXT NOT NULL DEFAULT '',   -- Import path, empty for the symbol's own package
+    name TEXT NOT NULL,
+    via TEXT NOT NULL DEFAULT '',       -- Receiver field a method is called on
+    FOREIGN KEY (symbol_id) REFERENCES symbols(id) ON DELETE CASCADE
+);
+
+-- Links inferred between symbols, e.g. a command handled_by its handler
+CREATE TABLE IF NOT EXISTS symbol_relations (
+    from_symbol_id INTEGER NOT NULL,
+    to_symbol_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (from_symbol_id, to_symbol_id, kind),
+    FOREIGN KEY (from_symbol_id) REFERENCES symbols(id) ON DELETE CASCADE,
+    FOREIGN KEY (to_symbol_id) REFERENCES symbols(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_symbol_refs_symbol ON symbol_refs(symbol_id);
+CREATE INDEX IF NOT EXISTS idx_symbol_refs_name ON symbol_refs(name);
+CREATE INDEX IF NOT EXISTS idx_symbol_relations_to ON symbol_relations(to_symbol_id);
+`
+
+const migrationV117Down = `
+DROP INDEX IF EXISTS idx_symbol_relations_to;
+DROP INDEX IF EXISTS idx_symbol_refs_name;
+DROP INDEX IF EXISTS idx_symbol_refs_symbol;
+DROP TABLE IF EXISTS symbol_relations;
+DROP TABLE IF EXISTS symbol_refs;
+`
+
+const migrationV118Up = `
+-- Complexity metrics of functions and methods
+ALTER TABLE symbols ADD COLUMN cyclomatic INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE symbols ADD COLUMN cognitive INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE symbols ADD COLUMN line_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE symbols ADD COLUMN param_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE symbols ADD COLUMN max_nesting INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE symbols ADD COLUMN return_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_symbols_cyclomatic ON symbols(cyclomatic);
+CREATE INDEX IF NOT EXISTS idx_symbols_cognitive ON symbols(cognitive);
+`
+
+const migrationV118Down = `
+DROP INDEX IF EXISTS idx_symbols_cognitive;
+DROP INDEX IF EXISTS idx_symbols_cyclomatic;
+ALTER TABLE symbols DROP COLUMN return_count;
+ALTER TABLE symbols DROP COLUMN max_nesting;
+ALTER TABLE symbols DROP COLUMN param_count;
+ALTER TABLE symbols DROP COLUMN line_count;
+ALTER TABLE symbols DROP COLUMN cognitive;
+ALTER TABLE symbols DROP COLUMN cyclomatic;
+`
+
+const migrationV119Up = `
+-- References are recorded for every symbol, with identifier uses (use, select)
+CREATE INDEX IF NOT EXISTS idx_symbol_refs_kind_name ON symbol_refs(kind, name);
+`
+
+const migrationV119Down = `
+DROP INDEX IF EXISTS idx_symbol_refs_kind_name;
+DELETE FROM symbol_refs WHERE kind IN ('use', 'select');
+`
+
+// backfillPackageDocs records package doc comments for files indexed before 1.0.10.
+// Files are read from disk; snapshots and files that no longer exist keep an empty doc.
+func backfillPackageDocs(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT f.id, f.file_path, p.root_path
+		FROM files f
+		INNER JOIN projects p ON f.project_id = p.id
+	`)
+	if err != nil {
+		return err
+	}
+
+	docs := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var filePath, rootPath string
+		if err := rows.Scan(&id, &filePath, &rootPath); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		fullPath := filepath.Join(rootPath, filePath)
+		file, err := parser.ParseFile(token.NewFileSet(), fullPath, nil, parser.PackageClauseOnly|parser.ParseComments)
+		if err == nil && file.Doc != nil {
+			docs[id] = strings.TrimSpace(file.Doc.Text())
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "UPDATE files SET package_doc = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for id, doc := range docs {
+		if _, err := stmt.ExecContext(ctx, doc, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillImportLines records the line of each stored import by re-reading the
+// import declarations of indexed files still on disk
+func backfillImportLines(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT f.id, f.file_path, p.root_path
+		FROM files f
+		INNER JOIN projects p ON f.project_id = p.id
+	`)
+	if err != nil {
+		return err
+	}
+
+	type importLine struct {
+		fileID int64
+		path   string
+		line   int
+	}
+	var lines []importLine
+	for rows.Next() {
+		var id int64
+		var filePath, rootPath string
+		if err := rows.Scan(&id, &filePath, &rootPath); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, filepath.Join(rootPath, filePath), nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range file.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			lines = append(lines, importLine{id, path, fset.Position(imp.Pos()).Line})
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "UPDATE imports SET line = ? WHERE file_id = ? AND import_path = ?")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, l := range lines {
+		if _, err := stmt.ExecContext(ctx, l.line, l.fileID, l.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillSymbolMetrics measures the functions and methods of files indexed before
+// 1.0.18, matched by name, receiver and line. Files are read from disk; snapshots
+// and files that changed since keep zero metrics until reindexed.
+func backfillSymbolMetrics(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT f.id, f.file_path, p.root_path
+		FROM files f
+		INNER JOIN projects p ON f.project_id = p.id
+	`)
+	if err != nil {
+		return err
+	}
+
+	type measured struct {
+		fileID int64
+		symbol types.Symbol
+	}
+	var functions []measured
+	p := srcparser.New()
+	for rows.Next() {
+		var id int64
+		var filePath, rootPath string
+		if err := rows.Scan(&id, &filePath, &rootPath); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		result, err := p.ParseFile(filepath.Join(rootPath, filePath))
+		if err != nil {
+			continue
+		}
+		for _, sym := range result.Symbols {
+			if sym.Kind == types.KindFunction || sym.Kind == types.KindMethod {
+				functions = append(functions, measured{id, sym})
+			}
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		UPDATE symbols
+		SET cyclomatic = ?, cognitive = ?, line_count = ?, param_count = ?, max_nesting = ?, return_count = ?
+		WHERE file_id = ? AND name = ? AND receiver = ? AND start_line = ?
+	`)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, f := range functions {
+		m := f.symbol.Metrics
+		if _, err := stmt.ExecContext(ctx, m.Cyclomatic, m.Cognitive, m.Lines, m.Params, m.Nesting, m.Returns,
+			f.fileID, f.symbol.Name, f.symbol.Receiver, f.symbol.Start.Line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillSymbolRefs replaces the references of symbols indexed before 1.0.19,
+// which lacked identifier uses and, outside functions, methods and fields, any
+// references. Symbols are matched by kind, name, receiver and line; files are
+// read from disk, and snapshots and files that changed since keep their
+// references until reindexed.
+func backfillSymbolRefs(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT f.id, f.file_path, p.root_path
+		FROM files f
+		INNER JOIN projects p ON f.project_id = p.id
+	`)
+	if err != nil {
+		return err
+	}
+
+	type parsed struct {
+		fileID int64
+		symbol types.Symbol
+	}
+	var symbols []parsed
+	p := srcparser.New()
+	for rows.Next() {
+		var id int64
+		var filePath, rootPath string
+		if err := rows.Scan(&id, &filePath, &rootPath); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		result, err := p.ParseFile(filepath.Join(rootPath, filePath))
+		if err != nil {
+			continue
+		}
+		for _, sym := range result.Symbols {
+			symbols = append(symbols, parsed{id, sym})
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, s := range symbols {
+		var symbolID int64
+		err := tx.QueryRowContext(ctx, `
+			SELECT id FROM symbols
+			WHERE file_id = ? AND kind = ? AND name = ? AND receiver = ? AND start_line = ?
+		`, s.fileID, s.symbol.Kind, s.symbol.Name, s.symbol.Receiver, s.symbol.Start.Line).Scan(&symbolID)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := replaceSymbolRefs(ctx, tx, &Symbol{ID: symbolID, Refs: s.symbol.Refs}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ApplyMigrations runs all pending migrations
 func ApplyMigrations(ctx context.Context, db *sql.DB) error {
 	// Check if schema_version table exists
@@ -264,7 +1174,7 @@ func ApplyMigrations(ctx context.Context, db *sql.DB) error {
 	} else {
 		// Table exists, check current version
 		var currentVersionStr string
-		err = db.QueryRowContext(ctx, "SELECT version FROM schema_version ORDER BY applied_at DESC LIMIT 1").Scan(&currentVersionStr)
+		err = db.QueryRowContext(ctx, "SELECT version FROM schema_version ORDER BY applied_at DESC, rowid DESC LIMIT 1").Scan(&currentVersionStr)
 		if err == sql.ErrNoRows || currentVersionStr == "" {
 			currentVersion = semver.MustParse("0.0.0")
 		} else if err != nil {
@@ -307,8 +1217,17 @@ func ApplyMigrations(ctx context.Context, db *sql.DB) error {
 			return fmt.Errorf("failed to apply migration %s: %w", migration.Version, err)
 		}
 
-		// Record migration with explicit timestamp including subseconds for uniqueness
-		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_version (version, applied_at) VALUES (?, strftime('%Y-%m-%d %H:%M:%f', 'now'))", migration.Version); err != nil {
+		if migration.Backfill != nil {
+			if err := migration.Backfill(ctx, tx); err != nil {
+				_ = tx.Rollback()
+				_, _ = db.ExecContext(ctx, "PRAGMA foreign_keys = ON") // Re-enable before returning
+				return fmt.Errorf("failed to backfill migration %s: %w", migration.Version, err)
+			}
+		}
+
+		// Record migration with explicit microsecond timestamp so consecutive migrations order correctly
+		appliedAt := time.Now().UTC().Format("2006-01-02 15:04:05.000000")
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_version (version, applied_at) VALUES (?, ?)", migration.Version, appliedAt); err != nil {
 			_ = tx.Rollback()
 			_, _ = db.ExecContext(ctx, "PRAGMA foreign_keys = ON") // Re-enable before returning
 			return fmt.Errorf("failed to record migration %s: %w", migration.Version, err)
@@ -347,7 +1266,7 @@ func ApplyMigrations(ctx context.Context, db *sql.DB) error {
 func RollbackMigration(ctx context.Context, db *sql.DB) error {
 	// Get current version
 	var currentVersion string
-	err := db.QueryRowContext(ctx, "SELECT version FROM schema_version ORDER BY applied_at DESC LIMIT 1").Scan(&currentVersion)
+	err := db.QueryRowContext(ctx, "SELECT version FROM schema_version ORDER BY applied_at DESC, rowid DESC LIMIT 1").Scan(&currentVersion)
 	if err != nil {
 		return fmt.Errorf("no migrations to rollback: %w", err)
 	}
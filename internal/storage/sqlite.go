@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/dshills/gocontext-mcp/internal/tokenize"
+	"github.com/dshills/gocontext-mcp/pkg/types"
 )
 
 var (
@@ -114,13 +117,14 @@ func (s *SQLiteStorage) querier() querier {
 // createProjectWithQuerier is the internal implementation that uses a querier
 func (s *SQLiteStorage) createProjectWithQuerier(ctx context.Context, q querier, project *Project) error {
 	query := `
-		INSERT INTO projects (root_path, module_name, go_version, index_version, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO projects (root_path, module_name, go_version, index_version, created_at, updated_at,
+		                      git_ref, git_commit, module_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	now := time.Now()
 	result, err := q.ExecContext(ctx, query,
 		project.RootPath, project.ModuleName, project.GoVersion,
-		project.IndexVersion, now, now)
+		project.IndexVersion, now, now, project.GitRef, project.GitCommit, project.ModuleVersion)
 	if err != nil {
 		return fmt.Errorf("failed to create project: %w", err)
 	}
@@ -143,7 +147,7 @@ func (s *SQLiteStorage) CreateProject(ctx context.Context, project *Project) err
 func (s *SQLiteStorage) getProjectWithQuerier(ctx context.Context, q querier, rootPath string) (*Project, error) {
 	query := `
 		SELECT id, root_path, module_name, go_version, total_files, total_chunks,
-		       index_version, last_indexed_at, created_at, updated_at
+		       index_version, last_indexed_at, created_at, updated_at, git_ref, git_commit, module_version
 		FROM projects
 		WHERE root_path = ?
 	`
@@ -152,7 +156,8 @@ func (s *SQLiteStorage) getProjectWithQuerier(ctx context.Context, q querier, ro
 	err := q.QueryRowContext(ctx, query, rootPath).Scan(
 		&project.ID, &project.RootPath, &project.ModuleName, &project.GoVersion,
 		&project.TotalFiles, &project.TotalChunks, &project.IndexVersion,
-		&lastIndexedAt, &project.CreatedAt, &project.UpdatedAt,
+		&lastIndexedAt, &project.CreatedAt, &project.UpdatedAt, &project.GitRef, &project.GitCommit,
+		&project.ModuleVersion,
 	)
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
@@ -170,18 +175,57 @@ func (s *SQLiteStorage) GetProject(ctx context.Context, rootPath string) (*Proje
 	return s.getProjectWithQuerier(ctx, s.querier(), rootPath)
 }
 
+// listProjectsWithQuerier is the internal implementation that uses a querier
+func (s *SQLiteStorage) listProjectsWithQuerier(ctx context.Context, q querier) ([]*Project, error) {
+	query := `
+		SELECT id, root_path, module_name, go_version, total_files, total_chunks,
+		       index_version, last_indexed_at, created_at, updated_at, git_ref, git_commit, module_version
+		FROM projects
+		ORDER BY root_path
+	`
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	projects := make([]*Project, 0)
+	for rows.Next() {
+		var project Project
+		var lastIndexedAt sql.NullTime
+		err := rows.Scan(
+			&project.ID, &project.RootPath, &project.ModuleName, &project.GoVersion,
+			&project.TotalFiles, &project.TotalChunks, &project.IndexVersion,
+			&lastIndexedAt, &project.CreatedAt, &project.UpdatedAt, &project.GitRef, &project.GitCommit,
+			&project.ModuleVersion,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if lastIndexedAt.Valid {
+			project.LastIndexedAt = lastIndexedAt.Time
+		}
+		projects = append(projects, &project)
+	}
+	return projects, rows.Err()
+}
+
+func (s *SQLiteStorage) ListProjects(ctx context.Context) ([]*Project, error) {
+	return s.listProjectsWithQuerier(ctx, s.querier())
+}
+
 // updateProjectWithQuerier is the internal implementation that uses a querier
 func (s *SQLiteStorage) updateProjectWithQuerier(ctx context.Context, q querier, project *Project) error {
 	query := `
 		UPDATE projects
 		SET module_name = ?, go_version = ?, total_files = ?, total_chunks = ?,
-		    last_indexed_at = ?, updated_at = ?
+		    last_indexed_at = ?, updated_at = ?, git_ref = ?, git_commit = ?, module_version = ?
 		WHERE id = ?
 	`
 	now := time.Now()
 	_, err := q.ExecContext(ctx, query,
 		project.ModuleName, project.GoVersion, project.TotalFiles, project.TotalChunks,
-		project.LastIndexedAt, now, project.ID)
+		project.LastIndexedAt, now, project.GitRef, project.GitCommit, project.ModuleVersion, project.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update project: %w", err)
 	}
@@ -198,14 +242,22 @@ func (s *SQLiteStorage) UpdateProject(ctx context.Context, project *Project) err
 // upsertFileWithQuerier is the internal implementation that uses a querier
 func (s *SQLiteStorage) upsertFileWithQuerier(ctx context.Context, q querier, file *File) error {
 	query := `
-		INSERT INTO files (project_id, file_path, package_name, content_hash, mod_time, size_bytes, parse_error, last_indexed_at, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO files (project_id, file_path, package_name, content_hash, mod_time, size_bytes, parse_error,
+		                   build_constraint, goos, goarch, module_path, import_path, package_doc,
+		                   last_indexed_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(project_id, file_path) DO UPDATE SET
 			package_name = excluded.package_name,
 			content_hash = excluded.content_hash,
 			mod_time = excluded.mod_time,
 			size_bytes = excluded.size_bytes,
 			parse_error = excluded.parse_error,
+			build_constraint = excluded.build_constraint,
+			goos = excluded.goos,
+			goarch = excluded.goarch,
+			module_path = excluded.module_path,
+			import_path = excluded.import_path,
+			package_doc = excluded.package_doc,
 			last_indexed_at = excluded.last_indexed_at,
 			updated_at = excluded.updated_at
 		RETURNING id
@@ -213,7 +265,9 @@ func (s *SQLiteStorage) upsertFileWithQuerier(ctx context.Context, q querier, fi
 	now := time.Now()
 	err := q.QueryRowContext(ctx, query,
 		file.ProjectID, file.FilePath, file.PackageName, file.ContentHash[:],
-		file.ModTime, file.SizeBytes, file.ParseError, now, now, now).Scan(&file.ID)
+		file.ModTime, file.SizeBytes, file.ParseError,
+		file.BuildConstraint, file.GOOS, file.GOARCH, file.ModulePath, file.ImportPath, file.PackageDoc,
+		now, now, now).Scan(&file.ID)
 	if err != nil {
 		return fmt.Errorf("failed to upsert file: %w", err)
 	}
@@ -231,7 +285,8 @@ func (s *SQLiteStorage) UpsertFile(ctx context.Context, file *File) error {
 func (s *SQLiteStorage) getFileWithQuerier(ctx context.Context, q querier, projectID int64, filePath string) (*File, error) {
 	query := `
 		SELECT id, project_id, file_path, package_name, content_hash, mod_time,
-		       size_bytes, parse_error, last_indexed_at, created_at, updated_at
+		       size_bytes, parse_error, build_constraint, goos, goarch,
+		       module_path, import_path, package_doc, last_indexed_at, created_at, updated_at
 		FROM files
 		WHERE project_id = ? AND file_path = ?
 	`
@@ -241,6 +296,8 @@ func (s *SQLiteStorage) getFileWithQuerier(ctx context.Context, q querier, proje
 	err := q.QueryRowContext(ctx, query, projectID, filePath).Scan(
 		&file.ID, &file.ProjectID, &file.FilePath, &file.PackageName,
 		&hash, &file.ModTime, &file.SizeBytes, &parseError,
+		&file.BuildConstraint, &file.GOOS, &file.GOARCH,
+		&file.ModulePath, &file.ImportPath, &file.PackageDoc,
 		&file.LastIndexedAt, &file.CreatedAt, &file.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
@@ -264,7 +321,8 @@ func (s *SQLiteStorage) GetFile(ctx context.Context, projectID int64, filePath s
 func (s *SQLiteStorage) getFileByIDWithQuerier(ctx context.Context, q querier, fileID int64) (*File, error) {
 	query := `
 		SELECT id, project_id, file_path, package_name, content_hash, mod_time,
-		       size_bytes, parse_error, last_indexed_at, created_at, updated_at
+		       size_bytes, parse_error, build_constraint, goos, goarch,
+		       module_path, import_path, package_doc, last_indexed_at, created_at, updated_at
 		FROM files
 		WHERE id = ?
 	`
@@ -274,6 +332,8 @@ func (s *SQLiteStorage) getFileByIDWithQuerier(ctx context.Context, q querier, f
 	err := q.QueryRowContext(ctx, query, fileID).Scan(
 		&file.ID, &file.ProjectID, &file.FilePath, &file.PackageName,
 		&hash, &file.ModTime, &file.SizeBytes, &parseError,
+		&file.BuildConstraint, &file.GOOS, &file.GOARCH,
+		&file.ModulePath, &file.ImportPath, &file.PackageDoc,
 		&file.LastIndexedAt, &file.CreatedAt, &file.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
@@ -313,7 +373,8 @@ func (s *SQLiteStorage) DeleteFile(ctx context.Context, fileID int64) error {
 func (s *SQLiteStorage) listFilesWithQuerier(ctx context.Context, q querier, projectID int64) ([]*File, error) {
 	query := `
 		SELECT id, project_id, file_path, package_name, content_hash, mod_time,
-		       size_bytes, parse_error, last_indexed_at, created_at, updated_at
+		       size_bytes, parse_error, build_constraint, goos, goarch,
+		       module_path, import_path, package_doc, last_indexed_at, created_at, updated_at
 		FROM files
 		WHERE project_id = ?
 		ORDER BY file_path
@@ -333,6 +394,8 @@ func (s *SQLiteStorage) listFilesWithQuerier(ctx context.Context, q querier, pro
 		err := rows.Scan(
 			&file.ID, &file.ProjectID, &file.FilePath, &file.PackageName,
 			&hash, &file.ModTime, &file.SizeBytes, &parseError,
+			&file.BuildConstraint, &file.GOOS, &file.GOARCH,
+			&file.ModulePath, &file.ImportPath, &file.PackageDoc,
 			&file.LastIndexedAt, &file.CreatedAt, &file.UpdatedAt,
 		)
 		if err != nil {
@@ -363,8 +426,10 @@ func (s *SQLiteStorage) upsertSymbolWithQuerier(ctx context.Context, q querier,
 			file_id, name, kind, package_name, signature, doc_comment, scope, receiver,
 			start_line, start_col, end_line, end_col,
 			is_aggregate_root, is_entity, is_value_object, is_repository,
-			is_service, is_command, is_query, is_handler, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			is_service, is_command, is_query, is_handler, type_params, type_constraints, embeds,
+			cyclomatic, cognitive, line_count, param_count, max_nesting, return_count,
+			identifier_terms, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(file_id, name, start_line, start_col)
 		DO UPDATE SET
 			kind = excluded.kind,
@@ -382,25 +447,242 @@ func (s *SQLiteStorage) upsertSymbolWithQuerier(ctx context.Context, q querier,
 			is_service = excluded.is_service,
 			is_command = excluded.is_command,
 			is_query = excluded.is_query,
-			is_handler = excluded.is_handler
+			is_handler = excluded.is_handler,
+			type_params = excluded.type_params,
+			type_constraints = excluded.type_constraints,
+			embeds = excluded.embeds,
+			cyclomatic = excluded.cyclomatic,
+			cognitive = excluded.cognitive,
+			line_count = excluded.line_count,
+			param_count = excluded.param_count,
+			max_nesting = excluded.max_nesting,
+			return_count = excluded.return_count,
+			identifier_terms = excluded.identifier_terms
 		RETURNING id, created_at
 	`
 	now := time.Now()
+	typeParams, typeConstraints := encodeTypeParams(symbol.TypeParams)
 	err := q.QueryRowContext(ctx, query,
 		symbol.FileID, symbol.Name, symbol.Kind, symbol.PackageName,
 		symbol.Signature, symbol.DocComment, symbol.Scope, symbol.Receiver,
 		symbol.StartLine, symbol.StartCol, symbol.EndLine, symbol.EndCol,
 		symbol.IsAggregateRoot, symbol.IsEntity, symbol.IsValueObject, symbol.IsRepository,
-		symbol.IsService, symbol.IsCommand, symbol.IsQuery, symbol.IsHandler, now,
+		symbol.IsService, symbol.IsCommand, symbol.IsQuery, symbol.IsHandler,
+		typeParams, typeConstraints, strings.Join(symbol.Embeds, ";"),
+		symbol.Metrics.Cyclomatic, symbol.Metrics.Cognitive, symbol.Metrics.Lines,
+		symbol.Metrics.Params, symbol.Metrics.Nesting, symbol.Metrics.Returns,
+		tokenize.IdentifierTerms(symbol.Name+" "+symbol.Signature), now,
 	).Scan(&symbol.ID, &symbol.CreatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to upsert symbol: %w", err)
 	}
 
+	if err := replaceSymbolRefs(ctx, q, symbol); err != nil {
+		return err
+	}
+	switch types.SymbolKind(symbol.Kind) {
+	case types.KindField:
+		return replaceSymbolTags(ctx, q, symbol)
+	case types.KindStruct, types.KindInterface, types.KindType:
+		return replaceSymbolPatterns(ctx, q, symbol.ID, symbol.DDD)
+	}
 	return nil
 }
 
+// replaceSymbolTags replaces the struct tag keys stored for a field symbol
+func replaceSymbolTags(ctx context.Context, q querier, symbol *Symbol) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM symbol_tags WHERE symbol_id = ?`, symbol.ID); err != nil {
+		return fmt.Errorf("failed to delete symbol tags: %w", err)
+	}
+	for _, tag := range symbol.Tags {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO symbol_tags (symbol_id, key, value, name) VALUES (?, ?, ?, ?)`,
+			symbol.ID, tag.Key, tag.Value, tag.Name(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert symbol tag: %w", err)
+		}
+	}
+	return nil
+}
+
+// replaceSymbolRefs replaces the references stored for a symbol
+func replaceSymbolRefs(ctx context.Context, q querier, symbol *Symbol) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM symbol_refs WHERE symbol_id = ?`, symbol.ID); err != nil {
+		return fmt.Errorf("failed to delete symbol refs: %w", err)
+	}
+	for _, ref := range symbol.Refs {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO symbol_refs (symbol_id, kind, package, name, via) VALUES (?, ?, ?, ?, ?)`,
+			symbol.ID, ref.Kind, ref.Package, ref.Name, ref.Via,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert symbol ref: %w", err)
+		}
+	}
+	return nil
+}
+
+// listSymbolRefsWithQuerier returns the references of every symbol of a project,
+// ordered by symbol
+func listSymbolRefsWithQuerier(ctx context.Context, q querier, projectID int64) ([]*SymbolRef, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT r.symbol_id, r.kind, r.package, r.name, r.via
+		FROM symbol_refs r
+		INNER JOIN symbols s ON r.symbol_id = s.id
+		INNER JOIN files f ON s.file_id = f.id
+		WHERE f.project_id = ?
+		ORDER BY r.symbol_id, r.rowid
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbol refs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	refs := make([]*SymbolRef, 0)
+	for rows.Next() {
+		var ref SymbolRef
+		if err := rows.Scan(&ref.SymbolID, &ref.Kind, &ref.Package, &ref.Name, &ref.Via); err != nil {
+			return nil, err
+		}
+		refs = append(refs, &ref)
+	}
+	return refs, rows.Err()
+}
+
+// ListSymbolRefs returns the names referred to by the project's symbols
+func (s *SQLiteStorage) ListSymbolRefs(ctx context.Context, projectID int64) ([]*SymbolRef, error) {
+	return listSymbolRefsWithQuerier(ctx, s.querier(), projectID)
+}
+
+// replaceSymbolRelationsWithQuerier replaces the relations starting at the project's symbols
+func replaceSymbolRelationsWithQuerier(ctx context.Context, q querier, projectID int64, relations []*SymbolRelation) error {
+	_, err := q.ExecContext(ctx, `
+		DELETE FROM symbol_relations WHERE from_symbol_id IN (
+			SELECT s.id FROM symbols s INNER JOIN files f ON s.file_id = f.id WHERE f.project_id = ?
+		)
+	`, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to delete symbol relations: %w", err)
+	}
+	for _, r := range relations {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO symbol_relations (from_symbol_id, to_symbol_id, kind, reason) VALUES (?, ?, ?, ?)
+			 ON CONFLICT(from_symbol_id, to_symbol_id, kind) DO NOTHING`,
+			r.FromID, r.ToID, r.Kind, r.Reason,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert symbol relation: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReplaceSymbolRelations replaces the relations inferred for a project
+func (s *SQLiteStorage) ReplaceSymbolRelations(ctx context.Context, projectID int64, relations []*SymbolRelation) error {
+	return replaceSymbolRelationsWithQuerier(ctx, s.querier(), projectID, relations)
+}
+
+// listSymbolRelationsWithQuerier returns the relations starting at a symbol
+func listSymbolRelationsWithQuerier(ctx context.Context, q querier, symbolID int64) ([]*SymbolRelation, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT r.from_symbol_id, r.to_symbol_id, r.kind, r.reason
+		FROM symbol_relations r
+		INNER JOIN symbols s ON r.to_symbol_id = s.id
+		WHERE r.from_symbol_id = ?
+		ORDER BY r.kind, s.name, r.to_symbol_id
+	`, symbolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbol relations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	relations := make([]*SymbolRelation, 0)
+	for rows.Next() {
+		var r SymbolRelation
+		if err := rows.Scan(&r.FromID, &r.ToID, &r.Kind, &r.Reason); err != nil {
+			return nil, err
+		}
+		relations = append(relations, &r)
+	}
+	return relations, rows.Err()
+}
+
+// ListSymbolRelations returns the relations starting at a symbol, ordered by kind
+// and target name
+func (s *SQLiteStorage) ListSymbolRelations(ctx context.Context, symbolID int64) ([]*SymbolRelation, error) {
+	return listSymbolRelationsWithQuerier(ctx, s.querier(), symbolID)
+}
+
+// replaceSymbolPatterns replaces the DDD patterns stored for a type symbol
+func replaceSymbolPatterns(ctx context.Context, q querier, symbolID int64, matches []types.DDDMatch) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM symbol_patterns WHERE symbol_id = ?`, symbolID); err != nil {
+		return fmt.Errorf("failed to delete symbol patterns: %w", err)
+	}
+	for _, m := range matches {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO symbol_patterns (symbol_id, pattern, confidence, reason) VALUES (?, ?, ?, ?)
+			 ON CONFLICT(symbol_id, pattern) DO NOTHING`,
+			symbolID, m.Pattern, m.Confidence, m.Reason,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert symbol pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// updateSymbolPatternsWithQuerier sets a symbol's DDD flags and stored patterns from matches
+func updateSymbolPatternsWithQuerier(ctx context.Context, q querier, symbolID int64, matches []types.DDDMatch) error {
+	flags := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		flags[m.Pattern] = true
+	}
+	_, err := q.ExecContext(ctx, `
+		UPDATE symbols SET
+			is_aggregate_root = ?, is_entity = ?, is_value_object = ?, is_repository = ?,
+			is_service = ?, is_command = ?, is_query = ?, is_handler = ?
+		WHERE id = ?
+	`,
+		flags["aggregate"], flags["entity"], flags["value_object"], flags["repository"],
+		flags["service"], flags["command"], flags["query"], flags["handler"], symbolID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update symbol patterns: %w", err)
+	}
+	return replaceSymbolPatterns(ctx, q, symbolID, matches)
+}
+
+// UpdateSymbolPatterns replaces a type's DDD patterns and flags, e.g. after
+// classifying it with the project's rules
+func (s *SQLiteStorage) UpdateSymbolPatterns(ctx context.Context, symbolID int64, matches []types.DDDMatch) error {
+	return updateSymbolPatternsWithQuerier(ctx, s.querier(), symbolID, matches)
+}
+
+// listSymbolPatterns returns the DDD patterns stored for a symbol, most confident first
+func listSymbolPatterns(ctx context.Context, q querier, symbolID int64) ([]types.DDDMatch, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT pattern, confidence, reason FROM symbol_patterns
+		WHERE symbol_id = ?
+		ORDER BY confidence DESC, pattern
+	`, symbolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbol patterns: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var matches []types.DDDMatch
+	for rows.Next() {
+		var m types.DDDMatch
+		if err := rows.Scan(&m.Pattern, &m.Confidence, &m.Reason); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
 func (s *SQLiteStorage) UpsertSymbol(ctx context.Context, symbol *Symbol) error {
 	return s.upsertSymbolWithQuerier(ctx, s.querier(), symbol)
 }
@@ -410,17 +692,22 @@ func (s *SQLiteStorage) GetSymbol(ctx context.Context, symbolID int64) (*Symbol,
 		SELECT id, file_id, name, kind, package_name, signature, doc_comment, scope, receiver,
 		       start_line, start_col, end_line, end_col,
 		       is_aggregate_root, is_entity, is_value_object, is_repository,
-		       is_service, is_command, is_query, is_handler, created_at
+		       is_service, is_command, is_query, is_handler, type_params, type_constraints, embeds,
+		       cyclomatic, cognitive, line_count, param_count, max_nesting, return_count, created_at
 		FROM symbols
 		WHERE id = ?
 	`
 	var symbol Symbol
+	var typeParams, typeConstraints, embeds string
 	err := s.db.QueryRowContext(ctx, query, symbolID).Scan(
 		&symbol.ID, &symbol.FileID, &symbol.Name, &symbol.Kind, &symbol.PackageName,
 		&symbol.Signature, &symbol.DocComment, &symbol.Scope, &symbol.Receiver,
 		&symbol.StartLine, &symbol.StartCol, &symbol.EndLine, &symbol.EndCol,
 		&symbol.IsAggregateRoot, &symbol.IsEntity, &symbol.IsValueObject, &symbol.IsRepository,
-		&symbol.IsService, &symbol.IsCommand, &symbol.IsQuery, &symbol.IsHandler, &symbol.CreatedAt,
+		&symbol.IsService, &symbol.IsCommand, &symbol.IsQuery, &symbol.IsHandler,
+		&typeParams, &typeConstraints, &embeds,
+		&symbol.Metrics.Cyclomatic, &symbol.Metrics.Cognitive, &symbol.Metrics.Lines,
+		&symbol.Metrics.Params, &symbol.Metrics.Nesting, &symbol.Metrics.Returns, &symbol.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
@@ -428,6 +715,11 @@ func (s *SQLiteStorage) GetSymbol(ctx context.Context, symbolID int64) (*Symbol,
 	if err != nil {
 		return nil, err
 	}
+	symbol.TypeParams = decodeTypeParams(typeParams, typeConstraints)
+	symbol.Embeds = splitList(embeds, ";")
+	if symbol.DDD, err = listSymbolPatterns(ctx, s.db, symbolID); err != nil {
+		return nil, err
+	}
 	return &symbol, nil
 }
 
@@ -436,7 +728,8 @@ func (s *SQLiteStorage) ListSymbolsByFile(ctx context.Context, fileID int64) ([]
 		SELECT id, file_id, name, kind, package_name, signature, doc_comment, scope, receiver,
 		       start_line, start_col, end_line, end_col,
 		       is_aggregate_root, is_entity, is_value_object, is_repository,
-		       is_service, is_command, is_query, is_handler, created_at
+		       is_service, is_command, is_query, is_handler, type_params, type_constraints, embeds,
+		       cyclomatic, cognitive, line_count, param_count, max_nesting, return_count, created_at
 		FROM symbols
 		WHERE file_id = ?
 		ORDER BY start_line
@@ -445,26 +738,218 @@ func (s *SQLiteStorage) ListSymbolsByFile(ctx context.Context, fileID int64) ([]
 	if err != nil {
 		return nil, err
 	}
+	return scanSymbols(rows)
+}
+
+// ListSymbolsByName returns the project's symbols with the given name, ordered by file and line
+func (s *SQLiteStorage) ListSymbolsByName(ctx context.Context, projectID int64, name string) ([]*Symbol, error) {
+	query := `
+		SELECT s.id, s.file_id, s.name, s.kind, s.package_name, s.signature, s.doc_comment, s.scope, s.receiver,
+		       s.start_line, s.start_col, s.end_line, s.end_col,
+		       s.is_aggregate_root, s.is_entity, s.is_value_object, s.is_repository,
+		       s.is_service, s.is_command, s.is_query, s.is_handler, s.type_params, s.type_constraints, s.embeds,
+		       s.cyclomatic, s.cognitive, s.line_count, s.param_count, s.max_nesting, s.return_count, s.created_at
+		FROM symbols s
+		INNER JOIN files f ON s.file_id = f.id
+		WHERE f.project_id = ? AND s.name = ?
+		ORDER BY f.file_path, s.start_line
+	`
+	rows, err := s.db.QueryContext(ctx, query, projectID, name)
+	if err != nil {
+		return nil, err
+	}
+	return scanSymbols(rows)
+}
+
+// FindFieldsByTag returns the project's struct fields with a tag key matching filter,
+// one result per matching key, ordered by file and line
+func (s *SQLiteStorage) FindFieldsByTag(ctx context.Context, projectID int64, filter TagFilter) ([]*TaggedField, error) {
+	query := `
+		SELECT s.id, s.file_id, s.name, s.kind, s.package_name, s.signature, s.doc_comment, s.scope, s.receiver,
+		       s.start_line, s.start_col, s.end_line, s.end_col, s.created_at,
+		       t.key, t.value, f.file_path, f.import_path
+		FROM symbol_tags t
+		INNER JOIN symbols s ON t.symbol_id = s.id
+		INNER JOIN files f ON s.file_id = f.id
+		WHERE f.project_id = ?
+	`
+	args := []interface{}{projectID}
+
+	if filter.Key != "" {
+		query += " AND t.key = ?"
+		args = append(args, filter.Key)
+	}
+	if filter.Name != "" {
+		query += " AND t.name = ?"
+		args = append(args, filter.Name)
+	}
+	if len(filter.Packages) > 0 {
+		query += " AND f.package_name IN (" + placeholders(len(filter.Packages)) + ")"
+		for _, pkg := range filter.Packages {
+			args = append(args, pkg)
+		}
+	}
+	if filter.FilePattern != "" {
+		query += " AND f.file_path GLOB ?"
+		args = append(args, filter.FilePattern)
+	}
+	query += " ORDER BY f.file_path, s.start_line, t.rowid"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find fields by tag: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	fields := make([]*TaggedField, 0)
+	for rows.Next() {
+		var f TaggedField
+		err := rows.Scan(
+			&f.ID, &f.FileID, &f.Name, &f.Kind, &f.PackageName, &f.Signature, &f.DocComment, &f.Scope, &f.Receiver,
+			&f.StartLine, &f.StartCol, &f.EndLine, &f.EndCol, &f.CreatedAt,
+			&f.Tag.Key, &f.Tag.Value, &f.FilePath, &f.ImportPath,
+		)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, &f)
+	}
+	return fields, rows.Err()
+}
+
+// metricColumns maps metric names to their symbols columns
+var metricColumns = map[string]string{
+	types.MetricCyclomatic: "cyclomatic",
+	types.MetricCognitive:  "cognitive",
+	types.MetricLines:      "line_count",
+	types.MetricParams:     "param_count",
+	types.MetricNesting:    "max_nesting",
+	types.MetricReturns:    "return_count",
+}
+
+// ListFunctionMetrics returns the project's functions and methods with their
+// metrics, file size and churn, ordered by file and line
+func (s *SQLiteStorage) ListFunctionMetrics(ctx context.Context, projectID int64, filter MetricsFilter) ([]*FunctionMetrics, error) {
+	query := `
+		SELECT s.id, s.file_id, s.name, s.kind, s.package_name, s.signature, s.doc_comment, s.scope, s.receiver,
+		       s.start_line, s.start_col, s.end_line, s.end_col, s.created_at,
+		       s.cyclomatic, s.cognitive, s.line_count, s.param_count, s.max_nesting, s.return_count,
+		       f.file_path, f.import_path, COALESCE(f.size_bytes, 0),
+		       COALESCE(MAX(c.change_count), 0), COALESCE(MAX(c.last_modified IS NOT NULL), 0)
+		FROM symbols s
+		INNER JOIN files f ON s.file_id = f.id
+		LEFT JOIN chunks c ON c.symbol_id = s.id
+		WHERE f.project_id = ? AND s.kind IN ('function', 'method') AND s.cyclomatic > 0
+	`
+	args := []interface{}{projectID}
+
+	if len(filter.Packages) > 0 {
+		query += " AND f.package_name IN (" + placeholders(len(filter.Packages)) + ")"
+		for _, pkg := range filter.Packages {
+			args = append(args, pkg)
+		}
+	}
+	if filter.FilePattern != "" {
+		query += " AND f.file_path GLOB ?"
+		args = append(args, filter.FilePattern)
+	}
+	if !filter.IncludeTests {
+		query += " AND f.file_path NOT LIKE '%\\_test.go' ESCAPE '\\'"
+	}
+	query += " GROUP BY s.id ORDER BY f.file_path, s.start_line"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list function metrics: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	functions := make([]*FunctionMetrics, 0)
+	for rows.Next() {
+		var f FunctionMetrics
+		err := rows.Scan(
+			&f.ID, &f.FileID, &f.Name, &f.Kind, &f.PackageName, &f.Signature, &f.DocComment, &f.Scope, &f.Receiver,
+			&f.StartLine, &f.StartCol, &f.EndLine, &f.EndCol, &f.CreatedAt,
+			&f.Metrics.Cyclomatic, &f.Metrics.Cognitive, &f.Metrics.Lines,
+			&f.Metrics.Params, &f.Metrics.Nesting, &f.Metrics.Returns,
+			&f.FilePath, &f.ImportPath, &f.FileSize, &f.Churn, &f.HasHistory,
+		)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, &f)
+	}
+	return functions, rows.Err()
+}
+
+// scanSymbols reads and closes rows of symbol columns
+func scanSymbols(rows *sql.Rows) ([]*Symbol, error) {
 	defer func() { _ = rows.Close() }()
 
 	symbols := make([]*Symbol, 0)
 	for rows.Next() {
 		var symbol Symbol
+		var typeParams, typeConstraints, embeds string
 		err := rows.Scan(
 			&symbol.ID, &symbol.FileID, &symbol.Name, &symbol.Kind, &symbol.PackageName,
 			&symbol.Signature, &symbol.DocComment, &symbol.Scope, &symbol.Receiver,
 			&symbol.StartLine, &symbol.StartCol, &symbol.EndLine, &symbol.EndCol,
 			&symbol.IsAggregateRoot, &symbol.IsEntity, &symbol.IsValueObject, &symbol.IsRepository,
-			&symbol.IsService, &symbol.IsCommand, &symbol.IsQuery, &symbol.IsHandler, &symbol.CreatedAt,
+			&symbol.IsService, &symbol.IsCommand, &symbol.IsQuery, &symbol.IsHandler,
+			&typeParams, &typeConstraints, &embeds,
+			&symbol.Metrics.Cyclomatic, &symbol.Metrics.Cognitive, &symbol.Metrics.Lines,
+			&symbol.Metrics.Params, &symbol.Metrics.Nesting, &symbol.Metrics.Returns, &symbol.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		symbol.TypeParams = decodeTypeParams(typeParams, typeConstraints)
+		symbol.Embeds = splitList(embeds, ";")
 		symbols = append(symbols, &symbol)
 	}
 	return symbols, rows.Err()
 }
 
+// encodeTypeParams stores type parameters as comma-separated names and
+// semicolon-separated constraints; constraints may themselves contain commas
+func encodeTypeParams(params []types.TypeParam) (names, constraints string) {
+	if len(params) == 0 {
+		return "", ""
+	}
+	nameList := make([]string, len(params))
+	constraintList := make([]string, len(params))
+	for i, p := range params {
+		nameList[i] = p.Name
+		constraintList[i] = p.Constraint
+	}
+	return strings.Join(nameList, ","), strings.Join(constraintList, ";")
+}
+
+// splitList splits a sep-joined column, returning nil for an empty one
+func splitList(value, sep string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, sep)
+}
+
+// decodeTypeParams reverses encodeTypeParams
+func decodeTypeParams(names, constraints string) []types.TypeParam {
+	if names == "" {
+		return nil
+	}
+	nameList := strings.Split(names, ",")
+	constraintList := strings.Split(constraints, ";")
+	params := make([]types.TypeParam, len(nameList))
+	for i, name := range nameList {
+		params[i].Name = name
+		if i < len(constraintList) {
+			params[i].Constraint = constraintList[i]
+		}
+	}
+	return params
+}
+
 func (s *SQLiteStorage) DeleteSymbolsByFile(ctx context.Context, fileID int64) error {
 	return s.deleteSymbolsByFileWithQuerier(ctx, s.querier(), fileID)
 }
@@ -485,14 +970,19 @@ func (s *SQLiteStorage) searchSymbolsWithQuerier(ctx context.Context, q querier,
 		SELECT s.id, s.file_id, s.name, s.kind, s.package_name, s.signature, s.doc_comment, s.scope, s.receiver,
 		       s.start_line, s.start_col, s.end_line, s.end_col,
 		       s.is_aggregate_root, s.is_entity, s.is_value_object, s.is_repository,
-		       s.is_service, s.is_command, s.is_query, s.is_handler, s.created_at
+		       s.is_service, s.is_command, s.is_query, s.is_handler, s.type_params, s.type_constraints, s.embeds,
+		       s.cyclomatic, s.cognitive, s.line_count, s.param_count, s.max_nesting, s.return_count, s.created_at
 		FROM symbols s
-		JOIN symbols_fts fts ON s.id = fts.symbol_id
-		WHERE fts MATCH ?
+		JOIN symbols_fts ON s.id = symbols_fts.symbol_id
+		WHERE symbols_fts MATCH ?
 		ORDER BY rank
 		LIMIT ?
 	`
-	rows, err := q.QueryContext(ctx, sqlQuery, query, limit)
+	sanitized := sanitizeFTSQuery(query)
+	if sanitized == "" {
+		return []*Symbol{}, nil
+	}
+	rows, err := q.QueryContext(ctx, sqlQuery, sanitized, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -501,16 +991,22 @@ func (s *SQLiteStorage) searchSymbolsWithQuerier(ctx context.Context, q querier,
 	symbols := make([]*Symbol, 0)
 	for rows.Next() {
 		var symbol Symbol
+		var typeParams, typeConstraints, embeds string
 		err := rows.Scan(
 			&symbol.ID, &symbol.FileID, &symbol.Name, &symbol.Kind, &symbol.PackageName,
 			&symbol.Signature, &symbol.DocComment, &symbol.Scope, &symbol.Receiver,
 			&symbol.StartLine, &symbol.StartCol, &symbol.EndLine, &symbol.EndCol,
 			&symbol.IsAggregateRoot, &symbol.IsEntity, &symbol.IsValueObject, &symbol.IsRepository,
-			&symbol.IsService, &symbol.IsCommand, &symbol.IsQuery, &symbol.IsHandler, &symbol.CreatedAt,
+			&symbol.IsService, &symbol.IsCommand, &symbol.IsQuery, &symbol.IsHandler,
+			&typeParams, &typeConstraints, &embeds,
+			&symbol.Metrics.Cyclomatic, &symbol.Metrics.Cognitive, &symbol.Metrics.Lines,
+			&symbol.Metrics.Params, &symbol.Metrics.Nesting, &symbol.Metrics.Returns, &symbol.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		symbol.TypeParams = decodeTypeParams(typeParams, typeConstraints)
+		symbol.Embeds = splitList(embeds, ";")
 		symbols = append(symbols, &symbol)
 	}
 	return symbols, rows.Err()
@@ -534,8 +1030,9 @@ func (s *SQLiteStorage) upsertChunkWithQuerier(ctx context.Context, q querier, c
 		INSERT INTO chunks (
 			file_id, symbol_id, content, content_hash, token_count,
 			start_line, end_line, context_before, context_after, chunk_type,
-			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			identifier_terms, created_at, updated_at,
+			last_commit, last_author, last_modified, change_count
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(file_id, start_line, end_line)
 		DO UPDATE SET
 			symbol_id = excluded.symbol_id,
@@ -545,7 +1042,12 @@ func (s *SQLiteStorage) upsertChunkWithQuerier(ctx context.Context, q querier, c
 			context_before = excluded.context_before,
 			context_after = excluded.context_after,
 			chunk_type = excluded.chunk_type,
-			updated_at = excluded.updated_at
+			identifier_terms = excluded.identifier_terms,
+			updated_at = excluded.updated_at,
+			last_commit = excluded.last_commit,
+			last_author = excluded.last_author,
+			last_modified = excluded.last_modified,
+			change_count = excluded.change_count
 		RETURNING id, created_at, updated_at
 	`
 	now := time.Now()
@@ -553,7 +1055,9 @@ func (s *SQLiteStorage) upsertChunkWithQuerier(ctx context.Context, q querier, c
 		chunk.FileID, symbolID, chunk.Content, chunk.ContentHash[:],
 		chunk.TokenCount, chunk.StartLine, chunk.EndLine,
 		chunk.ContextBefore, chunk.ContextAfter, chunk.ChunkType,
-		now, now,
+		tokenize.IdentifierTerms(chunk.Content), now, now,
+		chunk.History.LastCommit, chunk.History.LastAuthor, nullTime(chunk.History.LastModified),
+		chunk.History.ChangeCount,
 	).Scan(&chunk.ID, &chunk.CreatedAt, &chunk.UpdatedAt)
 
 	if err != nil {
@@ -568,21 +1072,28 @@ func (s *SQLiteStorage) UpsertChunk(ctx context.Context, chunk *Chunk) error {
 }
 
 func (s *SQLiteStorage) GetChunk(ctx context.Context, chunkID int64) (*Chunk, error) {
+	return s.getChunkWithQuerier(ctx, s.querier(), chunkID)
+}
+
+// getChunkWithQuerier is the internal implementation that uses a querier
+func (s *SQLiteStorage) getChunkWithQuerier(ctx context.Context, q querier, chunkID int64) (*Chunk, error) {
 	query := `
 		SELECT id, file_id, symbol_id, content, content_hash, token_count,
 		       start_line, end_line, context_before, context_after, chunk_type,
-		       created_at, updated_at
+		       created_at, updated_at, last_commit, last_author, last_modified, change_count
 		FROM chunks
 		WHERE id = ?
 	`
 	var chunk Chunk
 	var hash []byte
 	var symbolID sql.NullInt64
+	var lastModified sql.NullTime
 
-	err := s.db.QueryRowContext(ctx, query, chunkID).Scan(
+	err := q.QueryRowContext(ctx, query, chunkID).Scan(
 		&chunk.ID, &chunk.FileID, &symbolID, &chunk.Content, &hash, &chunk.TokenCount,
 		&chunk.StartLine, &chunk.EndLine, &chunk.ContextBefore, &chunk.ContextAfter,
 		&chunk.ChunkType, &chunk.CreatedAt, &chunk.UpdatedAt,
+		&chunk.History.LastCommit, &chunk.History.LastAuthor, &lastModified, &chunk.History.ChangeCount,
 	)
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
@@ -596,20 +1107,28 @@ func (s *SQLiteStorage) GetChunk(ctx context.Context, chunkID int64) (*Chunk, er
 		id := symbolID.Int64
 		chunk.SymbolID = &id
 	}
+	if lastModified.Valid {
+		chunk.History.LastModified = lastModified.Time
+	}
 
 	return &chunk, nil
 }
 
 func (s *SQLiteStorage) ListChunksByFile(ctx context.Context, fileID int64) ([]*Chunk, error) {
+	return s.listChunksByFileWithQuerier(ctx, s.querier(), fileID)
+}
+
+// listChunksByFileWithQuerier is the internal implementation that uses a querier
+func (s *SQLiteStorage) listChunksByFileWithQuerier(ctx context.Context, q querier, fileID int64) ([]*Chunk, error) {
 	query := `
 		SELECT id, file_id, symbol_id, content, content_hash, token_count,
 		       start_line, end_line, context_before, context_after, chunk_type,
-		       created_at, updated_at
+		       created_at, updated_at, last_commit, last_author, last_modified, change_count
 		FROM chunks
 		WHERE file_id = ?
 		ORDER BY start_line
 	`
-	rows, err := s.db.QueryContext(ctx, query, fileID)
+	rows, err := q.QueryContext(ctx, query, fileID)
 	if err != nil {
 		return nil, err
 	}
@@ -620,11 +1139,13 @@ func (s *SQLiteStorage) ListChunksByFile(ctx context.Context, fileID int64) ([]*
 		var chunk Chunk
 		var hash []byte
 		var symbolID sql.NullInt64
+		var lastModified sql.NullTime
 
 		err := rows.Scan(
 			&chunk.ID, &chunk.FileID, &symbolID, &chunk.Content, &hash, &chunk.TokenCount,
 			&chunk.StartLine, &chunk.EndLine, &chunk.ContextBefore, &chunk.ContextAfter,
 			&chunk.ChunkType, &chunk.CreatedAt, &chunk.UpdatedAt,
+			&chunk.History.LastCommit, &chunk.History.LastAuthor, &lastModified, &chunk.History.ChangeCount,
 		)
 		if err != nil {
 			return nil, err
@@ -635,12 +1156,44 @@ func (s *SQLiteStorage) ListChunksByFile(ctx context.Context, fileID int64) ([]*
 			id := symbolID.Int64
 			chunk.SymbolID = &id
 		}
+		if lastModified.Valid {
+			chunk.History.LastModified = lastModified.Time
+		}
 
 		chunks = append(chunks, &chunk)
 	}
 	return chunks, rows.Err()
 }
 
+// UpdateChunkHistory replaces the git history of a chunk
+func (s *SQLiteStorage) UpdateChunkHistory(ctx context.Context, chunkID int64, history types.ChunkHistory) error {
+	return s.updateChunkHistoryWithQuerier(ctx, s.querier(), chunkID, history)
+}
+
+// updateChunkHistoryWithQuerier is the internal implementation that uses a querier
+func (s *SQLiteStorage) updateChunkHistoryWithQuerier(ctx context.Context, q querier, chunkID int64, history types.ChunkHistory) error {
+	query := `
+		UPDATE chunks
+		SET last_commit = ?, last_author = ?, last_modified = ?, change_count = ?
+		WHERE id = ?
+	`
+	_, err := q.ExecContext(ctx, query,
+		history.LastCommit, history.LastAuthor, nullTime(history.LastModified), history.ChangeCount, chunkID)
+	if err != nil {
+		return fmt.Errorf("failed to update chunk history: %w", err)
+	}
+	return nil
+}
+
+// nullTime stores the zero time as NULL. Times are normalized to whole UTC
+// seconds so the stored text compares in chronological order.
+func nullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Truncate(time.Second)
+}
+
 // DeleteChunk deletes a single chunk by ID
 func (s *SQLiteStorage) DeleteChunk(ctx context.Context, chunkID int64) error {
 	return s.deleteChunkWithQuerier(ctx, s.querier(), chunkID)
@@ -777,16 +1330,23 @@ func (s *SQLiteStorage) SearchText(ctx context.Context, projectID int64, query s
 	return searchText(ctx, s.db, projectID, query, limit, filters)
 }
 
+// HighlightChunks returns the content of each chunk matching query, with FTS5 hits
+// wrapped in HighlightStart/HighlightEnd. Chunks that do not match are omitted.
+func (s *SQLiteStorage) HighlightChunks(ctx context.Context, chunkIDs []int64, query string) (map[int64]string, error) {
+	// Implementation moved to separate file for clarity
+	return highlightChunks(ctx, s.db, chunkIDs, query)
+}
+
 // Import operations
 
 // upsertImportWithQuerier is the internal implementation that uses a querier
 func (s *SQLiteStorage) upsertImportWithQuerier(ctx context.Context, q querier, imp *Import) error {
 	query := `
-		INSERT INTO imports (file_id, import_path, alias, created_at)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO imports (file_id, import_path, alias, line, created_at)
+		VALUES (?, ?, ?, ?, ?)
 	`
 	now := time.Now()
-	result, err := q.ExecContext(ctx, query, imp.FileID, imp.ImportPath, imp.Alias, now)
+	result, err := q.ExecContext(ctx, query, imp.FileID, imp.ImportPath, imp.Alias, imp.Line, now)
 	if err != nil {
 		return fmt.Errorf("failed to upsert import: %w", err)
 	}
@@ -807,7 +1367,7 @@ func (s *SQLiteStorage) UpsertImport(ctx context.Context, imp *Import) error {
 
 func (s *SQLiteStorage) ListImportsByFile(ctx context.Context, fileID int64) ([]*Import, error) {
 	query := `
-		SELECT id, file_id, import_path, alias, created_at
+		SELECT id, file_id, import_path, alias, line, created_at
 		FROM imports
 		WHERE file_id = ?
 		ORDER BY import_path
@@ -821,7 +1381,34 @@ func (s *SQLiteStorage) ListImportsByFile(ctx context.Context, fileID int64) ([]
 	imports := make([]*Import, 0)
 	for rows.Next() {
 		var imp Import
-		err := rows.Scan(&imp.ID, &imp.FileID, &imp.ImportPath, &imp.Alias, &imp.CreatedAt)
+		err := rows.Scan(&imp.ID, &imp.FileID, &imp.ImportPath, &imp.Alias, &imp.Line, &imp.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		imports = append(imports, &imp)
+	}
+	return imports, rows.Err()
+}
+
+// ListImportsByProject returns the imports of every file of a project, ordered by file and path
+func (s *SQLiteStorage) ListImportsByProject(ctx context.Context, projectID int64) ([]*Import, error) {
+	query := `
+		SELECT i.id, i.file_id, i.import_path, i.alias, i.line, i.created_at
+		FROM imports i
+		INNER JOIN files f ON i.file_id = f.id
+		WHERE f.project_id = ?
+		ORDER BY i.file_id, i.import_path
+	`
+	rows, err := s.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	imports := make([]*Import, 0)
+	for rows.Next() {
+		var imp Import
+		err := rows.Scan(&imp.ID, &imp.FileID, &imp.ImportPath, &imp.Alias, &imp.Line, &imp.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -841,6 +1428,402 @@ func (s *SQLiteStorage) deleteImportsByFileWithQuerier(ctx context.Context, q qu
 	return err
 }
 
+// Annotation operations
+
+func (s *SQLiteStorage) InsertAnnotation(ctx context.Context, annotation *Annotation) error {
+	return s.insertAnnotationWithQuerier(ctx, s.querier(), annotation)
+}
+
+// insertAnnotationWithQuerier is the internal implementation that uses a querier
+func (s *SQLiteStorage) insertAnnotationWithQuerier(ctx context.Context, q querier, a *Annotation) error {
+	query := `
+		INSERT INTO annotations (file_id, kind, text, line, symbol_name, owner, issues)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING id, created_at
+	`
+	err := q.QueryRowContext(ctx, query,
+		a.FileID, a.Kind, a.Text, a.Line, a.Symbol, a.Owner, strings.Join(a.Issues, ","),
+	).Scan(&a.ID, &a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert annotation: %w", err)
+	}
+	return nil
+}
+
+// ListAnnotations returns a project's annotations matching filter, ordered by file and line
+func (s *SQLiteStorage) ListAnnotations(ctx context.Context, projectID int64, filter AnnotationFilter) ([]*Annotation, error) {
+	query := `
+		SELECT a.id, a.file_id, a.kind, a.text, a.line, a.symbol_name, a.owner, a.issues,
+		       a.created_at, f.file_path, f.package_name
+		FROM annotations a
+		INNER JOIN files f ON a.file_id = f.id
+		WHERE f.project_id = ?
+	`
+	args := []interface{}{projectID}
+
+	if len(filter.Kinds) > 0 {
+		query += " AND a.kind IN (" + placeholders(len(filter.Kinds)) + ")"
+		for _, kind := range filter.Kinds {
+			args = append(args, kind)
+		}
+	}
+	if len(filter.Packages) > 0 {
+		query += " AND f.package_name IN (" + placeholders(len(filter.Packages)) + ")"
+		for _, pkg := range filter.Packages {
+			args = append(args, pkg)
+		}
+	}
+	if filter.Owner != "" {
+		query += " AND a.owner = ? COLLATE NOCASE"
+		args = append(args, filter.Owner)
+	}
+	if filter.FilePattern != "" {
+		query += " AND f.file_path GLOB ?"
+		args = append(args, filter.FilePattern)
+	}
+	query += " ORDER BY f.file_path, a.line"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list annotations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	annotations := make([]*Annotation, 0)
+	for rows.Next() {
+		var a Annotation
+		var issues string
+		err := rows.Scan(&a.ID, &a.FileID, &a.Kind, &a.Text, &a.Line, &a.Symbol, &a.Owner, &issues,
+			&a.CreatedAt, &a.FilePath, &a.PackageName)
+		if err != nil {
+			return nil, err
+		}
+		if issues != "" {
+			a.Issues = strings.Split(issues, ",")
+		}
+		annotations = append(annotations, &a)
+	}
+	return annotations, rows.Err()
+}
+
+func (s *SQLiteStorage) DeleteAnnotationsByFile(ctx context.Context, fileID int64) error {
+	return s.deleteAnnotationsByFileWithQuerier(ctx, s.querier(), fileID)
+}
+
+// deleteAnnotationsByFileWithQuerier is the internal implementation that uses a querier
+func (s *SQLiteStorage) deleteAnnotationsByFileWithQuerier(ctx context.Context, q querier, fileID int64) error {
+	_, err := q.ExecContext(ctx, `DELETE FROM annotations WHERE file_id = ?`, fileID)
+	return err
+}
+
+// Test operations
+
+func (s *SQLiteStorage) InsertTestFunction(ctx context.Context, test *TestFunction) error {
+	return s.insertTestFunctionWithQuerier(ctx, s.querier(), test)
+}
+
+// insertTestFunctionWithQuerier is the internal implementation that uses a querier
+func (s *SQLiteStorage) insertTestFunctionWithQuerier(ctx context.Context, q querier, test *TestFunction) error {
+	targetName, targetMember, _ := strings.Cut(test.Target, ".")
+	query := `
+		INSERT INTO test_functions (file_id, name, kind, start_line, end_line, target_name, target_member)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING id, created_at
+	`
+	err := q.QueryRowContext(ctx, query,
+		test.FileID, test.Name, test.Kind, test.StartLine, test.EndLine, targetName, targetMember,
+	).Scan(&test.ID, &test.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert test function: %w", err)
+	}
+
+	for _, call := range test.Calls {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO test_calls (test_id, package, name, is_method) VALUES (?, ?, ?, ?)`,
+			test.ID, call.Package, call.Name, call.Method,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert test call: %w", err)
+		}
+	}
+	return nil
+}
+
+// FindTests returns the project's test functions named after, or calling, any of
+// names, with all their calls. Matching a name is not proof of a link: callers
+// resolve packages and receivers.
+func (s *SQLiteStorage) FindTests(ctx context.Context, projectID int64, names []string) ([]*TestFunction, error) {
+	if len(names) == 0 {
+		return []*TestFunction{}, nil
+	}
+	in := placeholders(len(names))
+	query := `
+		SELECT t.id, t.file_id, t.name, t.kind, t.start_line, t.end_line, t.target_name, t.target_member,
+		       t.created_at, f.file_path, f.package_name, f.import_path
+		FROM test_functions t
+		INNER JOIN files f ON t.file_id = f.id
+		WHERE f.project_id = ?
+		  AND (t.target_name IN (` + in + `) OR t.target_member IN (` + in + `)
+		       OR t.id IN (SELECT test_id FROM test_calls WHERE name IN (` + in + `)))
+		ORDER BY f.file_path, t.start_line
+	`
+	args := []interface{}{projectID}
+	for i := 0; i < 3; i++ {
+		for _, name := range names {
+			args = append(args, name)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tests: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	tests := make([]*TestFunction, 0)
+	byID := make(map[int64]*TestFunction)
+	for rows.Next() {
+		var t TestFunction
+		var targetName, targetMember string
+		err := rows.Scan(&t.ID, &t.FileID, &t.Name, &t.Kind, &t.StartLine, &t.EndLine, &targetName, &targetMember,
+			&t.CreatedAt, &t.FilePath, &t.PackageName, &t.ImportPath)
+		if err != nil {
+			return nil, err
+		}
+		t.Target = targetName
+		if targetMember != "" {
+			t.Target += "." + targetMember
+		}
+		tests = append(tests, &t)
+		byID[t.ID] = &t
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(tests) == 0 {
+		return tests, nil
+	}
+
+	ids := make([]interface{}, 0, len(tests))
+	for _, t := range tests {
+		ids = append(ids, t.ID)
+	}
+	callRows, err := s.db.QueryContext(ctx,
+		`SELECT test_id, package, name, is_method FROM test_calls WHERE test_id IN (`+placeholders(len(ids))+`) ORDER BY rowid`,
+		ids...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list test calls: %w", err)
+	}
+	defer func() { _ = callRows.Close() }()
+
+	for callRows.Next() {
+		var testID int64
+		var call TestCall
+		if err := callRows.Scan(&testID, &call.Package, &call.Name, &call.Method); err != nil {
+			return nil, err
+		}
+		byID[testID].Calls = append(byID[testID].Calls, call)
+	}
+	return tests, callRows.Err()
+}
+
+func (s *SQLiteStorage) DeleteTestsByFile(ctx context.Context, fileID int64) error {
+	return s.deleteTestsByFileWithQuerier(ctx, s.querier(), fileID)
+}
+
+// deleteTestsByFileWithQuerier is the internal implementation that uses a querier.
+// Calls are removed by the test_calls foreign key cascade.
+func (s *SQLiteStorage) deleteTestsByFileWithQuerier(ctx context.Context, q querier, fileID int64) error {
+	_, err := q.ExecContext(ctx, `DELETE FROM test_functions WHERE file_id = ?`, fileID)
+	return err
+}
+
+// Module operations
+
+// replaceModulesWithQuerier is the internal implementation that uses a querier
+func (s *SQLiteStorage) replaceModulesWithQuerier(ctx context.Context, q querier, projectID int64, modules []*Module) error {
+	// Dependencies cascade with their module
+	if _, err := q.ExecContext(ctx, "DELETE FROM modules WHERE project_id = ?", projectID); err != nil {
+		return fmt.Errorf("failed to delete modules: %w", err)
+	}
+
+	for _, mod := range modules {
+		mod.ProjectID = projectID
+		err := q.QueryRowContext(ctx, `
+			INSERT INTO modules (project_id, module_path, dir, go_version, in_workspace)
+			VALUES (?, ?, ?, ?, ?)
+			RETURNING id
+		`, projectID, mod.ModulePath, mod.Dir, mod.GoVersion, mod.InWorkspace).Scan(&mod.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert module %s: %w", mod.ModulePath, err)
+		}
+
+		for _, dep := range mod.Dependencies {
+			_, err := q.ExecContext(ctx, `
+				INSERT INTO module_dependencies (module_id, path, version, indirect, replace_path, replace_version)
+				VALUES (?, ?, ?, ?, ?, ?)
+			`, mod.ID, dep.Path, dep.Version, dep.Indirect, dep.ReplacePath, dep.ReplaceVersion)
+			if err != nil {
+				return fmt.Errorf("failed to insert dependency %s of %s: %w", dep.Path, mod.ModulePath, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ReplaceModules replaces the project's module list and their dependencies atomically
+func (s *SQLiteStorage) ReplaceModules(ctx context.Context, projectID int64, modules []*Module) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := s.replaceModulesWithQuerier(ctx, tx, projectID, modules); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// listModulesWithQuerier is the internal implementation that uses a querier
+func (s *SQLiteStorage) listModulesWithQuerier(ctx context.Context, q querier, projectID int64) ([]*Module, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT m.id, m.project_id, m.module_path, m.dir, m.go_version, m.in_workspace,
+		       (SELECT COUNT(*) FROM files f WHERE f.project_id = m.project_id AND f.module_path = m.module_path)
+		FROM modules m
+		WHERE m.project_id = ?
+		ORDER BY m.dir
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]*Module, 0)
+	byID := make(map[int64]*Module)
+	for rows.Next() {
+		var mod Module
+		if err := rows.Scan(&mod.ID, &mod.ProjectID, &mod.ModulePath, &mod.Dir, &mod.GoVersion,
+			&mod.InWorkspace, &mod.FilesCount); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		modules = append(modules, &mod)
+		byID[mod.ID] = &mod
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	depRows, err := q.QueryContext(ctx, `
+		SELECT d.module_id, d.path, d.version, d.indirect, d.replace_path, d.replace_version
+		FROM module_dependencies d
+		INNER JOIN modules m ON d.module_id = m.id
+		WHERE m.project_id = ?
+		ORDER BY d.module_id, d.path
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = depRows.Close() }()
+
+	for depRows.Next() {
+		var moduleID int64
+		var dep ModuleDependency
+		if err := depRows.Scan(&moduleID, &dep.Path, &dep.Version, &dep.Indirect,
+			&dep.ReplacePath, &dep.ReplaceVersion); err != nil {
+			return nil, err
+		}
+		if mod, ok := byID[moduleID]; ok {
+			mod.Dependencies = append(mod.Dependencies, dep)
+		}
+	}
+	return modules, depRows.Err()
+}
+
+func (s *SQLiteStorage) ListModules(ctx context.Context, projectID int64) ([]*Module, error) {
+	return s.listModulesWithQuerier(ctx, s.querier(), projectID)
+}
+
+// replaceProjectDependenciesWithQuerier is the internal implementation that uses a querier
+func (s *SQLiteStorage) replaceProjectDependenciesWithQuerier(ctx context.Context, q querier, projectID int64, deps []*ProjectDependency) error {
+	if _, err := q.ExecContext(ctx, "DELETE FROM project_dependencies WHERE project_id = ?", projectID); err != nil {
+		return fmt.Errorf("failed to delete project dependencies: %w", err)
+	}
+
+	for _, dep := range deps {
+		// A module required both directly and indirectly keeps the direct flag
+		_, err := q.ExecContext(ctx, `
+			INSERT INTO project_dependencies (project_id, dependency_id, direct)
+			VALUES (?, ?, ?)
+			ON CONFLICT(project_id, dependency_id) DO UPDATE SET direct = direct OR excluded.direct
+		`, projectID, dep.DependencyID, dep.Direct)
+		if err != nil {
+			return fmt.Errorf("failed to insert project dependency %d: %w", dep.DependencyID, err)
+		}
+	}
+	return nil
+}
+
+// ReplaceProjectDependencies replaces the set of dependency projects linked to a project
+func (s *SQLiteStorage) ReplaceProjectDependencies(ctx context.Context, projectID int64, deps []*ProjectDependency) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := s.replaceProjectDependenciesWithQuerier(ctx, tx, projectID, deps); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// listProjectDependenciesWithQuerier is the internal implementation that uses a querier
+func (s *SQLiteStorage) listProjectDependenciesWithQuerier(ctx context.Context, q querier, projectID int64) ([]*ProjectDependency, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT d.dependency_id, d.direct,
+		       p.id, p.root_path, p.module_name, p.go_version, p.total_files, p.total_chunks,
+		       p.index_version, p.last_indexed_at, p.created_at, p.updated_at, p.git_ref, p.git_commit, p.module_version
+		FROM project_dependencies d
+		INNER JOIN projects p ON d.dependency_id = p.id
+		WHERE d.project_id = ?
+		ORDER BY p.module_name, p.module_version
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	deps := make([]*ProjectDependency, 0)
+	for rows.Next() {
+		var dep ProjectDependency
+		var project Project
+		var lastIndexedAt sql.NullTime
+		err := rows.Scan(
+			&dep.DependencyID, &dep.Direct,
+			&project.ID, &project.RootPath, &project.ModuleName, &project.GoVersion,
+			&project.TotalFiles, &project.TotalChunks, &project.IndexVersion,
+			&lastIndexedAt, &project.CreatedAt, &project.UpdatedAt, &project.GitRef, &project.GitCommit,
+			&project.ModuleVersion,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if lastIndexedAt.Valid {
+			project.LastIndexedAt = lastIndexedAt.Time
+		}
+		dep.Project = &project
+		deps = append(deps, &dep)
+	}
+	return deps, rows.Err()
+}
+
+// ListProjectDependencies returns the dependency projects linked to a project, ordered by module path
+func (s *SQLiteStorage) ListProjectDependencies(ctx context.Context, projectID int64) ([]*ProjectDependency, error) {
+	return s.listProjectDependenciesWithQuerier(ctx, s.querier(), projectID)
+}
+
 // Status operations
 
 func (s *SQLiteStorage) GetStatus(ctx context.Context, projectID int64) (*ProjectStatus, error) {
@@ -922,7 +1905,7 @@ func (s *SQLiteStorage) GetStatus(ctx context.Context, projectID int64) (*Projec
 func (s *SQLiteStorage) getProjectByID(ctx context.Context, projectID int64) (*Project, error) {
 	query := `
 		SELECT id, root_path, module_name, go_version, total_files, total_chunks,
-		       index_version, last_indexed_at, created_at, updated_at
+		       index_version, last_indexed_at, created_at, updated_at, git_ref, git_commit, module_version
 		FROM projects
 		WHERE id = ?
 	`
@@ -931,7 +1914,8 @@ func (s *SQLiteStorage) getProjectByID(ctx context.Context, projectID int64) (*P
 	err := s.db.QueryRowContext(ctx, query, projectID).Scan(
 		&project.ID, &project.RootPath, &project.ModuleName, &project.GoVersion,
 		&project.TotalFiles, &project.TotalChunks, &project.IndexVersion,
-		&lastIndexedAt, &project.CreatedAt, &project.UpdatedAt,
+		&lastIndexedAt, &project.CreatedAt, &project.UpdatedAt, &project.GitRef, &project.GitCommit,
+		&project.ModuleVersion,
 	)
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
@@ -958,6 +1942,10 @@ func (t *sqliteTx) GetProject(ctx context.Context, rootPath string) (*Project, e
 	return t.storage.getProjectWithQuerier(ctx, t.querier(), rootPath)
 }
 
+func (t *sqliteTx) ListProjects(ctx context.Context) ([]*Project, error) {
+	return t.storage.listProjectsWithQuerier(ctx, t.querier())
+}
+
 func (t *sqliteTx) UpdateProject(ctx context.Context, project *Project) error {
 	return t.storage.updateProjectWithQuerier(ctx, t.querier(), project)
 }
@@ -998,6 +1986,34 @@ func (t *sqliteTx) ListSymbolsByFile(ctx context.Context, fileID int64) ([]*Symb
 	return t.storage.ListSymbolsByFile(ctx, fileID)
 }
 
+func (t *sqliteTx) ListSymbolsByName(ctx context.Context, projectID int64, name string) ([]*Symbol, error) {
+	return t.storage.ListSymbolsByName(ctx, projectID, name)
+}
+
+func (t *sqliteTx) FindFieldsByTag(ctx context.Context, projectID int64, filter TagFilter) ([]*TaggedField, error) {
+	return t.storage.FindFieldsByTag(ctx, projectID, filter)
+}
+
+func (t *sqliteTx) ListFunctionMetrics(ctx context.Context, projectID int64, filter MetricsFilter) ([]*FunctionMetrics, error) {
+	return t.storage.ListFunctionMetrics(ctx, projectID, filter)
+}
+
+func (t *sqliteTx) UpdateSymbolPatterns(ctx context.Context, symbolID int64, matches []types.DDDMatch) error {
+	return updateSymbolPatternsWithQuerier(ctx, t.querier(), symbolID, matches)
+}
+
+func (t *sqliteTx) ListSymbolRefs(ctx context.Context, projectID int64) ([]*SymbolRef, error) {
+	return listSymbolRefsWithQuerier(ctx, t.querier(), projectID)
+}
+
+func (t *sqliteTx) ReplaceSymbolRelations(ctx context.Context, projectID int64, relations []*SymbolRelation) error {
+	return replaceSymbolRelationsWithQuerier(ctx, t.querier(), projectID, relations)
+}
+
+func (t *sqliteTx) ListSymbolRelations(ctx context.Context, symbolID int64) ([]*SymbolRelation, error) {
+	return listSymbolRelationsWithQuerier(ctx, t.querier(), symbolID)
+}
+
 func (t *sqliteTx) DeleteSymbolsByFile(ctx context.Context, fileID int64) error {
 	return t.storage.deleteSymbolsByFileWithQuerier(ctx, t.querier(), fileID)
 }
@@ -1011,11 +2027,15 @@ func (t *sqliteTx) UpsertChunk(ctx context.Context, chunk *Chunk) error {
 }
 
 func (t *sqliteTx) GetChunk(ctx context.Context, chunkID int64) (*Chunk, error) {
-	return t.storage.GetChunk(ctx, chunkID)
+	return t.storage.getChunkWithQuerier(ctx, t.querier(), chunkID)
 }
 
 func (t *sqliteTx) ListChunksByFile(ctx context.Context, fileID int64) ([]*Chunk, error) {
-	return t.storage.ListChunksByFile(ctx, fileID)
+	return t.storage.listChunksByFileWithQuerier(ctx, t.querier(), fileID)
+}
+
+func (t *sqliteTx) UpdateChunkHistory(ctx context.Context, chunkID int64, history types.ChunkHistory) error {
+	return t.storage.updateChunkHistoryWithQuerier(ctx, t.querier(), chunkID, history)
 }
 
 func (t *sqliteTx) DeleteChunk(ctx context.Context, chunkID int64) error {
@@ -1050,6 +2070,10 @@ func (t *sqliteTx) SearchText(ctx context.Context, projectID int64, query string
 	return t.storage.SearchText(ctx, projectID, query, limit, filters)
 }
 
+func (t *sqliteTx) HighlightChunks(ctx context.Context, chunkIDs []int64, query string) (map[int64]string, error) {
+	return t.storage.HighlightChunks(ctx, chunkIDs, query)
+}
+
 func (t *sqliteTx) UpsertImport(ctx context.Context, imp *Import) error {
 	return t.storage.upsertImportWithQuerier(ctx, t.querier(), imp)
 }
@@ -1058,10 +2082,54 @@ func (t *sqliteTx) ListImportsByFile(ctx context.Context, fileID int64) ([]*Impo
 	return t.storage.ListImportsByFile(ctx, fileID)
 }
 
+func (t *sqliteTx) ListImportsByProject(ctx context.Context, projectID int64) ([]*Import, error) {
+	return t.storage.ListImportsByProject(ctx, projectID)
+}
+
 func (t *sqliteTx) DeleteImportsByFile(ctx context.Context, fileID int64) error {
 	return t.storage.deleteImportsByFileWithQuerier(ctx, t.querier(), fileID)
 }
 
+func (t *sqliteTx) InsertAnnotation(ctx context.Context, annotation *Annotation) error {
+	return t.storage.insertAnnotationWithQuerier(ctx, t.querier(), annotation)
+}
+
+func (t *sqliteTx) ListAnnotations(ctx context.Context, projectID int64, filter AnnotationFilter) ([]*Annotation, error) {
+	return t.storage.ListAnnotations(ctx, projectID, filter)
+}
+
+func (t *sqliteTx) DeleteAnnotationsByFile(ctx context.Context, fileID int64) error {
+	return t.storage.deleteAnnotationsByFileWithQuerier(ctx, t.querier(), fileID)
+}
+
+func (t *sqliteTx) InsertTestFunction(ctx context.Context, test *TestFunction) error {
+	return t.storage.insertTestFunctionWithQuerier(ctx, t.querier(), test)
+}
+
+func (t *sqliteTx) FindTests(ctx context.Context, projectID int64, names []string) ([]*TestFunction, error) {
+	return t.storage.FindTests(ctx, projectID, names)
+}
+
+func (t *sqliteTx) DeleteTestsByFile(ctx context.Context, fileID int64) error {
+	return t.storage.deleteTestsByFileWithQuerier(ctx, t.querier(), fileID)
+}
+
+func (t *sqliteTx) ReplaceModules(ctx context.Context, projectID int64, modules []*Module) error {
+	return t.storage.replaceModulesWithQuerier(ctx, t.querier(), projectID, modules)
+}
+
+func (t *sqliteTx) ListModules(ctx context.Context, projectID int64) ([]*Module, error) {
+	return t.storage.listModulesWithQuerier(ctx, t.querier(), projectID)
+}
+
+func (t *sqliteTx) ReplaceProjectDependencies(ctx context.Context, projectID int64, deps []*ProjectDependency) error {
+	return t.storage.replaceProjectDependenciesWithQuerier(ctx, t.querier(), projectID, deps)
+}
+
+func (t *sqliteTx) ListProjectDependencies(ctx context.Context, projectID int64) ([]*ProjectDependency, error) {
+	return t.storage.listProjectDependenciesWithQuerier(ctx, t.querier(), projectID)
+}
+
 func (t *sqliteTx) GetStatus(ctx context.Context, projectID int64) (*ProjectStatus, error) {
 	return t.storage.GetStatus(ctx, projectID)
 }
@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
 )
 
 func setupTestDB(t *testing.T) *SQLiteStorage {
@@ -277,6 +279,26 @@ func TestUpsertSymbol(t *testing.T) {
 	err = storage.UpsertSymbol(ctx, symbol)
 	require.NoError(t, err)
 	assert.Greater(t, symbol.ID, int64(0))
+
+	symbol.Metrics = types.Metrics{Cyclomatic: 4, Cognitive: 6, Lines: 11, Params: 2, Nesting: 2, Returns: 3}
+	require.NoError(t, storage.UpsertSymbol(ctx, symbol))
+	stored, err := storage.GetSymbol(ctx, symbol.ID)
+	require.NoError(t, err)
+	assert.Equal(t, symbol.Metrics, stored.Metrics)
+
+	embedding := &Symbol{
+		FileID:    file.ID,
+		Name:      "Store",
+		Kind:      "struct",
+		Scope:     "exported",
+		StartLine: 30,
+		EndLine:   35,
+		Embeds:    []string{"*Base", "Cache[K, V]"},
+	}
+	require.NoError(t, storage.UpsertSymbol(ctx, embedding))
+	stored, err = storage.GetSymbol(ctx, embedding.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*Base", "Cache[K, V]"}, stored.Embeds)
 }
 
 func TestListSymbolsByFile(t *testing.T) {
@@ -503,11 +525,319 @@ func TestUpsertImport(t *testing.T) {
 		FileID:     file.ID,
 		ImportPath: "fmt",
 		Alias:      "",
+		Line:       3,
 	}
 
 	err = storage.UpsertImport(ctx, imp)
 	require.NoError(t, err)
 	assert.Greater(t, imp.ID, int64(0))
+
+	imports, err := storage.ListImportsByFile(ctx, file.ID)
+	require.NoError(t, err)
+	require.Len(t, imports, 1)
+	assert.Equal(t, 3, imports[0].Line)
+}
+
+func TestListAnnotations(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	project := &Project{RootPath: "/test", ModuleName: "test"}
+	require.NoError(t, storage.CreateProject(ctx, project))
+
+	var fileIDs []int64
+	for i, f := range []struct{ path, pkg string }{{"store/store.go", "store"}, {"api/api.go", "api"}} {
+		file := &File{ProjectID: project.ID, FilePath: f.path, PackageName: f.pkg, ContentHash: [32]byte{byte(i + 1)}, ModTime: time.Now()}
+		require.NoError(t, storage.UpsertFile(ctx, file))
+		fileIDs = append(fileIDs, file.ID)
+	}
+
+	for _, a := range []*Annotation{
+		{FileID: fileIDs[0], Kind: "todo", Text: "split", Line: 9, Owner: "Alice", Issues: []string{"#12", "#13"}},
+		{FileID: fileIDs[0], Kind: "deprecated", Text: "use New", Line: 3, Symbol: "Store"},
+		{FileID: fileIDs[1], Kind: "todo", Text: "auth", Line: 5, Owner: "bob"},
+	} {
+		require.NoError(t, storage.InsertAnnotation(ctx, a))
+		assert.Greater(t, a.ID, int64(0))
+	}
+
+	all, err := storage.ListAnnotations(ctx, project.ID, AnnotationFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "api/api.go", all[0].FilePath, "ordered by file then line")
+	assert.Equal(t, 3, all[1].Line)
+	assert.Equal(t, []string{"#12", "#13"}, all[2].Issues)
+	assert.Equal(t, "store", all[2].PackageName)
+
+	todos, err := storage.ListAnnotations(ctx, project.ID, AnnotationFilter{Kinds: []string{"todo"}, Owner: "alice"})
+	require.NoError(t, err)
+	require.Len(t, todos, 1)
+	assert.Equal(t, "split", todos[0].Text)
+
+	api, err := storage.ListAnnotations(ctx, project.ID, AnnotationFilter{Packages: []string{"api"}})
+	require.NoError(t, err)
+	assert.Len(t, api, 1)
+
+	require.NoError(t, storage.DeleteAnnotationsByFile(ctx, fileIDs[0]))
+	all, err = storage.ListAnnotations(ctx, project.ID, AnnotationFilter{FilePattern: "store/*"})
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestFindTests(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	project := &Project{RootPath: "/test", ModuleName: "test"}
+	require.NoError(t, storage.CreateProject(ctx, project))
+
+	file := &File{ProjectID: project.ID, FilePath: "store/store_test.go", PackageName: "store",
+		ContentHash: [32]byte{1}, ModTime: time.Now(), ImportPath: "test/store"}
+	require.NoError(t, storage.UpsertFile(ctx, file))
+
+	for _, test := range []*TestFunction{
+		{FileID: file.ID, Name: "TestStore_Load", Kind: "test", StartLine: 10, EndLine: 20, Target: "Store.Load",
+			Calls: []TestCall{{Name: "NewStore"}, {Name: "Load", Method: true}}},
+		{FileID: file.ID, Name: "TestSave", Kind: "test", StartLine: 3, EndLine: 8, Target: "Save",
+			Calls: []TestCall{{Package: "test/codec", Name: "Encode"}}},
+	} {
+		require.NoError(t, storage.InsertTestFunction(ctx, test))
+		assert.Greater(t, test.ID, int64(0))
+	}
+
+	tests, err := storage.FindTests(ctx, project.ID, []string{"Load"})
+	require.NoError(t, err)
+	require.Len(t, tests, 1)
+	assert.Equal(t, "Store.Load", tests[0].Target)
+	assert.Equal(t, "test/store", tests[0].ImportPath)
+	assert.Equal(t, []TestCall{{Name: "NewStore"}, {Name: "Load", Method: true}}, tests[0].Calls)
+
+	tests, err = storage.FindTests(ctx, project.ID, []string{"Encode", "Store"})
+	require.NoError(t, err)
+	require.Len(t, tests, 2)
+	assert.Equal(t, "TestSave", tests[0].Name, "ordered by file then line")
+	assert.Equal(t, []TestCall{{Package: "test/codec", Name: "Encode"}}, tests[0].Calls)
+
+	tests, err = storage.FindTests(ctx, project.ID, []string{"Missing"})
+	require.NoError(t, err)
+	assert.Empty(t, tests)
+
+	require.NoError(t, storage.DeleteTestsByFile(ctx, file.ID))
+	tests, err = storage.FindTests(ctx, project.ID, []string{"Load", "Save"})
+	require.NoError(t, err)
+	assert.Empty(t, tests)
+}
+
+func TestFindFieldsByTag(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	project := &Project{RootPath: "/test", ModuleName: "test"}
+	require.NoError(t, storage.CreateProject(ctx, project))
+
+	file := &File{ProjectID: project.ID, FilePath: "model/user.go", PackageName: "model",
+		ContentHash: [32]byte{1}, ModTime: time.Now(), ImportPath: "test/model"}
+	require.NoError(t, storage.UpsertFile(ctx, file))
+
+	fields := []*Symbol{
+		{Name: "ID", Receiver: "User", StartLine: 4, Tags: []types.StructTag{{Key: "json", Value: "id"}, {Key: "db", Value: "user_id"}}},
+		{Name: "UserID", Receiver: "Order", StartLine: 12, Tags: []types.StructTag{{Key: "db", Value: "user_id"}}},
+		{Name: "CreatedAt", Receiver: "User", StartLine: 5, Tags: []types.StructTag{{Key: "json", Value: "created_at,omitempty"}}},
+		{Name: "secret", Receiver: "User", StartLine: 6},
+	}
+	for _, f := range fields {
+		f.FileID, f.Kind, f.Scope, f.EndLine = file.ID, "field", "exported", f.StartLine
+		require.NoError(t, storage.UpsertSymbol(ctx, f))
+	}
+
+	found, err := storage.FindFieldsByTag(ctx, project.ID, TagFilter{Key: "db", Name: "user_id"})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "User", found[0].Receiver, "ordered by file then line")
+	assert.Equal(t, "Order", found[1].Receiver)
+	assert.Equal(t, types.StructTag{Key: "db", Value: "user_id"}, found[0].Tag)
+	assert.Equal(t, "test/model", found[0].ImportPath)
+
+	found, err = storage.FindFieldsByTag(ctx, project.ID, TagFilter{Name: "created_at"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "created_at,omitempty", found[0].Tag.Value)
+
+	found, err = storage.FindFieldsByTag(ctx, project.ID, TagFilter{Key: "json"})
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	// Upserting a field again replaces its tags
+	fields[0].Tags = []types.StructTag{{Key: "json", Value: "id"}}
+	require.NoError(t, storage.UpsertSymbol(ctx, fields[0]))
+	found, err = storage.FindFieldsByTag(ctx, project.ID, TagFilter{Key: "db"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "UserID", found[0].Name)
+
+	require.NoError(t, storage.DeleteSymbolsByFile(ctx, file.ID))
+	found, err = storage.FindFieldsByTag(ctx, project.ID, TagFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestListFunctionMetrics(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	project := &Project{RootPath: "/test", ModuleName: "test"}
+	require.NoError(t, storage.CreateProject(ctx, project))
+
+	file := &File{ProjectID: project.ID, FilePath: "order/order.go", PackageName: "order",
+		ContentHash: [32]byte{1}, ModTime: time.Now(), SizeBytes: 4096, ImportPath: "test/order"}
+	testFile := &File{ProjectID: project.ID, FilePath: "order/order_test.go", PackageName: "order",
+		ContentHash: [32]byte{2}, ModTime: time.Now(), SizeBytes: 512}
+	require.NoError(t, storage.UpsertFile(ctx, file))
+	require.NoError(t, storage.UpsertFile(ctx, testFile))
+
+	place := &Symbol{FileID: file.ID, Name: "Place", Kind: "method", Receiver: "Order", Scope: "exported",
+		StartLine: 10, EndLine: 40, Metrics: types.Metrics{Cyclomatic: 8, Cognitive: 11, Lines: 31, Params: 1, Nesting: 3, Returns: 4}}
+	order := &Symbol{FileID: file.ID, Name: "Order", Kind: "struct", Scope: "exported", StartLine: 3, EndLine: 6}
+	test := &Symbol{FileID: testFile.ID, Name: "TestPlace", Kind: "function", Scope: "exported",
+		StartLine: 5, EndLine: 9, Metrics: types.Metrics{Cyclomatic: 1, Lines: 5, Params: 1}}
+	for _, sym := range []*Symbol{place, order, test} {
+		require.NoError(t, storage.UpsertSymbol(ctx, sym))
+	}
+
+	functions, err := storage.ListFunctionMetrics(ctx, project.ID, MetricsFilter{})
+	require.NoError(t, err)
+	require.Len(t, functions, 1, "types and test files are left out")
+	assert.Equal(t, "Place", functions[0].Name)
+	assert.Equal(t, place.Metrics, functions[0].Metrics)
+	assert.Equal(t, int64(4096), functions[0].FileSize)
+	assert.Equal(t, "test/order", functions[0].ImportPath)
+	assert.False(t, functions[0].HasHistory)
+
+	// Churn is the most changed chunk of the function
+	for i, changes := range []int{3, 7} {
+		chunk := &Chunk{FileID: file.ID, SymbolID: &place.ID, Content: "part", ContentHash: [32]byte{byte(i + 10)},
+			StartLine: 10 + i*15, EndLine: 24 + i*15, ChunkType: "method"}
+		require.NoError(t, storage.UpsertChunk(ctx, chunk))
+		require.NoError(t, storage.UpdateChunkHistory(ctx, chunk.ID, types.ChunkHistory{
+			LastCommit: "abc", LastModified: time.Now(), ChangeCount: changes,
+		}))
+	}
+
+	functions, err = storage.ListFunctionMetrics(ctx, project.ID, MetricsFilter{IncludeTests: true})
+	require.NoError(t, err)
+	require.Len(t, functions, 2)
+	assert.Equal(t, 7, functions[0].Churn)
+	assert.True(t, functions[0].HasHistory)
+	assert.Equal(t, "TestPlace", functions[1].Name)
+
+	functions, err = storage.ListFunctionMetrics(ctx, project.ID, MetricsFilter{Packages: []string{"other"}})
+	require.NoError(t, err)
+	assert.Empty(t, functions)
+}
+
+func TestUpdateSymbolPatterns(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	project := &Project{RootPath: "/test", ModuleName: "test"}
+	require.NoError(t, storage.CreateProject(ctx, project))
+	file := &File{ProjectID: project.ID, FilePath: "domain/order.go", PackageName: "domain",
+		ContentHash: [32]byte{1}, ModTime: time.Now()}
+	require.NoError(t, storage.UpsertFile(ctx, file))
+
+	// Upsert stores the patterns detected while parsing
+	sym := &Symbol{FileID: file.ID, Name: "OrderRepository", Kind: "interface", Scope: "exported",
+		StartLine: 3, EndLine: 8, IsRepository: true,
+		DDD: []types.DDDMatch{{Pattern: "repository", Confidence: 0.7, Reason: "name ends in Repository or Repo"}}}
+	require.NoError(t, storage.UpsertSymbol(ctx, sym))
+	got, err := storage.GetSymbol(ctx, sym.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sym.DDD, got.DDD)
+
+	// Reclassification replaces patterns and flags
+	matches := []types.DDDMatch{
+		{Pattern: "aggregate", Confidence: 0.9, Reason: "embeds AggregateRoot"},
+		{Pattern: "entity", Confidence: 0.9, Reason: "aggregate roots are entities"},
+	}
+	require.NoError(t, storage.UpdateSymbolPatterns(ctx, sym.ID, matches))
+	got, err = storage.GetSymbol(ctx, sym.ID)
+	require.NoError(t, err)
+	assert.Equal(t, matches, got.DDD)
+	assert.True(t, got.IsAggregateRoot)
+	assert.True(t, got.IsEntity)
+	assert.False(t, got.IsRepository)
+
+	results, err := storage.SearchSymbols(ctx, "OrderRepository", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].IsAggregateRoot)
+
+	require.NoError(t, storage.UpdateSymbolPatterns(ctx, sym.ID, nil))
+	got, err = storage.GetSymbol(ctx, sym.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.DDD)
+	assert.False(t, got.IsAggregateRoot)
+}
+
+func TestSymbolRefsAndRelations(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	project := &Project{RootPath: "/test", ModuleName: "test"}
+	require.NoError(t, storage.CreateProject(ctx, project))
+	file := &File{ProjectID: project.ID, FilePath: "app/orders.go", PackageName: "app",
+		ContentHash: [32]byte{1}, ModTime: time.Now()}
+	require.NoError(t, storage.UpsertFile(ctx, file))
+
+	command := &Symbol{FileID: file.ID, Name: "PlaceOrderCommand", Kind: "struct", Scope: "exported", StartLine: 3, IsCommand: true}
+	handler := &Symbol{FileID: file.ID, Name: "PlaceOrderHandler", Kind: "struct", Scope: "exported", StartLine: 8, IsHandler: true}
+	handle := &Symbol{FileID: file.ID, Name: "Handle", Kind: "method", Scope: "exported", Receiver: "PlaceOrderHandler", StartLine: 12,
+		Refs: []types.Ref{
+			{Kind: types.RefParam, Name: "PlaceOrderCommand"},
+			{Kind: types.RefMethod, Name: "Save", Via: "orders"},
+		}}
+	for _, sym := range []*Symbol{command, handler, handle} {
+		require.NoError(t, storage.UpsertSymbol(ctx, sym))
+	}
+
+	refs, err := storage.ListSymbolRefs(ctx, project.ID)
+	require.NoError(t, err)
+	require.Len(t, refs, 2)
+	assert.Equal(t, handle.ID, refs[0].SymbolID)
+	assert.Equal(t, handle.Refs[0], refs[0].Ref)
+	assert.Equal(t, "orders", refs[1].Via)
+
+	// Upserting again replaces the refs
+	handle.Refs = handle.Refs[:1]
+	require.NoError(t, storage.UpsertSymbol(ctx, handle))
+	refs, err = storage.ListSymbolRefs(ctx, project.ID)
+	require.NoError(t, err)
+	assert.Len(t, refs, 1)
+
+	relation := &SymbolRelation{FromID: command.ID, ToID: handler.ID, Kind: "handled_by", Reason: "Handle takes PlaceOrderCommand"}
+	require.NoError(t, storage.ReplaceSymbolRelations(ctx, project.ID, []*SymbolRelation{relation, relation}))
+	relations, err := storage.ListSymbolRelations(ctx, command.ID)
+	require.NoError(t, err)
+	require.Len(t, relations, 1)
+	assert.Equal(t, relation, relations[0])
+
+	require.NoError(t, storage.ReplaceSymbolRelations(ctx, project.ID, nil))
+	relations, err = storage.ListSymbolRelations(ctx, command.ID)
+	require.NoError(t, err)
+	assert.Empty(t, relations)
+
+	// Relations go with their symbols
+	require.NoError(t, storage.ReplaceSymbolRelations(ctx, project.ID, []*SymbolRelation{relation}))
+	require.NoError(t, storage.DeleteSymbolsByFile(ctx, file.ID))
+	relations, err = storage.ListSymbolRelations(ctx, command.ID)
+	require.NoError(t, err)
+	assert.Empty(t, relations)
 }
 
 // TestNewSQLiteStorage_PRAGMAFailure tests that database connection is properly closed on PRAGMA failure.
@@ -560,3 +890,105 @@ func TestNewSQLiteStorage_PRAGMAFailure(t *testing.T) {
 		})
 	}
 }
+
+func TestListProjects(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	projects, err := storage.ListProjects(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, projects)
+
+	for _, root := range []string{"/repos/service", "/repos/lib"} {
+		require.NoError(t, storage.CreateProject(ctx, &Project{RootPath: root, ModuleName: "test"}))
+	}
+
+	projects, err = storage.ListProjects(ctx)
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+	assert.Equal(t, "/repos/lib", projects[0].RootPath)
+	assert.Equal(t, "/repos/service", projects[1].RootPath)
+}
+
+func TestReplaceAndListProjectDependencies(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	app := &Project{RootPath: "/repos/app", ModuleName: "example.com/app"}
+	require.NoError(t, storage.CreateProject(ctx, app))
+	lib := &Project{RootPath: "/mod/example.com/lib@v1.2.0", ModuleName: "example.com/lib", ModuleVersion: "v1.2.0"}
+	require.NoError(t, storage.CreateProject(ctx, lib))
+	text := &Project{RootPath: "/mod/golang.org/x/text@v0.14.0", ModuleName: "golang.org/x/text", ModuleVersion: "v0.14.0"}
+	require.NoError(t, storage.CreateProject(ctx, text))
+
+	// A module required directly by one go.mod and indirectly by another stays direct
+	require.NoError(t, storage.ReplaceProjectDependencies(ctx, app.ID, []*ProjectDependency{
+		{DependencyID: text.ID},
+		{DependencyID: lib.ID, Direct: true},
+		{DependencyID: lib.ID},
+	}))
+
+	deps, err := storage.ListProjectDependencies(ctx, app.ID)
+	require.NoError(t, err)
+	require.Len(t, deps, 2)
+	assert.Equal(t, "example.com/lib@v1.2.0", deps[0].Project.ModuleID())
+	assert.True(t, deps[0].Direct)
+	assert.True(t, deps[0].Project.IsDependency())
+	assert.False(t, deps[1].Direct)
+
+	got, err := storage.GetProject(ctx, lib.RootPath)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", got.ModuleVersion)
+
+	require.NoError(t, storage.ReplaceProjectDependencies(ctx, app.ID, nil))
+	deps, err = storage.ListProjectDependencies(ctx, app.ID)
+	require.NoError(t, err)
+	assert.Empty(t, deps)
+}
+
+func TestReplaceAndListModules(t *testing.T) {
+	storage := setupTestDB(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+	project := &Project{RootPath: "/repos/mono", ModuleName: "example.com/mono"}
+	require.NoError(t, storage.CreateProject(ctx, project))
+
+	modules := []*Module{
+		{ModulePath: "example.com/mono", Dir: "", GoVersion: "1.22", InWorkspace: true,
+			Dependencies: []ModuleDependency{
+				{Path: "example.com/lib", Version: "v1.0.0", ReplacePath: "../lib"},
+				{Path: "golang.org/x/text", Version: "v0.14.0", Indirect: true},
+			}},
+		{ModulePath: "example.com/billing", Dir: "services/billing", GoVersion: "1.21"},
+	}
+	require.NoError(t, storage.ReplaceModules(ctx, project.ID, modules))
+
+	file := &File{ProjectID: project.ID, FilePath: "services/billing/main.go", PackageName: "main",
+		ModTime: time.Now(), ModulePath: "example.com/billing", ImportPath: "example.com/billing"}
+	require.NoError(t, storage.UpsertFile(ctx, file))
+
+	listed, err := storage.ListModules(ctx, project.ID)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	assert.Equal(t, "example.com/mono", listed[0].ModulePath)
+	assert.True(t, listed[0].InWorkspace)
+	assert.Len(t, listed[0].Dependencies, 2)
+	assert.Equal(t, "../lib", listed[0].Dependencies[0].ReplacePath)
+	assert.Equal(t, 0, listed[0].FilesCount)
+	assert.Equal(t, "services/billing", listed[1].Dir)
+	assert.Equal(t, 1, listed[1].FilesCount)
+
+	got, err := storage.GetFileByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/billing", got.ModulePath)
+
+	// Replacing drops modules that no longer exist
+	require.NoError(t, storage.ReplaceModules(ctx, project.ID, modules[1:]))
+	listed, err = storage.ListModules(ctx, project.ID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Empty(t, listed[0].Dependencies)
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/dshills/gocontext-mcp/pkg/types"
@@ -12,6 +13,7 @@ type Storage interface {
 	// Project operations
 	CreateProject(ctx context.Context, project *Project) error
 	GetProject(ctx context.Context, rootPath string) (*Project, error)
+	ListProjects(ctx context.Context) ([]*Project, error)
 	UpdateProject(ctx context.Context, project *Project) error
 
 	// File operations
@@ -26,6 +28,13 @@ type Storage interface {
 	UpsertSymbol(ctx context.Context, symbol *Symbol) error
 	GetSymbol(ctx context.Context, symbolID int64) (*Symbol, error)
 	ListSymbolsByFile(ctx context.Context, fileID int64) ([]*Symbol, error)
+	ListSymbolsByName(ctx context.Context, projectID int64, name string) ([]*Symbol, error)
+	FindFieldsByTag(ctx context.Context, projectID int64, filter TagFilter) ([]*TaggedField, error)
+	ListFunctionMetrics(ctx context.Context, projectID int64, filter MetricsFilter) ([]*FunctionMetrics, error)
+	UpdateSymbolPatterns(ctx context.Context, symbolID int64, matches []types.DDDMatch) error
+	ListSymbolRefs(ctx context.Context, projectID int64) ([]*SymbolRef, error)
+	ReplaceSymbolRelations(ctx context.Context, projectID int64, relations []*SymbolRelation) error
+	ListSymbolRelations(ctx context.Context, symbolID int64) ([]*SymbolRelation, error)
 	DeleteSymbolsByFile(ctx context.Context, fileID int64) error
 	SearchSymbols(ctx context.Context, query string, limit int) ([]*Symbol, error)
 
@@ -33,6 +42,7 @@ type Storage interface {
 	UpsertChunk(ctx context.Context, chunk *Chunk) error
 	GetChunk(ctx context.Context, chunkID int64) (*Chunk, error)
 	ListChunksByFile(ctx context.Context, fileID int64) ([]*Chunk, error)
+	UpdateChunkHistory(ctx context.Context, chunkID int64, history types.ChunkHistory) error
 	DeleteChunk(ctx context.Context, chunkID int64) error
 	DeleteChunksBatch(ctx context.Context, chunkIDs []int64) (deletedCount int, err error)
 	DeleteChunksByFile(ctx context.Context, fileID int64) error
@@ -45,12 +55,30 @@ type Storage interface {
 	// Search operations
 	SearchVector(ctx context.Context, projectID int64, vector []float32, limit int, filters *SearchFilters) ([]VectorResult, error)
 	SearchText(ctx context.Context, projectID int64, query string, limit int, filters *SearchFilters) ([]TextResult, error)
+	HighlightChunks(ctx context.Context, chunkIDs []int64, query string) (map[int64]string, error)
 
 	// Import operations
 	UpsertImport(ctx context.Context, imp *Import) error
 	ListImportsByFile(ctx context.Context, fileID int64) ([]*Import, error)
+	ListImportsByProject(ctx context.Context, projectID int64) ([]*Import, error)
 	DeleteImportsByFile(ctx context.Context, fileID int64) error
 
+	// Annotation operations
+	InsertAnnotation(ctx context.Context, annotation *Annotation) error
+	ListAnnotations(ctx context.Context, projectID int64, filter AnnotationFilter) ([]*Annotation, error)
+	DeleteAnnotationsByFile(ctx context.Context, fileID int64) error
+
+	// Test operations
+	InsertTestFunction(ctx context.Context, test *TestFunction) error
+	FindTests(ctx context.Context, projectID int64, names []string) ([]*TestFunction, error)
+	DeleteTestsByFile(ctx context.Context, fileID int64) error
+
+	// Module operations
+	ReplaceModules(ctx context.Context, projectID int64, modules []*Module) error
+	ListModules(ctx context.Context, projectID int64) ([]*Module, error)
+	ReplaceProjectDependencies(ctx context.Context, projectID int64, deps []*ProjectDependency) error
+	ListProjectDependencies(ctx context.Context, projectID int64) ([]*ProjectDependency, error)
+
 	// Status operations
 	GetStatus(ctx context.Context, projectID int64) (*ProjectStatus, error)
 
@@ -78,6 +106,49 @@ type Project struct {
 	LastIndexedAt time.Time
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
+
+	// Set for snapshots of a git revision; RootPath is then SnapshotRootPath(repo, GitRef)
+	GitRef    string // Revision as requested, e.g. "main" or "v1.2.0"
+	GitCommit string // Commit the revision resolved to when last indexed
+
+	// Set for required modules indexed from the module cache or a vendor directory;
+	// RootPath is then the module's source directory and ModuleName its path
+	ModuleVersion string
+}
+
+// SnapshotRootPath returns the project key under which the git revision ref of the
+// repository at rootPath is stored, keeping it apart from the working tree's project
+func SnapshotRootPath(rootPath, ref string) string {
+	return rootPath + "@" + ref
+}
+
+// IsSnapshot reports whether the project was indexed from a git revision
+func (p *Project) IsSnapshot() bool {
+	return p.GitRef != ""
+}
+
+// IsDependency reports whether the project is a required module indexed read-only
+func (p *Project) IsDependency() bool {
+	return p.ModuleVersion != ""
+}
+
+// IsStdlib reports whether the project holds the standard library of a Go release
+func (p *Project) IsStdlib() bool {
+	return p.IsDependency() && p.ModuleName == StdlibModule
+}
+
+// ModuleID returns "path@version" for dependencies
+func (p *Project) ModuleID() string {
+	return p.ModuleName + "@" + p.ModuleVersion
+}
+
+// SourcePath returns the directory on disk the project was indexed from; for
+// snapshots this is RootPath without the "@ref" suffix
+func (p *Project) SourcePath() string {
+	if !p.IsSnapshot() {
+		return p.RootPath
+	}
+	return strings.TrimSuffix(p.RootPath, "@"+p.GitRef)
 }
 
 // File represents a tracked Go source file
@@ -93,6 +164,23 @@ type File struct {
 	LastIndexedAt time.Time
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
+
+	// Build constraint: //go:build expression and file name GOOS/GOARCH suffixes
+	BuildConstraint string
+	GOOS            string
+	GOARCH          string
+
+	// Owning module path and the import path of the file's package
+	ModulePath string
+	ImportPath string
+
+	// Package doc comment preceding the file's package clause
+	PackageDoc string
+}
+
+// Constraint returns the file's build constraint
+func (f *File) Constraint() types.BuildConstraint {
+	return types.BuildConstraint{Expr: f.BuildConstraint, GOOS: f.GOOS, GOARCH: f.GOARCH}
 }
 
 // Symbol represents a code symbol from AST parsing
@@ -118,9 +206,64 @@ type Symbol struct {
 	IsCommand       bool
 	IsQuery         bool
 	IsHandler       bool
+	TypeParams      []types.TypeParam // Nil unless the function or type is generic
+	Tags            []types.StructTag // Struct tag of fields; written on upsert, read by FindFieldsByTag
+	Embeds          []string          // Types embedded in structs and interfaces
+	DDD             []types.DDDMatch  // Patterns behind the flags; written on upsert, read by GetSymbol
+	Refs            []types.Ref       // Names used by the declaration; written on upsert, read by ListSymbolRefs
+	Metrics         types.Metrics     // Complexity of functions and methods
 	CreatedAt       time.Time
 }
 
+// SymbolRef is a name a function, method or field refers to
+type SymbolRef struct {
+	SymbolID int64
+	types.Ref
+}
+
+// SymbolRelation is a link inferred between two symbols of a project, such as a
+// command handled_by its handler
+type SymbolRelation struct {
+	FromID int64
+	ToID   int64
+	Kind   string
+	Reason string // Evidence for the link, e.g. "Handle takes PlaceOrderCommand"
+}
+
+// TaggedField is a struct field with one of its struct tag keys, as found by FindFieldsByTag
+type TaggedField struct {
+	Symbol     // The field; Receiver is its struct
+	Tag        types.StructTag
+	FilePath   string
+	ImportPath string
+}
+
+// TagFilter narrows FindFieldsByTag; zero values match everything
+type TagFilter struct {
+	Key         string   // Tag key such as "json" or "db"
+	Name        string   // Mapped name, the tag value up to the first comma
+	Packages    []string // Filter by package names
+	FilePattern string   // Glob pattern for file paths
+}
+
+// FunctionMetrics is a function or method with the size of its file and the
+// churn of its lines, as listed by ListFunctionMetrics
+type FunctionMetrics struct {
+	Symbol
+	FilePath   string
+	ImportPath string
+	FileSize   int64 // Bytes
+	Churn      int   // Commits touching its lines; zero without git history
+	HasHistory bool  // Its chunks were indexed with git history
+}
+
+// MetricsFilter narrows ListFunctionMetrics; zero values match everything
+type MetricsFilter struct {
+	Packages     []string // Filter by package names
+	FilePattern  string   // Glob pattern for file paths
+	IncludeTests bool     // Include functions of _test.go files
+}
+
 // Chunk represents a code section for embedding
 type Chunk struct {
 	ID            int64
@@ -136,6 +279,9 @@ type Chunk struct {
 	ChunkType     string
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
+
+	// History is set when the project is indexed with git history
+	History types.ChunkHistory
 }
 
 // Embedding represents a vector embedding for a chunk
@@ -155,16 +301,123 @@ type Import struct {
 	FileID     int64
 	ImportPath string
 	Alias      string
+	Line       int
 	CreatedAt  time.Time
 }
 
+// Annotation is a marker comment (TODO, FIXME, Deprecated:, //nolint, ...) in an indexed file
+type Annotation struct {
+	ID        int64
+	FileID    int64
+	Kind      string
+	Text      string
+	Line      int
+	Symbol    string   // Documented or enclosing symbol, empty at file level
+	Owner     string   // Owner from "TODO(owner):"
+	Issues    []string // Issue references such as "#123"
+	CreatedAt time.Time
+
+	// Set by ListAnnotations
+	FilePath    string
+	PackageName string
+}
+
+// AnnotationFilter narrows ListAnnotations; zero values match everything
+type AnnotationFilter struct {
+	Kinds       []string
+	Packages    []string
+	Owner       string // Case-insensitive
+	FilePattern string // Glob pattern for file paths
+}
+
+// TestFunction is a Test, Benchmark, Fuzz or Example function in an indexed _test.go file
+type TestFunction struct {
+	ID        int64
+	FileID    int64
+	Name      string
+	Kind      string
+	StartLine int
+	EndLine   int
+	Target    string // Symbol the test is named after, "Type.Method" or "Name"
+	Calls     []TestCall
+	CreatedAt time.Time
+
+	// Set by FindTests
+	FilePath    string
+	PackageName string
+	ImportPath  string
+}
+
+// TestCall is a function or method called from a test function
+type TestCall struct {
+	Package string // Import path, empty for the test's own package
+	Name    string
+	Method  bool
+}
+
+// Module represents a Go module (go.mod) within a project
+type Module struct {
+	ID           int64
+	ProjectID    int64
+	ModulePath   string
+	Dir          string // Relative to project root, "" for the root module
+	GoVersion    string
+	InWorkspace  bool // Listed in the project's go.work
+	FilesCount   int  // Indexed files owned by the module (set by ListModules)
+	Dependencies []ModuleDependency
+}
+
+// StdlibModule is the module name of standard library projects, which are indexed
+// once per Go release and linked to every project built with it
+const StdlibModule = "std"
+
+// ProjectDependency links a project to the indexed project of a module it requires
+type ProjectDependency struct {
+	DependencyID int64 // Project holding the module's sources
+	Direct       bool  // Required without "// indirect" by one of the project's modules
+
+	// Set by ListProjectDependencies
+	Project *Project
+}
+
+// ModuleDependency is a require directive with any matching replace directive applied
+type ModuleDependency struct {
+	Path           string
+	Version        string
+	Indirect       bool
+	ReplacePath    string // Empty when not replaced
+	ReplaceVersion string // Empty for local directory replacements
+}
+
 // SearchFilters contains filters for narrowing search results
 type SearchFilters struct {
 	SymbolTypes  []string // Filter by symbol kind
 	FilePattern  string   // Glob pattern for file paths
 	DDDPatterns  []string // Filter by DDD pattern flags
 	Packages     []string // Filter by package names
+	Modules      []string // Filter by module paths
 	MinRelevance float64  // Minimum relevance score
+
+	// Generic keeps generic functions and types (those declaring type parameters)
+	Generic bool
+
+	// Constraints keeps symbols with a type parameter constrained by one of these,
+	// as written in the source, e.g. "comparable" or "~int | ~string"
+	Constraints []string
+
+	// BuildContext keeps only files compiled for the target GOOS/GOARCH and tags
+	BuildContext *types.BuildContext
+
+	// MinMetrics keeps functions and methods reaching every non-zero minimum,
+	// e.g. Cyclomatic 10 for "cyclomatic complexity of at least 10"
+	MinMetrics types.Metrics
+
+	// ModifiedSince keeps chunks whose lines last changed at or after this time
+	// (requires git history); zero disables the filter
+	ModifiedSince time.Time
+
+	// excludedFileIDs is resolved from BuildContext before querying
+	excludedFileIDs []int64
 }
 
 // VectorResult represents a result from vector similarity search
@@ -179,6 +432,12 @@ type TextResult struct {
 	BM25Score float64
 }
 
+// Highlight markers wrapped around matched tokens by HighlightChunks
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
 // ProjectStatus contains statistics about an indexed project
 type ProjectStatus struct {
 	Project         *Project
@@ -225,6 +484,12 @@ func (s *Symbol) ToTypesSymbol() types.Symbol {
 		IsCommand:       s.IsCommand,
 		IsQuery:         s.IsQuery,
 		IsHandler:       s.IsHandler,
+		TypeParams:      s.TypeParams,
+		Tags:            s.Tags,
+		Embeds:          s.Embeds,
+		DDD:             s.DDD,
+		Refs:            s.Refs,
+		Metrics:         s.Metrics,
 	}
 }
 
@@ -251,5 +516,11 @@ func FromTypesSymbol(s types.Symbol, fileID int64) *Symbol {
 		IsCommand:       s.IsCommand,
 		IsQuery:         s.IsQuery,
 		IsHandler:       s.IsHandler,
+		TypeParams:      s.TypeParams,
+		Tags:            s.Tags,
+		Embeds:          s.Embeds,
+		DDD:             s.DDD,
+		Refs:            s.Refs,
+		Metrics:         s.Metrics,
 	}
 }
@@ -232,11 +232,11 @@ func TestMigration_V101_Applied(t *testing.T) {
 
 	ctx := context.Background()
 
-	// Check that the current schema version is 1.0.1
+	// Check that the 1.0.1 migration is recorded
 	var version string
-	err = store.db.QueryRowContext(ctx, "SELECT version FROM schema_version ORDER BY applied_at DESC LIMIT 1").Scan(&version)
+	err = store.db.QueryRowContext(ctx, "SELECT version FROM schema_version WHERE version = ?", "1.0.1").Scan(&version)
 	require.NoError(t, err)
-	assert.Equal(t, "1.0.1", version, "Schema version should be 1.0.1 after migrations")
+	assert.Equal(t, "1.0.1", version, "Schema version 1.0.1 should be recorded after migrations")
 
 	// Verify symbols table has UNIQUE constraint (not just index)
 	// SQLite stores constraint info in sqlite_master table
@@ -6,13 +6,20 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
-	"regexp"
 	"sort"
 	"strings"
+
+	"github.com/dshills/gocontext-mcp/internal/tokenize"
+	"github.com/dshills/gocontext-mcp/pkg/types"
 )
 
 // searchVector performs vector similarity search using cosine similarity
 func searchVector(ctx context.Context, db *sql.DB, projectID int64, queryVector []float32, limit int, filters *SearchFilters) ([]VectorResult, error) {
+	filters, err := resolveBuildFilter(ctx, db, projectID, filters)
+	if err != nil {
+		return nil, err
+	}
+
 	// Use optimized SQL-based search when sqlite-vec is available
 	if VectorExtensionAvailable {
 		return searchVectorOptimized(ctx, db, projectID, queryVector, limit, filters)
@@ -127,6 +134,11 @@ func searchText(ctx context.Context, db *sql.DB, projectID int64, query string,
 		return nil, fmt.Errorf("empty search query")
 	}
 
+	filters, err := resolveBuildFilter(ctx, db, projectID, filters)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build query with filters
 	sqlQuery := `
 		SELECT
@@ -195,6 +207,21 @@ func applyVectorFilters(query string, args []interface{}, filters *SearchFilters
 		args = append(args, filters.FilePattern)
 	}
 
+	if len(filters.Modules) > 0 {
+		query += " AND f.module_path IN (" + placeholders(len(filters.Modules)) + ")"
+		for _, mod := range filters.Modules {
+			args = append(args, mod)
+		}
+	}
+
+	if !filters.ModifiedSince.IsZero() {
+		query += " AND c.last_modified >= ?"
+		args = append(args, nullTime(filters.ModifiedSince))
+	}
+
+	query, args = applyFileExclusions(query, args, filters)
+	query, args = applyGenericFilters(query, args, filters)
+	query, args = applyMetricFilters(query, args, filters)
 	query = applyDDDFilters(query, filters)
 	return query, args
 }
@@ -234,10 +261,113 @@ func applyTextFilters(query string, args []interface{}, filters *SearchFilters)
 		args = append(args, filters.FilePattern)
 	}
 
+	if len(filters.Modules) > 0 {
+		query += " AND f.module_path IN (" + placeholders(len(filters.Modules)) + ")"
+		for _, mod := range filters.Modules {
+			args = append(args, mod)
+		}
+	}
+
+	if !filters.ModifiedSince.IsZero() {
+		query += " AND c.last_modified >= ?"
+		args = append(args, nullTime(filters.ModifiedSince))
+	}
+
+	query, args = applyFileExclusions(query, args, filters)
+	query, args = applyGenericFilters(query, args, filters)
+	query, args = applyMetricFilters(query, args, filters)
 	query = applyDDDFilters(query, filters)
 	return query, args
 }
 
+// applyFileExclusions excludes files resolved by resolveBuildFilter
+func applyFileExclusions(query string, args []interface{}, filters *SearchFilters) (string, []interface{}) {
+	if len(filters.excludedFileIDs) == 0 {
+		return query, args
+	}
+
+	for _, id := range filters.excludedFileIDs {
+		args = append(args, id)
+	}
+	query += " AND f.id NOT IN (" + placeholders(len(filters.excludedFileIDs)) + ")"
+	return query, args
+}
+
+// placeholders returns n comma-separated SQL parameter placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// resolveBuildFilter evaluates filters.BuildContext against the project's constrained
+// files in Go (SQLite cannot evaluate build expressions) and returns a copy of filters
+// listing the files that are not compiled in that context
+func resolveBuildFilter(ctx context.Context, db *sql.DB, projectID int64, filters *SearchFilters) (*SearchFilters, error) {
+	if filters == nil || filters.BuildContext == nil {
+		return filters, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, build_constraint, goos, goarch
+		FROM files
+		WHERE project_id = ? AND (build_constraint != '' OR goos != '' OR goarch != '')
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load build constraints: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	resolved := *filters
+	resolved.excludedFileIDs = nil
+	for rows.Next() {
+		var file File
+		if err := rows.Scan(&file.ID, &file.BuildConstraint, &file.GOOS, &file.GOARCH); err != nil {
+			return nil, fmt.Errorf("failed to scan build constraint: %w", err)
+		}
+		if !filters.BuildContext.Matches(file.Constraint()) {
+			resolved.excludedFileIDs = append(resolved.excludedFileIDs, file.ID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
+
+// applyGenericFilters keeps chunks of generic symbols, optionally with a type
+// parameter constrained by one of filters.Constraints
+func applyGenericFilters(query string, args []interface{}, filters *SearchFilters) (string, []interface{}) {
+	if filters.Generic && len(filters.Constraints) == 0 {
+		query += " AND c.symbol_id IN (SELECT id FROM symbols WHERE type_params != '')"
+	}
+	if len(filters.Constraints) > 0 {
+		query += " AND c.symbol_id IN (SELECT id FROM symbols WHERE "
+		for i, constraint := range filters.Constraints {
+			if i > 0 {
+				query += " OR "
+			}
+			query += "instr(';' || type_constraints || ';', ?) > 0"
+			args = append(args, ";"+constraint+";")
+		}
+		query += ")"
+	}
+	return query, args
+}
+
+// applyMetricFilters keeps chunks of functions and methods reaching the minimum metrics
+func applyMetricFilters(query string, args []interface{}, filters *SearchFilters) (string, []interface{}) {
+	var conditions []string
+	for _, name := range types.MetricNames {
+		if minimum, _ := filters.MinMetrics.Get(name); minimum > 0 {
+			conditions = append(conditions, metricColumns[name]+" >= ?")
+			args = append(args, minimum)
+		}
+	}
+	if len(conditions) > 0 {
+		query += " AND c.symbol_id IN (SELECT id FROM symbols WHERE " + strings.Join(conditions, " AND ") + ")"
+	}
+	return query, args
+}
+
 // applyDDDFilters adds DDD pattern filters to query
 func applyDDDFilters(query string, filters *SearchFilters) string {
 	if filters == nil || len(filters.DDDPatterns) == 0 {
@@ -399,6 +529,48 @@ func cosineSimilarity(a, b []float32) float64 {
 	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
 }
 
+// highlightChunks runs FTS5 highlight() over the content column of the given chunks
+func highlightChunks(ctx context.Context, db *sql.DB, chunkIDs []int64, query string) (map[int64]string, error) {
+	highlighted := make(map[int64]string)
+	sanitized := sanitizeFTSQuery(query)
+	if sanitized == "" || len(chunkIDs) == 0 {
+		return highlighted, nil
+	}
+
+	placeholders := make([]string, len(chunkIDs))
+	args := make([]interface{}, 0, len(chunkIDs)+3)
+	args = append(args, HighlightStart, HighlightEnd, sanitized)
+	for i, id := range chunkIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	// Column 1 is content (column 0 is the unindexed chunk_id)
+	sqlQuery := `
+		SELECT chunk_id, highlight(chunks_fts, 1, ?, ?)
+		FROM chunks_fts
+		WHERE chunks_fts MATCH ?
+		AND chunk_id IN (` + strings.Join(placeholders, ",") + `)
+	`
+
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to highlight chunks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var chunkID int64
+		var content string
+		if err := rows.Scan(&chunkID, &content); err != nil {
+			return nil, err
+		}
+		highlighted[chunkID] = content
+	}
+
+	return highlighted, rows.Err()
+}
+
 // candidate represents a chunk with its similarity score
 type candidate struct {
 	chunkID int64
@@ -412,31 +584,31 @@ func sortCandidates(candidates []candidate) {
 	})
 }
 
-// FTS5 operator pattern for escaping Boolean operators
-var ftsOperatorPattern = regexp.MustCompile(`\b(AND|OR|NOT|NEAR)\b`)
-
-// sanitizeFTSQuery sanitizes a search query for FTS5 to prevent injection attacks.
-// Escapes special FTS5 operators and characters that could be used for SQL injection.
+// sanitizeFTSQuery converts a free-text query into a safe FTS5 expression.
+// Each word becomes a quoted string, so FTS5 operators and special characters
+// in user input are never interpreted. Compound identifiers also match their
+// split form in the identifier_terms column: "upsertEmbedding" becomes
+// ("upsertEmbedding" OR "upsert embedding"). Words are implicitly ANDed.
 func sanitizeFTSQuery(query string) string {
-	if query == "" {
+	words := tokenize.Words(query)
+	if len(words) == 0 {
 		return ""
 	}
 
-	// Replace special characters that have meaning in FTS5
-	replacer := strings.NewReplacer(
-		`"`, `\"`, // Quote
-		`*`, `\*`, // Wildcard
-		`(`, `\(`, // Grouping
-		`)`, `\)`, // Grouping
-	)
-	escaped := replacer.Replace(query)
-
-	// Escape Boolean operators to prevent injection
-	escaped = ftsOperatorPattern.ReplaceAllStringFunc(escaped, func(match string) string {
-		return `\` + match
-	})
+	groups := make([]string, 0, len(words))
+	for _, word := range words {
+		parts := tokenize.SplitIdentifier(word)
+		if len(parts) == 0 {
+			continue // only underscores
+		}
+		quoted := `"` + word + `"`
+		if len(parts) > 1 {
+			quoted = `(` + quoted + ` OR "` + strings.Join(parts, " ") + `")`
+		}
+		groups = append(groups, quoted)
+	}
 
-	return escaped
+	return strings.Join(groups, " ")
 }
 
 // SerializeVector is an exported helper for testing
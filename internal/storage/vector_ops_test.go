@@ -3,10 +3,15 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dshills/gocontext-mcp/pkg/types"
 )
 
 // TestVectorSearchOptimization verifies that the optimized vector search produces
@@ -434,3 +439,504 @@ func BenchmarkVectorSearchComparison(b *testing.B) {
 		}
 	})
 }
+
+func TestSanitizeFTSQuery_IdentifierSplitting(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"chunk", `"chunk"`},
+		{"upsert embedding", `"upsert" "embedding"`},
+		{"upsertEmbedding", `("upsertEmbedding" OR "upsert embedding")`},
+		{"http.Handler", `"http" "Handler"`},
+		{`" OR 1=1 --`, `"OR" "1" "1"`},
+		{"test*value", `"test" "value"`},
+		{"___", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitizeFTSQuery(tt.input))
+		})
+	}
+}
+
+// setupIdentifierSearchData stores chunks and symbols with compound Go identifiers
+func setupIdentifierSearchData(t *testing.T, store *SQLiteStorage) *Project {
+	ctx := context.Background()
+	project := &Project{RootPath: "/test", ModuleName: "test"}
+	require.NoError(t, store.CreateProject(ctx, project))
+
+	file := &File{
+		ProjectID:   project.ID,
+		FilePath:    "storage.go",
+		PackageName: "storage",
+		ContentHash: [32]byte{1},
+		ModTime:     time.Now(),
+	}
+	require.NoError(t, store.UpsertFile(ctx, file))
+
+	contents := []string{
+		"func (s *Store) DeleteChunksBatch(ids []int64) (int, error) { return 0, nil }",
+		"func (s *Store) upsertEmbeddingWithQuerier(q querier) error { return nil }",
+		"func max_file_size() int { return 10 }",
+	}
+	for i, content := range contents {
+		chunk := &Chunk{
+			FileID:      file.ID,
+			Content:     content,
+			ContentHash: [32]byte{byte(i + 1)},
+			StartLine:   i*10 + 1,
+			EndLine:     i*10 + 5,
+			ChunkType:   "function",
+		}
+		require.NoError(t, store.UpsertChunk(ctx, chunk))
+	}
+
+	symbol := &Symbol{
+		FileID:    file.ID,
+		Name:      "DeleteChunksBatch",
+		Kind:      "method",
+		Signature: "func (s *Store) DeleteChunksBatch(ids []int64) (int, error)",
+		Scope:     "exported",
+		StartLine: 1,
+		EndLine:   5,
+	}
+	require.NoError(t, store.UpsertSymbol(ctx, symbol))
+
+	return project
+}
+
+func TestSearchText_IdentifierAware(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+	project := setupIdentifierSearchData(t, store)
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"chunk", 1},            // singular part of DeleteChunksBatch
+		{"chunks batch", 1},     // parts in order
+		{"upsert embedding", 1}, // upsertEmbeddingWithQuerier
+		{"upsertEmbeddingWithQuerier", 1},
+		{"DeleteChunksBatch", 1},
+		{"file size", 1}, // snake_case
+		{"nonexistent", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := store.SearchText(ctx, project.ID, tt.query, 10, nil)
+			require.NoError(t, err)
+			assert.Len(t, results, tt.want)
+		})
+	}
+}
+
+func TestSearchSymbols_IdentifierAware(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+	setupIdentifierSearchData(t, store)
+
+	symbols, err := store.SearchSymbols(ctx, "delete chunk", 10)
+	require.NoError(t, err)
+	require.Len(t, symbols, 1)
+	assert.Equal(t, "DeleteChunksBatch", symbols[0].Name)
+}
+
+func TestBackfillIdentifierTerms(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+	project := setupIdentifierSearchData(t, store)
+
+	// Simulate rows indexed before migration 1.0.3
+	_, err := store.db.ExecContext(ctx, "UPDATE chunks SET identifier_terms = NULL")
+	require.NoError(t, err)
+	results, err := store.SearchText(ctx, project.ID, "upsert embedding", 10, nil)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, backfillIdentifierTerms(ctx, tx))
+	require.NoError(t, tx.Commit())
+
+	results, err = store.SearchText(ctx, project.ID, "upsert embedding", 10, nil)
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestHighlightChunks(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+	project := setupIdentifierSearchData(t, store)
+
+	results, err := store.SearchText(ctx, project.ID, "return", 10, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	chunkIDs := make([]int64, len(results))
+	for i, r := range results {
+		chunkIDs[i] = r.ChunkID
+	}
+
+	highlighted, err := store.HighlightChunks(ctx, chunkIDs, "error")
+	require.NoError(t, err)
+	require.Len(t, highlighted, 2, "only chunks matching the query are returned")
+	for _, content := range highlighted {
+		assert.Contains(t, content, HighlightStart+"error"+HighlightEnd)
+	}
+
+	empty, err := store.HighlightChunks(ctx, nil, "error")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+// setupBuildConstraintData indexes three files defining DriverName under different constraints
+func setupBuildConstraintData(t *testing.T, store *SQLiteStorage, rootPath string) *Project {
+	ctx := context.Background()
+	project := &Project{RootPath: rootPath, ModuleName: "test"}
+	require.NoError(t, store.CreateProject(ctx, project))
+
+	files := []*File{
+		{FilePath: "build_cgo.go", BuildConstraint: "sqlite_vec"},
+		{FilePath: "build_purego.go", BuildConstraint: "purego || !sqlite_vec"},
+		{FilePath: "driver_windows.go", GOOS: "windows"},
+	}
+	for i, file := range files {
+		file.ProjectID = project.ID
+		file.PackageName = "storage"
+		file.ContentHash = [32]byte{byte(i + 1)}
+		file.ModTime = time.Now()
+		require.NoError(t, store.UpsertFile(ctx, file))
+
+		chunk := &Chunk{
+			FileID:      file.ID,
+			Content:     "const DriverName = \"sqlite\"",
+			ContentHash: [32]byte{byte(i + 1)},
+			StartLine:   1,
+			EndLine:     1,
+			ChunkType:   "const_group",
+		}
+		require.NoError(t, store.UpsertChunk(ctx, chunk))
+	}
+	return project
+}
+
+func TestSearchText_BuildContext(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+	project := setupBuildConstraintData(t, store, "/test")
+
+	files := func(filters *SearchFilters) []string {
+		results, err := store.SearchText(ctx, project.ID, "DriverName", 10, filters)
+		require.NoError(t, err)
+		var paths []string
+		for _, r := range results {
+			chunk, err := store.GetChunk(ctx, r.ChunkID)
+			require.NoError(t, err)
+			file, err := store.GetFileByID(ctx, chunk.FileID)
+			require.NoError(t, err)
+			paths = append(paths, file.FilePath)
+		}
+		return paths
+	}
+
+	assert.Len(t, files(nil), 3)
+	assert.ElementsMatch(t, []string{"build_purego.go"},
+		files(&SearchFilters{BuildContext: &types.BuildContext{GOOS: "linux", GOARCH: "amd64"}}))
+	assert.ElementsMatch(t, []string{"build_cgo.go"},
+		files(&SearchFilters{BuildContext: &types.BuildContext{GOOS: "linux", GOARCH: "amd64", Tags: []string{"sqlite_vec"}}}))
+	assert.ElementsMatch(t, []string{"build_purego.go", "driver_windows.go"},
+		files(&SearchFilters{BuildContext: &types.BuildContext{GOOS: "windows", GOARCH: "amd64"}}))
+}
+
+func TestBackfillBuildConstraints(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "build_cgo.go"), []byte("//go:build sqlite_vec\n\npackage storage\n"), 0644))
+	project := setupBuildConstraintData(t, store, root)
+
+	// Simulate rows indexed before migration 1.0.4
+	_, err := store.db.ExecContext(ctx, "UPDATE files SET build_constraint = '', goos = '', goarch = ''")
+	require.NoError(t, err)
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, backfillBuildConstraints(ctx, tx))
+	require.NoError(t, tx.Commit())
+
+	cgo, err := store.GetFile(ctx, project.ID, "build_cgo.go")
+	require.NoError(t, err)
+	assert.Equal(t, "sqlite_vec", cgo.BuildConstraint)
+
+	// Missing files still get name-based constraints
+	windows, err := store.GetFile(ctx, project.ID, "driver_windows.go")
+	require.NoError(t, err)
+	assert.Equal(t, "windows", windows.GOOS)
+}
+
+func TestBackfillPackageDocs(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "doc.go"),
+		[]byte("//go:build linux\n\n// Package storage stores things.\npackage storage\n"), 0644))
+	project := &Project{RootPath: root, ModuleName: "test"}
+	require.NoError(t, store.CreateProject(ctx, project))
+	for i, path := range []string{"doc.go", "missing.go"} {
+		file := &File{ProjectID: project.ID, FilePath: path, PackageName: "storage", ContentHash: [32]byte{byte(i)}, ModTime: time.Now()}
+		require.NoError(t, store.UpsertFile(ctx, file))
+	}
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, backfillPackageDocs(ctx, tx))
+	require.NoError(t, tx.Commit())
+
+	doc, err := store.GetFile(ctx, project.ID, "doc.go")
+	require.NoError(t, err)
+	assert.Equal(t, "Package storage stores things.", doc.PackageDoc)
+
+	missing, err := store.GetFile(ctx, project.ID, "missing.go")
+	require.NoError(t, err)
+	assert.Empty(t, missing.PackageDoc)
+}
+
+func TestChunkHistory_ModifiedSince(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+	project := setupBuildConstraintData(t, store, "/test")
+
+	results, err := store.SearchText(ctx, project.ID, "DriverName", 10, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	old := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 6, 1, 12, 0, 0, 0, time.FixedZone("+0200", 2*3600))
+	history := types.ChunkHistory{LastCommit: "abc123", LastAuthor: "Ada", LastModified: recent, ChangeCount: 4}
+	require.NoError(t, store.UpdateChunkHistory(ctx, results[0].ChunkID, history))
+	require.NoError(t, store.UpdateChunkHistory(ctx, results[1].ChunkID,
+		types.ChunkHistory{LastCommit: "def456", LastModified: old, ChangeCount: 1}))
+
+	chunk, err := store.GetChunk(ctx, results[0].ChunkID)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", chunk.History.LastCommit)
+	assert.Equal(t, "Ada", chunk.History.LastAuthor)
+	assert.Equal(t, 4, chunk.History.ChangeCount)
+	assert.True(t, chunk.History.LastModified.Equal(recent))
+
+	// Chunks without history never match, and zones are compared as instants
+	filtered, err := store.SearchText(ctx, project.ID, "DriverName", 10,
+		&SearchFilters{ModifiedSince: time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, results[0].ChunkID, filtered[0].ChunkID)
+
+	filtered, err = store.SearchText(ctx, project.ID, "DriverName", 10,
+		&SearchFilters{ModifiedSince: time.Date(2024, 6, 1, 10, 0, 1, 0, time.UTC)})
+	require.NoError(t, err)
+	assert.Empty(t, filtered)
+}
+
+func TestSearchText_Generics(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+	project := &Project{RootPath: "/test", ModuleName: "test"}
+	require.NoError(t, store.CreateProject(ctx, project))
+	file := &File{ProjectID: project.ID, FilePath: "set.go", PackageName: "set", ContentHash: [32]byte{1}, ModTime: time.Now()}
+	require.NoError(t, store.UpsertFile(ctx, file))
+
+	symbols := []*Symbol{
+		{Name: "NewSet", Kind: "function", Signature: "func NewSet[T comparable]() Set[T]",
+			TypeParams: []types.TypeParam{{Name: "T", Constraint: "comparable"}}},
+		{Name: "Keys", Kind: "function", Signature: "func Keys[M ~map[K]V, K comparable, V any](m M) []K",
+			TypeParams: []types.TypeParam{{Name: "M", Constraint: "~map[K]V"}, {Name: "K", Constraint: "comparable"}, {Name: "V", Constraint: "any"}}},
+		{Name: "Sum", Kind: "function", Signature: "func Sum[T ~int | ~float64](values ...T) T",
+			TypeParams: []types.TypeParam{{Name: "T", Constraint: "~int | ~float64"}}},
+		{Name: "Clear", Kind: "function", Signature: "func Clear()"},
+	}
+	names := make(map[int64]string)
+	for i, sym := range symbols {
+		sym.FileID = file.ID
+		sym.Scope = "exported"
+		sym.StartLine, sym.EndLine = i*10+1, i*10+5
+		require.NoError(t, store.UpsertSymbol(ctx, sym))
+		chunk := &Chunk{
+			FileID: file.ID, SymbolID: &sym.ID, Content: sym.Signature + " { /* set helper */ }",
+			ContentHash: [32]byte{byte(i + 1)}, StartLine: sym.StartLine, EndLine: sym.EndLine, ChunkType: "function",
+		}
+		require.NoError(t, store.UpsertChunk(ctx, chunk))
+		names[chunk.ID] = sym.Name
+	}
+
+	stored, err := store.ListSymbolsByFile(ctx, file.ID)
+	require.NoError(t, err)
+	require.Len(t, stored, 4)
+	assert.Equal(t, symbols[1].TypeParams, stored[1].TypeParams)
+	assert.Nil(t, stored[3].TypeParams)
+
+	search := func(filters *SearchFilters) []string {
+		results, err := store.SearchText(ctx, project.ID, "set helper", 10, filters)
+		require.NoError(t, err)
+		var found []string
+		for _, r := range results {
+			found = append(found, names[r.ChunkID])
+		}
+		return found
+	}
+
+	assert.ElementsMatch(t, []string{"NewSet", "Keys", "Sum", "Clear"}, search(nil))
+	assert.ElementsMatch(t, []string{"NewSet", "Keys", "Sum"}, search(&SearchFilters{Generic: true}))
+	assert.ElementsMatch(t, []string{"NewSet", "Keys"}, search(&SearchFilters{Constraints: []string{"comparable"}}))
+	assert.ElementsMatch(t, []string{"Sum"}, search(&SearchFilters{Constraints: []string{"~int | ~float64"}}))
+	assert.Empty(t, search(&SearchFilters{Constraints: []string{"~int"}}), "constraints match as written")
+}
+
+func TestSearchFilters_Metrics(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	project := &Project{RootPath: "/test", ModuleName: "test"}
+	require.NoError(t, store.CreateProject(ctx, project))
+	file := &File{ProjectID: project.ID, FilePath: "parse.go", PackageName: "parse", ContentHash: [32]byte{1}, ModTime: time.Now()}
+	require.NoError(t, store.UpsertFile(ctx, file))
+
+	symbols := []*Symbol{
+		{Name: "Parse", Metrics: types.Metrics{Cyclomatic: 12, Cognitive: 20, Lines: 80, Params: 2, Nesting: 4, Returns: 6}},
+		{Name: "parseLine", Metrics: types.Metrics{Cyclomatic: 5, Cognitive: 4, Lines: 20, Params: 1, Nesting: 2, Returns: 2}},
+		{Name: "Reset", Metrics: types.Metrics{Cyclomatic: 1, Lines: 3}},
+	}
+	names := make(map[int64]string)
+	for i, sym := range symbols {
+		sym.FileID, sym.Kind, sym.Scope = file.ID, "function", "exported"
+		sym.StartLine, sym.EndLine = i*100+1, i*100+sym.Metrics.Lines
+		require.NoError(t, store.UpsertSymbol(ctx, sym))
+		chunk := &Chunk{
+			FileID: file.ID, SymbolID: &sym.ID, Content: "func " + sym.Name + "() { /* parser step */ }",
+			ContentHash: [32]byte{byte(i + 1)}, StartLine: sym.StartLine, EndLine: sym.EndLine, ChunkType: "function",
+		}
+		require.NoError(t, store.UpsertChunk(ctx, chunk))
+		names[chunk.ID] = sym.Name
+	}
+
+	search := func(filters *SearchFilters) []string {
+		results, err := store.SearchText(ctx, project.ID, "parser step", 10, filters)
+		require.NoError(t, err)
+		var found []string
+		for _, r := range results {
+			found = append(found, names[r.ChunkID])
+		}
+		return found
+	}
+
+	assert.Len(t, search(&SearchFilters{}), 3)
+	assert.ElementsMatch(t, []string{"Parse", "parseLine"}, search(&SearchFilters{MinMetrics: types.Metrics{Cyclomatic: 5}}))
+	assert.Equal(t, []string{"Parse"}, search(&SearchFilters{MinMetrics: types.Metrics{Cyclomatic: 5, Nesting: 3}}))
+	assert.Empty(t, search(&SearchFilters{MinMetrics: types.Metrics{Params: 3}}))
+}
+
+func TestBackfillImportLines(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"),
+		[]byte("package main\n\nimport (\n\t\"fmt\"\n\tlog \"log/slog\"\n)\n"), 0644))
+	project := &Project{RootPath: root, ModuleName: "test"}
+	require.NoError(t, store.CreateProject(ctx, project))
+	file := &File{ProjectID: project.ID, FilePath: "main.go", PackageName: "main", ContentHash: [32]byte{1}, ModTime: time.Now()}
+	require.NoError(t, store.UpsertFile(ctx, file))
+	for _, imp := range []*Import{{FileID: file.ID, ImportPath: "fmt"}, {FileID: file.ID, ImportPath: "log/slog", Alias: "log"}} {
+		require.NoError(t, store.UpsertImport(ctx, imp))
+	}
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, backfillImportLines(ctx, tx))
+	require.NoError(t, tx.Commit())
+
+	imports, err := store.ListImportsByFile(ctx, file.ID)
+	require.NoError(t, err)
+	require.Len(t, imports, 2)
+	assert.Equal(t, 4, imports[0].Line)
+	assert.Equal(t, 5, imports[1].Line)
+}
+
+func TestBackfillSymbolMetrics(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"),
+		[]byte("package main\n\nfunc run(args []string) error {\n\tif len(args) == 0 {\n\t\treturn nil\n\t}\n\treturn nil\n}\n\ntype T struct{}\n"), 0644))
+	project := &Project{RootPath: root, ModuleName: "test"}
+	require.NoError(t, store.CreateProject(ctx, project))
+	file := &File{ProjectID: project.ID, FilePath: "main.go", PackageName: "main", ContentHash: [32]byte{1}, ModTime: time.Now()}
+	require.NoError(t, store.UpsertFile(ctx, file))
+	run := &Symbol{FileID: file.ID, Name: "run", Kind: "function", Scope: "unexported", StartLine: 3, EndLine: 8}
+	typ := &Symbol{FileID: file.ID, Name: "T", Kind: "struct", Scope: "exported", StartLine: 10, EndLine: 10}
+	for _, sym := range []*Symbol{run, typ} {
+		require.NoError(t, store.UpsertSymbol(ctx, sym))
+	}
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, backfillSymbolMetrics(ctx, tx))
+	require.NoError(t, tx.Commit())
+
+	stored, err := store.GetSymbol(ctx, run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, types.Metrics{Cyclomatic: 2, Cognitive: 1, Lines: 6, Params: 1, Nesting: 1, Returns: 2}, stored.Metrics)
+	stored, err = store.GetSymbol(ctx, typ.ID)
+	require.NoError(t, err)
+	assert.Zero(t, stored.Metrics)
+}
+
+func TestBackfillSymbolRefs(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"),
+		[]byte("package main\n\nvar limit = defaultLimit\n\nfunc run() int {\n\treturn limit\n}\n"), 0644))
+	project := &Project{RootPath: root, ModuleName: "test"}
+	require.NoError(t, store.CreateProject(ctx, project))
+	file := &File{ProjectID: project.ID, FilePath: "main.go", PackageName: "main", ContentHash: [32]byte{1}, ModTime: time.Now()}
+	require.NoError(t, store.UpsertFile(ctx, file))
+	limit := &Symbol{FileID: file.ID, Name: "limit", Kind: "var", Scope: "unexported", StartLine: 3, EndLine: 3}
+	run := &Symbol{FileID: file.ID, Name: "run", Kind: "function", Scope: "unexported", StartLine: 5, EndLine: 7}
+	for _, sym := range []*Symbol{limit, run} {
+		require.NoError(t, store.UpsertSymbol(ctx, sym))
+	}
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, backfillSymbolRefs(ctx, tx))
+	require.NoError(t, tx.Commit())
+
+	refs, err := store.ListSymbolRefs(ctx, project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []*SymbolRef{
+		{SymbolID: limit.ID, Ref: types.Ref{Kind: types.RefUse, Name: "defaultLimit"}},
+		{SymbolID: run.ID, Ref: types.Ref{Kind: types.RefUse, Name: "limit"}},
+	}, refs)
+}
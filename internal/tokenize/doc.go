@@ -0,0 +1,18 @@
+// Package tokenize splits Go identifiers into searchable word parts.
+//
+// Go code favors camelCase and MixedCaps names, which full-text tokenizers treat
+// as single opaque words. A keyword search for "chunk" therefore misses
+// DeleteChunksBatch. This package splits identifiers on case, underscore and
+// digit boundaries so both the index and queries can work with word parts.
+//
+// # Basic Usage
+//
+//	tokenize.SplitIdentifier("parseHTTPRequest")
+//	// []string{"parse", "http", "request"}
+//
+//	tokenize.IdentifierTerms("func upsertEmbeddingWithQuerier(q querier)")
+//	// "upsert embedding with querier"
+//
+// IdentifierTerms output is stored in an extra FTS5 column next to the original
+// text, so exact matches on whole identifiers keep working.
+package tokenize
@@ -0,0 +1,85 @@
+package tokenize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IsSeparator reports whether r separates identifiers in code or prose
+func IsSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+}
+
+// Words splits text into identifier-like words, keeping underscores
+func Words(text string) []string {
+	return strings.FieldsFunc(text, IsSeparator)
+}
+
+// SplitIdentifier splits a Go identifier into lowercase parts on camelCase,
+// snake_case and digit boundaries ("parseHTTPRequest2" -> parse, http, request, 2)
+func SplitIdentifier(ident string) []string {
+	var parts []string
+	runes := []rune(ident)
+	start := 0
+
+	flush := func(end int) {
+		if end > start {
+			parts = append(parts, strings.ToLower(string(runes[start:end])))
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '_' {
+			flush(i)
+			start = i + 1
+			continue
+		}
+		if i == start {
+			continue
+		}
+		prev := runes[i-1]
+		switch {
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			// fooBar -> foo | Bar
+			flush(i)
+			start = i
+		case unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// HTTPRequest -> HTTP | Request
+			flush(i)
+			start = i
+		case unicode.IsDigit(r) != unicode.IsDigit(prev):
+			flush(i)
+			start = i
+		}
+	}
+	flush(len(runes))
+
+	return parts
+}
+
+// IdentifierTerms returns the split parts of every compound identifier in text,
+// space-separated and in first-seen order. Identifiers that do not split
+// (plain words) are omitted since the original text already contains them.
+func IdentifierTerms(text string) string {
+	seen := make(map[string]bool)
+	var b strings.Builder
+
+	for _, word := range Words(text) {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+
+		parts := SplitIdentifier(word)
+		if len(parts) < 2 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strings.Join(parts, " "))
+	}
+
+	return b.String()
+}
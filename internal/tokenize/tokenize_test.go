@@ -0,0 +1,68 @@
+package tokenize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitIdentifier(t *testing.T) {
+	tests := []struct {
+		ident string
+		want  []string
+	}{
+		{"DeleteChunksBatch", []string{"delete", "chunks", "batch"}},
+		{"upsertEmbeddingWithQuerier", []string{"upsert", "embedding", "with", "querier"}},
+		{"parseHTTPRequest", []string{"parse", "http", "request"}},
+		{"snake_case_name", []string{"snake", "case", "name"}},
+		{"sha256Sum", []string{"sha", "256", "sum"}},
+		{"ID", []string{"id"}},
+		{"_private", []string{"private"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ident, func(t *testing.T) {
+			got := SplitIdentifier(tt.ident)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitIdentifier(%q) = %v, want %v", tt.ident, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifierTerms(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "function signature",
+			text: "func (s *Storage) upsertEmbeddingWithQuerier(ctx context.Context) error",
+			want: "upsert embedding with querier",
+		},
+		{
+			name: "plain words omitted",
+			text: "return nil, err",
+			want: "",
+		},
+		{
+			name: "duplicates collapsed",
+			text: "DeleteChunksBatch(ids); DeleteChunksBatch(more)",
+			want: "delete chunks batch",
+		},
+		{
+			name: "snake case",
+			text: "max_file_size = 10",
+			want: "max file size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IdentifierTerms(tt.text); got != tt.want {
+				t.Errorf("IdentifierTerms(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
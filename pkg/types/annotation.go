@@ -0,0 +1,41 @@
+package types
+
+// AnnotationKind classifies a comment annotation
+type AnnotationKind string
+
+const (
+	AnnotationTodo       AnnotationKind = "todo"
+	AnnotationFixme      AnnotationKind = "fixme"
+	AnnotationHack       AnnotationKind = "hack"
+	AnnotationXXX        AnnotationKind = "xxx"
+	AnnotationNote       AnnotationKind = "note"
+	AnnotationDeprecated AnnotationKind = "deprecated"
+	AnnotationNolint     AnnotationKind = "nolint"
+)
+
+// AnnotationKinds lists every annotation kind
+var AnnotationKinds = []AnnotationKind{
+	AnnotationTodo, AnnotationFixme, AnnotationHack, AnnotationXXX,
+	AnnotationNote, AnnotationDeprecated, AnnotationNolint,
+}
+
+// Annotation is a marker comment such as "TODO(alice): ...", a "Deprecated:"
+// paragraph or a //nolint directive
+type Annotation struct {
+	Kind   AnnotationKind
+	Text   string   // Marker text; for nolint, the linters followed by the reason
+	Line   int      // Line of the marker
+	Symbol string   // Documented or enclosing symbol ("Type.Method" for methods and fields), empty at file level
+	Owner  string   // Owner from "TODO(owner):"
+	Issues []string // Issue references such as "#123" or "GH-45"
+}
+
+// IsValidAnnotationKind reports whether kind is a known annotation kind
+func IsValidAnnotationKind(kind string) bool {
+	for _, k := range AnnotationKinds {
+		if string(k) == kind {
+			return true
+		}
+	}
+	return false
+}
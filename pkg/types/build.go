@@ -0,0 +1,200 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"go/build/constraint"
+	"path/filepath"
+	"strings"
+)
+
+// KnownOS lists GOOS values recognized in file name suffixes and build tags
+var KnownOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+	"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+	"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+	"windows": true, "zos": true,
+}
+
+// KnownArch lists GOARCH values recognized in file name suffixes and build tags
+var KnownArch = map[string]bool{
+	"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+	"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true,
+	"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+	"ppc64le": true, "riscv": true, "riscv64": true, "s390": true, "s390x": true,
+	"sparc": true, "sparc64": true, "wasm": true,
+}
+
+// UnixOS lists GOOS values that satisfy the "unix" build tag
+var UnixOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+	"hurd": true, "illumos": true, "ios": true, "linux": true, "netbsd": true,
+	"openbsd": true, "solaris": true,
+}
+
+// BuildConstraint describes the build conditions under which a file is compiled
+type BuildConstraint struct {
+	Expr   string // Normalized //go:build expression, empty if none
+	GOOS   string // Operating system implied by the file name (e.g. _linux.go)
+	GOARCH string // Architecture implied by the file name (e.g. _arm64.go)
+}
+
+// IsZero reports whether the file is compiled unconditionally
+func (c BuildConstraint) IsZero() bool {
+	return c.Expr == "" && c.GOOS == "" && c.GOARCH == ""
+}
+
+// String renders the effective constraint as a single //go:build-style expression
+func (c BuildConstraint) String() string {
+	var parts []string
+	if c.GOOS != "" {
+		parts = append(parts, c.GOOS)
+	}
+	if c.GOARCH != "" {
+		parts = append(parts, c.GOARCH)
+	}
+	if c.Expr != "" {
+		if len(parts) > 0 && strings.ContainsAny(c.Expr, "|") {
+			parts = append(parts, "("+c.Expr+")")
+		} else {
+			parts = append(parts, c.Expr)
+		}
+	}
+	return strings.Join(parts, " && ")
+}
+
+// BuildContext is a target GOOS/GOARCH plus extra build tags to evaluate constraints against
+type BuildContext struct {
+	GOOS   string
+	GOARCH string
+	Tags   []string
+}
+
+// Matches reports whether a file with constraint c is compiled in this build context.
+// Release tags (go1.N) and the gc compiler tag are always satisfied; cgo must be listed in Tags.
+func (b BuildContext) Matches(c BuildConstraint) bool {
+	if c.GOOS != "" && !b.hasTag(c.GOOS) {
+		return false
+	}
+	if c.GOARCH != "" && c.GOARCH != b.GOARCH {
+		return false
+	}
+	if c.Expr == "" {
+		return true
+	}
+
+	expr, err := constraint.Parse("//go:build " + c.Expr)
+	if err != nil {
+		return true // Unparseable constraints are not used to hide files
+	}
+	return expr.Eval(b.hasTag)
+}
+
+// hasTag reports whether a single build tag is satisfied
+func (b BuildContext) hasTag(tag string) bool {
+	switch {
+	case tag == b.GOOS || tag == b.GOARCH:
+		return true
+	case tag == "unix":
+		return UnixOS[b.GOOS]
+	case tag == "linux" && b.GOOS == "android",
+		tag == "solaris" && b.GOOS == "illumos",
+		tag == "darwin" && b.GOOS == "ios":
+		return true
+	case tag == "gc", strings.HasPrefix(tag, "go1."):
+		return true
+	}
+	for _, t := range b.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseBuildConstraint extracts a file's build constraint from its //go:build
+// (or legacy // +build) lines and its _GOOS/_GOARCH file name suffixes
+func ParseBuildConstraint(filePath string, src []byte) BuildConstraint {
+	c := BuildConstraint{}
+	c.GOOS, c.GOARCH = FilenameOSArch(filePath)
+
+	var goBuild constraint.Expr
+	var plusBuild []constraint.Expr
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	inBlock := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Constraints must precede the package clause; skip comments and blank lines
+		if inBlock {
+			inBlock = !strings.Contains(line, "*/")
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "/*") {
+			inBlock = !strings.Contains(line[2:], "*/")
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+
+		switch {
+		case constraint.IsGoBuild(line):
+			if goBuild == nil {
+				if expr, err := constraint.Parse(line); err == nil {
+					goBuild = expr
+				}
+			}
+		case constraint.IsPlusBuild(line):
+			if expr, err := constraint.Parse(line); err == nil {
+				plusBuild = append(plusBuild, expr)
+			}
+		}
+	}
+
+	switch {
+	case goBuild != nil:
+		c.Expr = goBuild.String()
+	case len(plusBuild) > 0:
+		expr := plusBuild[0]
+		for _, e := range plusBuild[1:] {
+			expr = &constraint.AndExpr{X: expr, Y: e}
+		}
+		c.Expr = expr.String()
+	}
+	return c
+}
+
+// FilenameOSArch returns the GOOS and GOARCH implied by a file name suffix,
+// following the go/build rules (name_GOOS.go, name_GOARCH.go, name_GOOS_GOARCH.go,
+// each optionally followed by _test)
+func FilenameOSArch(filePath string) (goos, goarch string) {
+	name := filepath.Base(filePath)
+	name, _, _ = strings.Cut(name, ".")
+
+	// Everything before the first underscore is the file's own name
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return "", ""
+	}
+	parts := strings.Split(name[i:], "_")
+	if n := len(parts); n > 0 && parts[n-1] == "test" {
+		parts = parts[:n-1]
+	}
+
+	n := len(parts)
+	if n >= 2 && KnownOS[parts[n-2]] && KnownArch[parts[n-1]] {
+		return parts[n-2], parts[n-1]
+	}
+	if n >= 1 && KnownOS[parts[n-1]] {
+		return parts[n-1], ""
+	}
+	if n >= 1 && KnownArch[parts[n-1]] {
+		return "", parts[n-1]
+	}
+	return "", ""
+}
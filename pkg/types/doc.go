@@ -26,12 +26,16 @@
 //
 // # Domain-Driven Design (DDD) Pattern Detection
 //
-// Symbol types include flags for detecting DDD patterns based on naming conventions:
+// Symbol types include flags for DDD patterns, set by naming conventions or by
+// the project's rules (see package ddd):
 //
-//	symbol.IsRepository  // "*Repository" suffix
-//	symbol.IsService     // "*Service" suffix
-//	symbol.IsEntity      // "*Entity" suffix or has "ID" field
-//	symbol.IsAggregate   // "*Aggregate" suffix
+//	symbol.IsRepository     // "*Repository" suffix
+//	symbol.IsService        // "*Service" suffix
+//	symbol.IsEntity         // "*Entity" suffix, or an aggregate
+//	symbol.IsAggregateRoot  // "*Aggregate" suffix
+//
+// symbol.DDD lists the patterns behind the flags, each with a confidence and the
+// criteria that matched.
 //
 // These flags enable architectural pattern queries:
 //
@@ -6,6 +6,16 @@ type ParseResult struct {
 	Symbols     []Symbol
 	Imports     []Import
 	PackageName string
+	PackageDoc  string // Doc comment preceding the package clause
+
+	// Build constraint from //go:build lines and the file name suffix
+	Build BuildConstraint
+
+	// Marker comments (TODO, FIXME, Deprecated:, //nolint, ...)
+	Annotations []Annotation
+
+	// Test, Benchmark, Fuzz and Example functions (_test.go files only)
+	Tests []TestFunc
 
 	// Errors encountered during parsing
 	Errors []ParseError
@@ -15,6 +25,7 @@ type ParseResult struct {
 type Import struct {
 	Path  string // Import path (e.g., "github.com/pkg/errors")
 	Alias string // Import alias if present (e.g., ".")
+	Line  int    // Line of the import spec
 }
 
 // ParseError represents an error that occurred during parsing
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // SearchResult represents a single search result with relevance information
 type SearchResult struct {
 	// Identification
@@ -9,11 +11,51 @@ type SearchResult struct {
 	// Scoring
 	RelevanceScore float64 // Combined score from vector + BM25 + RRF
 
+	// Project the result belongs to
+	ProjectID   int64
+	ProjectRoot string // Set for cross-project searches
+	Dependency  string // "module@version" when the project is an indexed dependency
+
 	// Metadata
 	Symbol  *Symbol // Nullable - may not have an associated symbol
 	File    *FileInfo
 	Content string // Chunk content
 	Context string // Combined context before and after
+
+	// Hit locations (populated when highlighting is requested)
+	MatchedTerms []string    // Query terms found in the chunk
+	Matches      []LineMatch // Lines inside the chunk that explain the match
+	Snippet      string      // Compact excerpt of matched lines with line numbers
+
+	// Git history of the chunk's lines, nil unless indexed with git history
+	History *ChunkHistory
+}
+
+// ChunkHistory is git metadata for the lines of a chunk, computed at index time
+// by following the lines back through first-parent history
+type ChunkHistory struct {
+	LastCommit   string    // Newest commit touching the lines; empty if uncommitted or beyond the history depth
+	LastAuthor   string    // Author of LastCommit
+	LastModified time.Time // Author date of LastCommit, or the file time for uncommitted changes; zero if unknown
+	ChangeCount  int       // Commits touching the lines within the history depth
+}
+
+// IsZero reports whether no history was recorded
+func (h ChunkHistory) IsZero() bool {
+	return h.LastCommit == "" && h.LastModified.IsZero() && h.ChangeCount == 0
+}
+
+// Uncommitted reports whether the lines have working tree changes not yet committed
+func (h ChunkHistory) Uncommitted() bool {
+	return h.LastCommit == "" && !h.LastModified.IsZero()
+}
+
+// LineMatch locates a query hit on a single line of a search result
+type LineMatch struct {
+	Line  int      // Absolute line number in the file
+	Text  string   // Line content with surrounding whitespace trimmed
+	Terms []string // Query terms found on the line; empty for similarity-only hits
+	Score float64  // Per-line relevance in [0, 1]
 }
 
 // FileInfo contains file metadata for a search result
@@ -22,6 +64,12 @@ type FileInfo struct {
 	Package   string
 	StartLine int
 	EndLine   int
+
+	// BuildConstraint is the file's effective build constraint, empty if always compiled
+	BuildConstraint string
+
+	// Module is the path of the Go module that owns the file
+	Module string
 }
 
 // Validate checks if the search result is valid
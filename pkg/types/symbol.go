@@ -3,6 +3,7 @@ package types
 import (
 	"errors"
 	"go/token"
+	"strings"
 )
 
 // SymbolKind represents the type of Go language symbol
@@ -34,6 +35,98 @@ type Position struct {
 	Column int
 }
 
+// TypeParam is a type parameter of a generic function or type
+type TypeParam struct {
+	Name       string
+	Constraint string // As written, e.g. "any", "comparable" or "~int | ~string"
+}
+
+// StructTag is one key of a struct field tag, e.g. json:"created_at,omitempty"
+type StructTag struct {
+	Key   string // "json", "db", "yaml", "validate", ...
+	Value string // Unquoted value: "created_at,omitempty"
+}
+
+// Name returns the value up to the first comma, the name most encoders map the
+// field to ("created_at"); "-" marks a skipped field
+func (t StructTag) Name() string {
+	name, _, _ := strings.Cut(t.Value, ",")
+	return name
+}
+
+// DDDMatch is a domain-driven design pattern detected on a type
+type DDDMatch struct {
+	Pattern    string  // "aggregate", "entity", "value_object", "repository", "service", "command", "query" or "handler"
+	Confidence float64 // 0-1; evidence from several rules adds up
+	Reason     string  // Criteria that matched, e.g. "name ends in Repository"
+}
+
+// RefKind classifies a name a declaration refers to
+type RefKind string
+
+const (
+	RefParam  RefKind = "param"  // Parameter type
+	RefResult RefKind = "result" // Result type
+	RefType   RefKind = "type"   // Type named in a body (composite literal, declaration, new) or a field's type
+	RefCall   RefKind = "call"   // Function call, or conversion to a named type
+	RefMethod RefKind = "method" // Method call on a value; the receiver type is not resolved
+	RefUse    RefKind = "use"    // Any other name: a value, constant or type, or a composite literal key
+	RefSelect RefKind = "select" // Field or method selected without a call, or a composite literal key
+)
+
+// Ref is a name referred to by a declaration. Names are resolved syntactically,
+// without type information.
+type Ref struct {
+	Kind    RefKind
+	Package string // Import path of a qualified name such as domain.Order, empty for the same package
+	Name    string
+	Via     string // For method calls on a field of the receiver: the field ("orders" in h.orders.Save)
+}
+
+// Metrics measure the complexity of a function or method body. Function literals
+// count toward the function declaring them.
+type Metrics struct {
+	Cyclomatic int // 1 + decision points: if, for, case, && and ||
+	Cognitive  int // Breaks in linear flow, weighted by nesting (SonarSource)
+	Lines      int // Lines of the declaration, from func to the closing brace
+	Params     int // Declared parameters; a variadic parameter counts once
+	Nesting    int // Deepest nesting of control structures and function literals
+	Returns    int // Return statements, excluding those of function literals
+}
+
+// Metric names, as used by search filters and sorts
+const (
+	MetricCyclomatic = "cyclomatic"
+	MetricCognitive  = "cognitive"
+	MetricLines      = "lines"
+	MetricParams     = "params"
+	MetricNesting    = "nesting"
+	MetricReturns    = "returns"
+)
+
+// MetricNames lists the metrics in a stable order
+var MetricNames = []string{MetricCyclomatic, MetricCognitive, MetricLines, MetricParams, MetricNesting, MetricReturns}
+
+// Get returns the named metric; ok is false for an unknown name
+func (m Metrics) Get(name string) (value int, ok bool) {
+	switch name {
+	case MetricCyclomatic:
+		return m.Cyclomatic, true
+	case MetricCognitive:
+		return m.Cognitive, true
+	case MetricLines:
+		return m.Lines, true
+	case MetricParams:
+		return m.Params, true
+	case MetricNesting:
+		return m.Nesting, true
+	case MetricReturns:
+		return m.Returns, true
+	default:
+		return 0, false
+	}
+}
+
 // Symbol represents a code symbol extracted from Go source via AST parsing
 type Symbol struct {
 	// Identification
@@ -49,6 +142,21 @@ type Symbol struct {
 	Scope    SymbolScope
 	Receiver string // For methods: receiver type name
 
+	// Generics
+	TypeParams []TypeParam // Type parameters of generic functions and types, in order
+
+	// Struct fields
+	Tags []StructTag // Parsed struct tag keys, in tag order
+
+	// Structs and interfaces
+	Embeds []string // Embedded types as written, e.g. "*Base", "io.Reader", "List[T]"
+
+	// All declarations
+	Refs []Ref // Names referred to, once each
+
+	// Functions and methods
+	Metrics Metrics
+
 	// Location
 	Start Position
 	End   Position
@@ -62,6 +170,7 @@ type Symbol struct {
 	IsCommand       bool
 	IsQuery         bool
 	IsHandler       bool
+	DDD             []DDDMatch // Patterns behind the flags, most confident first
 }
 
 // ValidateKind checks if the symbol kind is valid
@@ -129,6 +238,11 @@ func (s *Symbol) Validate() error {
 	return nil
 }
 
+// IsGeneric returns true if the symbol declares type parameters
+func (s *Symbol) IsGeneric() bool {
+	return len(s.TypeParams) > 0
+}
+
 // IsDDDPattern returns true if this symbol matches any DDD pattern
 func (s *Symbol) IsDDDPattern() bool {
 	return s.IsAggregateRoot || s.IsEntity || s.IsValueObject ||
@@ -0,0 +1,28 @@
+package types
+
+// TestKind classifies a test function
+type TestKind string
+
+const (
+	TestKindTest      TestKind = "test"
+	TestKindBenchmark TestKind = "benchmark"
+	TestKindFuzz      TestKind = "fuzz"
+	TestKindExample   TestKind = "example"
+)
+
+// TestFunc is a Test, Benchmark, Fuzz or Example function declared in a _test.go file
+type TestFunc struct {
+	Name   string
+	Kind   TestKind
+	Start  Position
+	End    Position
+	Target string     // Symbol named by the test: "SQLiteStorage.GetChunk" for TestSQLiteStorage_GetChunk
+	Calls  []TestCall // Functions and methods called from the test body
+}
+
+// TestCall is a function or method called from a test
+type TestCall struct {
+	Package string // Import path for qualified calls such as storage.New, empty for the test's own package
+	Name    string
+	Method  bool // Called on a value; the receiver type is not resolved
+}
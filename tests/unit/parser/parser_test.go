@@ -53,12 +53,13 @@ func TestParseFile_Simple(t *testing.T) {
 		symbolCounts[sym.Kind]++
 	}
 
-	// Expected: 1 struct (User), 1 interface (UserRepository), 1 method (Greet),
-	// 1 function (ValidateEmail), 2 consts, 2 vars, 4 fields (ID, Name, Email, CreatedAt)
+	// Expected: 1 struct (User), 1 interface (UserRepository), 3 methods (Greet and the
+	// interface's FindByID and Save), 1 function (ValidateEmail), 2 consts, 2 vars,
+	// 4 fields (ID, Name, Email, CreatedAt)
 	expectedCounts := map[types.SymbolKind]int{
 		types.KindStruct:    1,
 		types.KindInterface: 1,
-		types.KindMethod:    1,
+		types.KindMethod:    3,
 		types.KindFunction:  1,
 		types.KindConst:     2,
 		types.KindVar:       2,
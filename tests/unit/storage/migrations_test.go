@@ -37,6 +37,7 @@ func TestApplyMigrations(t *testing.T) {
 	tables := []string{
 		"projects", "files", "symbols", "chunks", "embeddings",
 		"imports", "search_queries", "symbols_fts", "chunks_fts",
+		"embedding_cache",
 	}
 
 	for _, table := range tables {
@@ -70,15 +71,15 @@ func TestMigrationsIdempotent(t *testing.T) {
 		t.Fatalf("Second migration failed: %v", err)
 	}
 
-	// Should only have one version record
+	// Should only have one version record per migration
 	var count int
 	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_version").Scan(&count)
 	if err != nil {
 		t.Fatalf("Failed to count versions: %v", err)
 	}
 
-	if count != 1 {
-		t.Errorf("Expected 1 schema version record, got %d", count)
+	if count != len(storage.AllMigrations) {
+		t.Errorf("Expected %d schema version records, got %d", len(storage.AllMigrations), count)
 	}
 }
 
@@ -352,7 +353,7 @@ func TestMigrationErrorHandling(t *testing.T) {
 				return db
 			},
 			expectError:   false,
-			expectVersion: "1.0.1", // Should apply all migrations starting from 0.0.0
+			expectVersion: storage.CurrentSchemaVersion, // Should apply all migrations starting from 0.0.0
 		},
 		{
 			name: "Empty schema_version table - starts from 0.0.0",
@@ -372,7 +373,7 @@ func TestMigrationErrorHandling(t *testing.T) {
 				return db
 			},
 			expectError:   false,
-			expectVersion: "1.0.1", // Should apply all migrations starting from 0.0.0
+			expectVersion: storage.CurrentSchemaVersion, // Should apply all migrations starting from 0.0.0
 		},
 		{
 			name: "Invalid version in database",